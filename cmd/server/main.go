@@ -1,17 +1,26 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github/GuilhermeHermes/GO_API/configs"
 	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
 	"github/GuilhermeHermes/GO_API/internal/infra/webserver"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// defaultTrashRetention is how long deleted products and users are kept
+// when TRASH_RETENTION_DAYS is not set.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
 func main() {
 	cfg, err := configs.LoadConfig(".")
 	if err != nil {
@@ -27,7 +36,36 @@ func main() {
 	}
 
 	// Auto migrate
-	db.AutoMigrate(&entity.User{}, &entity.Product{})
+	if err := database.Migrate(db); err != nil {
+		panic(err)
+	}
+
+	if cfg.ExchangeRatesFile != "" {
+		if err := loadExchangeRates(db, cfg.ExchangeRatesFile); err != nil {
+			panic(err)
+		}
+	}
+
+	if cfg.TaxRatesFile != "" {
+		if err := loadTaxRates(db, cfg.TaxRatesFile); err != nil {
+			panic(err)
+		}
+	}
+
+	if cfg.AdminEmail != "" {
+		if err := ensureAdmin(database.NewUserRepository(db), cfg.AdminEmail, cfg.AdminPassword); err != nil {
+			panic(err)
+		}
+	}
+
+	go expireReservations(database.NewInventoryRepository(db), time.Minute)
+	go publishScheduled(database.NewProductRepository(db), time.Minute)
+
+	retention := defaultTrashRetention
+	if cfg.TrashRetentionDays > 0 {
+		retention = time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+	}
+	go purgeTrash(database.NewProductRepository(db), database.NewUserRepository(db), retention, time.Hour)
 
 	// Setup routes
 	router := webserver.SetupRoutes(db)
@@ -35,3 +73,108 @@ func main() {
 	fmt.Printf("Server starting on port %s\n", cfg.WebServerPort)
 	http.ListenAndServe(":"+cfg.WebServerPort, router)
 }
+
+// loadExchangeRates replaces the stored exchange rates with the contents
+// of path. Later changes through PUT /exchange-rates last until the next
+// restart.
+func loadExchangeRates(db *gorm.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rates, err := entity.ReadExchangeRates(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return database.NewExchangeRateRepository(db).Replace(rates)
+}
+
+// loadTaxRates replaces the stored tax rates with the contents of path.
+// Later changes through PUT /tax/rates last until the next restart.
+func loadTaxRates(db *gorm.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rates, err := entity.ReadTaxRates(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return database.NewTaxRateRepository(db).Replace(rates)
+}
+
+// ensureAdmin creates an admin with email and password unless an account
+// with that email already exists. Signing up never grants a role, so this
+// is how the first admin is made; it assigns the others with
+// PUT /users/{id}/role.
+func ensureAdmin(users *database.UserRepository, email, password string) error {
+	exists, err := users.Exists(email)
+	if err != nil || exists {
+		return err
+	}
+	if password == "" {
+		return errors.New("ADMIN_PASSWORD is required with ADMIN_EMAIL")
+	}
+	admin, err := entity.NewUser("admin", email, password, entity.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return users.Create(admin)
+}
+
+// expireReservations gives the units of lapsed stock reservations back
+// every interval. Reservations are also expired lazily when their stock is
+// reserved or they are committed, so this only keeps levels tidy.
+func expireReservations(inventory *database.InventoryRepository, interval time.Duration) {
+	for range time.Tick(interval) {
+		expired, err := inventory.ExpireReservations(time.Now())
+		if err != nil {
+			log.Printf("expiring stock reservations: %v", err)
+			continue
+		}
+		if expired > 0 {
+			log.Printf("expired %d stock reservations", expired)
+		}
+	}
+}
+
+// publishScheduled publishes and archives, every interval, the products
+// whose publish_at or unpublish_at has come, so they go live or come down
+// within one interval of the time set.
+func publishScheduled(products database.ProductDB, interval time.Duration) {
+	for range time.Tick(interval) {
+		changed, err := products.PublishScheduled(time.Now())
+		if err != nil {
+			log.Printf("applying product schedules: %v", err)
+			continue
+		}
+		if changed > 0 {
+			log.Printf("published or archived %d scheduled products", changed)
+		}
+	}
+}
+
+// purgeTrash permanently removes, every interval, the products and users
+// that were deleted more than retention ago.
+func purgeTrash(products database.ProductDB, users database.UserDB, retention, interval time.Duration) {
+	for range time.Tick(interval) {
+		cutoff := time.Now().Add(-retention)
+		for name, purge := range map[string]func(time.Time) (int, error){
+			"products": products.Purge,
+			"users":    users.Purge,
+		} {
+			purged, err := purge(cutoff)
+			if err != nil {
+				log.Printf("purging deleted %s: %v", name, err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("purged %d deleted %s", purged, name)
+			}
+		}
+	}
+}
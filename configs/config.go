@@ -17,6 +17,35 @@ type config struct {
 	WebServerPort string `mapstructure:"WEB_SERVER_PORT"`
 	JwtSecret     string `mapstructure:"JWT_SECRET"`
 	JwtExpiration int64  `mapstructure:"JWT_EXPIRATION"`
+	// ExchangeRatesFile, when set, replaces the exchange-rate table at startup.
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+	// ReservationTTL is how long stock reservations last, in seconds.
+	ReservationTTL int64 `mapstructure:"RESERVATION_TTL"`
+	// PaymentProvider names the payment gateway; only "fake" exists so far.
+	PaymentProvider      string `mapstructure:"PAYMENT_PROVIDER"`
+	PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	// TaxRatesFile, when set, replaces the tax-rate table at startup.
+	TaxRatesFile string `mapstructure:"TAX_RATES_FILE"`
+	// TaxPricesIncludeTax says catalog prices already contain tax.
+	TaxPricesIncludeTax bool `mapstructure:"TAX_PRICES_INCLUDE_TAX"`
+	// TaxRounding is half_up (the default), half_even or down.
+	TaxRounding string `mapstructure:"TAX_ROUNDING"`
+	// TaxDefaultCountry and TaxDefaultRegion are where goods are taxed when
+	// the shopper does not say.
+	TaxDefaultCountry string `mapstructure:"TAX_DEFAULT_COUNTRY"`
+	TaxDefaultRegion  string `mapstructure:"TAX_DEFAULT_REGION"`
+	// ReviewsVerifiedOnly only lets users who bought a product review it.
+	ReviewsVerifiedOnly bool `mapstructure:"REVIEWS_VERIFIED_ONLY"`
+	// TrashRetentionDays is how long deleted products and users are kept
+	// before they are purged; zero keeps them 30 days.
+	TrashRetentionDays int `mapstructure:"TRASH_RETENTION_DAYS"`
+	// RequireIfMatch makes If-Match mandatory on writes to products and
+	// users instead of only honoring it when sent.
+	RequireIfMatch bool `mapstructure:"REQUIRE_IF_MATCH"`
+	// AdminEmail and AdminPassword, when set, create the first admin at
+	// startup if no account uses that email yet.
+	AdminEmail    string `mapstructure:"ADMIN_EMAIL"`
+	AdminPassword string `mapstructure:"ADMIN_PASSWORD"`
 	TokenAuth     *jwtauth.JWTAuth
 }
 
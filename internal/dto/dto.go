@@ -1,35 +1,857 @@
 package dto
 
+import (
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
 // Product DTOs
 type CreateProductRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       entity.Money `json:"price"`
+	// Tags are normalized to lowercase. On PUT, omitting them keeps the
+	// current tags and [] clears them.
+	Tags []string `json:"tags,omitempty"`
+	// TaxClass defaults to "standard" on POST; on PUT, omitting it keeps
+	// the current class.
+	TaxClass string `json:"tax_class,omitempty"`
+	// WeightGrams and Dimensions default to 0 on POST; on PUT, omitting
+	// them keeps the current values.
+	WeightGrams *int64      `json:"weight_grams,omitempty"`
+	Dimensions  *Dimensions `json:"dimensions,omitempty"`
+}
+
+// Dimensions are a packed product's size in millimetres.
+type Dimensions struct {
+	LengthMM int64 `json:"length_mm"`
+	WidthMM  int64 `json:"width_mm"`
+	HeightMM int64 `json:"height_mm"`
 }
 
+// UpdateProductRequest is the editable part of a product. Sent as a JSON
+// Merge Patch to PATCH /products/{id}, nil fields are left as they are;
+// the server applies patches to this document filled in with the
+// product's current values.
 type UpdateProductRequest struct {
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Price       float64 `json:"price,omitempty"`
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Price       *entity.Money `json:"price,omitempty"`
+	Tags        *[]string     `json:"tags,omitempty"`
+	TaxClass    *string       `json:"tax_class,omitempty"`
+	WeightGrams *int64        `json:"weight_grams,omitempty"`
+	Dimensions  *Dimensions   `json:"dimensions,omitempty"`
 }
 
+// ProductResponse carries the price in the requested currency. When that
+// is not the product's own currency, BasePrice holds the stored price and
+// PriceSource says whether Price came from the price list ("list") or an
+// exchange-rate conversion ("converted").
 type ProductResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Price       entity.Money  `json:"price"`
+	Tags        []string      `json:"tags"`
+	TaxClass    string        `json:"tax_class"`
+	WeightGrams int64         `json:"weight_grams"`
+	Dimensions  Dimensions    `json:"dimensions"`
+	BasePrice   *entity.Money `json:"base_price,omitempty"`
+	PriceSource string        `json:"price_source,omitempty"`
+	// Breadcrumbs holds one root-to-leaf path per assigned category.
+	Breadcrumbs [][]CategoryRef `json:"breadcrumbs,omitempty"`
+	// Rating is only filled in by GET /products/{id}.
+	Rating *RatingSummary `json:"rating,omitempty"`
+	// Status is draft, published or archived; NextStatuses lists the ones
+	// it may move to. PublishAt and UnpublishAt are when the scheduler
+	// publishes or archives the product.
+	Status       string   `json:"status"`
+	NextStatuses []string `json:"next_statuses"`
+	PublishAt    string   `json:"publish_at,omitempty"`
+	UnpublishAt  string   `json:"unpublish_at,omitempty"`
+	Version      int64    `json:"version"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+	// DeletedAt is only set on products in the trash.
+	DeletedAt string `json:"deleted_at,omitempty"`
+}
+
+type ProductTransitionRequest struct {
+	Status string `json:"status"`
+}
+
+// ProductScheduleRequest replaces a product's schedule; an omitted time is
+// unscheduled.
+type ProductScheduleRequest struct {
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+}
+
+// ProductRevisionResponse is one entry of a product's history. Product
+// holds the product's editable fields after the change.
+type ProductRevisionResponse struct {
+	Revision  int64                `json:"revision"`
+	Action    string               `json:"action"`
+	Version   int64                `json:"version"`
+	ActorID   string               `json:"actor_id,omitempty"`
+	RevertOf  *int64               `json:"revert_of,omitempty"`
+	Changes   []FieldChange        `json:"changes"`
+	Product   UpdateProductRequest `json:"product"`
+	CreatedAt string               `json:"created_at"`
+}
+
+// FieldChange is one field a revision changed, with its values before
+// and after; Old is null on a product's first revision.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// ProductListResponse is the cursor-paginated listing envelope. Total is
+// only present when requested with include_total=true.
+type ProductListResponse struct {
+	Data       []ProductResponse `json:"data"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
+	Total      *int64            `json:"total,omitempty"`
+}
+
+// SearchHighlights are HTML: the product text escaped, with the matching
+// words wrapped in <mark>.
+type SearchHighlights struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ProductSearchResult struct {
+	Product    ProductResponse  `json:"product"`
+	Rank       float64          `json:"rank"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type CategoryFacet struct {
+	CategoryRef
+	Count int64 `json:"count"`
+}
+
+// PriceBucketFacet counts prices in [min, max); min is absent on the first
+// bucket and max on the last.
+type PriceBucketFacet struct {
+	Min   *entity.Money `json:"min,omitempty"`
+	Max   *entity.Money `json:"max,omitempty"`
+	Count int64         `json:"count"`
+}
+
+// ProductFacetsResponse counts the products matching the current filters.
+// Price buckets only count products priced in Currency.
+type ProductFacetsResponse struct {
+	Total        int64              `json:"total"`
+	Tags         []TagFacet         `json:"tags"`
+	Categories   []CategoryFacet    `json:"categories"`
+	Currency     string             `json:"currency"`
+	PriceBuckets []PriceBucketFacet `json:"price_buckets"`
+}
+
+// ProductPricesResponse lists a product's base price and its explicit
+// prices in other currencies.
+type ProductPricesResponse struct {
+	ProductID string         `json:"product_id"`
+	Base      entity.Money   `json:"base"`
+	Prices    []entity.Money `json:"prices"`
+}
+
+// SetProductPriceRequest is the body of PUT /products/{id}/prices/{currency};
+// the amount is a decimal string in that currency.
+type SetProductPriceRequest struct {
+	Amount string `json:"amount"`
+}
+
+// ExchangeRatesRequest maps ISO 4217 codes to how many units one unit of
+// Base buys, as decimal strings.
+type ExchangeRatesRequest struct {
+	Rates map[string]string `json:"rates"`
+}
+
+type ExchangeRatesResponse struct {
+	Base      string            `json:"base"`
+	Rates     map[string]string `json:"rates"`
+	UpdatedAt string            `json:"updated_at,omitempty"`
+}
+
+// Category DTOs
+type CreateCategoryRequest struct {
+	Name string `json:"name"`
+	// Slug is derived from Name when empty.
+	Slug     string  `json:"slug,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"`
+	Position int     `json:"position"`
+}
+
+type CategoryRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategoryResponse struct {
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"`
+	Name     string  `json:"name"`
+	Slug     string  `json:"slug"`
+	Position int     `json:"position"`
+	// Path runs from the root down to this category.
+	Path      []CategoryRef `json:"path"`
+	CreatedAt string        `json:"created_at"`
+	UpdatedAt string        `json:"updated_at"`
+}
+
+type CategoryTreeNode struct {
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	Slug     string             `json:"slug"`
+	Position int                `json:"position"`
+	Children []CategoryTreeNode `json:"children"`
+}
+
+type SetProductCategoriesRequest struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+type ProductCategoriesResponse struct {
+	ProductID   string          `json:"product_id"`
+	Breadcrumbs [][]CategoryRef `json:"breadcrumbs"`
+}
+
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// SetProductOptionsRequest replaces a product's options. Variants for new
+// value combinations get SKUs like SKU_PREFIX-RED-M; SKUPrefix defaults to
+// the product name.
+type SetProductOptionsRequest struct {
+	Options   []ProductOption `json:"options"`
+	SKUPrefix string          `json:"sku_prefix,omitempty"`
+}
+
+// VariantRequest creates or replaces a variant. Options give one value for
+// every product option; without Price the variant sells at the product's.
+type VariantRequest struct {
+	SKU     string            `json:"sku"`
+	Barcode string            `json:"barcode,omitempty"`
+	Options map[string]string `json:"options"`
+	Price   *entity.Money     `json:"price,omitempty"`
+}
+
+// VariantResponse carries the price the variant sells at; PriceOverride
+// is set when that is the variant's own price rather than the product's.
+type VariantResponse struct {
+	ID            string            `json:"id"`
+	ProductID     string            `json:"product_id"`
+	SKU           string            `json:"sku"`
+	Barcode       string            `json:"barcode,omitempty"`
+	Options       map[string]string `json:"options"`
+	Price         entity.Money      `json:"price"`
+	PriceOverride bool              `json:"price_override"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
+}
+
+type ProductVariantsResponse struct {
+	ProductID string            `json:"product_id"`
+	Options   []ProductOption   `json:"options"`
+	Variants  []VariantResponse `json:"variants"`
+}
+
+// SKUResponse is a variant with the product it belongs to.
+type SKUResponse struct {
+	VariantResponse
+	Product ProductResponse `json:"product"`
+}
+
+type WarehouseRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type WarehouseResponse struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// WarehouseStock counts units in one warehouse. Reserved units are on hand
+// but held for pending checkouts; Available is what can still be reserved.
+type WarehouseStock struct {
+	Warehouse string `json:"warehouse"`
+	OnHand    int64  `json:"on_hand"`
+	Reserved  int64  `json:"reserved"`
+	Available int64  `json:"available"`
+}
+
+// StockResponse totals a variant's stock over every warehouse.
+type StockResponse struct {
+	SKU        string           `json:"sku"`
+	VariantID  string           `json:"variant_id"`
+	OnHand     int64            `json:"on_hand"`
+	Reserved   int64            `json:"reserved"`
+	Available  int64            `json:"available"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+}
+
+// StockAdjustmentRequest adds Delta units (negative to remove) to the
+// stock in Warehouse, given by code.
+type StockAdjustmentRequest struct {
+	Warehouse string `json:"warehouse"`
+	Delta     int64  `json:"delta"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type StockMovementResponse struct {
+	ID            string `json:"id"`
+	Warehouse     string `json:"warehouse"`
+	Kind          string `json:"kind"`
+	OnHandDelta   int64  `json:"on_hand_delta"`
+	ReservedDelta int64  `json:"reserved_delta"`
+	OnHand        int64  `json:"on_hand"`
+	Reserved      int64  `json:"reserved"`
+	ReservationID string `json:"reservation_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// ReservationRequest holds Quantity units of a SKU. Without Warehouse the
+// one with the most units available is used; without TTLSeconds the
+// server default applies.
+type ReservationRequest struct {
+	SKU        string `json:"sku"`
+	Quantity   int64  `json:"quantity"`
+	Warehouse  string `json:"warehouse,omitempty"`
+	TTLSeconds int64  `json:"ttl_seconds,omitempty"`
+	Reference  string `json:"reference,omitempty"`
+}
+
+type ReservationResponse struct {
+	ID        string `json:"id"`
+	SKU       string `json:"sku"`
+	Warehouse string `json:"warehouse"`
+	Quantity  int64  `json:"quantity"`
+	// Status is active, committed, released or expired.
+	Status    string `json:"status"`
+	ExpiresAt string `json:"expires_at"`
+	Reference string `json:"reference,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AddCartItemRequest names what to add either by SKU or by product ID,
+// with VariantID required when the product has variants.
+type AddCartItemRequest struct {
+	SKU       string `json:"sku,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity"`
+}
+
+// UpdateCartItemRequest sets a line's quantity; zero removes it.
+type UpdateCartItemRequest struct {
+	Quantity int64 `json:"quantity"`
+}
+
+// CartItemResponse carries the name, unit price and tax class as they were
+// when the line was added. Total is after discounts; it includes Tax only
+// when prices include tax.
+type CartItemResponse struct {
+	ID        string                    `json:"id"`
+	ProductID string                    `json:"product_id"`
+	VariantID string                    `json:"variant_id,omitempty"`
+	SKU       string                    `json:"sku,omitempty"`
+	Name      string                    `json:"name"`
+	UnitPrice entity.Money              `json:"unit_price"`
+	Quantity  int64                     `json:"quantity"`
+	Subtotal  entity.Money              `json:"subtotal"`
+	Discounts []AppliedDiscountResponse `json:"discounts"`
+	Discount  entity.Money              `json:"discount"`
+	TaxClass  string                    `json:"tax_class"`
+	TaxRate   string                    `json:"tax_rate"`
+	Tax       entity.Money              `json:"tax"`
+	Total     entity.Money              `json:"total"`
+}
+
+// CartResponse has no ID for a guest who has not added anything yet. When
+// the cart's coupon no longer applies, CouponError says why and the totals
+// leave it out. Tax is for TaxCountry and TaxRegion, from ?country= and
+// ?region= or the store default; Total is what would be charged, tax
+// included.
+type CartResponse struct {
+	ID               string                    `json:"id,omitempty"`
+	Items            []CartItemResponse        `json:"items"`
+	ItemCount        int64                     `json:"item_count"`
+	CouponCode       string                    `json:"coupon_code,omitempty"`
+	CouponError      string                    `json:"coupon_error,omitempty"`
+	Subtotal         entity.Money              `json:"subtotal"`
+	Discounts        []AppliedDiscountResponse `json:"discounts"`
+	Discount         entity.Money              `json:"discount"`
+	TaxCountry       string                    `json:"tax_country,omitempty"`
+	TaxRegion        string                    `json:"tax_region,omitempty"`
+	PricesIncludeTax bool                      `json:"prices_include_tax"`
+	Tax              entity.Money              `json:"tax"`
+	Total            entity.Money              `json:"total"`
+}
+
+type ApplyCouponRequest struct {
+	Code string `json:"code"`
+}
+
+// AppliedDiscountResponse is an amount a promotion or coupon took off a
+// line or, at the top level of a cart or order, off all of it. Source is
+// the coupon code or the promotion name.
+type AppliedDiscountResponse struct {
+	Kind     string       `json:"kind"`
+	SourceID string       `json:"source_id"`
+	Source   string       `json:"source"`
+	Amount   entity.Money `json:"amount"`
+}
+
+// CreateCouponRequest creates a "percent" coupon, with PercentOff, or a
+// "fixed" one, with AmountOff. Without ProductIDs and CategoryIDs it
+// applies to every item; limits of 0 mean unlimited.
+type CreateCouponRequest struct {
+	Code           string        `json:"code"`
+	Type           string        `json:"type"`
+	PercentOff     int64         `json:"percent_off,omitempty"`
+	AmountOff      *entity.Money `json:"amount_off,omitempty"`
+	MinTotal       *entity.Money `json:"min_total,omitempty"`
+	ProductIDs     []string      `json:"product_ids,omitempty"`
+	CategoryIDs    []string      `json:"category_ids,omitempty"`
+	MaxUses        int64         `json:"max_uses,omitempty"`
+	MaxUsesPerUser int64         `json:"max_uses_per_user,omitempty"`
+	StartsAt       *time.Time    `json:"starts_at,omitempty"`
+	EndsAt         *time.Time    `json:"ends_at,omitempty"`
+}
+
+type CouponResponse struct {
+	ID             string        `json:"id"`
+	Code           string        `json:"code"`
+	Type           string        `json:"type"`
+	PercentOff     int64         `json:"percent_off,omitempty"`
+	AmountOff      *entity.Money `json:"amount_off,omitempty"`
+	MinTotal       *entity.Money `json:"min_total,omitempty"`
+	ProductIDs     []string      `json:"product_ids"`
+	CategoryIDs    []string      `json:"category_ids"`
+	MaxUses        int64         `json:"max_uses"`
+	MaxUsesPerUser int64         `json:"max_uses_per_user"`
+	Uses           int64         `json:"uses"`
+	StartsAt       string        `json:"starts_at,omitempty"`
+	EndsAt         string        `json:"ends_at,omitempty"`
+	CreatedAt      string        `json:"created_at"`
+}
+
+// CreatePromotionRequest creates a "buy BuyQuantity, get GetQuantity"
+// promotion; the GetQuantity units get PercentOff percent off, or are free
+// when it is omitted.
+type CreatePromotionRequest struct {
+	Name        string     `json:"name"`
+	BuyQuantity int64      `json:"buy_quantity"`
+	GetQuantity int64      `json:"get_quantity"`
+	PercentOff  int64      `json:"percent_off,omitempty"`
+	ProductIDs  []string   `json:"product_ids,omitempty"`
+	CategoryIDs []string   `json:"category_ids,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+}
+
+type PromotionResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	BuyQuantity int64    `json:"buy_quantity"`
+	GetQuantity int64    `json:"get_quantity"`
+	PercentOff  int64    `json:"percent_off"`
+	ProductIDs  []string `json:"product_ids"`
+	CategoryIDs []string `json:"category_ids"`
+	StartsAt    string   `json:"starts_at,omitempty"`
+	EndsAt      string   `json:"ends_at,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// OrderItemRequest names an item the same way AddCartItemRequest does.
+type OrderItemRequest struct {
+	SKU       string `json:"sku,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity"`
+}
+
+// CreateOrderRequest places an order for Items or, when Items is empty,
+// for everything in the user's cart. CouponCode defaults to the cart's
+// coupon when ordering the cart. ShippingAddressID and BillingAddressID
+// pick addresses from the user's book and default to the book's defaults.
+// Country, Region and PostalCode, when Country is set, override the
+// shipping address as where the order is taxed and shipped; with neither,
+// it is taxed at the store's tax location. ShippingMethodID picks one of
+// the options quoted for that destination; without it nothing is charged
+// for shipping.
+type CreateOrderRequest struct {
+	Items             []OrderItemRequest `json:"items,omitempty"`
+	CouponCode        string             `json:"coupon_code,omitempty"`
+	ShippingAddressID string             `json:"shipping_address_id,omitempty"`
+	BillingAddressID  string             `json:"billing_address_id,omitempty"`
+	Country           string             `json:"country,omitempty"`
+	Region            string             `json:"region,omitempty"`
+	PostalCode        string             `json:"postal_code,omitempty"`
+	ShippingMethodID  string             `json:"shipping_method_id,omitempty"`
+}
+
+type OrderTransitionRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+// OrderItemResponse carries the name, unit price and tax as they were when
+// the order was placed. Total includes Tax only when prices include tax.
+type OrderItemResponse struct {
+	ID        string                    `json:"id"`
+	ProductID string                    `json:"product_id"`
+	VariantID string                    `json:"variant_id,omitempty"`
+	SKU       string                    `json:"sku,omitempty"`
+	Name      string                    `json:"name"`
+	UnitPrice entity.Money              `json:"unit_price"`
+	Quantity  int64                     `json:"quantity"`
+	Subtotal  entity.Money              `json:"subtotal"`
+	Discounts []AppliedDiscountResponse `json:"discounts"`
+	Discount  entity.Money              `json:"discount"`
+	TaxClass  string                    `json:"tax_class"`
+	TaxRate   string                    `json:"tax_rate"`
+	Tax       entity.Money              `json:"tax"`
+	Total     entity.Money              `json:"total"`
+}
+
+type OrderTransitionResponse struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ActorID   string `json:"actor_id,omitempty"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// OrderResponse lists the statuses the order may move to next in
+// NextStatuses. Transitions is only filled in for a single order.
+type OrderResponse struct {
+	ID           string                    `json:"id"`
+	UserID       string                    `json:"user_id"`
+	Status       string                    `json:"status"`
+	NextStatuses []string                  `json:"next_statuses"`
+	Items        []OrderItemResponse       `json:"items"`
+	CouponCode   string                    `json:"coupon_code,omitempty"`
+	Subtotal     entity.Money              `json:"subtotal"`
+	Discounts    []AppliedDiscountResponse `json:"discounts"`
+	Discount     entity.Money              `json:"discount"`
+	TaxCountry   string                    `json:"tax_country,omitempty"`
+	TaxRegion    string                    `json:"tax_region,omitempty"`
+	// PricesIncludeTax says Tax is already in the item totals; otherwise
+	// it was added to Total.
+	PricesIncludeTax bool                      `json:"prices_include_tax"`
+	Tax              entity.Money              `json:"tax"`
+	ShippingMethodID string                    `json:"shipping_method_id,omitempty"`
+	ShippingMethod   string                    `json:"shipping_method,omitempty"`
+	Shipping         entity.Money              `json:"shipping"`
+	Total            entity.Money              `json:"total"`
+	ShippingAddress  *PostalAddress            `json:"shipping_address,omitempty"`
+	BillingAddress   *PostalAddress            `json:"billing_address,omitempty"`
+	Transitions      []OrderTransitionResponse `json:"transitions,omitempty"`
+	CreatedAt        string                    `json:"created_at"`
+	UpdatedAt        string                    `json:"updated_at"`
+}
+
+// TaxRateEntry is the percentage charged on TaxClass goods in Country or,
+// when Region is set, only in that region of it.
+type TaxRateEntry struct {
+	Country  string `json:"country"`
+	Region   string `json:"region,omitempty"`
+	TaxClass string `json:"tax_class"`
+	Rate     string `json:"rate"`
+}
+
+// TaxRatesRequest replaces the whole tax table.
+type TaxRatesRequest struct {
+	Rates []TaxRateEntry `json:"rates"`
+}
+
+// TaxRatesResponse lists the tax table with the store-wide settings, which
+// come from the server configuration.
+type TaxRatesResponse struct {
+	PricesIncludeTax bool           `json:"prices_include_tax"`
+	Rounding         string         `json:"rounding"`
+	DefaultCountry   string         `json:"default_country,omitempty"`
+	DefaultRegion    string         `json:"default_region,omitempty"`
+	Rates            []TaxRateEntry `json:"rates"`
+	UpdatedAt        string         `json:"updated_at,omitempty"`
+}
+
+// TaxQuoteRequest asks for the tax on Items at their current catalog
+// prices, before discounts, in Country and Region (by default the store's
+// tax location).
+type TaxQuoteRequest struct {
+	Items   []OrderItemRequest `json:"items"`
+	Country string             `json:"country,omitempty"`
+	Region  string             `json:"region,omitempty"`
+}
+
+// TaxQuoteLine is the tax on one item; Net + Tax = Gross, and Amount is
+// whichever of them the catalog price is.
+type TaxQuoteLine struct {
+	ProductID string       `json:"product_id"`
+	VariantID string       `json:"variant_id,omitempty"`
+	SKU       string       `json:"sku,omitempty"`
+	Name      string       `json:"name"`
+	Quantity  int64        `json:"quantity"`
+	Amount    entity.Money `json:"amount"`
+	TaxClass  string       `json:"tax_class"`
+	Rate      string       `json:"rate"`
+	Net       entity.Money `json:"net"`
+	Tax       entity.Money `json:"tax"`
+	Gross     entity.Money `json:"gross"`
+}
+
+type TaxQuoteResponse struct {
+	Country          string         `json:"country,omitempty"`
+	Region           string         `json:"region,omitempty"`
+	PricesIncludeTax bool           `json:"prices_include_tax"`
+	Lines            []TaxQuoteLine `json:"lines"`
+	Net              entity.Money   `json:"net"`
+	Tax              entity.Money   `json:"tax"`
+	Gross            entity.Money   `json:"gross"`
+}
+
+// ShippingArea is a country, optionally narrowed to a region and to postal
+// codes starting with PostalPrefix.
+type ShippingArea struct {
+	Country      string `json:"country"`
+	Region       string `json:"region,omitempty"`
+	PostalPrefix string `json:"postal_prefix,omitempty"`
+}
+
+type CreateShippingZoneRequest struct {
+	Name  string         `json:"name"`
+	Areas []ShippingArea `json:"areas"`
+}
+
+type WeightTier struct {
+	UpToGrams int64        `json:"up_to_grams"`
+	Cost      entity.Money `json:"cost"`
+}
+
+// CreateShippingMethodRequest adds a method to a zone. Kind "flat" charges
+// Cost, "weight" charges the first of Tiers the parcel fits in and
+// "free_over" is free for goods costing Threshold or more.
+// VolumetricDivisor, in mm³ per gram, makes weight methods charge bulky
+// parcels by size.
+type CreateShippingMethodRequest struct {
+	Name              string        `json:"name"`
+	Kind              string        `json:"kind"`
+	Cost              *entity.Money `json:"cost,omitempty"`
+	Tiers             []WeightTier  `json:"tiers,omitempty"`
+	Threshold         *entity.Money `json:"threshold,omitempty"`
+	VolumetricDivisor int64         `json:"volumetric_divisor,omitempty"`
+}
+
+type ShippingMethodResponse struct {
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	Kind              string        `json:"kind"`
+	Cost              *entity.Money `json:"cost,omitempty"`
+	Tiers             []WeightTier  `json:"tiers,omitempty"`
+	Threshold         *entity.Money `json:"threshold,omitempty"`
+	VolumetricDivisor int64         `json:"volumetric_divisor,omitempty"`
+}
+
+type ShippingZoneResponse struct {
+	ID        string                   `json:"id"`
+	Name      string                   `json:"name"`
+	Areas     []ShippingArea           `json:"areas"`
+	Methods   []ShippingMethodResponse `json:"methods"`
+	CreatedAt string                   `json:"created_at"`
+}
+
+type ShippingRateResponse struct {
+	MethodID string       `json:"method_id"`
+	Name     string       `json:"name"`
+	Kind     string       `json:"kind"`
+	ZoneID   string       `json:"zone_id"`
+	ZoneName string       `json:"zone_name"`
+	Cost     entity.Money `json:"cost"`
+}
+
+// ShippingQuoteResponse lists the ways to ship the cart to the
+// destination, cheapest first. Subtotal is what the goods cost after
+// discounts, which free shipping thresholds are compared with.
+type ShippingQuoteResponse struct {
+	Country     string                 `json:"country"`
+	Region      string                 `json:"region,omitempty"`
+	PostalCode  string                 `json:"postal_code,omitempty"`
+	WeightGrams int64                  `json:"weight_grams"`
+	Subtotal    entity.Money           `json:"subtotal"`
+	Rates       []ShippingRateResponse `json:"rates"`
+}
+
+// CreatePaymentRequest pays an order with a payment method token issued
+// by the gateway. The payment is captured right away unless ManualCapture
+// is set, in which case an admin captures it later.
+type CreatePaymentRequest struct {
+	PaymentMethod string `json:"payment_method"`
+	ManualCapture bool   `json:"manual_capture,omitempty"`
+}
+
+// RefundPaymentRequest refunds Amount, or everything not refunded yet when
+// it is omitted.
+type RefundPaymentRequest struct {
+	Amount *entity.Money `json:"amount,omitempty"`
+}
+
+type PaymentResponse struct {
+	ID          string       `json:"id"`
+	OrderID     string       `json:"order_id"`
+	Provider    string       `json:"provider"`
+	Reference   string       `json:"reference,omitempty"`
+	Status      string       `json:"status"`
+	Amount      entity.Money `json:"amount"`
+	Refunded    entity.Money `json:"refunded"`
+	FailureCode string       `json:"failure_code,omitempty"`
+	CreatedAt   string       `json:"created_at"`
+	UpdatedAt   string       `json:"updated_at"`
 }
 
 // User DTOs
+// PostalAddress is a place to deliver to or bill. PostalCode is checked
+// against Country's format and returned in its usual layout.
+type PostalAddress struct {
+	Recipient  string `json:"recipient"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// AddressRequest creates or replaces an address book entry. Setting
+// DefaultShipping or DefaultBilling moves that default to this address;
+// on PUT, leaving them out keeps the current flags.
+type AddressRequest struct {
+	Label string `json:"label,omitempty"`
+	PostalAddress
+	DefaultShipping *bool `json:"default_shipping,omitempty"`
+	DefaultBilling  *bool `json:"default_billing,omitempty"`
+}
+
+type AddressResponse struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	PostalAddress
+	DefaultShipping bool   `json:"default_shipping"`
+	DefaultBilling  bool   `json:"default_billing"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// ReviewRequest writes or rewrites a review; either sends it to the
+// moderation queue.
+type ReviewRequest struct {
+	Rating int    `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body,omitempty"`
+}
+
+// ModerateReviewRequest approves or rejects a review. Note is shown to the
+// review's author.
+type ModerateReviewRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+type ReviewResponse struct {
+	ID               string `json:"id"`
+	ProductID        string `json:"product_id"`
+	UserID           string `json:"user_id"`
+	Rating           int    `json:"rating"`
+	Title            string `json:"title"`
+	Body             string `json:"body,omitempty"`
+	VerifiedPurchase bool   `json:"verified_purchase"`
+	Status           string `json:"status"`
+	ModerationNote   string `json:"moderation_note,omitempty"`
+	ModeratedAt      string `json:"moderated_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// RatingSummary aggregates a product's approved reviews. Distribution maps
+// each star count, "1" to "5", to how many reviews gave it.
+type RatingSummary struct {
+	Average      float64          `json:"average"`
+	Count        int64            `json:"count"`
+	Distribution map[string]int64 `json:"distribution"`
+}
+
+type WishlistRequest struct {
+	Name string `json:"name"`
+}
+
+type AddWishlistItemRequest struct {
+	ProductID string `json:"product_id"`
+}
+
+// WishlistItemResponse shows a wishlisted product at its current price next
+// to what it cost when it was added.
+type WishlistItemResponse struct {
+	ProductID    string       `json:"product_id"`
+	Name         string       `json:"name"`
+	Price        entity.Money `json:"price"`
+	AddedPrice   entity.Money `json:"added_price"`
+	PriceDropped bool         `json:"price_dropped"`
+	AddedAt      string       `json:"added_at"`
+}
+
+// WishlistResponse is a wishlist with its products. ShareToken is only
+// there while the list is shared; GET /wishlists/shared/{token} reads it.
+type WishlistResponse struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	ShareToken string                 `json:"share_token,omitempty"`
+	Items      []WishlistItemResponse `json:"items"`
+	CreatedAt  string                 `json:"created_at"`
+	UpdatedAt  string                 `json:"updated_at"`
+}
+
+type PriceDropResponse struct {
+	ProductID  string       `json:"product_id"`
+	OldPrice   entity.Money `json:"old_price"`
+	NewPrice   entity.Money `json:"new_price"`
+	DetectedAt string       `json:"detected_at"`
+}
+
+// CreateUserRequest signs up a user with the "user" role; only admins
+// assign other roles, with UpdateUserRoleRequest.
 type CreateUserRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
-	Role     string `json:"role"`
 }
 
+// UpdateUserRoleRequest is the body of PUT /users/{id}/role.
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// UpdateUserRequest changes the fields it sets, on PUT and as a JSON Merge
+// Patch on PATCH /users/{id}.
 type UpdateUserRequest struct {
 	Username string `json:"username,omitempty"`
 	Role     string `json:"role,omitempty"`
@@ -40,6 +862,7 @@ type UserResponse struct {
 	Username  string `json:"username"`
 	Email     string `json:"email"`
 	Role      string `json:"role"`
+	Version   int64  `json:"version"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -48,3 +871,7 @@ type GetJwtRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type GetJwtResponse struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// MaxAddresses caps how many addresses one user's address book holds.
+const MaxAddresses = 20
+
+var (
+	ErrAddressIncomplete = errors.New("recipient, line1, city and country are required")
+	ErrAddressTooLong    = errors.New("address field too long")
+	ErrInvalidPostalCode = errors.New("invalid postal code for the country")
+	ErrAddressBookFull   = fmt.Errorf("an address book holds at most %d addresses", MaxAddresses)
+)
+
+// postalFormat checks a postal code with spaces and dashes removed and
+// puts it back in the country's usual layout.
+type postalFormat struct {
+	pattern *regexp.Regexp
+	format  func(compact string) string
+}
+
+func splitAt(n int, sep string) func(string) string {
+	return func(c string) string { return c[:n] + sep + c[n:] }
+}
+
+func asIs(c string) string { return c }
+
+var fiveDigits = postalFormat{regexp.MustCompile(`^\d{5}$`), asIs}
+
+// postalFormats lists the countries whose postal codes are checked
+// strictly. Other countries accept any short code of letters and digits,
+// or none.
+var postalFormats = map[string]postalFormat{
+	"BR": {regexp.MustCompile(`^\d{8}$`), splitAt(5, "-")},
+	"PT": {regexp.MustCompile(`^\d{7}$`), splitAt(4, "-")},
+	"JP": {regexp.MustCompile(`^\d{7}$`), splitAt(3, "-")},
+	"CA": {regexp.MustCompile(`^[A-Z]\d[A-Z]\d[A-Z]\d$`), splitAt(3, " ")},
+	"NL": {regexp.MustCompile(`^\d{4}[A-Z]{2}$`), splitAt(4, " ")},
+	"AR": {regexp.MustCompile(`^(\d{4}|[A-Z]\d{4}[A-Z]{3})$`), asIs},
+	"US": {regexp.MustCompile(`^\d{5}(\d{4})?$`), func(c string) string {
+		if len(c) == 9 {
+			return c[:5] + "-" + c[5:]
+		}
+		return c
+	}},
+	"GB": {regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]?\d[A-Z]{2}$`), func(c string) string {
+		return c[:len(c)-3] + " " + c[len(c)-3:]
+	}},
+	"DE": fiveDigits,
+	"ES": fiveDigits,
+	"FR": fiveDigits,
+	"IT": fiveDigits,
+	"MX": fiveDigits,
+}
+
+var otherPostalCode = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+
+// FormatPostalCode validates code for country and returns it in the
+// country's usual layout, e.g. "01310-100" for BR or "SW1A 1AA" for GB.
+func FormatPostalCode(country, code string) (string, error) {
+	compact := normalizePostalCode(code)
+	format, ok := postalFormats[country]
+	if !ok {
+		if compact != "" && !otherPostalCode.MatchString(compact) {
+			return "", ErrInvalidPostalCode
+		}
+		return compact, nil
+	}
+	if !format.pattern.MatchString(compact) {
+		return "", ErrInvalidPostalCode
+	}
+	return format.format(compact), nil
+}
+
+// PostalAddress is a place to deliver to or bill. Address book entries
+// and the snapshots kept on orders share it.
+type PostalAddress struct {
+	Recipient  string `json:"recipient" gorm:"type:varchar(100);not null"`
+	Line1      string `json:"line1" gorm:"type:varchar(200);not null"`
+	Line2      string `json:"line2,omitempty" gorm:"type:varchar(200)"`
+	City       string `json:"city" gorm:"type:varchar(100);not null"`
+	Region     string `json:"region,omitempty" gorm:"type:varchar(10)"`
+	PostalCode string `json:"postal_code,omitempty" gorm:"type:varchar(20)"`
+	Country    string `json:"country" gorm:"type:varchar(2);not null"`
+	Phone      string `json:"phone,omitempty" gorm:"type:varchar(30)"`
+}
+
+// Normalize trims the fields, upper-cases the country and region and
+// formats the postal code, checking it against the country's format.
+func (p *PostalAddress) Normalize() error {
+	for _, field := range []struct {
+		value *string
+		max   int
+	}{
+		{&p.Recipient, 100}, {&p.Line1, 200}, {&p.Line2, 200}, {&p.City, 100}, {&p.Phone, 30},
+	} {
+		*field.value = strings.TrimSpace(*field.value)
+		if utf8.RuneCountInString(*field.value) > field.max {
+			return ErrAddressTooLong
+		}
+	}
+	if p.Recipient == "" || p.Line1 == "" || p.City == "" || strings.TrimSpace(p.Country) == "" {
+		return ErrAddressIncomplete
+	}
+	loc, err := NewTaxLocation(p.Country, p.Region)
+	if err != nil {
+		return err
+	}
+	p.Country, p.Region = loc.Country, loc.Region
+	if p.PostalCode, err = FormatPostalCode(p.Country, p.PostalCode); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Destination is where to ship to this address.
+func (p PostalAddress) Destination() ShippingDestination {
+	return ShippingDestination{Country: p.Country, Region: p.Region, PostalCode: normalizePostalCode(p.PostalCode)}
+}
+
+// Address is an entry in a user's address book. At most one address per
+// user is the default for shipping and one for billing; the repository
+// keeps it that way.
+type Address struct {
+	ID              entity.ID `json:"id"`
+	UserID          entity.ID `json:"user_id" gorm:"index;not null"`
+	Label           string    `json:"label,omitempty" gorm:"type:varchar(50)"`
+	PostalAddress   `gorm:"embedded"`
+	DefaultShipping bool      `json:"default_shipping" gorm:"not null;default:false"`
+	DefaultBilling  bool      `json:"default_billing" gorm:"not null;default:false"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func NewAddress(userID entity.ID, label string, postal PostalAddress) (*Address, error) {
+	address := &Address{
+		ID:            entity.NewID(),
+		UserID:        userID,
+		Label:         label,
+		PostalAddress: postal,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Validate normalizes the address and checks it.
+func (a *Address) Validate() error {
+	a.Label = strings.TrimSpace(a.Label)
+	if utf8.RuneCountInString(a.Label) > 50 {
+		return ErrAddressTooLong
+	}
+	return a.PostalAddress.Normalize()
+}
+
+// Snapshot copies the postal part of the address, for an order to keep
+// however the address book changes later.
+func (a *Address) Snapshot() *PostalAddress {
+	snapshot := a.PostalAddress
+	return &snapshot
+}
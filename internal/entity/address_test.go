@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPostalCode(t *testing.T) {
+	t.Run("should format codes the country's way", func(t *testing.T) {
+		for _, tc := range []struct{ country, in, want string }{
+			{"BR", "01310100", "01310-100"},
+			{"BR", " 01310-100 ", "01310-100"},
+			{"US", "94105", "94105"},
+			{"US", "941051234", "94105-1234"},
+			{"CA", "k1a0b1", "K1A 0B1"},
+			{"GB", "sw1a1aa", "SW1A 1AA"},
+			{"PT", "1000-001", "1000-001"},
+			{"DE", "10115", "10115"},
+			{"CL", "8320000", "8320000"},
+			{"CL", "", ""},
+		} {
+			got, err := FormatPostalCode(tc.country, tc.in)
+			require.NoError(t, err, tc)
+			assert.Equal(t, tc.want, got, tc)
+		}
+	})
+
+	t.Run("should reject codes that do not fit the country", func(t *testing.T) {
+		for _, tc := range []struct{ country, in string }{
+			{"BR", "1310-100"},
+			{"BR", ""},
+			{"US", "9410"},
+			{"CA", "123456"},
+			{"DE", "ABCDE"},
+			{"CL", "83-20.000"},
+		} {
+			_, err := FormatPostalCode(tc.country, tc.in)
+			assert.ErrorIs(t, err, ErrInvalidPostalCode, tc)
+		}
+	})
+}
+
+func TestNewAddress(t *testing.T) {
+	userID := entity.NewID()
+
+	t.Run("should normalize the address", func(t *testing.T) {
+		address, err := NewAddress(userID, " Casa ", PostalAddress{
+			Recipient: " Ana ", Line1: "Rua A, 1", City: "Rio de Janeiro", Region: "rj", PostalCode: "20000000", Country: "br",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Casa", address.Label)
+		assert.Equal(t, "Ana", address.Recipient)
+		assert.Equal(t, "RJ", address.Region)
+		assert.Equal(t, "BR", address.Country)
+		assert.Equal(t, "20000-000", address.PostalCode)
+		assert.Equal(t, ShippingDestination{Country: "BR", Region: "RJ", PostalCode: "20000000"}, address.Destination())
+	})
+
+	t.Run("should reject incomplete or malformed addresses", func(t *testing.T) {
+		_, err := NewAddress(userID, "", PostalAddress{Line1: "Rua A, 1", City: "Rio", Country: "BR", PostalCode: "20000000"})
+		assert.ErrorIs(t, err, ErrAddressIncomplete)
+		_, err = NewAddress(userID, "", PostalAddress{Recipient: "Ana", Line1: "Rua A, 1", City: "Rio", Country: "Brasil"})
+		assert.ErrorIs(t, err, ErrInvalidCountry)
+		_, err = NewAddress(userID, "", PostalAddress{Recipient: "Ana", Line1: "Rua A, 1", City: "Rio", Country: "BR", PostalCode: "2000"})
+		assert.ErrorIs(t, err, ErrInvalidPostalCode)
+		_, err = NewAddress(userID, strings.Repeat("x", 51), PostalAddress{Recipient: "Ana", Line1: "Rua A, 1", City: "Rio", Country: "CL"})
+		assert.ErrorIs(t, err, ErrAddressTooLong)
+	})
+
+	t.Run("should snapshot a copy", func(t *testing.T) {
+		address, err := NewAddress(userID, "", PostalAddress{Recipient: "Ana", Line1: "Rua A, 1", City: "Santiago", Country: "CL"})
+		require.NoError(t, err)
+		snapshot := address.Snapshot()
+		address.City = "Valparaíso"
+		assert.Equal(t, "Santiago", snapshot.City)
+	})
+}
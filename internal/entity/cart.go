@@ -0,0 +1,254 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+const (
+	// MaxItemQuantity caps the quantity of one cart line.
+	MaxItemQuantity = 99
+	// MaxCartLines caps how many distinct lines a cart holds.
+	MaxCartLines = 50
+)
+
+var (
+	ErrQuantityLimit    = fmt.Errorf("quantity must be between 1 and %d", MaxItemQuantity)
+	ErrCartFull         = fmt.Errorf("a cart holds at most %d lines", MaxCartLines)
+	ErrCartCurrency     = errors.New("every item in a cart must be priced in the same currency")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrVariantRequired  = errors.New("product has variants; choose one")
+)
+
+// Cart belongs either to a user or, before login, to a guest identified
+// by GuestToken. Items live in the cart_items table; the repository loads
+// and saves them. CouponCode is the coupon the shopper entered; it is
+// checked again each time the cart is priced.
+type Cart struct {
+	ID         entity.ID   `json:"id"`
+	UserID     *entity.ID  `json:"user_id,omitempty" gorm:"uniqueIndex"`
+	GuestToken *string     `json:"-" gorm:"uniqueIndex;type:varchar(64)"`
+	CouponCode string      `json:"coupon_code,omitempty" gorm:"type:varchar(50)"`
+	Items      []*CartItem `json:"items" gorm:"-"`
+	// Version goes up by one on every save, so two requests that read the
+	// same cart cannot both write it back.
+	Version   int64     `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewUserCart(userID entity.ID) *Cart {
+	return &Cart{
+		ID:        entity.NewID(),
+		UserID:    &userID,
+		Items:     []*CartItem{},
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// NewGuestCart creates an anonymous cart with a random token.
+func NewGuestCart() (*Cart, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return &Cart{
+		ID:         entity.NewID(),
+		GuestToken: &token,
+		Items:      []*CartItem{},
+		Version:    1,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// CartItem is one line of a cart. Name, SKU, UnitPrice and TaxClass are
+// snapshots taken when the line was added, so later catalog changes do not
+// alter the cart silently.
+type CartItem struct {
+	ID     entity.ID `json:"id"`
+	CartID entity.ID `json:"-" gorm:"not null;uniqueIndex:idx_cart_line"`
+	// LineKey identifies what the line sells so adding it again increases
+	// the quantity instead of adding a line.
+	LineKey   string       `json:"-" gorm:"not null;uniqueIndex:idx_cart_line"`
+	ProductID entity.ID    `json:"product_id" gorm:"index;not null"`
+	VariantID *entity.ID   `json:"variant_id,omitempty"`
+	SKU       string       `json:"sku,omitempty"`
+	Name      string       `json:"name" gorm:"not null"`
+	UnitPrice entity.Money `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"`
+	TaxClass  string       `json:"tax_class" gorm:"type:varchar(30);not null;default:'standard'"`
+	Quantity  int64        `json:"quantity" gorm:"not null"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// NewCartItem snapshots a product, or one of its variants, at its current
+// price. variant is nil for products sold without variants.
+func NewCartItem(product *Product, variant *ProductVariant, quantity int64) (*CartItem, error) {
+	if quantity < 1 || quantity > MaxItemQuantity {
+		return nil, ErrQuantityLimit
+	}
+	item := &CartItem{
+		ID:        entity.NewID(),
+		LineKey:   product.ID.String(),
+		ProductID: product.ID,
+		Name:      product.Name,
+		UnitPrice: product.Price,
+		TaxClass:  product.TaxClass,
+		Quantity:  quantity,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if variant != nil {
+		id := variant.ID
+		item.VariantID = &id
+		item.LineKey += ":" + variant.ID.String()
+		item.SKU = variant.SKU
+		item.UnitPrice = variant.EffectivePrice(product)
+	}
+	return item, nil
+}
+
+// Total is the unit price times the quantity.
+func (i *CartItem) Total() entity.Money {
+	return i.UnitPrice.Mul(i.Quantity)
+}
+
+// Add puts item in the cart, or adds its quantity to the line already
+// selling the same thing, keeping that line's price snapshot.
+func (c *Cart) Add(item *CartItem) error {
+	if existing := c.line(item.LineKey); existing != nil {
+		quantity := existing.Quantity + item.Quantity
+		if quantity > MaxItemQuantity {
+			return ErrQuantityLimit
+		}
+		existing.Quantity = quantity
+		existing.UpdatedAt = time.Now()
+		return nil
+	}
+	if len(c.Items) >= MaxCartLines {
+		return ErrCartFull
+	}
+	if len(c.Items) > 0 && c.Items[0].UnitPrice.Currency != item.UnitPrice.Currency {
+		return ErrCartCurrency
+	}
+	item.CartID = c.ID
+	c.Items = append(c.Items, item)
+	return nil
+}
+
+// SetQuantity changes a line's quantity; zero removes the line.
+func (c *Cart) SetQuantity(itemID entity.ID, quantity int64) error {
+	if quantity == 0 {
+		return c.Remove(itemID)
+	}
+	if quantity < 0 || quantity > MaxItemQuantity {
+		return ErrQuantityLimit
+	}
+	for _, item := range c.Items {
+		if item.ID == itemID {
+			item.Quantity = quantity
+			item.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return ErrCartItemNotFound
+}
+
+func (c *Cart) Remove(itemID entity.ID) error {
+	for i, item := range c.Items {
+		if item.ID == itemID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCartItemNotFound
+}
+
+// Merge moves the lines of a guest cart into c. Lines c already has get
+// the quantities added, capped at MaxItemQuantity; lines that do not fit
+// (a full cart or another currency) are dropped. The guest's coupon is
+// kept if c has none.
+func (c *Cart) Merge(guest *Cart) {
+	if c.CouponCode == "" {
+		c.CouponCode = guest.CouponCode
+	}
+	for _, item := range guest.Items {
+		if existing := c.line(item.LineKey); existing != nil {
+			existing.Quantity = min(existing.Quantity+item.Quantity, MaxItemQuantity)
+			existing.UpdatedAt = time.Now()
+			continue
+		}
+		moved := *item
+		moved.ID = entity.NewID()
+		_ = c.Add(&moved)
+	}
+}
+
+// Subtotal sums the line totals, in the cart's currency. An empty cart
+// totals zero in DefaultCurrency.
+func (c *Cart) Subtotal() entity.Money {
+	if len(c.Items) == 0 {
+		return entity.Money{Currency: entity.DefaultCurrency}
+	}
+	total := entity.Money{Currency: c.Items[0].UnitPrice.Currency}
+	for _, item := range c.Items {
+		total.Amount += item.Total().Amount
+	}
+	return total
+}
+
+// DiscountLines describes the lines to ApplyDiscounts, without their
+// categories.
+func (c *Cart) DiscountLines() []DiscountLine {
+	lines := make([]DiscountLine, 0, len(c.Items))
+	for _, item := range c.Items {
+		lines = append(lines, DiscountLine{
+			ID:        item.ID.String(),
+			ProductID: item.ProductID.String(),
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+		})
+	}
+	return lines
+}
+
+// TaxLines describes the lines to TaxTable.Calculate at what they cost
+// after discounts; discounts is the breakdown of DiscountLines.
+func (c *Cart) TaxLines(discounts *DiscountBreakdown) []TaxLine {
+	lines := make([]TaxLine, 0, len(c.Items))
+	for _, item := range c.Items {
+		amount := item.Total()
+		if line := discounts.Line(item.ID.String()); line != nil {
+			amount = line.Total
+		}
+		lines = append(lines, TaxLine{ID: item.ID.String(), TaxClass: item.TaxClass, Amount: amount})
+	}
+	return lines
+}
+
+// ItemCount is the number of units in the cart.
+func (c *Cart) ItemCount() int64 {
+	var count int64
+	for _, item := range c.Items {
+		count += item.Quantity
+	}
+	return count
+}
+
+func (c *Cart) line(key string) *CartItem {
+	for _, item := range c.Items {
+		if item.LineKey == key {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCart(t *testing.T) {
+	product, options := newShirt(t)
+	red, err := NewProductVariant(product, options, map[string]string{"color": "Red", "size": "M"}, "TEE-RED-M", "", nil)
+	require.NoError(t, err)
+	book, err := NewProduct("Book", "", entity.MustParseMoney("35.50", "BRL"))
+	require.NoError(t, err)
+
+	cart := NewUserCart(entity.NewID())
+
+	t.Run("should merge lines for the same thing", func(t *testing.T) {
+		item, err := NewCartItem(product, red, 2)
+		require.NoError(t, err)
+		require.NoError(t, cart.Add(item))
+		item, err = NewCartItem(product, red, 1)
+		require.NoError(t, err)
+		require.NoError(t, cart.Add(item))
+		item, err = NewCartItem(book, nil, 1)
+		require.NoError(t, err)
+		require.NoError(t, cart.Add(item))
+
+		require.Len(t, cart.Items, 2)
+		assert.Equal(t, int64(3), cart.Items[0].Quantity)
+		assert.Equal(t, "TEE-RED-M", cart.Items[0].SKU)
+		assert.Equal(t, int64(4), cart.ItemCount())
+		assert.Equal(t, entity.MustParseMoney("185.20", "BRL"), cart.Subtotal())
+	})
+
+	t.Run("should enforce quantity limits", func(t *testing.T) {
+		_, err := NewCartItem(book, nil, 0)
+		assert.Equal(t, ErrQuantityLimit, err)
+		assert.Equal(t, ErrQuantityLimit, cart.SetQuantity(cart.Items[1].ID, MaxItemQuantity+1))
+
+		item, err := NewCartItem(book, nil, MaxItemQuantity)
+		require.NoError(t, err)
+		assert.Equal(t, ErrQuantityLimit, cart.Add(item))
+	})
+
+	t.Run("should refuse another currency", func(t *testing.T) {
+		imported, err := NewProduct("Import", "", entity.MustParseMoney("10", "USD"))
+		require.NoError(t, err)
+		item, err := NewCartItem(imported, nil, 1)
+		require.NoError(t, err)
+		assert.Equal(t, ErrCartCurrency, cart.Add(item))
+	})
+
+	t.Run("should remove a line with quantity zero", func(t *testing.T) {
+		require.NoError(t, cart.SetQuantity(cart.Items[1].ID, 0))
+		assert.Len(t, cart.Items, 1)
+		assert.Equal(t, ErrCartItemNotFound, cart.Remove(entity.NewID()))
+	})
+
+	t.Run("should merge a guest cart capping quantities", func(t *testing.T) {
+		guest, err := NewGuestCart()
+		require.NoError(t, err)
+		require.NotNil(t, guest.GuestToken)
+
+		item, err := NewCartItem(product, red, MaxItemQuantity)
+		require.NoError(t, err)
+		require.NoError(t, guest.Add(item))
+		item, err = NewCartItem(book, nil, 2)
+		require.NoError(t, err)
+		require.NoError(t, guest.Add(item))
+
+		cart.Merge(guest)
+		require.Len(t, cart.Items, 2)
+		assert.Equal(t, int64(MaxItemQuantity), cart.Items[0].Quantity)
+		assert.Equal(t, cart.ID, cart.Items[1].CartID)
+		assert.NotEqual(t, guest.Items[1].ID, cart.Items[1].ID)
+	})
+}
@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrCategoryNameIsRequired = errors.New("category name is required")
+	ErrInvalidSlug            = errors.New("slug must be lowercase letters, digits and single dashes")
+	ErrCategoryOwnParent      = errors.New("a category cannot be its own parent")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// Category is a node in the product classification tree. Siblings are
+// ordered by Position, then Name.
+type Category struct {
+	ID        entity.ID  `json:"id"`
+	ParentID  *entity.ID `json:"parent_id" gorm:"index"`
+	Name      string     `json:"name" gorm:"not null"`
+	Slug      string     `json:"slug" gorm:"uniqueIndex;not null"`
+	Position  int        `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ProductCategory assigns a product to a category.
+type ProductCategory struct {
+	ProductID  entity.ID `gorm:"primaryKey"`
+	CategoryID entity.ID `gorm:"primaryKey;index"`
+}
+
+// NewCategory builds a category, deriving the slug from the name when
+// slug is empty.
+func NewCategory(name, slug string, parentID *entity.ID, position int) (*Category, error) {
+	if strings.TrimSpace(slug) == "" {
+		slug = Slugify(name)
+	}
+	category := &Category{
+		ID:        entity.NewID(),
+		ParentID:  parentID,
+		Name:      strings.TrimSpace(name),
+		Slug:      slug,
+		Position:  position,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (c *Category) Validate() error {
+	if c.Name == "" {
+		return ErrCategoryNameIsRequired
+	}
+	if !slugPattern.MatchString(c.Slug) {
+		return ErrInvalidSlug
+	}
+	if c.ParentID != nil && *c.ParentID == c.ID {
+		return ErrCategoryOwnParent
+	}
+	return nil
+}
+
+// Slugify lowercases s, strips accents and joins words with dashes:
+// "Eletrônicos & Games" becomes "eletronicos-games".
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining accent left over from NFD
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// CategoryTree indexes a full set of categories to answer path and child
+// queries without going back to the database.
+type CategoryTree struct {
+	byID     map[entity.ID]*Category
+	children map[entity.ID][]*Category
+	roots    []*Category
+}
+
+// NewCategoryTree expects categories already sorted by Position and Name;
+// that order is kept among siblings.
+func NewCategoryTree(categories []*Category) *CategoryTree {
+	tree := &CategoryTree{
+		byID:     make(map[entity.ID]*Category, len(categories)),
+		children: map[entity.ID][]*Category{},
+	}
+	for _, c := range categories {
+		tree.byID[c.ID] = c
+	}
+	for _, c := range categories {
+		if c.ParentID == nil || tree.byID[*c.ParentID] == nil {
+			tree.roots = append(tree.roots, c)
+			continue
+		}
+		tree.children[*c.ParentID] = append(tree.children[*c.ParentID], c)
+	}
+	return tree
+}
+
+func (t *CategoryTree) Get(id entity.ID) *Category {
+	return t.byID[id]
+}
+
+func (t *CategoryTree) Roots() []*Category {
+	return t.roots
+}
+
+func (t *CategoryTree) Children(id entity.ID) []*Category {
+	return t.children[id]
+}
+
+// Path returns the breadcrumb from the root down to id, or nil when id is
+// unknown.
+func (t *CategoryTree) Path(id entity.ID) []*Category {
+	var path []*Category
+	seen := map[entity.ID]bool{}
+	for c := t.byID[id]; c != nil && !seen[c.ID]; {
+		seen[c.ID] = true
+		path = append([]*Category{c}, path...)
+		if c.ParentID == nil {
+			break
+		}
+		c = t.byID[*c.ParentID]
+	}
+	return path
+}
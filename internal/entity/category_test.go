@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCategory(t *testing.T) {
+	category, err := NewCategory("Eletrônicos & Games", "", nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "eletronicos-games", category.Slug)
+	assert.Equal(t, 2, category.Position)
+	assert.Nil(t, category.ParentID)
+
+	_, err = NewCategory("  ", "", nil, 0)
+	assert.Equal(t, ErrCategoryNameIsRequired, err)
+
+	_, err = NewCategory("Books", "Not A Slug", nil, 0)
+	assert.Equal(t, ErrInvalidSlug, err)
+}
+
+func TestCategoryOwnParent(t *testing.T) {
+	category, err := NewCategory("Books", "books", nil, 0)
+	require.NoError(t, err)
+
+	id := category.ID
+	category.ParentID = &id
+	assert.Equal(t, ErrCategoryOwnParent, category.Validate())
+}
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Cama, Mesa e Banho": "cama-mesa-e-banho",
+		"  USB-C  cables ":   "usb-c-cables",
+		"Ação":               "acao",
+		"日本":                 "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, Slugify(in), in)
+	}
+}
@@ -0,0 +1,291 @@
+package entity
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type DiscountKind string
+
+const (
+	DiscountKindPromotion DiscountKind = "promotion"
+	DiscountKindCoupon    DiscountKind = "coupon"
+)
+
+// DiscountLine is one cart or order line as the discount engine sees it.
+// CategoryIDs are the categories the product is directly in.
+type DiscountLine struct {
+	ID          string
+	ProductID   string
+	CategoryIDs []string
+	UnitPrice   entity.Money
+	Quantity    int64
+}
+
+// AppliedDiscount is an amount a promotion or coupon took off, either off
+// one line or, in DiscountBreakdown.Discounts, off the whole order.
+type AppliedDiscount struct {
+	Kind     DiscountKind `json:"kind"`
+	SourceID string       `json:"source_id"`
+	// Source is the coupon code or the promotion name.
+	Source string       `json:"source"`
+	Amount entity.Money `json:"amount"`
+}
+
+// LineDiscounts is the breakdown for one line.
+type LineDiscounts struct {
+	LineID    string            `json:"line_id"`
+	Subtotal  entity.Money      `json:"subtotal"`
+	Discounts []AppliedDiscount `json:"discounts"`
+	Discount  entity.Money      `json:"discount"`
+	Total     entity.Money      `json:"total"`
+}
+
+// DiscountBreakdown is the result of ApplyDiscounts. Lines are in the order
+// they were given; Discounts sums each promotion and coupon over them.
+type DiscountBreakdown struct {
+	Subtotal  entity.Money      `json:"subtotal"`
+	Lines     []LineDiscounts   `json:"lines"`
+	Discounts []AppliedDiscount `json:"discounts"`
+	Discount  entity.Money      `json:"discount"`
+	Total     entity.Money      `json:"total"`
+}
+
+// Line returns the breakdown of the line with id, or nil.
+func (b *DiscountBreakdown) Line(id string) *LineDiscounts {
+	for i := range b.Lines {
+		if b.Lines[i].LineID == id {
+			return &b.Lines[i]
+		}
+	}
+	return nil
+}
+
+// ApplyDiscounts prices lines with the promotions active at now and then
+// with coupon, which may be nil. Usage limits are not checked here; see
+// Coupon.CheckUsage.
+//
+// The result depends only on the arguments. Promotions apply oldest first
+// and each unit counts towards at most one of them. Within a promotion,
+// units are taken from the most expensive down (ties in line order) in
+// groups of BuyQuantity+GetQuantity, and the last GetQuantity units of each
+// full group are discounted. The coupon then applies to what is left of
+// the lines in its scope: a percent coupon line by line, a fixed coupon
+// spread over them in proportion to their amounts. Amounts are rounded half
+// up to whole minor units per line, and leftover minor units of a fixed
+// coupon go to the lines with the largest remainders.
+func ApplyDiscounts(lines []DiscountLine, promotions []*Promotion, coupon *Coupon, now time.Time) (*DiscountBreakdown, error) {
+	currency := entity.DefaultCurrency
+	if len(lines) > 0 {
+		currency = lines[0].UnitPrice.Currency
+	}
+	zero := entity.Money{Currency: currency}
+	breakdown := &DiscountBreakdown{
+		Subtotal:  zero,
+		Lines:     make([]LineDiscounts, len(lines)),
+		Discounts: []AppliedDiscount{},
+		Discount:  zero,
+		Total:     zero,
+	}
+	for i, line := range lines {
+		subtotal := line.UnitPrice.Mul(line.Quantity)
+		breakdown.Lines[i] = LineDiscounts{
+			LineID:    line.ID,
+			Subtotal:  subtotal,
+			Discounts: []AppliedDiscount{},
+			Discount:  zero,
+			Total:     subtotal,
+		}
+		breakdown.Subtotal.Amount += subtotal.Amount
+	}
+
+	active := make([]*Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotion.Window.Contains(now) {
+			active = append(active, promotion)
+		}
+	}
+	slices.SortStableFunc(active, func(a, b *Promotion) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	consumed := make([]int64, len(lines))
+	for _, promotion := range active {
+		applyPromotion(breakdown, lines, consumed, promotion)
+	}
+
+	if coupon != nil {
+		if err := applyCoupon(breakdown, lines, coupon, now); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range breakdown.Lines {
+		line := &breakdown.Lines[i]
+		for _, discount := range line.Discounts {
+			line.Discount.Amount += discount.Amount.Amount
+		}
+		line.Total.Amount = line.Subtotal.Amount - line.Discount.Amount
+		breakdown.Discount.Amount += line.Discount.Amount
+	}
+	breakdown.Total.Amount = breakdown.Subtotal.Amount - breakdown.Discount.Amount
+	return breakdown, nil
+}
+
+func applyPromotion(breakdown *DiscountBreakdown, lines []DiscountLine, consumed []int64, promotion *Promotion) {
+	// Units of a line are interchangeable, so a unit is just its line index.
+	var units []int
+	for i, line := range lines {
+		if !promotion.Scope.Covers(line) {
+			continue
+		}
+		for range line.Quantity - consumed[i] {
+			units = append(units, i)
+		}
+	}
+	slices.SortStableFunc(units, func(a, b int) int {
+		return cmp.Compare(lines[b].UnitPrice.Amount, lines[a].UnitPrice.Amount)
+	})
+
+	group := int(promotion.BuyQuantity + promotion.GetQuantity)
+	discounted := make([]int64, len(lines))
+	for start := 0; start+group <= len(units); start += group {
+		for k, i := range units[start : start+group] {
+			consumed[i]++
+			if k >= int(promotion.BuyQuantity) {
+				discounted[i]++
+			}
+		}
+	}
+
+	var total int64
+	for i, count := range discounted {
+		if count == 0 {
+			continue
+		}
+		amount := percentOf(lines[i].UnitPrice.Amount*count, promotion.PercentOff)
+		total += amount
+		line := &breakdown.Lines[i]
+		line.Discounts = append(line.Discounts, promotionDiscount(promotion, amount, line.Subtotal.Currency))
+	}
+	if total > 0 {
+		breakdown.Discounts = append(breakdown.Discounts, promotionDiscount(promotion, total, breakdown.Subtotal.Currency))
+	}
+}
+
+func applyCoupon(breakdown *DiscountBreakdown, lines []DiscountLine, coupon *Coupon, now time.Time) error {
+	if !coupon.Window.Contains(now) {
+		return ErrCouponNotActive
+	}
+	currency := breakdown.Subtotal.Currency
+	if coupon.Type == DiscountFixed && coupon.AmountOff.Currency != currency {
+		return ErrCouponCurrency
+	}
+	if coupon.MinTotal.Amount > 0 {
+		if coupon.MinTotal.Currency != currency {
+			return ErrCouponCurrency
+		}
+		// The minimum applies to the total after promotions.
+		total := breakdown.Subtotal.Amount
+		for _, discount := range breakdown.Discounts {
+			total -= discount.Amount.Amount
+		}
+		if total < coupon.MinTotal.Amount {
+			return ErrCouponMinTotal
+		}
+	}
+
+	// remaining is what each line in scope still costs after promotions.
+	remaining := make([]int64, len(lines))
+	var base int64
+	eligible := false
+	for i, line := range lines {
+		if !coupon.Scope.Covers(line) {
+			continue
+		}
+		eligible = true
+		remaining[i] = breakdown.Lines[i].Subtotal.Amount
+		for _, discount := range breakdown.Lines[i].Discounts {
+			remaining[i] -= discount.Amount.Amount
+		}
+		base += remaining[i]
+	}
+	if !eligible {
+		return ErrCouponNotApplicable
+	}
+
+	amounts := make([]int64, len(lines))
+	switch coupon.Type {
+	case DiscountPercent:
+		for i := range lines {
+			amounts[i] = percentOf(remaining[i], coupon.PercentOff)
+		}
+	case DiscountFixed:
+		amounts = allocate(min(coupon.AmountOff.Amount, base), remaining, base)
+	}
+
+	var total int64
+	for i, amount := range amounts {
+		if amount == 0 {
+			continue
+		}
+		total += amount
+		line := &breakdown.Lines[i]
+		line.Discounts = append(line.Discounts, couponDiscount(coupon, amount, currency))
+	}
+	breakdown.Discounts = append(breakdown.Discounts, couponDiscount(coupon, total, currency))
+	return nil
+}
+
+// allocate splits amount over weights, which sum to total, in whole units.
+// Each share is rounded down, and the units left over go one each to the
+// largest remainders, ties going to the earlier weight.
+func allocate(amount int64, weights []int64, total int64) []int64 {
+	shares := make([]int64, len(weights))
+	if total <= 0 || amount <= 0 {
+		return shares
+	}
+	remainders := make([]int64, len(weights))
+	left := amount
+	for i, weight := range weights {
+		shares[i] = amount * weight / total
+		remainders[i] = amount * weight % total
+		left -= shares[i]
+	}
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for _, i := range order[:left] {
+		shares[i]++
+	}
+	return shares
+}
+
+// percentOf is percent% of amount, rounded half up.
+func percentOf(amount, percent int64) int64 {
+	return (amount*percent + 50) / 100
+}
+
+func promotionDiscount(promotion *Promotion, amount int64, currency string) AppliedDiscount {
+	return AppliedDiscount{
+		Kind:     DiscountKindPromotion,
+		SourceID: promotion.ID.String(),
+		Source:   promotion.Name,
+		Amount:   entity.Money{Amount: amount, Currency: currency},
+	}
+}
+
+func couponDiscount(coupon *Coupon, amount int64, currency string) AppliedDiscount {
+	return AppliedDiscount{
+		Kind:     DiscountKindCoupon,
+		SourceID: coupon.ID.String(),
+		Source:   coupon.Code,
+		Amount:   entity.Money{Amount: amount, Currency: currency},
+	}
+}
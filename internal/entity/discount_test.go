@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func brl(amount string) entity.Money {
+	return entity.MustParseMoney(amount, "BRL")
+}
+
+func TestCoupon(t *testing.T) {
+	t.Run("should normalize the code and validate the discount", func(t *testing.T) {
+		coupon, err := NewPercentCoupon(" welcome10 ", 10)
+		require.NoError(t, err)
+		assert.Equal(t, "WELCOME10", coupon.Code)
+
+		_, err = NewPercentCoupon("BIG", 101)
+		assert.Equal(t, ErrInvalidDiscount, err)
+		_, err = NewFixedCoupon("FREE", brl("0"))
+		assert.Equal(t, ErrInvalidDiscount, err)
+		_, err = NewPercentCoupon("no spaces", 5)
+		assert.Equal(t, ErrInvalidCouponCode, err)
+	})
+
+	t.Run("should reject a window that ends before it starts", func(t *testing.T) {
+		coupon, err := NewPercentCoupon("WINDOW", 5)
+		require.NoError(t, err)
+		start := time.Now()
+		end := start.Add(-time.Hour)
+		coupon.Window = ValidityWindow{StartsAt: &start, EndsAt: &end}
+		assert.Equal(t, ErrInvalidWindow, coupon.Validate())
+	})
+
+	t.Run("should enforce usage limits per code and per user", func(t *testing.T) {
+		coupon, err := NewPercentCoupon("LIMITED", 5)
+		require.NoError(t, err)
+		coupon.MaxUses, coupon.MaxUsesPerUser = 10, 1
+		assert.NoError(t, coupon.CheckUsage(0))
+		assert.Equal(t, ErrCouponUserLimit, coupon.CheckUsage(1))
+		coupon.Uses = 10
+		assert.Equal(t, ErrCouponUsedUp, coupon.CheckUsage(0))
+	})
+}
+
+func TestApplyDiscounts(t *testing.T) {
+	now := time.Now()
+	shirt := DiscountLine{ID: "a", ProductID: "shirt", CategoryIDs: []string{"clothes"}, UnitPrice: brl("50"), Quantity: 2}
+	socks := DiscountLine{ID: "b", ProductID: "socks", CategoryIDs: []string{"clothes"}, UnitPrice: brl("10"), Quantity: 3}
+	book := DiscountLine{ID: "c", ProductID: "book", CategoryIDs: []string{"books"}, UnitPrice: brl("33.33"), Quantity: 1}
+
+	t.Run("should total the lines without discounts", func(t *testing.T) {
+		breakdown, err := ApplyDiscounts([]DiscountLine{shirt, book}, nil, nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("133.33"), breakdown.Subtotal)
+		assert.Equal(t, brl("0"), breakdown.Discount)
+		assert.Equal(t, brl("133.33"), breakdown.Total)
+		assert.Empty(t, breakdown.Discounts)
+	})
+
+	t.Run("should give the cheapest units of each group for buy 2 get 1", func(t *testing.T) {
+		promotion, err := NewBuyXGetY("3 for 2", 2, 1, 0)
+		require.NoError(t, err)
+		promotion.Scope.CategoryIDs = []string{"clothes"}
+
+		// Units by price: 50 50 10 | 10 10, so one sock is free and the
+		// last two socks are not a full group.
+		breakdown, err := ApplyDiscounts([]DiscountLine{socks, shirt, book}, []*Promotion{promotion}, nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("10"), breakdown.Discount)
+		assert.Equal(t, brl("20"), breakdown.Line("b").Total)
+		assert.Equal(t, brl("100"), breakdown.Line("a").Total)
+		require.Len(t, breakdown.Discounts, 1)
+		assert.Equal(t, "3 for 2", breakdown.Discounts[0].Source)
+		assert.Equal(t, DiscountKindPromotion, breakdown.Discounts[0].Kind)
+	})
+
+	t.Run("should count each unit towards one promotion only", func(t *testing.T) {
+		first, err := NewBuyXGetY("first", 1, 1, 50)
+		require.NoError(t, err)
+		second, err := NewBuyXGetY("second", 1, 1, 100)
+		require.NoError(t, err)
+		second.CreatedAt = first.CreatedAt.Add(time.Second)
+
+		// The older promotion applies first whatever the order given.
+		breakdown, err := ApplyDiscounts([]DiscountLine{shirt}, []*Promotion{second, first}, nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("25"), breakdown.Discount)
+		assert.Equal(t, "first", breakdown.Discounts[0].Source)
+	})
+
+	t.Run("should skip promotions outside their window", func(t *testing.T) {
+		promotion, err := NewBuyXGetY("later", 1, 1, 0)
+		require.NoError(t, err)
+		tomorrow := now.Add(24 * time.Hour)
+		promotion.Window.StartsAt = &tomorrow
+
+		breakdown, err := ApplyDiscounts([]DiscountLine{shirt}, []*Promotion{promotion}, nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("0"), breakdown.Discount)
+	})
+
+	t.Run("should take a percent coupon off the lines in scope after promotions", func(t *testing.T) {
+		promotion, err := NewBuyXGetY("3 for 2", 2, 1, 0)
+		require.NoError(t, err)
+		promotion.Scope.CategoryIDs = []string{"clothes"}
+		coupon, err := NewPercentCoupon("CLOTHES15", 15)
+		require.NoError(t, err)
+		coupon.Scope.CategoryIDs = []string{"clothes"}
+
+		breakdown, err := ApplyDiscounts([]DiscountLine{shirt, socks, book}, []*Promotion{promotion}, coupon, now)
+		require.NoError(t, err)
+		// Socks: 30 - 10 free = 20, 15% = 3. Shirts: 100, 15% = 15.
+		assert.Equal(t, brl("15"), breakdown.Line("a").Discount)
+		assert.Equal(t, brl("13"), breakdown.Line("b").Discount)
+		assert.Equal(t, brl("0"), breakdown.Line("c").Discount)
+		assert.Equal(t, brl("28"), breakdown.Discount)
+		assert.Equal(t, brl("135.33"), breakdown.Total)
+		require.Len(t, breakdown.Discounts, 2)
+		assert.Equal(t, AppliedDiscount{
+			Kind: DiscountKindCoupon, SourceID: coupon.ID.String(), Source: "CLOTHES15", Amount: brl("18"),
+		}, breakdown.Discounts[1])
+	})
+
+	t.Run("should spread a fixed coupon in proportion, to the cent", func(t *testing.T) {
+		coupon, err := NewFixedCoupon("TENOFF", brl("10"))
+		require.NoError(t, err)
+		a := DiscountLine{ID: "a", ProductID: "x", UnitPrice: brl("10"), Quantity: 1}
+		b := DiscountLine{ID: "b", ProductID: "y", UnitPrice: brl("10"), Quantity: 1}
+		c := DiscountLine{ID: "c", ProductID: "z", UnitPrice: brl("10"), Quantity: 1}
+
+		breakdown, err := ApplyDiscounts([]DiscountLine{a, b, c}, nil, coupon, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("3.34"), breakdown.Line("a").Discount)
+		assert.Equal(t, brl("3.33"), breakdown.Line("b").Discount)
+		assert.Equal(t, brl("3.33"), breakdown.Line("c").Discount)
+		assert.Equal(t, brl("20"), breakdown.Total)
+	})
+
+	t.Run("should never discount more than the lines cost", func(t *testing.T) {
+		coupon, err := NewFixedCoupon("HUGE", brl("500"))
+		require.NoError(t, err)
+		breakdown, err := ApplyDiscounts([]DiscountLine{book}, nil, coupon, now)
+		require.NoError(t, err)
+		assert.Equal(t, brl("0"), breakdown.Total)
+	})
+
+	t.Run("should refuse coupons that do not apply", func(t *testing.T) {
+		coupon, err := NewPercentCoupon("BOOKS", 10)
+		require.NoError(t, err)
+		coupon.Scope.CategoryIDs = []string{"books"}
+		_, err = ApplyDiscounts([]DiscountLine{shirt}, nil, coupon, now)
+		assert.Equal(t, ErrCouponNotApplicable, err)
+
+		coupon.Scope = DiscountScope{}
+		coupon.MinTotal = brl("200")
+		_, err = ApplyDiscounts([]DiscountLine{shirt}, nil, coupon, now)
+		assert.Equal(t, ErrCouponMinTotal, err)
+
+		coupon.MinTotal = entity.Money{}
+		yesterday := now.Add(-24 * time.Hour)
+		coupon.Window.EndsAt = &yesterday
+		_, err = ApplyDiscounts([]DiscountLine{shirt}, nil, coupon, now)
+		assert.Equal(t, ErrCouponNotActive, err)
+
+		dollars, err := NewFixedCoupon("DOLLARS", entity.MustParseMoney("5", "USD"))
+		require.NoError(t, err)
+		_, err = ApplyDiscounts([]DiscountLine{shirt}, nil, dollars, now)
+		assert.Equal(t, ErrCouponCurrency, err)
+	})
+
+	t.Run("should give the same result every time", func(t *testing.T) {
+		promotion, err := NewBuyXGetY("2 for 1", 1, 1, 0)
+		require.NoError(t, err)
+		coupon, err := NewFixedCoupon("SEVEN", brl("7"))
+		require.NoError(t, err)
+		lines := []DiscountLine{shirt, socks, book}
+
+		first, err := ApplyDiscounts(lines, []*Promotion{promotion}, coupon, now)
+		require.NoError(t, err)
+		for range 10 {
+			again, err := ApplyDiscounts(lines, []*Promotion{promotion}, coupon, now)
+			require.NoError(t, err)
+			assert.Equal(t, first, again)
+		}
+	})
+}
+
+func TestOrder_ApplyDiscounts(t *testing.T) {
+	book, err := NewProduct("Book", "", brl("40"))
+	require.NoError(t, err)
+	item, err := NewOrderItem(book, nil, 2)
+	require.NoError(t, err)
+	order, err := NewOrder(entity.NewID(), []*OrderItem{item})
+	require.NoError(t, err)
+	coupon, err := NewPercentCoupon("QUARTER", 25)
+	require.NoError(t, err)
+
+	breakdown, err := ApplyDiscounts(order.DiscountLines(), nil, coupon, time.Now())
+	require.NoError(t, err)
+	order.ApplyDiscounts(breakdown, coupon)
+
+	assert.Equal(t, brl("80"), order.Subtotal)
+	assert.Equal(t, brl("20"), order.Discount)
+	assert.Equal(t, brl("60"), order.Total)
+	assert.Equal(t, "QUARTER", order.CouponCode)
+	assert.Equal(t, brl("60"), item.Total())
+	assert.Len(t, item.Discounts, 1)
+}
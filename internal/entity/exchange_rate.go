@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var (
+	ErrInvalidRate  = errors.New("exchange rate must be a positive decimal")
+	ErrRateNotFound = errors.New("no exchange rate for currency")
+)
+
+// ExchangeRate is how many units of Currency one unit of
+// entity.DefaultCurrency buys. Rate is kept as the decimal string it was
+// given in so conversions stay exact.
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"primaryKey;type:varchar(3)"`
+	Rate      string    `json:"rate" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewExchangeRate(currency, rate string) (*ExchangeRate, error) {
+	currency, err := entity.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	r := &ExchangeRate{Currency: currency, Rate: strings.TrimSpace(rate), UpdatedAt: time.Now()}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ParseExchangeRates builds the rows for a currency → rate map, as sent to
+// the admin endpoint or read from the rates file.
+func ParseExchangeRates(rates map[string]string) ([]*ExchangeRate, error) {
+	result := make([]*ExchangeRate, 0, len(rates))
+	for currency, rate := range rates {
+		r, err := NewExchangeRate(currency, rate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", currency, err)
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// ReadExchangeRates parses a rates file: {"rates": {"USD": "0.18", ...}},
+// the same shape the admin endpoint accepts.
+func ReadExchangeRates(r io.Reader) ([]*ExchangeRate, error) {
+	var file struct {
+		Rates map[string]string `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	return ParseExchangeRates(file.Rates)
+}
+
+func (r *ExchangeRate) Validate() error {
+	if _, err := entity.CurrencyExponent(r.Currency); err != nil {
+		return err
+	}
+	if _, err := r.ratio(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ExchangeRate) ratio() (*big.Rat, error) {
+	ratio, ok := new(big.Rat).SetString(r.Rate)
+	// SetString also accepts fractions like "1/3"; only plain decimals are
+	// allowed so the stored value reads the same everywhere.
+	if !ok || ratio.Sign() <= 0 || strings.ContainsAny(r.Rate, "/eE") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, r.Rate)
+	}
+	return ratio, nil
+}
+
+// ExchangeRates converts amounts between currencies through
+// entity.DefaultCurrency.
+type ExchangeRates struct {
+	rates map[string]*big.Rat
+}
+
+func NewExchangeRates(rates []*ExchangeRate) (*ExchangeRates, error) {
+	x := &ExchangeRates{rates: map[string]*big.Rat{entity.DefaultCurrency: big.NewRat(1, 1)}}
+	for _, r := range rates {
+		ratio, err := r.ratio()
+		if err != nil {
+			return nil, err
+		}
+		if r.Currency != entity.DefaultCurrency {
+			x.rates[r.Currency] = ratio
+		}
+	}
+	return x, nil
+}
+
+// Convert returns m in currency to. The cross rate is applied exactly and
+// the result is rounded once, half away from zero, to the minor units of
+// the target currency.
+func (x *ExchangeRates) Convert(m entity.Money, to string) (entity.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	fromRate, ok := x.rates[m.Currency]
+	if !ok {
+		return entity.Money{}, fmt.Errorf("%w %s", ErrRateNotFound, m.Currency)
+	}
+	toRate, ok := x.rates[to]
+	if !ok {
+		return entity.Money{}, fmt.Errorf("%w %s", ErrRateNotFound, to)
+	}
+	fromExp, err := entity.CurrencyExponent(m.Currency)
+	if err != nil {
+		return entity.Money{}, err
+	}
+	toExp, err := entity.CurrencyExponent(to)
+	if err != nil {
+		return entity.Money{}, err
+	}
+
+	// amount / 10^fromExp / fromRate * toRate, in major units of "to".
+	amount := new(big.Rat).SetFrac(big.NewInt(m.Amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromExp)), nil))
+	amount.Quo(amount, fromRate)
+	amount.Mul(amount, toRate)
+	return entity.Money{Amount: entity.RoundRat(amount, toExp), Currency: to}, nil
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExchangeRate(t *testing.T) {
+	rate, err := NewExchangeRate("usd", "0.1825")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rate.Currency)
+	assert.Equal(t, "0.1825", rate.Rate)
+
+	for _, bad := range []string{"", "0", "-1", "abc", "1/3", "1e2"} {
+		_, err := NewExchangeRate("USD", bad)
+		assert.ErrorIs(t, err, ErrInvalidRate, bad)
+	}
+
+	_, err = NewExchangeRate("XYZ", "1")
+	assert.ErrorIs(t, err, entity.ErrInvalidCurrency)
+}
+
+func TestReadExchangeRates(t *testing.T) {
+	rates, err := ReadExchangeRates(strings.NewReader(`{"rates": {"usd": "0.18"}}`))
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "USD", rates[0].Currency)
+
+	_, err = ReadExchangeRates(strings.NewReader(`{"rates": {"USD": 0.18}}`))
+	assert.Error(t, err)
+}
+
+func TestExchangeRates_Convert(t *testing.T) {
+	rates, err := ParseExchangeRates(map[string]string{"USD": "0.2", "EUR": "0.18", "JPY": "28.5"})
+	require.NoError(t, err)
+	x, err := NewExchangeRates(rates)
+	require.NoError(t, err)
+
+	t.Run("should convert from the base currency", func(t *testing.T) {
+		m, err := x.Convert(entity.MustParseMoney("10.99", "BRL"), "USD")
+		require.NoError(t, err)
+		// 10.99 * 0.2 = 2.198 → 2.20
+		assert.Equal(t, "2.20", m.Decimal())
+		assert.Equal(t, "USD", m.Currency)
+	})
+
+	t.Run("should round half away from zero once", func(t *testing.T) {
+		// 0.25 * 0.18 = 0.045 exactly → 0.05
+		m, err := x.Convert(entity.MustParseMoney("0.25", "BRL"), "EUR")
+		require.NoError(t, err)
+		assert.Equal(t, "0.05", m.Decimal())
+	})
+
+	t.Run("should cross convert through the base currency", func(t *testing.T) {
+		// 10 USD = 50 BRL = 1425 JPY
+		m, err := x.Convert(entity.MustParseMoney("10", "USD"), "JPY")
+		require.NoError(t, err)
+		assert.Equal(t, "1425", m.Decimal())
+	})
+
+	t.Run("should return the same amount for the same currency", func(t *testing.T) {
+		price := entity.MustParseMoney("3.33", "USD")
+		m, err := x.Convert(price, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, price, m)
+	})
+
+	t.Run("should fail without a rate", func(t *testing.T) {
+		_, err := x.Convert(entity.MustParseMoney("1", "BRL"), "GBP")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+}
+
+func TestNewProductPrice(t *testing.T) {
+	product, err := NewProduct(name, description, price)
+	require.NoError(t, err)
+
+	p, err := NewProductPrice(product, entity.MustParseMoney("2.50", "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, entity.MustParseMoney("2.50", "USD"), p.Money())
+
+	_, err = NewProductPrice(product, entity.MustParseMoney("2.50", "BRL"))
+	assert.ErrorIs(t, err, ErrPriceInBaseCurrency)
+
+	_, err = NewProductPrice(product, entity.MustParseMoney("0", "USD"))
+	assert.ErrorIs(t, err, ErrPriceMustBePositive)
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var (
+	ErrWarehouseNameIsRequired = errors.New("warehouse name is required")
+	ErrInvalidWarehouseCode    = errors.New("warehouse code must be 1 to 32 uppercase letters, digits or '-'")
+	ErrQuantityMustBePositive  = errors.New("quantity must be positive")
+)
+
+// MaxReservedPerUser is how many units of a variant one user may hold in
+// active reservations at once, so a shopper cannot hoard a SKU's stock by
+// stacking reservations.
+const MaxReservedPerUser = 100
+
+var warehouseCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{0,31}$`)
+
+// Warehouse is a place stock is kept in.
+type Warehouse struct {
+	ID        entity.ID `json:"id"`
+	Code      string    `json:"code" gorm:"uniqueIndex;type:varchar(32);not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWarehouse(code, name string) (*Warehouse, error) {
+	warehouse := &Warehouse{
+		ID:        entity.NewID(),
+		Code:      strings.ToUpper(strings.TrimSpace(code)),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := warehouse.Validate(); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (w *Warehouse) Validate() error {
+	if !warehouseCodePattern.MatchString(w.Code) {
+		return ErrInvalidWarehouseCode
+	}
+	if w.Name == "" {
+		return ErrWarehouseNameIsRequired
+	}
+	return nil
+}
+
+// StockLevel is how many units of a variant a warehouse holds. Reserved
+// units are still on hand but promised to a pending checkout.
+type StockLevel struct {
+	VariantID   entity.ID `json:"variant_id" gorm:"primaryKey"`
+	WarehouseID entity.ID `json:"warehouse_id" gorm:"primaryKey;index"`
+	OnHand      int64     `json:"on_hand" gorm:"not null;default:0"`
+	Reserved    int64     `json:"reserved" gorm:"not null;default:0"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Available is what can still be reserved.
+func (s *StockLevel) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+	// ReservationReturned is a committed reservation whose units came back
+	// to the warehouse, as when its order is cancelled.
+	ReservationReturned ReservationStatus = "returned"
+)
+
+// StockReservation holds units of one variant in one warehouse until it is
+// committed (the units leave), released, or ExpiresAt passes.
+type StockReservation struct {
+	ID          entity.ID         `json:"id"`
+	VariantID   entity.ID         `json:"variant_id" gorm:"index;not null"`
+	WarehouseID entity.ID         `json:"warehouse_id" gorm:"not null"`
+	Quantity    int64             `json:"quantity" gorm:"not null"`
+	Status      ReservationStatus `json:"status" gorm:"type:varchar(16);not null;index:idx_reservation_expiry"`
+	ExpiresAt   time.Time         `json:"expires_at" gorm:"not null;index:idx_reservation_expiry"`
+	// UserID is who made the reservation; only they and admins see or
+	// release it. It is nil for reservations the store makes itself.
+	UserID *entity.ID `json:"user_id,omitempty" gorm:"index"`
+	// Reference ties the reservation to what it was made for, e.g. a cart.
+	Reference string    `json:"reference,omitempty" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Expired reports whether an active reservation has outlived ExpiresAt.
+func (r *StockReservation) Expired(now time.Time) bool {
+	return r.Status == ReservationActive && !now.Before(r.ExpiresAt)
+}
+
+type MovementKind string
+
+const (
+	MovementAdjust  MovementKind = "adjust"
+	MovementReserve MovementKind = "reserve"
+	MovementCommit  MovementKind = "commit"
+	MovementRelease MovementKind = "release"
+	MovementExpire  MovementKind = "expire"
+	MovementRestock MovementKind = "restock"
+)
+
+// StockMovement is one ledger entry. OnHandDelta and ReservedDelta are what
+// the movement changed; OnHand and Reserved are the levels right after it.
+type StockMovement struct {
+	ID            entity.ID    `json:"id"`
+	VariantID     entity.ID    `json:"variant_id" gorm:"index:idx_movement_stock;not null"`
+	WarehouseID   entity.ID    `json:"warehouse_id" gorm:"index:idx_movement_stock;not null"`
+	Kind          MovementKind `json:"kind" gorm:"type:varchar(16);not null"`
+	OnHandDelta   int64        `json:"on_hand_delta"`
+	ReservedDelta int64        `json:"reserved_delta"`
+	OnHand        int64        `json:"on_hand"`
+	Reserved      int64        `json:"reserved"`
+	ReservationID *entity.ID   `json:"reservation_id,omitempty"`
+	Reason        string       `json:"reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,327 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderFulfilled OrderStatus = "fulfilled"
+	OrderCompleted OrderStatus = "completed"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions lists, for each status, the statuses an order may move
+// to next. Cancelled and refunded orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending:   {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderFulfilled, OrderRefunded},
+	OrderFulfilled: {OrderCompleted, OrderRefunded},
+	OrderCompleted: {OrderRefunded},
+	OrderCancelled: {},
+	OrderRefunded:  {},
+}
+
+var (
+	ErrEmptyOrder         = errors.New("an order needs at least one item")
+	ErrOrderCurrency      = errors.New("every item in an order must be priced in the same currency")
+	ErrInvalidOrderStatus = errors.New("invalid order status")
+	ErrInvalidTransition  = errors.New("invalid order status transition")
+)
+
+// ParseOrderStatus validates s as an order status.
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	status := OrderStatus(s)
+	if _, ok := orderTransitions[status]; !ok {
+		return "", ErrInvalidOrderStatus
+	}
+	return status, nil
+}
+
+// CanTransition reports whether an order in status s may move to next.
+func (s OrderStatus) CanTransition(next OrderStatus) bool {
+	return slices.Contains(orderTransitions[s], next)
+}
+
+// NextStatuses lists the statuses an order in status s may move to; it is
+// empty for final statuses.
+func (s OrderStatus) NextStatuses() []OrderStatus {
+	return slices.Clone(orderTransitions[s])
+}
+
+// Order is a purchase. Items live in the order_items table and Transitions
+// in order_transitions; the repository loads and saves them. Total is
+// Subtotal minus Discount, plus Tax unless PricesIncludeTax, plus Shipping;
+// Discounts sums the discounts by promotion and coupon. ShippingAddress
+// and BillingAddress are copies made when the order was placed, so editing
+// the address book does not change them.
+type Order struct {
+	ID               entity.ID          `json:"id"`
+	UserID           entity.ID          `json:"user_id" gorm:"index;not null"`
+	Status           OrderStatus        `json:"status" gorm:"type:varchar(20);index;not null"`
+	Subtotal         entity.Money       `json:"subtotal" gorm:"embedded;embeddedPrefix:subtotal_"`
+	Discount         entity.Money       `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	Tax              entity.Money       `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	Total            entity.Money       `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	TaxCountry       string             `json:"tax_country,omitempty" gorm:"type:varchar(2)"`
+	TaxRegion        string             `json:"tax_region,omitempty" gorm:"type:varchar(10)"`
+	PricesIncludeTax bool               `json:"prices_include_tax" gorm:"not null;default:false"`
+	Shipping         entity.Money       `json:"shipping" gorm:"embedded;embeddedPrefix:shipping_"`
+	ShippingMethodID *entity.ID         `json:"shipping_method_id,omitempty"`
+	ShippingMethod   string             `json:"shipping_method,omitempty"`
+	ShippingAddress  *PostalAddress     `json:"shipping_address,omitempty" gorm:"serializer:json"`
+	BillingAddress   *PostalAddress     `json:"billing_address,omitempty" gorm:"serializer:json"`
+	CouponID         *entity.ID         `json:"coupon_id,omitempty" gorm:"index"`
+	CouponCode       string             `json:"coupon_code,omitempty"`
+	Discounts        []AppliedDiscount  `json:"discounts" gorm:"serializer:json"`
+	Items            []*OrderItem       `json:"items" gorm:"-"`
+	Transitions      []*OrderTransition `json:"transitions" gorm:"-"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// OrderItem is one line of an order. Name, SKU, UnitPrice, TaxClass, weight
+// and dimensions are copied from the catalog when the order is placed.
+type OrderItem struct {
+	ID        entity.ID    `json:"id"`
+	OrderID   entity.ID    `json:"-" gorm:"index;not null"`
+	ProductID entity.ID    `json:"product_id" gorm:"index;not null"`
+	VariantID *entity.ID   `json:"variant_id,omitempty"`
+	SKU       string       `json:"sku,omitempty"`
+	Name      string       `json:"name" gorm:"not null"`
+	UnitPrice entity.Money `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"`
+	Quantity  int64        `json:"quantity" gorm:"not null"`
+	// Discounts lists what promotions and the coupon took off the line.
+	Discounts []AppliedDiscount `json:"discounts" gorm:"serializer:json"`
+	Discount  entity.Money      `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	TaxClass  string            `json:"tax_class" gorm:"type:varchar(30);not null;default:'standard'"`
+	// TaxRate is the percentage Tax was worked out with.
+	TaxRate string       `json:"tax_rate" gorm:"type:varchar(20);not null;default:'0'"`
+	Tax     entity.Money `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	// WeightGrams and Dimensions are per unit.
+	WeightGrams int64      `json:"weight_grams" gorm:"not null;default:0"`
+	Dimensions  Dimensions `json:"dimensions" gorm:"embedded"`
+	// Position keeps the lines in the order they were placed.
+	Position int `json:"-"`
+}
+
+// OrderTransition records one status change and who made it.
+type OrderTransition struct {
+	ID        entity.ID   `json:"id"`
+	OrderID   entity.ID   `json:"-" gorm:"index;not null"`
+	From      OrderStatus `json:"from" gorm:"column:from_status;type:varchar(20);not null"`
+	To        OrderStatus `json:"to" gorm:"column:to_status;type:varchar(20);not null"`
+	ActorID   *entity.ID  `json:"actor_id,omitempty"`
+	Note      string      `json:"note,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// NewOrderItem copies a product, or one of its variants, at its current
+// price. variant is nil for products sold without variants.
+func NewOrderItem(product *Product, variant *ProductVariant, quantity int64) (*OrderItem, error) {
+	if quantity < 1 || quantity > MaxItemQuantity {
+		return nil, ErrQuantityLimit
+	}
+	item := &OrderItem{
+		ID:        entity.NewID(),
+		ProductID: product.ID,
+		Name:      product.Name,
+		UnitPrice: product.Price,
+		TaxClass:  product.TaxClass,
+		TaxRate:   "0",
+		Quantity:  quantity,
+		// Variants ship like the product they belong to.
+		WeightGrams: product.WeightGrams,
+		Dimensions:  product.Dimensions,
+	}
+	if variant != nil {
+		id := variant.ID
+		item.VariantID = &id
+		item.SKU = variant.SKU
+		item.UnitPrice = variant.EffectivePrice(product)
+	}
+	return item, nil
+}
+
+// Subtotal is the unit price times the quantity.
+func (i *OrderItem) Subtotal() entity.Money {
+	return i.UnitPrice.Mul(i.Quantity)
+}
+
+// Total is the subtotal minus the line's discount. It includes the line's
+// tax only when the order's prices do.
+func (i *OrderItem) Total() entity.Money {
+	total := i.Subtotal()
+	total.Amount -= i.Discount.Amount
+	return total
+}
+
+// NewOrder creates a pending order for userID.
+func NewOrder(userID entity.ID, items []*OrderItem) (*Order, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyOrder
+	}
+	if len(items) > MaxCartLines {
+		return nil, ErrCartFull
+	}
+	order := &Order{
+		ID:          entity.NewID(),
+		UserID:      userID,
+		Status:      OrderPending,
+		Subtotal:    entity.Money{Currency: items[0].UnitPrice.Currency},
+		Discount:    entity.Money{Currency: items[0].UnitPrice.Currency},
+		Tax:         entity.Money{Currency: items[0].UnitPrice.Currency},
+		Shipping:    entity.Money{Currency: items[0].UnitPrice.Currency},
+		Total:       entity.Money{Currency: items[0].UnitPrice.Currency},
+		Discounts:   []AppliedDiscount{},
+		Items:       items,
+		Transitions: []*OrderTransition{},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	for _, item := range items {
+		if item.UnitPrice.Currency != order.Total.Currency {
+			return nil, ErrOrderCurrency
+		}
+		item.OrderID = order.ID
+		item.Discounts = []AppliedDiscount{}
+		item.Discount = entity.Money{Currency: item.UnitPrice.Currency}
+		item.Tax = entity.Money{Currency: item.UnitPrice.Currency}
+		order.Subtotal.Amount += item.Subtotal().Amount
+	}
+	order.Total = order.Subtotal
+	return order, nil
+}
+
+// DiscountLines describes the items to ApplyDiscounts, without their
+// categories.
+func (o *Order) DiscountLines() []DiscountLine {
+	lines := make([]DiscountLine, 0, len(o.Items))
+	for _, item := range o.Items {
+		lines = append(lines, DiscountLine{
+			ID:        item.ID.String(),
+			ProductID: item.ProductID.String(),
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+		})
+	}
+	return lines
+}
+
+// ApplyDiscounts records breakdown, computed from DiscountLines, on the
+// order and its items. coupon is the coupon the breakdown used, if any.
+// Tax depends on the discounted lines, so it is applied after this.
+func (o *Order) ApplyDiscounts(breakdown *DiscountBreakdown, coupon *Coupon) {
+	for _, item := range o.Items {
+		if line := breakdown.Line(item.ID.String()); line != nil {
+			item.Discounts = line.Discounts
+			item.Discount = line.Discount
+		}
+	}
+	o.Subtotal = breakdown.Subtotal
+	o.Discount = breakdown.Discount
+	o.updateTotal()
+	o.Discounts = breakdown.Discounts
+	o.CouponID, o.CouponCode = nil, ""
+	if coupon != nil {
+		id := coupon.ID
+		o.CouponID, o.CouponCode = &id, coupon.Code
+	}
+}
+
+// TaxLines describes the items to TaxTable.Calculate at what they cost
+// after discounts.
+func (o *Order) TaxLines() []TaxLine {
+	lines := make([]TaxLine, 0, len(o.Items))
+	for _, item := range o.Items {
+		lines = append(lines, TaxLine{ID: item.ID.String(), TaxClass: item.TaxClass, Amount: item.Total()})
+	}
+	return lines
+}
+
+// ApplyTax records breakdown, computed from TaxLines, on the order and its
+// items, adding the tax to Total when it is not already in the prices.
+func (o *Order) ApplyTax(breakdown *TaxBreakdown) {
+	for _, item := range o.Items {
+		if line := breakdown.Line(item.ID.String()); line != nil {
+			item.TaxRate = line.Rate
+			item.Tax = line.Tax
+		}
+	}
+	o.Tax = breakdown.Tax
+	o.TaxCountry = breakdown.Location.Country
+	o.TaxRegion = breakdown.Location.Region
+	o.PricesIncludeTax = breakdown.PricesIncludeTax
+	o.updateTotal()
+}
+
+// ShippingItems describes the items to a shipping method.
+func (o *Order) ShippingItems() []ShippingItem {
+	items := make([]ShippingItem, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, ShippingItem{WeightGrams: item.WeightGrams, Dimensions: item.Dimensions, Quantity: item.Quantity})
+	}
+	return items
+}
+
+// GoodsTotal is what the items cost after discounts, which is what free
+// shipping thresholds are compared with.
+func (o *Order) GoodsTotal() entity.Money {
+	total := o.Subtotal
+	total.Amount -= o.Discount.Amount
+	return total
+}
+
+// ApplyShipping charges rate for shipping the order; nil ships nothing.
+func (o *Order) ApplyShipping(rate *ShippingRate) {
+	o.Shipping = entity.Money{Currency: o.Subtotal.Currency}
+	o.ShippingMethodID, o.ShippingMethod = nil, ""
+	if rate != nil {
+		id := rate.MethodID
+		o.Shipping = rate.Cost
+		o.ShippingMethodID, o.ShippingMethod = &id, rate.Name
+	}
+	o.updateTotal()
+}
+
+func (o *Order) updateTotal() {
+	o.Total = o.GoodsTotal()
+	if !o.PricesIncludeTax {
+		o.Total.Amount += o.Tax.Amount
+	}
+	o.Total.Amount += o.Shipping.Amount
+}
+
+// Transition moves the order to status next, returning the record of the
+// change, or an error wrapping ErrInvalidTransition if the state machine
+// does not allow it. actorID may be nil for changes the system makes.
+func (o *Order) Transition(next OrderStatus, actorID *entity.ID, note string) (*OrderTransition, error) {
+	if _, ok := orderTransitions[next]; !ok {
+		return nil, ErrInvalidOrderStatus
+	}
+	if !o.Status.CanTransition(next) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, o.Status, next)
+	}
+	transition := &OrderTransition{
+		ID:        entity.NewID(),
+		OrderID:   o.ID,
+		From:      o.Status,
+		To:        next,
+		ActorID:   actorID,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+	o.Status = next
+	o.UpdatedAt = transition.CreatedAt
+	o.Transitions = append(o.Transitions, transition)
+	return transition, nil
+}
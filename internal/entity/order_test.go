@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder(t *testing.T) {
+	product, options := newShirt(t)
+	red, err := NewProductVariant(product, options, map[string]string{"color": "Red", "size": "M"}, "TEE-RED-M", "", nil)
+	require.NoError(t, err)
+	book, err := NewProduct("Book", "", entity.MustParseMoney("35.50", "BRL"))
+	require.NoError(t, err)
+
+	t.Run("should copy names and prices and total the lines", func(t *testing.T) {
+		shirt, err := NewOrderItem(product, red, 2)
+		require.NoError(t, err)
+		paperback, err := NewOrderItem(book, nil, 1)
+		require.NoError(t, err)
+
+		order, err := NewOrder(entity.NewID(), []*OrderItem{shirt, paperback})
+		require.NoError(t, err)
+		assert.Equal(t, OrderPending, order.Status)
+		assert.Equal(t, "TEE-RED-M", order.Items[0].SKU)
+		assert.Equal(t, order.ID, order.Items[1].OrderID)
+		assert.Equal(t, entity.MustParseMoney("135.30", "BRL"), order.Total)
+
+		price := book.Price
+		book.Price = entity.MustParseMoney("99", "BRL")
+		assert.Equal(t, entity.MustParseMoney("35.50", "BRL"), paperback.UnitPrice)
+		book.Price = price
+	})
+
+	t.Run("should refuse empty orders and mixed currencies", func(t *testing.T) {
+		_, err := NewOrder(entity.NewID(), nil)
+		assert.Equal(t, ErrEmptyOrder, err)
+
+		imported, err := NewProduct("Import", "", entity.MustParseMoney("10", "USD"))
+		require.NoError(t, err)
+		a, _ := NewOrderItem(book, nil, 1)
+		b, _ := NewOrderItem(imported, nil, 1)
+		_, err = NewOrder(entity.NewID(), []*OrderItem{a, b})
+		assert.Equal(t, ErrOrderCurrency, err)
+	})
+
+	t.Run("should follow the state machine", func(t *testing.T) {
+		item, err := NewOrderItem(book, nil, 1)
+		require.NoError(t, err)
+		order, err := NewOrder(entity.NewID(), []*OrderItem{item})
+		require.NoError(t, err)
+
+		_, err = order.Transition(OrderFulfilled, nil, "")
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		assert.Equal(t, OrderPending, order.Status)
+
+		admin := entity.NewID()
+		for _, next := range []OrderStatus{OrderPaid, OrderFulfilled, OrderCompleted, OrderRefunded} {
+			transition, err := order.Transition(next, &admin, "")
+			require.NoError(t, err)
+			assert.Equal(t, next, transition.To)
+		}
+		assert.Len(t, order.Transitions, 4)
+		assert.Equal(t, OrderCompleted, order.Transitions[3].From)
+		assert.Empty(t, order.Status.NextStatuses())
+
+		_, err = order.Transition(OrderPaid, &admin, "")
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		_, err = order.Transition("shipped", &admin, "")
+		assert.Equal(t, ErrInvalidOrderStatus, err)
+	})
+
+	t.Run("should only cancel before payment", func(t *testing.T) {
+		assert.True(t, OrderPending.CanTransition(OrderCancelled))
+		assert.False(t, OrderPaid.CanTransition(OrderCancelled))
+		assert.False(t, OrderCancelled.CanTransition(OrderRefunded))
+	})
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type PaymentStatus string
+
+const (
+	// PaymentPending intents have not been sent to the gateway yet.
+	PaymentPending    PaymentStatus = "pending"
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentCaptured   PaymentStatus = "captured"
+	PaymentFailed     PaymentStatus = "failed"
+	// PaymentRefunded intents have had their whole amount refunded; a
+	// partially refunded intent stays captured.
+	PaymentRefunded PaymentStatus = "refunded"
+)
+
+var (
+	ErrOrderNotPayable = errors.New("only pending orders can be paid")
+	ErrPaymentStatus   = errors.New("operation not allowed in the payment's status")
+	ErrRefundAmount    = errors.New("refund must be positive and at most what is left to refund")
+)
+
+// PaymentIntent is one attempt to pay an order through a gateway.
+// Reference is the gateway's ID for the payment.
+type PaymentIntent struct {
+	ID          entity.ID     `json:"id"`
+	OrderID     entity.ID     `json:"order_id" gorm:"index;not null"`
+	Provider    string        `json:"provider" gorm:"type:varchar(50);not null"`
+	Reference   string        `json:"reference" gorm:"index"`
+	Amount      entity.Money  `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Refunded    int64         `json:"refunded"`
+	Status      PaymentStatus `json:"status" gorm:"type:varchar(20);not null"`
+	FailureCode string        `json:"failure_code,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// NewPaymentIntent starts paying the whole total of a pending order.
+func NewPaymentIntent(order *Order, provider string) (*PaymentIntent, error) {
+	if order.Status != OrderPending {
+		return nil, ErrOrderNotPayable
+	}
+	return &PaymentIntent{
+		ID:        entity.NewID(),
+		OrderID:   order.ID,
+		Provider:  provider,
+		Amount:    order.Total,
+		Status:    PaymentPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Active reports whether the intent holds or has taken money, in which case
+// the order must not be paid again.
+func (p *PaymentIntent) Active() bool {
+	return p.Status == PaymentAuthorized || p.Status == PaymentCaptured
+}
+
+// RefundedMoney is the amount refunded so far.
+func (p *PaymentIntent) RefundedMoney() entity.Money {
+	return entity.Money{Amount: p.Refunded, Currency: p.Amount.Currency}
+}
+
+// Authorized records the gateway's approval.
+func (p *PaymentIntent) Authorized(reference string) error {
+	if p.Status != PaymentPending {
+		return p.statusError("authorize")
+	}
+	p.Reference = reference
+	p.setStatus(PaymentAuthorized)
+	return nil
+}
+
+// Fail records that the gateway declined or failed the payment.
+func (p *PaymentIntent) Fail(reference, code string) error {
+	if p.Status != PaymentPending && p.Status != PaymentAuthorized {
+		return p.statusError("fail")
+	}
+	if reference != "" {
+		p.Reference = reference
+	}
+	p.FailureCode = code
+	p.setStatus(PaymentFailed)
+	return nil
+}
+
+func (p *PaymentIntent) Capture() error {
+	if p.Status != PaymentAuthorized {
+		return p.statusError("capture")
+	}
+	p.setStatus(PaymentCaptured)
+	return nil
+}
+
+// Refund records a refund of amount, which must not exceed what is left.
+// The intent becomes refunded once everything was given back.
+func (p *PaymentIntent) Refund(amount entity.Money) error {
+	if p.Status != PaymentCaptured {
+		return p.statusError("refund")
+	}
+	if amount.Currency != p.Amount.Currency || !amount.IsPositive() || p.Refunded+amount.Amount > p.Amount.Amount {
+		return ErrRefundAmount
+	}
+	p.Refunded += amount.Amount
+	if p.Refunded == p.Amount.Amount {
+		p.setStatus(PaymentRefunded)
+	} else {
+		p.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (p *PaymentIntent) setStatus(status PaymentStatus) {
+	p.Status = status
+	p.UpdatedAt = time.Now()
+}
+
+func (p *PaymentIntent) statusError(operation string) error {
+	return fmt.Errorf("%w: cannot %s a %s payment", ErrPaymentStatus, operation, p.Status)
+}
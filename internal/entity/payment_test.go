@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPendingOrder(t *testing.T) *Order {
+	book, err := NewProduct("Book", "", entity.MustParseMoney("50", "BRL"))
+	require.NoError(t, err)
+	item, err := NewOrderItem(book, nil, 2)
+	require.NoError(t, err)
+	order, err := NewOrder(entity.NewID(), []*OrderItem{item})
+	require.NoError(t, err)
+	return order
+}
+
+func TestPaymentIntent(t *testing.T) {
+	t.Run("should pay the order total", func(t *testing.T) {
+		intent, err := NewPaymentIntent(newPendingOrder(t), "fake")
+		require.NoError(t, err)
+		assert.Equal(t, PaymentPending, intent.Status)
+		assert.Equal(t, entity.MustParseMoney("100", "BRL"), intent.Amount)
+		assert.False(t, intent.Active())
+
+		assert.ErrorIs(t, intent.Capture(), ErrPaymentStatus)
+		require.NoError(t, intent.Authorized("ref_1"))
+		assert.True(t, intent.Active())
+		require.NoError(t, intent.Capture())
+		assert.Equal(t, PaymentCaptured, intent.Status)
+	})
+
+	t.Run("should refund partially and then fully", func(t *testing.T) {
+		intent, err := NewPaymentIntent(newPendingOrder(t), "fake")
+		require.NoError(t, err)
+		require.NoError(t, intent.Authorized("ref_2"))
+		require.NoError(t, intent.Capture())
+
+		require.NoError(t, intent.Refund(entity.MustParseMoney("30", "BRL")))
+		assert.Equal(t, PaymentCaptured, intent.Status)
+		assert.Equal(t, ErrRefundAmount, intent.Refund(entity.MustParseMoney("70.01", "BRL")))
+		assert.Equal(t, ErrRefundAmount, intent.Refund(entity.MustParseMoney("10", "USD")))
+		require.NoError(t, intent.Refund(entity.MustParseMoney("70", "BRL")))
+		assert.Equal(t, PaymentRefunded, intent.Status)
+		assert.Equal(t, entity.MustParseMoney("100", "BRL"), intent.RefundedMoney())
+	})
+
+	t.Run("should record declines", func(t *testing.T) {
+		intent, err := NewPaymentIntent(newPendingOrder(t), "fake")
+		require.NoError(t, err)
+		require.NoError(t, intent.Fail("ref_3", "card_declined"))
+		assert.Equal(t, PaymentFailed, intent.Status)
+		assert.ErrorIs(t, intent.Authorized("ref_3"), ErrPaymentStatus)
+	})
+
+	t.Run("should only pay pending orders", func(t *testing.T) {
+		order := newPendingOrder(t)
+		_, err := order.Transition(OrderCancelled, nil, "")
+		require.NoError(t, err)
+		_, err = NewPaymentIntent(order, "fake")
+		assert.Equal(t, ErrOrderNotPayable, err)
+	})
+}
@@ -3,7 +3,12 @@ package entity
 import (
 	"errors"
 	"github/GuilhermeHermes/GO_API/pkg/entity"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
 )
 
 var (
@@ -12,15 +17,78 @@ var (
 	ErrNameIsRequired      = errors.New("product name is required")
 	ErrPriceIsRequired     = errors.New("product price is required")
 	ErrPriceMustBePositive = errors.New("product price must be positive")
+	ErrInvalidTag          = errors.New("tags must be 1 to 50 characters without commas")
 )
 
+const maxTagLength = 50
+
 type Product struct {
-	ID          entity.ID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          entity.ID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       entity.Money `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	// Tags live in the product_tags table; the repository loads and saves them.
+	Tags []string `json:"tags" gorm:"-"`
+	// TaxClass picks the product's rate from the tax tables.
+	TaxClass string `json:"tax_class" gorm:"type:varchar(30);not null;default:'standard'"`
+	// WeightGrams and Dimensions are the packed product, for shipping.
+	WeightGrams int64      `json:"weight_grams" gorm:"not null;default:0"`
+	Dimensions  Dimensions `json:"dimensions" gorm:"embedded"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Version goes up by one on every update; writes made against an older
+	// version are rejected.
+	Version int64 `json:"version" gorm:"not null;default:1"`
+	// Status starts as draft for new products; rows from before statuses
+	// existed default to published so they stay visible.
+	Status ProductStatus `json:"status" gorm:"type:varchar(20);not null;default:'published';index"`
+	// PublishAt and UnpublishAt, when set, have the scheduler publish a
+	// draft or archive a published product at that time.
+	PublishAt   *time.Time `json:"publish_at,omitempty" gorm:"index"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty" gorm:"index"`
+	// DeletedAt is set while the product is in the trash; GORM leaves such
+	// products out of every query that does not ask for them.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ProductTag is one free-form label on a product.
+type ProductTag struct {
+	ProductID entity.ID `gorm:"primaryKey"`
+	Tag       string    `gorm:"primaryKey;type:varchar(50);index"`
+}
+
+// NormalizeTags lowercases and trims tags, drops duplicates and sorts them.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength || strings.Contains(tag, ",") {
+			return nil, ErrInvalidTag
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// SetTags replaces the product's tags with their normalized form.
+func (p *Product) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	p.Tags = normalized
+	return nil
+}
+
+// SetTaxClass sets the product's tax class in its normalized form.
+func (p *Product) SetTaxClass(class string) error {
+	normalized, err := NormalizeTaxClass(class)
+	if err != nil {
+		return err
+	}
+	p.TaxClass = normalized
+	return nil
 }
 
 func (p *Product) Validate() error {
@@ -33,21 +101,37 @@ func (p *Product) Validate() error {
 	if p.Name == "" {
 		return ErrNameIsRequired
 	}
-	if p.Price <= 0 {
+	if p.Price.Currency == "" {
+		return ErrPriceIsRequired
+	}
+	if err := p.Price.Validate(); err != nil {
+		return err
+	}
+	if !p.Price.IsPositive() {
 		return ErrPriceMustBePositive
 	}
-	if p.Price < 0 {
-		return ErrPriceIsRequired
+	if !taxClassPattern.MatchString(p.TaxClass) {
+		return ErrInvalidTaxClass
+	}
+	if p.WeightGrams < 0 {
+		return ErrInvalidDimensions
+	}
+	if err := p.Dimensions.Validate(); err != nil {
+		return err
 	}
 	return nil
 }
 
-func NewProduct(name, description string, price float64) (*Product, error) {
+func NewProduct(name, description string, price entity.Money) (*Product, error) {
 	product := &Product{
 		ID:          entity.NewID(),
 		Name:        name,
 		Description: description,
 		Price:       price,
+		Tags:        []string{},
+		TaxClass:    DefaultTaxClass,
+		Version:     1,
+		Status:      ProductDraft,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var ErrPriceInBaseCurrency = errors.New("the base price is set on the product itself")
+
+// ProductPrice is an explicit price for a product in a currency other
+// than its base price's. When present it wins over exchange-rate
+// conversion.
+type ProductPrice struct {
+	ProductID entity.ID `json:"-" gorm:"primaryKey"`
+	Currency  string    `json:"-" gorm:"primaryKey;type:varchar(3)"`
+	Amount    int64     `json:"-" gorm:"not null"`
+}
+
+func NewProductPrice(product *Product, price entity.Money) (*ProductPrice, error) {
+	if err := price.Validate(); err != nil {
+		return nil, err
+	}
+	if !price.IsPositive() {
+		return nil, ErrPriceMustBePositive
+	}
+	if price.Currency == product.Price.Currency {
+		return nil, ErrPriceInBaseCurrency
+	}
+	return &ProductPrice{ProductID: product.ID, Currency: price.Currency, Amount: price.Amount}, nil
+}
+
+func (p *ProductPrice) Money() entity.Money {
+	return entity.Money{Amount: p.Amount, Currency: p.Currency}
+}
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// RevisionAction is what a product revision did.
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionDeleted  RevisionAction = "deleted"
+	RevisionRestored RevisionAction = "restored"
+	RevisionReverted RevisionAction = "reverted"
+	// RevisionStatusChanged is a transition, by an editor or the scheduler.
+	RevisionStatusChanged RevisionAction = "status_changed"
+)
+
+// ProductSnapshot is a product's editable fields, status and schedule as
+// they were at one revision.
+type ProductSnapshot struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       entity.Money `json:"price"`
+	Tags        []string     `json:"tags"`
+	TaxClass    string       `json:"tax_class"`
+	WeightGrams int64        `json:"weight_grams"`
+	Dimensions  Dimensions   `json:"dimensions"`
+	// Status is empty in revisions recorded before products had one,
+	// when every product was published.
+	Status      ProductStatus `json:"status,omitempty"`
+	PublishAt   *time.Time    `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time    `json:"unpublish_at,omitempty"`
+}
+
+func NewProductSnapshot(p *Product) ProductSnapshot {
+	tags := slices.Clone(p.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return ProductSnapshot{
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Tags:        tags,
+		TaxClass:    p.TaxClass,
+		WeightGrams: p.WeightGrams,
+		Dimensions:  p.Dimensions,
+		Status:      p.Status,
+		PublishAt:   p.PublishAt,
+		UnpublishAt: p.UnpublishAt,
+	}
+}
+
+// ApplyTo sets p's editable fields to the snapshot's. Status and schedule
+// are left alone: they only change through transitions.
+func (s ProductSnapshot) ApplyTo(p *Product) {
+	p.Name = s.Name
+	p.Description = s.Description
+	p.Price = s.Price
+	p.Tags = slices.Clone(s.Tags)
+	p.TaxClass = s.TaxClass
+	p.WeightGrams = s.WeightGrams
+	p.Dimensions = s.Dimensions
+}
+
+// FieldChange is one field a revision changed, named as in the API.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// DiffProductSnapshots lists the fields that differ between before and
+// after. A nil before, for a new product, lists every field with a nil
+// Old.
+func DiffProductSnapshots(before *ProductSnapshot, after ProductSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	diff := func(field string, oldValue, newValue any, same bool) {
+		if before == nil {
+			changes = append(changes, FieldChange{Field: field, New: newValue})
+		} else if !same {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	var b ProductSnapshot
+	if before != nil {
+		b = *before
+	}
+	diff("name", b.Name, after.Name, b.Name == after.Name)
+	diff("description", b.Description, after.Description, b.Description == after.Description)
+	diff("price", b.Price, after.Price, b.Price == after.Price)
+	diff("tags", b.Tags, after.Tags, slices.Equal(b.Tags, after.Tags))
+	diff("tax_class", b.TaxClass, after.TaxClass, b.TaxClass == after.TaxClass)
+	diff("weight_grams", b.WeightGrams, after.WeightGrams, b.WeightGrams == after.WeightGrams)
+	diff("dimensions", b.Dimensions, after.Dimensions, b.Dimensions == after.Dimensions)
+	diff("status", b.Status, after.Status, b.Status == after.Status)
+	diff("publish_at", b.PublishAt, after.PublishAt, sameTime(b.PublishAt, after.PublishAt))
+	diff("unpublish_at", b.UnpublishAt, after.UnpublishAt, sameTime(b.UnpublishAt, after.UnpublishAt))
+	return changes
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ProductRevision records one change to a product: who made it, which
+// fields it changed and the product as it was afterwards. Revisions are
+// numbered from 1 per product.
+type ProductRevision struct {
+	ID        entity.ID      `json:"id"`
+	ProductID entity.ID      `json:"product_id" gorm:"not null;uniqueIndex:idx_product_revision"`
+	Revision  int64          `json:"revision" gorm:"not null;uniqueIndex:idx_product_revision"`
+	Action    RevisionAction `json:"action" gorm:"type:varchar(20);not null"`
+	// Version is the product's version after the change.
+	Version int64 `json:"version" gorm:"not null"`
+	// ActorID is the user who made the change; nil for changes the server
+	// made on its own.
+	ActorID *entity.ID `json:"actor_id,omitempty" gorm:"index"`
+	// RevertOf is the revision a revert went back to.
+	RevertOf  *int64          `json:"revert_of,omitempty"`
+	Changes   []FieldChange   `json:"changes" gorm:"serializer:json"`
+	Snapshot  ProductSnapshot `json:"snapshot" gorm:"serializer:json"`
+	CreatedAt time.Time       `json:"created_at" gorm:"index"`
+}
+
+// NewProductRevision records action on product, which was before (nil
+// for a new product) and is now as product says. The repository numbers
+// the revision.
+func NewProductRevision(product *Product, action RevisionAction, before *ProductSnapshot, actorID *entity.ID) *ProductRevision {
+	after := NewProductSnapshot(product)
+	return &ProductRevision{
+		ID:        entity.NewID(),
+		ProductID: product.ID,
+		Action:    action,
+		Version:   product.Version,
+		ActorID:   actorID,
+		Changes:   DiffProductSnapshots(before, after),
+		Snapshot:  after,
+		CreatedAt: time.Now(),
+	}
+}
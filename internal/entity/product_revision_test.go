@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProductSnapshots(t *testing.T) {
+	product, err := NewProduct("Mouse", "Wired", entity.MustParseMoney("99.90", "BRL"))
+	require.NoError(t, err)
+	before := NewProductSnapshot(product)
+
+	t.Run("should list every field for a new product", func(t *testing.T) {
+		changes := DiffProductSnapshots(nil, before)
+		require.Len(t, changes, 10)
+		assert.Equal(t, FieldChange{Field: "name", New: "Mouse"}, changes[0])
+	})
+
+	t.Run("should list only the changed fields", func(t *testing.T) {
+		product.Price = entity.MustParseMoney("79.90", "BRL")
+		require.NoError(t, product.SetTags([]string{"sale"}))
+		changes := DiffProductSnapshots(&before, NewProductSnapshot(product))
+		assert.Equal(t, []FieldChange{
+			{Field: "price", Old: entity.MustParseMoney("99.90", "BRL"), New: entity.MustParseMoney("79.90", "BRL")},
+			{Field: "tags", Old: []string{}, New: []string{"sale"}},
+		}, changes)
+	})
+
+	t.Run("should list nothing when nothing changed", func(t *testing.T) {
+		assert.Empty(t, DiffProductSnapshots(&before, before))
+	})
+}
+
+func TestProductSnapshot_ApplyTo(t *testing.T) {
+	product, err := NewProduct("Mouse", "Wired", entity.MustParseMoney("99.90", "BRL"))
+	require.NoError(t, err)
+	snapshot := NewProductSnapshot(product)
+
+	product.Name = "Trackball"
+	product.WeightGrams = 250
+	snapshot.ApplyTo(product)
+	assert.Equal(t, "Mouse", product.Name)
+	assert.Zero(t, product.WeightGrams)
+
+	revision := NewProductRevision(product, RevisionUpdated, &snapshot, nil)
+	assert.Equal(t, product.ID, revision.ProductID)
+	assert.Equal(t, product.Version, revision.Version)
+	assert.Empty(t, revision.Changes)
+}
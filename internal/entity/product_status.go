@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ProductStatus decides who sees a product: everyone sees published
+// products, only editors see drafts and archived ones.
+type ProductStatus string
+
+const (
+	ProductDraft     ProductStatus = "draft"
+	ProductPublished ProductStatus = "published"
+	ProductArchived  ProductStatus = "archived"
+)
+
+// productTransitions lists, for each status, the statuses a product may
+// move to next. Archived products go back through draft before they are
+// published again.
+var productTransitions = map[ProductStatus][]ProductStatus{
+	ProductDraft:     {ProductPublished, ProductArchived},
+	ProductPublished: {ProductDraft, ProductArchived},
+	ProductArchived:  {ProductDraft},
+}
+
+var (
+	ErrInvalidProductStatus     = errors.New("product status must be draft, published or archived")
+	ErrInvalidProductTransition = errors.New("invalid product status transition")
+	ErrInvalidSchedule          = errors.New("unpublish_at must be after publish_at")
+)
+
+// ParseProductStatus validates s as a product status.
+func ParseProductStatus(s string) (ProductStatus, error) {
+	status := ProductStatus(s)
+	if _, ok := productTransitions[status]; !ok {
+		return "", ErrInvalidProductStatus
+	}
+	return status, nil
+}
+
+// CanTransition reports whether a product in status s may move to next.
+func (s ProductStatus) CanTransition(next ProductStatus) bool {
+	return slices.Contains(productTransitions[s], next)
+}
+
+// NextStatuses lists the statuses a product in status s may move to.
+func (s ProductStatus) NextStatuses() []ProductStatus {
+	return slices.Clone(productTransitions[s])
+}
+
+// Transition moves the product to status next, or returns an error
+// wrapping ErrInvalidProductTransition. Publishing clears PublishAt and
+// archiving clears both schedule times, since they no longer apply.
+func (p *Product) Transition(next ProductStatus) error {
+	if _, ok := productTransitions[next]; !ok {
+		return ErrInvalidProductStatus
+	}
+	if !p.Status.CanTransition(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidProductTransition, p.Status, next)
+	}
+	p.Status = next
+	switch next {
+	case ProductPublished:
+		p.PublishAt = nil
+	case ProductArchived:
+		p.PublishAt, p.UnpublishAt = nil, nil
+	}
+	return nil
+}
+
+// SetSchedule sets when the scheduler publishes the product, if it is a
+// draft then, and when it archives it, if it is published then. Nil
+// times are not scheduled; the others are kept in UTC.
+func (p *Product) SetSchedule(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return ErrInvalidSchedule
+	}
+	p.PublishAt, p.UnpublishAt = utc(publishAt), utc(unpublishAt)
+	return nil
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// DueTransition returns the status the scheduler should move the product
+// to at now, if any.
+func (p *Product) DueTransition(now time.Time) (ProductStatus, bool) {
+	switch {
+	case p.Status == ProductDraft && p.PublishAt != nil && !p.PublishAt.After(now):
+		return ProductPublished, true
+	case p.Status == ProductPublished && p.UnpublishAt != nil && !p.UnpublishAt.After(now):
+		return ProductArchived, true
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductStatus(t *testing.T) {
+	newMouse := func(t *testing.T) *Product {
+		product, err := NewProduct("Mouse", "Wired", entity.MustParseMoney("99.90", "BRL"))
+		require.NoError(t, err)
+		return product
+	}
+
+	t.Run("should start new products as drafts", func(t *testing.T) {
+		assert.Equal(t, ProductDraft, newMouse(t).Status)
+	})
+
+	t.Run("should follow the transition table", func(t *testing.T) {
+		product := newMouse(t)
+		require.NoError(t, product.Transition(ProductPublished))
+		require.NoError(t, product.Transition(ProductArchived))
+		assert.ErrorIs(t, product.Transition(ProductPublished), ErrInvalidProductTransition)
+		assert.Equal(t, ProductArchived, product.Status)
+		assert.ErrorIs(t, product.Transition("deleted"), ErrInvalidProductStatus)
+
+		_, err := ParseProductStatus("hidden")
+		assert.ErrorIs(t, err, ErrInvalidProductStatus)
+		assert.Equal(t, []ProductStatus{ProductDraft}, ProductArchived.NextStatuses())
+	})
+
+	t.Run("should clear the schedule that no longer applies", func(t *testing.T) {
+		product := newMouse(t)
+		later, evenLater := time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)
+		require.NoError(t, product.SetSchedule(&later, &evenLater))
+
+		require.NoError(t, product.Transition(ProductPublished))
+		assert.Nil(t, product.PublishAt)
+		require.NotNil(t, product.UnpublishAt)
+		assert.True(t, evenLater.Equal(*product.UnpublishAt))
+
+		require.NoError(t, product.Transition(ProductArchived))
+		assert.Nil(t, product.UnpublishAt)
+	})
+
+	t.Run("should reject an unpublish time before the publish time", func(t *testing.T) {
+		product := newMouse(t)
+		now := time.Now()
+		assert.ErrorIs(t, product.SetSchedule(&now, &now), ErrInvalidSchedule)
+		assert.Nil(t, product.PublishAt)
+	})
+
+	t.Run("should say which transition is due", func(t *testing.T) {
+		product := newMouse(t)
+		now := time.Now()
+		later := now.Add(time.Hour)
+		require.NoError(t, product.SetSchedule(&now, &later))
+
+		_, due := product.DueTransition(now.Add(-time.Minute))
+		assert.False(t, due)
+		next, due := product.DueTransition(now)
+		assert.True(t, due)
+		assert.Equal(t, ProductPublished, next)
+
+		require.NoError(t, product.Transition(next))
+		_, due = product.DueTransition(now)
+		assert.False(t, due)
+		next, due = product.DueTransition(later)
+		assert.True(t, due)
+		assert.Equal(t, ProductArchived, next)
+	})
+}
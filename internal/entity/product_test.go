@@ -3,13 +3,15 @@ package entity
 import (
 	"testing"
 
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
 	"github.com/stretchr/testify/assert"
 )
 
 var (
 	name        = "Test Product"
 	description = "This is a test product"
-	price       = 10.99
+	price       = entity.MustParseMoney("10.99", "BRL")
 )
 
 func TestNewProduct(t *testing.T) {
@@ -32,8 +34,27 @@ func TestProductWhenNameIsRequired(t *testing.T) {
 }
 
 func TestProductWhenPriceIsInvalid(t *testing.T) {
-	product, err := NewProduct(name, description, -1)
+	product, err := NewProduct(name, description, entity.Money{Amount: -1, Currency: "BRL"})
 	assert.Nil(t, product)
 	assert.NotNil(t, err)
 	assert.Equal(t, ErrPriceMustBePositive, err)
 }
+
+func TestProductWhenCurrencyIsInvalid(t *testing.T) {
+	product, err := NewProduct(name, description, entity.Money{Amount: 100, Currency: "XXX"})
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, entity.ErrInvalidCurrency)
+}
+
+func TestProductSetTags(t *testing.T) {
+	product, err := NewProduct(name, description, price)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, product.Tags)
+
+	assert.Nil(t, product.SetTags([]string{" Summer", "clearance", "summer"}))
+	assert.Equal(t, []string{"clearance", "summer"}, product.Tags)
+
+	assert.Equal(t, ErrInvalidTag, product.SetTags([]string{""}))
+	assert.Equal(t, ErrInvalidTag, product.SetTags([]string{"a,b"}))
+	assert.Equal(t, []string{"clearance", "summer"}, product.Tags)
+}
@@ -0,0 +1,225 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+var (
+	ErrInvalidCouponCode   = errors.New("coupon code must be 3 to 50 letters, digits, '-' or '_'")
+	ErrInvalidDiscount     = errors.New("percent_off must be between 1 and 100, or amount_off positive")
+	ErrInvalidWindow       = errors.New("ends_at must be after starts_at")
+	ErrInvalidUsageLimit   = errors.New("usage limits cannot be negative")
+	ErrInvalidPromotion    = errors.New("buy and get quantities must be positive")
+	ErrCouponNotActive     = errors.New("coupon is not valid at this time")
+	ErrCouponMinTotal      = errors.New("order total is below the coupon minimum")
+	ErrCouponNotApplicable = errors.New("coupon does not apply to any item")
+	ErrCouponCurrency      = errors.New("coupon is in another currency")
+	ErrCouponUsedUp        = errors.New("coupon usage limit reached")
+	ErrCouponUserLimit     = errors.New("coupon already used the maximum times by this user")
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// NormalizeCouponCode uppercases and trims a code; codes are matched
+// case-insensitively.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// DiscountScope restricts a discount to some products or categories. An
+// empty scope covers every item.
+type DiscountScope struct {
+	ProductIDs  []string `json:"product_ids" gorm:"serializer:json"`
+	CategoryIDs []string `json:"category_ids" gorm:"serializer:json"`
+}
+
+// Covers reports whether the scope includes line.
+func (s DiscountScope) Covers(line DiscountLine) bool {
+	if len(s.ProductIDs) == 0 && len(s.CategoryIDs) == 0 {
+		return true
+	}
+	if slices.Contains(s.ProductIDs, line.ProductID) {
+		return true
+	}
+	for _, id := range line.CategoryIDs {
+		if slices.Contains(s.CategoryIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidityWindow bounds when a discount applies; nil ends are open.
+type ValidityWindow struct {
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+// Contains reports whether now falls in [StartsAt, EndsAt).
+func (w ValidityWindow) Contains(now time.Time) bool {
+	return (w.StartsAt == nil || !now.Before(*w.StartsAt)) && (w.EndsAt == nil || now.Before(*w.EndsAt))
+}
+
+func (w ValidityWindow) validate() error {
+	if w.StartsAt != nil && w.EndsAt != nil && !w.EndsAt.After(*w.StartsAt) {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Coupon is a discount code. Percent coupons take PercentOff percent off
+// the items in scope; fixed coupons take AmountOff spread over them. A zero
+// MinTotal or usage limit means none.
+type Coupon struct {
+	ID             entity.ID      `json:"id"`
+	Code           string         `json:"code" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Type           DiscountType   `json:"type" gorm:"type:varchar(10);not null"`
+	PercentOff     int64          `json:"percent_off,omitempty"`
+	AmountOff      entity.Money   `json:"amount_off" gorm:"embedded;embeddedPrefix:amount_off_"`
+	MinTotal       entity.Money   `json:"min_total" gorm:"embedded;embeddedPrefix:min_total_"`
+	Scope          DiscountScope  `json:"scope" gorm:"embedded"`
+	Window         ValidityWindow `json:"window" gorm:"embedded"`
+	MaxUses        int64          `json:"max_uses"`
+	MaxUsesPerUser int64          `json:"max_uses_per_user"`
+	Uses           int64          `json:"uses"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func NewPercentCoupon(code string, percentOff int64) (*Coupon, error) {
+	coupon := newCoupon(code, DiscountPercent)
+	coupon.PercentOff = percentOff
+	return coupon, coupon.Validate()
+}
+
+func NewFixedCoupon(code string, amountOff entity.Money) (*Coupon, error) {
+	coupon := newCoupon(code, DiscountFixed)
+	coupon.AmountOff = amountOff
+	return coupon, coupon.Validate()
+}
+
+func newCoupon(code string, discountType DiscountType) *Coupon {
+	return &Coupon{
+		ID:        entity.NewID(),
+		Code:      NormalizeCouponCode(code),
+		Type:      discountType,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate checks the coupon after its optional fields were set.
+func (c *Coupon) Validate() error {
+	if !couponCodePattern.MatchString(c.Code) {
+		return ErrInvalidCouponCode
+	}
+	switch c.Type {
+	case DiscountPercent:
+		if c.PercentOff < 1 || c.PercentOff > 100 {
+			return ErrInvalidDiscount
+		}
+	case DiscountFixed:
+		if c.AmountOff.Validate() != nil || !c.AmountOff.IsPositive() {
+			return ErrInvalidDiscount
+		}
+	default:
+		return ErrInvalidDiscount
+	}
+	if c.MinTotal != (entity.Money{}) {
+		if err := c.MinTotal.Validate(); err != nil || c.MinTotal.IsNegative() {
+			return ErrInvalidDiscount
+		}
+	}
+	if c.MaxUses < 0 || c.MaxUsesPerUser < 0 {
+		return ErrInvalidUsageLimit
+	}
+	return c.Window.validate()
+}
+
+// CheckUsage reports whether the coupon may be used again by a user who
+// already used it userUses times.
+func (c *Coupon) CheckUsage(userUses int64) error {
+	if c.MaxUses > 0 && c.Uses >= c.MaxUses {
+		return ErrCouponUsedUp
+	}
+	if c.MaxUsesPerUser > 0 && userUses >= c.MaxUsesPerUser {
+		return ErrCouponUserLimit
+	}
+	return nil
+}
+
+// CouponRedemption records one use of a coupon by an order.
+type CouponRedemption struct {
+	ID        entity.ID `json:"id"`
+	CouponID  entity.ID `json:"coupon_id" gorm:"index:idx_redemption_user;not null"`
+	UserID    entity.ID `json:"user_id" gorm:"index:idx_redemption_user;not null"`
+	OrderID   entity.ID `json:"order_id" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CouponUsage counts how many times a user has used a coupon. Redeeming
+// bumps it with a conditional update, so the per-user limit holds even
+// when the same user places orders concurrently.
+type CouponUsage struct {
+	CouponID entity.ID `json:"coupon_id" gorm:"primaryKey"`
+	UserID   entity.ID `json:"user_id" gorm:"primaryKey"`
+	Uses     int64     `json:"uses" gorm:"not null;default:0"`
+}
+
+// Promotion is an automatic "buy BuyQuantity, get GetQuantity" offer: in
+// every group of BuyQuantity+GetQuantity units in scope, the GetQuantity
+// cheapest get PercentOff percent off (100 makes them free).
+type Promotion struct {
+	ID          entity.ID      `json:"id"`
+	Name        string         `json:"name" gorm:"not null"`
+	BuyQuantity int64          `json:"buy_quantity" gorm:"not null"`
+	GetQuantity int64          `json:"get_quantity" gorm:"not null"`
+	PercentOff  int64          `json:"percent_off" gorm:"not null"`
+	Scope       DiscountScope  `json:"scope" gorm:"embedded"`
+	Window      ValidityWindow `json:"window" gorm:"embedded"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// NewBuyXGetY creates a promotion; percentOff 0 means the Y units are free.
+func NewBuyXGetY(name string, buy, get, percentOff int64) (*Promotion, error) {
+	if percentOff == 0 {
+		percentOff = 100
+	}
+	promotion := &Promotion{
+		ID:          entity.NewID(),
+		Name:        strings.TrimSpace(name),
+		BuyQuantity: buy,
+		GetQuantity: get,
+		PercentOff:  percentOff,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	return promotion, promotion.Validate()
+}
+
+func (p *Promotion) Validate() error {
+	if p.Name == "" {
+		return ErrNameIsRequired
+	}
+	if p.BuyQuantity < 1 || p.GetQuantity < 1 || p.BuyQuantity+p.GetQuantity > MaxItemQuantity {
+		return ErrInvalidPromotion
+	}
+	if p.PercentOff < 1 || p.PercentOff > 100 {
+		return ErrInvalidDiscount
+	}
+	return p.Window.validate()
+}
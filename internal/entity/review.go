@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// ReviewStatus is where a review is in moderation. Only approved reviews
+// are shown on the product or counted in its rating.
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+
+	maxReviewTitle = 120
+	maxReviewBody  = 5000
+	maxReviewNote  = 500
+)
+
+var (
+	ErrInvalidRating       = errors.New("rating must be between 1 and 5")
+	ErrReviewIncomplete    = errors.New("a review needs a title")
+	ErrReviewTooLong       = errors.New("review title, body or moderation note too long")
+	ErrInvalidReviewStatus = errors.New("review status must be pending, approved or rejected")
+	ErrInvalidModeration   = errors.New("a review can only be approved or rejected")
+)
+
+// ParseReviewStatus validates s as a review status.
+func ParseReviewStatus(s string) (ReviewStatus, error) {
+	switch status := ReviewStatus(s); status {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+		return status, nil
+	}
+	return "", ErrInvalidReviewStatus
+}
+
+// Review is one user's opinion of a product. A user reviews a product at
+// most once; editing the review sends it back to moderation.
+type Review struct {
+	ID        entity.ID `json:"id"`
+	ProductID entity.ID `json:"product_id" gorm:"not null;uniqueIndex:idx_reviews_product_user"`
+	UserID    entity.ID `json:"user_id" gorm:"not null;uniqueIndex:idx_reviews_product_user"`
+	Rating    int       `json:"rating" gorm:"not null"`
+	Title     string    `json:"title" gorm:"type:varchar(120);not null"`
+	Body      string    `json:"body,omitempty" gorm:"type:text"`
+	// VerifiedPurchase says the author had a paid order for the product
+	// when the review was written.
+	VerifiedPurchase bool         `json:"verified_purchase" gorm:"not null;default:false"`
+	Status           ReviewStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	// ModerationNote tells the author why a review was rejected.
+	ModerationNote string     `json:"moderation_note,omitempty" gorm:"type:varchar(500)"`
+	ModeratedBy    *entity.ID `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// NewReview creates a review waiting for moderation.
+func NewReview(productID, userID entity.ID, rating int, title, body string) (*Review, error) {
+	review := &Review{
+		ID:        entity.NewID(),
+		ProductID: productID,
+		UserID:    userID,
+		Status:    ReviewPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := review.Edit(rating, title, body); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Edit replaces the rating, title and body and sends the review back to
+// moderation.
+func (r *Review) Edit(rating int, title, body string) error {
+	title, body = strings.TrimSpace(title), strings.TrimSpace(body)
+	if rating < MinRating || rating > MaxRating {
+		return ErrInvalidRating
+	}
+	if title == "" {
+		return ErrReviewIncomplete
+	}
+	if utf8.RuneCountInString(title) > maxReviewTitle || utf8.RuneCountInString(body) > maxReviewBody {
+		return ErrReviewTooLong
+	}
+	r.Rating, r.Title, r.Body = rating, title, body
+	r.Status = ReviewPending
+	r.ModerationNote, r.ModeratedBy, r.ModeratedAt = "", nil, nil
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Moderate approves or rejects the review on behalf of moderatorID. An
+// approved review may still be rejected later, and the other way round.
+func (r *Review) Moderate(status ReviewStatus, moderatorID entity.ID, note string) error {
+	if status != ReviewApproved && status != ReviewRejected {
+		return ErrInvalidModeration
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxReviewNote {
+		return ErrReviewTooLong
+	}
+	now := time.Now()
+	r.Status = status
+	r.ModerationNote = note
+	r.ModeratedBy = &moderatorID
+	r.ModeratedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// RatingSummary aggregates a product's approved reviews. Distribution has
+// an entry for every star count, zero included.
+type RatingSummary struct {
+	Average      float64       `json:"average"`
+	Count        int64         `json:"count"`
+	Distribution map[int]int64 `json:"distribution"`
+}
+
+// NewRatingSummary builds the summary from how many reviews gave each
+// rating. The average is rounded to two decimals and is zero when there
+// are no reviews.
+func NewRatingSummary(counts map[int]int64) RatingSummary {
+	summary := RatingSummary{Distribution: make(map[int]int64, MaxRating)}
+	var total int64
+	for rating := MinRating; rating <= MaxRating; rating++ {
+		n := counts[rating]
+		summary.Distribution[rating] = n
+		summary.Count += n
+		total += int64(rating) * n
+	}
+	if summary.Count > 0 {
+		summary.Average = math.Round(float64(total)/float64(summary.Count)*100) / 100
+	}
+	return summary
+}
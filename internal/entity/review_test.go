@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReview(t *testing.T) {
+	t.Run("should start pending with trimmed text", func(t *testing.T) {
+		review, err := NewReview(entity.NewID(), entity.NewID(), 4, "  Muito bom ", " Chegou rápido. ")
+		require.NoError(t, err)
+		assert.Equal(t, ReviewPending, review.Status)
+		assert.Equal(t, "Muito bom", review.Title)
+		assert.Equal(t, "Chegou rápido.", review.Body)
+	})
+
+	t.Run("should reject invalid reviews", func(t *testing.T) {
+		for _, tc := range []struct {
+			rating      int
+			title, body string
+			err         error
+		}{
+			{0, "ok", "", ErrInvalidRating},
+			{6, "ok", "", ErrInvalidRating},
+			{3, "  ", "", ErrReviewIncomplete},
+			{3, strings.Repeat("a", 121), "", ErrReviewTooLong},
+			{3, "ok", strings.Repeat("a", 5001), ErrReviewTooLong},
+		} {
+			_, err := NewReview(entity.NewID(), entity.NewID(), tc.rating, tc.title, tc.body)
+			assert.ErrorIs(t, err, tc.err, tc)
+		}
+	})
+}
+
+func TestReview_Moderate(t *testing.T) {
+	review, err := NewReview(entity.NewID(), entity.NewID(), 5, "Ótimo", "")
+	require.NoError(t, err)
+	admin := entity.NewID()
+
+	t.Run("should only approve or reject", func(t *testing.T) {
+		assert.ErrorIs(t, review.Moderate(ReviewPending, admin, ""), ErrInvalidModeration)
+	})
+
+	t.Run("should record who moderated and why", func(t *testing.T) {
+		require.NoError(t, review.Moderate(ReviewRejected, admin, " linguagem ofensiva "))
+		assert.Equal(t, ReviewRejected, review.Status)
+		assert.Equal(t, "linguagem ofensiva", review.ModerationNote)
+		require.NotNil(t, review.ModeratedBy)
+		assert.Equal(t, admin, *review.ModeratedBy)
+		assert.NotNil(t, review.ModeratedAt)
+	})
+
+	t.Run("should go back to moderation when edited", func(t *testing.T) {
+		require.NoError(t, review.Edit(4, "Bom", "Revisado"))
+		assert.Equal(t, ReviewPending, review.Status)
+		assert.Empty(t, review.ModerationNote)
+		assert.Nil(t, review.ModeratedBy)
+		assert.Nil(t, review.ModeratedAt)
+	})
+}
+
+func TestNewRatingSummary(t *testing.T) {
+	t.Run("should average and fill in every star count", func(t *testing.T) {
+		summary := NewRatingSummary(map[int]int64{5: 2, 4: 1})
+		assert.Equal(t, int64(3), summary.Count)
+		assert.Equal(t, 4.67, summary.Average)
+		assert.Equal(t, map[int]int64{1: 0, 2: 0, 3: 0, 4: 1, 5: 2}, summary.Distribution)
+	})
+
+	t.Run("should be zero without reviews", func(t *testing.T) {
+		summary := NewRatingSummary(nil)
+		assert.Zero(t, summary.Count)
+		assert.Zero(t, summary.Average)
+		assert.Len(t, summary.Distribution, 5)
+	})
+}
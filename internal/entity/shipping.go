@@ -0,0 +1,373 @@
+package entity
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// ShippingMethodKind says how a shipping method works out its cost.
+type ShippingMethodKind string
+
+const (
+	// ShippingFlat charges Cost whatever the parcel.
+	ShippingFlat ShippingMethodKind = "flat"
+	// ShippingWeight charges the first tier the parcel's weight fits in.
+	ShippingWeight ShippingMethodKind = "weight"
+	// ShippingFreeOver is free, but only offered from Threshold up.
+	ShippingFreeOver ShippingMethodKind = "free_over"
+)
+
+var (
+	ErrInvalidDimensions   = errors.New("weight and dimensions cannot be negative")
+	ErrInvalidShippingKind = errors.New("shipping method kind must be flat, weight or free_over")
+	ErrInvalidShippingCost = errors.New("shipping costs cannot be negative and must share one currency")
+	ErrInvalidWeightTiers  = errors.New("weight tiers need positive, increasing limits")
+	ErrInvalidShippingZone = errors.New("a shipping zone needs a name and at least one area")
+	ErrInvalidPostalPrefix = errors.New("postal prefixes are 1 to 10 letters or digits")
+	ErrDestinationRequired = errors.New("a destination country is required for shipping")
+	ErrShippingUnavailable = errors.New("shipping method not available for this destination")
+)
+
+const maxPostalPrefix = 10
+
+// Dimensions are a product's packed size in millimetres.
+type Dimensions struct {
+	LengthMM int64 `json:"length_mm" gorm:"not null;default:0"`
+	WidthMM  int64 `json:"width_mm" gorm:"not null;default:0"`
+	HeightMM int64 `json:"height_mm" gorm:"not null;default:0"`
+}
+
+func (d Dimensions) Validate() error {
+	if d.LengthMM < 0 || d.WidthMM < 0 || d.HeightMM < 0 {
+		return ErrInvalidDimensions
+	}
+	return nil
+}
+
+// VolumeMM3 is the box's volume in cubic millimetres.
+func (d Dimensions) VolumeMM3() int64 {
+	return d.LengthMM * d.WidthMM * d.HeightMM
+}
+
+// ShippingDestination is where a parcel goes. PostalCode is kept without
+// spaces or dashes so that prefixes compare simply.
+type ShippingDestination struct {
+	Country    string
+	Region     string
+	PostalCode string
+}
+
+// NewShippingDestination validates and normalizes a destination. Unlike a
+// tax location, it needs a country.
+func NewShippingDestination(country, region, postalCode string) (ShippingDestination, error) {
+	if strings.TrimSpace(country) == "" {
+		return ShippingDestination{}, ErrDestinationRequired
+	}
+	loc, err := NewTaxLocation(country, region)
+	if err != nil {
+		return ShippingDestination{}, err
+	}
+	return ShippingDestination{Country: loc.Country, Region: loc.Region, PostalCode: normalizePostalCode(postalCode)}, nil
+}
+
+func normalizePostalCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// ShippingArea is one place a zone covers: a country, optionally narrowed
+// to a region and to postal codes starting with PostalPrefix.
+type ShippingArea struct {
+	Country      string `json:"country"`
+	Region       string `json:"region,omitempty"`
+	PostalPrefix string `json:"postal_prefix,omitempty"`
+}
+
+func (a ShippingArea) normalize() (ShippingArea, error) {
+	loc, err := NewTaxLocation(a.Country, a.Region)
+	if err != nil {
+		return ShippingArea{}, err
+	}
+	if loc.Country == "" {
+		return ShippingArea{}, ErrInvalidCountry
+	}
+	prefix := normalizePostalCode(a.PostalPrefix)
+	if len(prefix) > maxPostalPrefix || strings.ContainsFunc(prefix, func(r rune) bool {
+		return (r < '0' || r > '9') && (r < 'A' || r > 'Z')
+	}) {
+		return ShippingArea{}, ErrInvalidPostalPrefix
+	}
+	return ShippingArea{Country: loc.Country, Region: loc.Region, PostalPrefix: prefix}, nil
+}
+
+// match scores how closely the area covers dest: 0 when it does not, and
+// more for a region and for longer postal prefixes.
+func (a ShippingArea) match(dest ShippingDestination) int {
+	if a.Country != dest.Country {
+		return 0
+	}
+	score := 1
+	if a.Region != "" {
+		if a.Region != dest.Region {
+			return 0
+		}
+		score++
+	}
+	if a.PostalPrefix != "" {
+		if !strings.HasPrefix(dest.PostalCode, a.PostalPrefix) {
+			return 0
+		}
+		score += 1 + len(a.PostalPrefix)
+	}
+	return score
+}
+
+// ShippingZone groups the places that share shipping methods. Methods live
+// in the shipping_methods table; the repository loads them.
+type ShippingZone struct {
+	ID        entity.ID         `json:"id"`
+	Name      string            `json:"name" gorm:"not null"`
+	Areas     []ShippingArea    `json:"areas" gorm:"serializer:json"`
+	Methods   []*ShippingMethod `json:"methods" gorm:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func NewShippingZone(name string, areas []ShippingArea) (*ShippingZone, error) {
+	zone := &ShippingZone{
+		ID:        entity.NewID(),
+		Name:      strings.TrimSpace(name),
+		Areas:     make([]ShippingArea, 0, len(areas)),
+		Methods:   []*ShippingMethod{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	for _, area := range areas {
+		normalized, err := area.normalize()
+		if err != nil {
+			return nil, err
+		}
+		zone.Areas = append(zone.Areas, normalized)
+	}
+	if err := zone.Validate(); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (z *ShippingZone) Validate() error {
+	if z.Name == "" || len(z.Areas) == 0 {
+		return ErrInvalidShippingZone
+	}
+	return nil
+}
+
+// Match is the score of the zone's closest area to dest, 0 if none covers it.
+func (z *ShippingZone) Match(dest ShippingDestination) int {
+	best := 0
+	for _, area := range z.Areas {
+		best = max(best, area.match(dest))
+	}
+	return best
+}
+
+// MatchShippingZone picks the zone that covers dest most closely, the
+// oldest on a tie, or nil when none does.
+func MatchShippingZone(zones []*ShippingZone, dest ShippingDestination) *ShippingZone {
+	var found *ShippingZone
+	best := 0
+	for _, zone := range zones {
+		score := zone.Match(dest)
+		if score == 0 {
+			continue
+		}
+		if found == nil || score > best || score == best && olderZone(zone, found) {
+			found, best = zone, score
+		}
+	}
+	return found
+}
+
+func olderZone(a, b *ShippingZone) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// WeightTier is the cost of parcels up to UpToGrams.
+type WeightTier struct {
+	UpToGrams int64        `json:"up_to_grams"`
+	Cost      entity.Money `json:"cost"`
+}
+
+// ShippingMethod is one way to ship to a zone. Kind decides which of Cost,
+// Tiers and Threshold applies.
+type ShippingMethod struct {
+	ID     entity.ID          `json:"id"`
+	ZoneID entity.ID          `json:"zone_id" gorm:"index;not null"`
+	Name   string             `json:"name" gorm:"not null"`
+	Kind   ShippingMethodKind `json:"kind" gorm:"type:varchar(20);not null"`
+	Cost   entity.Money       `json:"cost" gorm:"embedded;embeddedPrefix:cost_"`
+	Tiers  []WeightTier       `json:"tiers,omitempty" gorm:"serializer:json"`
+	// Threshold is the least the goods must cost for a free_over method.
+	Threshold entity.Money `json:"threshold" gorm:"embedded;embeddedPrefix:threshold_"`
+	// VolumetricDivisor turns a weight method's parcel size into grams, as
+	// cubic millimetres per gram; 0 charges by actual weight only.
+	VolumetricDivisor int64     `json:"volumetric_divisor,omitempty" gorm:"not null;default:0"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NewFlatShipping creates a method charging cost for any parcel.
+func NewFlatShipping(zoneID entity.ID, name string, cost entity.Money) (*ShippingMethod, error) {
+	return newShippingMethod(&ShippingMethod{ZoneID: zoneID, Name: name, Kind: ShippingFlat, Cost: cost, Threshold: entity.Money{Currency: cost.Currency}})
+}
+
+// NewWeightShipping creates a method charging by the parcel's weight.
+// volumetricDivisor may be 0.
+func NewWeightShipping(zoneID entity.ID, name string, tiers []WeightTier, volumetricDivisor int64) (*ShippingMethod, error) {
+	method := &ShippingMethod{ZoneID: zoneID, Name: name, Kind: ShippingWeight, Tiers: tiers, VolumetricDivisor: volumetricDivisor}
+	if len(tiers) > 0 {
+		method.Cost = entity.Money{Currency: tiers[0].Cost.Currency}
+		method.Threshold = entity.Money{Currency: tiers[0].Cost.Currency}
+	}
+	return newShippingMethod(method)
+}
+
+// NewFreeShipping creates a free method offered for goods costing at
+// least threshold.
+func NewFreeShipping(zoneID entity.ID, name string, threshold entity.Money) (*ShippingMethod, error) {
+	return newShippingMethod(&ShippingMethod{ZoneID: zoneID, Name: name, Kind: ShippingFreeOver, Cost: entity.Money{Currency: threshold.Currency}, Threshold: threshold})
+}
+
+func newShippingMethod(method *ShippingMethod) (*ShippingMethod, error) {
+	method.ID = entity.NewID()
+	method.Name = strings.TrimSpace(method.Name)
+	method.CreatedAt = time.Now()
+	if err := method.Validate(); err != nil {
+		return nil, err
+	}
+	return method, nil
+}
+
+func (m *ShippingMethod) Validate() error {
+	if m.Name == "" {
+		return ErrNameIsRequired
+	}
+	if m.Cost.Currency == "" || m.Cost.Validate() != nil || m.Cost.IsNegative() ||
+		m.Threshold.Validate() != nil || m.Threshold.IsNegative() || m.Threshold.Currency != m.Cost.Currency {
+		return ErrInvalidShippingCost
+	}
+	switch m.Kind {
+	case ShippingFlat, ShippingFreeOver:
+		if len(m.Tiers) > 0 || m.VolumetricDivisor != 0 {
+			return ErrInvalidWeightTiers
+		}
+	case ShippingWeight:
+		if len(m.Tiers) == 0 || m.VolumetricDivisor < 0 {
+			return ErrInvalidWeightTiers
+		}
+		var last int64
+		for _, tier := range m.Tiers {
+			if tier.UpToGrams <= last {
+				return ErrInvalidWeightTiers
+			}
+			if tier.Cost.Validate() != nil || tier.Cost.IsNegative() || tier.Cost.Currency != m.Cost.Currency {
+				return ErrInvalidShippingCost
+			}
+			last = tier.UpToGrams
+		}
+	default:
+		return ErrInvalidShippingKind
+	}
+	return nil
+}
+
+// ShippingItem is what a shipping method needs to know about one line.
+type ShippingItem struct {
+	WeightGrams int64
+	Dimensions  Dimensions
+	Quantity    int64
+}
+
+// ChargeableWeight is the parcel's weight in grams, counting each unit at
+// its volumetric weight when that is more than its actual weight.
+func (m *ShippingMethod) ChargeableWeight(items []ShippingItem) int64 {
+	var total int64
+	for _, item := range items {
+		weight := item.WeightGrams
+		if m.VolumetricDivisor > 0 {
+			volumetric := item.Dimensions.VolumeMM3() / m.VolumetricDivisor
+			weight = max(weight, volumetric)
+		}
+		total += weight * item.Quantity
+	}
+	return total
+}
+
+// Quote prices the parcel, reporting false when the method does not apply:
+// subtotal is in another currency, is below a free_over threshold, or the
+// parcel is heavier than the last weight tier.
+func (m *ShippingMethod) Quote(items []ShippingItem, subtotal entity.Money) (entity.Money, bool) {
+	if subtotal.Currency != m.Cost.Currency {
+		return entity.Money{}, false
+	}
+	switch m.Kind {
+	case ShippingFlat:
+		return m.Cost, true
+	case ShippingFreeOver:
+		if subtotal.Amount < m.Threshold.Amount {
+			return entity.Money{}, false
+		}
+		return entity.Money{Currency: m.Cost.Currency}, true
+	case ShippingWeight:
+		weight := m.ChargeableWeight(items)
+		for _, tier := range m.Tiers {
+			if weight <= tier.UpToGrams {
+				return tier.Cost, true
+			}
+		}
+	}
+	return entity.Money{}, false
+}
+
+// ShippingRate is one priced option for a parcel.
+type ShippingRate struct {
+	MethodID entity.ID
+	ZoneID   entity.ID
+	ZoneName string
+	Name     string
+	Kind     ShippingMethodKind
+	Cost     entity.Money
+}
+
+// QuoteShipping prices the parcel with every method of zone that applies,
+// cheapest first.
+func QuoteShipping(zone *ShippingZone, items []ShippingItem, subtotal entity.Money) []ShippingRate {
+	rates := []ShippingRate{}
+	if zone == nil {
+		return rates
+	}
+	for _, method := range zone.Methods {
+		cost, ok := method.Quote(items, subtotal)
+		if !ok {
+			continue
+		}
+		rates = append(rates, ShippingRate{
+			MethodID: method.ID,
+			ZoneID:   zone.ID,
+			ZoneName: zone.Name,
+			Name:     method.Name,
+			Kind:     method.Kind,
+			Cost:     cost,
+		})
+	}
+	slices.SortStableFunc(rates, func(a, b ShippingRate) int {
+		return cmp.Or(cmp.Compare(a.Cost.Amount, b.Cost.Amount), strings.Compare(a.Name, b.Name))
+	})
+	return rates
+}
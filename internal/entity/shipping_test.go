@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShippingZone(t *testing.T) {
+	t.Run("should normalize areas", func(t *testing.T) {
+		zone, err := NewShippingZone(" Sul ", []ShippingArea{{Country: "br", Region: "rs", PostalPrefix: "90-0"}})
+		require.NoError(t, err)
+		assert.Equal(t, "Sul", zone.Name)
+		assert.Equal(t, ShippingArea{Country: "BR", Region: "RS", PostalPrefix: "900"}, zone.Areas[0])
+	})
+
+	t.Run("should reject zones without areas or with bad ones", func(t *testing.T) {
+		_, err := NewShippingZone("Empty", nil)
+		assert.ErrorIs(t, err, ErrInvalidShippingZone)
+		_, err = NewShippingZone("Nowhere", []ShippingArea{{Region: "SP"}})
+		assert.ErrorIs(t, err, ErrInvalidRegion)
+		_, err = NewShippingZone("Odd", []ShippingArea{{Country: "BR", PostalPrefix: "01*"}})
+		assert.ErrorIs(t, err, ErrInvalidPostalPrefix)
+	})
+}
+
+func TestMatchShippingZone(t *testing.T) {
+	country, err := NewShippingZone("Brasil", []ShippingArea{{Country: "BR"}})
+	require.NoError(t, err)
+	region, err := NewShippingZone("São Paulo", []ShippingArea{{Country: "BR", Region: "SP"}})
+	require.NoError(t, err)
+	postal, err := NewShippingZone("Centro", []ShippingArea{{Country: "BR", PostalPrefix: "010"}})
+	require.NoError(t, err)
+	zones := []*ShippingZone{country, region, postal}
+
+	for _, tc := range []struct {
+		country, region, postal string
+		want                    *ShippingZone
+	}{
+		{"BR", "SP", "01001-000", postal},
+		{"BR", "SP", "13000-000", region},
+		{"BR", "MG", "30000-000", country},
+		{"AR", "", "", nil},
+	} {
+		dest, err := NewShippingDestination(tc.country, tc.region, tc.postal)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, MatchShippingZone(zones, dest), tc)
+	}
+
+	_, err = NewShippingDestination("", "SP", "")
+	assert.ErrorIs(t, err, ErrDestinationRequired)
+}
+
+func TestShippingMethod_Quote(t *testing.T) {
+	zoneID := entity.NewID()
+
+	t.Run("should charge the weight tier the parcel fits in", func(t *testing.T) {
+		method, err := NewWeightShipping(zoneID, "Correio", []WeightTier{
+			{UpToGrams: 500, Cost: brl("12")},
+			{UpToGrams: 2000, Cost: brl("20")},
+		}, 0)
+		require.NoError(t, err)
+
+		cost, ok := method.Quote([]ShippingItem{{WeightGrams: 250, Quantity: 2}}, brl("10"))
+		require.True(t, ok)
+		assert.Equal(t, brl("12"), cost)
+		cost, ok = method.Quote([]ShippingItem{{WeightGrams: 250, Quantity: 3}}, brl("10"))
+		require.True(t, ok)
+		assert.Equal(t, brl("20"), cost)
+		_, ok = method.Quote([]ShippingItem{{WeightGrams: 2001, Quantity: 1}}, brl("10"))
+		assert.False(t, ok)
+		_, ok = method.Quote(nil, entity.MustParseMoney("10", "USD"))
+		assert.False(t, ok)
+	})
+
+	t.Run("should charge light, bulky parcels by volume", func(t *testing.T) {
+		method, err := NewWeightShipping(zoneID, "Expresso", []WeightTier{
+			{UpToGrams: 1000, Cost: brl("15")},
+			{UpToGrams: 10000, Cost: brl("40")},
+		}, 6000)
+		require.NoError(t, err)
+		// 300 x 200 x 200 mm is 12,000,000 mm³, or 2,000 g at 6,000 mm³/g.
+		pillow := ShippingItem{WeightGrams: 400, Dimensions: Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 200}, Quantity: 1}
+		assert.Equal(t, int64(2000), method.ChargeableWeight([]ShippingItem{pillow}))
+		cost, ok := method.Quote([]ShippingItem{pillow}, brl("10"))
+		require.True(t, ok)
+		assert.Equal(t, brl("40"), cost)
+	})
+
+	t.Run("should reject malformed methods", func(t *testing.T) {
+		_, err := NewWeightShipping(zoneID, "Bad", []WeightTier{{UpToGrams: 500, Cost: brl("5")}, {UpToGrams: 500, Cost: brl("6")}}, 0)
+		assert.ErrorIs(t, err, ErrInvalidWeightTiers)
+		_, err = NewWeightShipping(zoneID, "Mixed", []WeightTier{{UpToGrams: 500, Cost: brl("5")}, {UpToGrams: 900, Cost: entity.MustParseMoney("6", "USD")}}, 0)
+		assert.ErrorIs(t, err, ErrInvalidShippingCost)
+		_, err = NewFlatShipping(zoneID, "Negative", brl("-1"))
+		assert.ErrorIs(t, err, ErrInvalidShippingCost)
+		_, err = NewFlatShipping(zoneID, "", brl("1"))
+		assert.ErrorIs(t, err, ErrNameIsRequired)
+	})
+}
+
+func TestOrder_ApplyShipping(t *testing.T) {
+	lamp, err := NewProduct("Lamp", "", brl("100"))
+	require.NoError(t, err)
+	lamp.WeightGrams = 1200
+	item, err := NewOrderItem(lamp, nil, 1)
+	require.NoError(t, err)
+	order, err := NewOrder(entity.NewID(), []*OrderItem{item})
+	require.NoError(t, err)
+	assert.Equal(t, []ShippingItem{{WeightGrams: 1200, Quantity: 1}}, order.ShippingItems())
+
+	rate := &ShippingRate{MethodID: entity.NewID(), Name: "Standard", Cost: brl("15")}
+	order.ApplyShipping(rate)
+	assert.Equal(t, brl("115"), order.Total)
+	assert.Equal(t, "Standard", order.ShippingMethod)
+
+	order.ApplyShipping(nil)
+	assert.Equal(t, brl("100"), order.Total)
+	assert.Nil(t, order.ShippingMethodID)
+}
@@ -0,0 +1,330 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// DefaultTaxClass is the tax class of products created without one.
+const DefaultTaxClass = "standard"
+
+var (
+	ErrInvalidTaxRate   = errors.New("tax rate must be a decimal percentage from 0 to 100")
+	ErrInvalidTaxClass  = errors.New("tax class must be 1 to 30 lowercase letters, digits, '-' or '_'")
+	ErrInvalidCountry   = errors.New("country must be a two-letter ISO 3166-1 code")
+	ErrInvalidRegion    = errors.New("region must be 1 to 10 letters, digits or '-', with a country")
+	ErrInvalidRounding  = errors.New("tax rounding must be half_up, half_even or down")
+	ErrDuplicateTaxRate = errors.New("duplicate tax rate")
+)
+
+var (
+	taxClassPattern = regexp.MustCompile(`^[a-z0-9_-]{1,30}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	regionPattern   = regexp.MustCompile(`^[A-Z0-9-]{1,10}$`)
+)
+
+// NormalizeTaxClass lowercases and trims class and checks its format.
+func NormalizeTaxClass(class string) (string, error) {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if !taxClassPattern.MatchString(class) {
+		return "", ErrInvalidTaxClass
+	}
+	return class, nil
+}
+
+// TaxLocation is where goods are taxed. Region is a state or province
+// code within Country and may be empty.
+type TaxLocation struct {
+	Country string `json:"country"`
+	Region  string `json:"region"`
+}
+
+// NewTaxLocation upper-cases and validates a location. Both parts may be
+// empty, which means no location: nothing is taxed.
+func NewTaxLocation(country, region string) (TaxLocation, error) {
+	loc := TaxLocation{
+		Country: strings.ToUpper(strings.TrimSpace(country)),
+		Region:  strings.ToUpper(strings.TrimSpace(region)),
+	}
+	if loc.Country != "" && !countryPattern.MatchString(loc.Country) {
+		return TaxLocation{}, ErrInvalidCountry
+	}
+	if loc.Region != "" && (loc.Country == "" || !regionPattern.MatchString(loc.Region)) {
+		return TaxLocation{}, ErrInvalidRegion
+	}
+	return loc, nil
+}
+
+// TaxRate is the percentage charged on TaxClass goods in Country or, when
+// Region is set, only in that region of it. Rate is kept as the decimal
+// string it was given in, like ExchangeRate.Rate.
+type TaxRate struct {
+	Country   string    `json:"country" gorm:"primaryKey;type:varchar(2)"`
+	Region    string    `json:"region" gorm:"primaryKey;type:varchar(10)"`
+	TaxClass  string    `json:"tax_class" gorm:"primaryKey;type:varchar(30)"`
+	Rate      string    `json:"rate" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewTaxRate(country, region, taxClass, rate string) (*TaxRate, error) {
+	loc, err := NewTaxLocation(country, region)
+	if err != nil {
+		return nil, err
+	}
+	taxClass, err = NormalizeTaxClass(taxClass)
+	if err != nil {
+		return nil, err
+	}
+	r := &TaxRate{
+		Country:   loc.Country,
+		Region:    loc.Region,
+		TaxClass:  taxClass,
+		Rate:      strings.TrimSpace(rate),
+		UpdatedAt: time.Now(),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ParseTaxRates normalizes rows, as sent to the admin endpoint or read
+// from the rates file, and rejects two rows for the same place and class.
+func ParseTaxRates(rows []TaxRate) ([]*TaxRate, error) {
+	result := make([]*TaxRate, 0, len(rows))
+	seen := make(map[taxKey]bool, len(rows))
+	for _, row := range rows {
+		r, err := NewTaxRate(row.Country, row.Region, row.TaxClass, row.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s/%s: %w", row.Country, row.Region, row.TaxClass, err)
+		}
+		key := r.key()
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s/%s/%s", ErrDuplicateTaxRate, r.Country, r.Region, r.TaxClass)
+		}
+		seen[key] = true
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// ReadTaxRates parses a rates file:
+// {"rates": [{"country": "BR", "region": "SP", "tax_class": "standard", "rate": "18"}, ...]},
+// the same shape the admin endpoint accepts.
+func ReadTaxRates(r io.Reader) ([]*TaxRate, error) {
+	var file struct {
+		Rates []TaxRate `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	return ParseTaxRates(file.Rates)
+}
+
+func (r *TaxRate) Validate() error {
+	if r.Country == "" {
+		return ErrInvalidCountry
+	}
+	if _, err := NewTaxLocation(r.Country, r.Region); err != nil {
+		return err
+	}
+	if !taxClassPattern.MatchString(r.TaxClass) {
+		return ErrInvalidTaxClass
+	}
+	if _, err := r.percent(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *TaxRate) percent() (*big.Rat, error) {
+	percent, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || percent.Sign() < 0 || percent.Cmp(big.NewRat(100, 1)) > 0 || strings.ContainsAny(r.Rate, "/eE") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTaxRate, r.Rate)
+	}
+	return percent, nil
+}
+
+type taxKey struct {
+	country, region, class string
+}
+
+func (r *TaxRate) key() taxKey {
+	return taxKey{r.Country, r.Region, r.TaxClass}
+}
+
+// RoundingMode says how a line's tax is rounded to whole minor units.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero, like the rest of the API.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the even unit (banker's rounding).
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown drops the fraction.
+	RoundDown RoundingMode = "down"
+)
+
+// ParseRoundingMode validates s; an empty s is RoundHalfUp.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return RoundHalfUp, nil
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+		return mode, nil
+	default:
+		return "", ErrInvalidRounding
+	}
+}
+
+// round rounds a non-negative r to an integer.
+func (m RoundingMode) round(r *big.Rat) int64 {
+	switch m {
+	case RoundDown:
+		return new(big.Int).Quo(r.Num(), r.Denom()).Int64()
+	case RoundHalfEven:
+		quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+		switch new(big.Int).Mul(rem, big.NewInt(2)).Cmp(r.Denom()) {
+		case 1:
+			quo.Add(quo, big.NewInt(1))
+		case 0:
+			if quo.Bit(0) == 1 {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+		return quo.Int64()
+	default:
+		return entity.RoundRat(r, 0)
+	}
+}
+
+// TaxSettings are the store-wide tax rules. With PricesIncludeTax, catalog
+// prices already contain the tax and it is worked out of them; otherwise
+// it is added on top. DefaultLocation is used when the shopper gives none.
+type TaxSettings struct {
+	PricesIncludeTax bool
+	Rounding         RoundingMode
+	DefaultLocation  TaxLocation
+}
+
+// TaxLine is one cart or order line as the tax engine sees it. Amount is
+// what the line costs after discounts.
+type TaxLine struct {
+	ID       string
+	TaxClass string
+	Amount   entity.Money
+}
+
+// LineTax is the tax on one line. Net + Tax = Gross; Rate is the percentage
+// applied, "0" when no rate matched.
+type LineTax struct {
+	LineID   string       `json:"line_id"`
+	TaxClass string       `json:"tax_class"`
+	Rate     string       `json:"rate"`
+	Net      entity.Money `json:"net"`
+	Tax      entity.Money `json:"tax"`
+	Gross    entity.Money `json:"gross"`
+}
+
+// TaxBreakdown is the result of TaxTable.Calculate. Lines are in the order
+// they were given and the totals are their sums.
+type TaxBreakdown struct {
+	Location         TaxLocation  `json:"location"`
+	PricesIncludeTax bool         `json:"prices_include_tax"`
+	Lines            []LineTax    `json:"lines"`
+	Net              entity.Money `json:"net"`
+	Tax              entity.Money `json:"tax"`
+	Gross            entity.Money `json:"gross"`
+}
+
+// Line returns the tax on the line with id, or nil.
+func (b *TaxBreakdown) Line(id string) *LineTax {
+	for i := range b.Lines {
+		if b.Lines[i].LineID == id {
+			return &b.Lines[i]
+		}
+	}
+	return nil
+}
+
+// TaxTable looks rates up by location and tax class.
+type TaxTable struct {
+	rates map[taxKey]string
+}
+
+func NewTaxTable(rates []*TaxRate) (*TaxTable, error) {
+	t := &TaxTable{rates: make(map[taxKey]string, len(rates))}
+	for _, r := range rates {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		t.rates[r.key()] = r.Rate
+	}
+	return t, nil
+}
+
+// Rate returns the percentage for class at loc: the region's rate if it
+// has one, else the country's, else "0".
+func (t *TaxTable) Rate(loc TaxLocation, class string) string {
+	if loc.Region != "" {
+		if rate, ok := t.rates[taxKey{loc.Country, loc.Region, class}]; ok {
+			return rate
+		}
+	}
+	if rate, ok := t.rates[taxKey{loc.Country, "", class}]; ok {
+		return rate
+	}
+	return "0"
+}
+
+// Calculate taxes lines at loc. Each line's tax is worked out exactly and
+// rounded on its own with settings.Rounding, so the total tax is the sum of
+// the lines' and each line can be shown on its own. Tax-exclusive lines pay
+// amount × rate / 100 on top; tax-inclusive lines contain
+// amount × rate / (100 + rate).
+func (t *TaxTable) Calculate(lines []TaxLine, loc TaxLocation, settings TaxSettings) *TaxBreakdown {
+	currency := entity.DefaultCurrency
+	if len(lines) > 0 {
+		currency = lines[0].Amount.Currency
+	}
+	zero := entity.Money{Currency: currency}
+	breakdown := &TaxBreakdown{
+		Location:         loc,
+		PricesIncludeTax: settings.PricesIncludeTax,
+		Lines:            make([]LineTax, len(lines)),
+		Net:              zero,
+		Tax:              zero,
+		Gross:            zero,
+	}
+
+	for i, line := range lines {
+		rate := t.Rate(loc, line.TaxClass)
+		percent, _ := new(big.Rat).SetString(rate)
+		divisor := big.NewRat(100, 1)
+		if settings.PricesIncludeTax {
+			divisor.Add(divisor, percent)
+		}
+		exact := new(big.Rat).Mul(new(big.Rat).SetInt64(line.Amount.Amount), percent)
+		tax := entity.Money{Amount: settings.Rounding.round(exact.Quo(exact, divisor)), Currency: line.Amount.Currency}
+
+		lineTax := LineTax{LineID: line.ID, TaxClass: line.TaxClass, Rate: rate, Net: line.Amount, Tax: tax, Gross: line.Amount}
+		if settings.PricesIncludeTax {
+			lineTax.Net.Amount -= tax.Amount
+		} else {
+			lineTax.Gross.Amount += tax.Amount
+		}
+		breakdown.Lines[i] = lineTax
+		breakdown.Net.Amount += lineTax.Net.Amount
+		breakdown.Tax.Amount += lineTax.Tax.Amount
+		breakdown.Gross.Amount += lineTax.Gross.Amount
+	}
+	return breakdown
+}
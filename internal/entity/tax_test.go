@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaxRate(t *testing.T) {
+	t.Run("should normalize the location and class", func(t *testing.T) {
+		rate, err := NewTaxRate("br", " sp ", "Standard", "18")
+		require.NoError(t, err)
+		assert.Equal(t, "BR", rate.Country)
+		assert.Equal(t, "SP", rate.Region)
+		assert.Equal(t, "standard", rate.TaxClass)
+	})
+
+	t.Run("should reject bad rates and places", func(t *testing.T) {
+		for _, bad := range []string{"", "-1", "100.5", "abc", "1/3", "1e1"} {
+			_, err := NewTaxRate("BR", "", "standard", bad)
+			assert.ErrorIs(t, err, ErrInvalidTaxRate, bad)
+		}
+		_, err := NewTaxRate("BRA", "", "standard", "10")
+		assert.ErrorIs(t, err, ErrInvalidCountry)
+		_, err = NewTaxRate("", "SP", "standard", "10")
+		assert.ErrorIs(t, err, ErrInvalidRegion)
+		_, err = NewTaxRate("BR", "", "no spaces", "10")
+		assert.ErrorIs(t, err, ErrInvalidTaxClass)
+	})
+}
+
+func TestReadTaxRates(t *testing.T) {
+	rates, err := ReadTaxRates(strings.NewReader(`{"rates": [
+		{"country": "br", "tax_class": "standard", "rate": "17"},
+		{"country": "br", "region": "sp", "tax_class": "standard", "rate": "18"}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "SP", rates[1].Region)
+
+	_, err = ReadTaxRates(strings.NewReader(`{"rates": [
+		{"country": "BR", "tax_class": "standard", "rate": "17"},
+		{"country": "br", "tax_class": "STANDARD", "rate": "12"}
+	]}`))
+	assert.ErrorIs(t, err, ErrDuplicateTaxRate)
+}
+
+func TestTaxTable_Calculate(t *testing.T) {
+	rates, err := ParseTaxRates([]TaxRate{
+		{Country: "BR", TaxClass: "standard", Rate: "17"},
+		{Country: "BR", Region: "SP", TaxClass: "standard", Rate: "18"},
+		{Country: "BR", TaxClass: "reduced", Rate: "7.5"},
+		{Country: "US", Region: "CA", TaxClass: "standard", Rate: "10"},
+	})
+	require.NoError(t, err)
+	table, err := NewTaxTable(rates)
+	require.NoError(t, err)
+	exclusive := TaxSettings{Rounding: RoundHalfUp}
+
+	t.Run("should prefer the region's rate over the country's", func(t *testing.T) {
+		assert.Equal(t, "18", table.Rate(TaxLocation{Country: "BR", Region: "SP"}, "standard"))
+		assert.Equal(t, "17", table.Rate(TaxLocation{Country: "BR", Region: "RJ"}, "standard"))
+		assert.Equal(t, "7.5", table.Rate(TaxLocation{Country: "BR", Region: "SP"}, "reduced"))
+		assert.Equal(t, "0", table.Rate(TaxLocation{Country: "US"}, "standard"))
+		assert.Equal(t, "0", table.Rate(TaxLocation{Country: "BR"}, "exempt"))
+	})
+
+	t.Run("should add tax on top of exclusive prices", func(t *testing.T) {
+		lines := []TaxLine{
+			{ID: "a", TaxClass: "standard", Amount: brl("100")},
+			{ID: "b", TaxClass: "reduced", Amount: brl("10.10")},
+			{ID: "c", TaxClass: "exempt", Amount: brl("5")},
+		}
+		breakdown := table.Calculate(lines, TaxLocation{Country: "BR", Region: "SP"}, exclusive)
+		assert.Equal(t, brl("18"), breakdown.Line("a").Tax)
+		assert.Equal(t, brl("118"), breakdown.Line("a").Gross)
+		// 10.10 * 7.5% = 0.7575
+		assert.Equal(t, brl("0.76"), breakdown.Line("b").Tax)
+		assert.Equal(t, "0", breakdown.Line("c").Rate)
+		assert.Equal(t, brl("115.10"), breakdown.Net)
+		assert.Equal(t, brl("18.76"), breakdown.Tax)
+		assert.Equal(t, brl("133.86"), breakdown.Gross)
+	})
+
+	t.Run("should work the tax out of inclusive prices", func(t *testing.T) {
+		inclusive := TaxSettings{PricesIncludeTax: true, Rounding: RoundHalfUp}
+		breakdown := table.Calculate([]TaxLine{{ID: "a", TaxClass: "standard", Amount: brl("11")}},
+			TaxLocation{Country: "US", Region: "CA"}, inclusive)
+		assert.Equal(t, brl("1"), breakdown.Tax)
+		assert.Equal(t, brl("10"), breakdown.Net)
+		assert.Equal(t, brl("11"), breakdown.Gross)
+		assert.True(t, breakdown.PricesIncludeTax)
+	})
+
+	t.Run("should round each line with the configured mode", func(t *testing.T) {
+		loc := TaxLocation{Country: "US", Region: "CA"}
+		// 10% of 10.25 and 10.35 is 1.025 and 1.035.
+		lines := []TaxLine{
+			{ID: "a", TaxClass: "standard", Amount: brl("10.25")},
+			{ID: "b", TaxClass: "standard", Amount: brl("10.35")},
+		}
+		for mode, want := range map[RoundingMode][2]string{
+			RoundHalfUp:   {"1.03", "1.04"},
+			RoundHalfEven: {"1.02", "1.04"},
+			RoundDown:     {"1.02", "1.03"},
+		} {
+			breakdown := table.Calculate(lines, loc, TaxSettings{Rounding: mode})
+			assert.Equal(t, brl(want[0]), breakdown.Line("a").Tax, mode)
+			assert.Equal(t, brl(want[1]), breakdown.Line("b").Tax, mode)
+		}
+	})
+
+	t.Run("should charge nothing without a location", func(t *testing.T) {
+		breakdown := table.Calculate([]TaxLine{{ID: "a", TaxClass: "standard", Amount: brl("10")}}, TaxLocation{}, exclusive)
+		assert.Equal(t, brl("0"), breakdown.Tax)
+		assert.Equal(t, brl("10"), breakdown.Gross)
+	})
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfUp, mode)
+	mode, err = ParseRoundingMode("HALF_EVEN")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfEven, mode)
+	_, err = ParseRoundingMode("ceiling")
+	assert.Equal(t, ErrInvalidRounding, err)
+}
+
+func TestOrder_ApplyTax(t *testing.T) {
+	book, err := NewProduct("Book", "", brl("40"))
+	require.NoError(t, err)
+	require.NoError(t, book.SetTaxClass("reduced"))
+	item, err := NewOrderItem(book, nil, 2)
+	require.NoError(t, err)
+	order, err := NewOrder(entity.NewID(), []*OrderItem{item})
+	require.NoError(t, err)
+	coupon, err := NewPercentCoupon("QUARTER", 25)
+	require.NoError(t, err)
+	discounts, err := ApplyDiscounts(order.DiscountLines(), nil, coupon, time.Now())
+	require.NoError(t, err)
+	order.ApplyDiscounts(discounts, coupon)
+
+	rates, err := ParseTaxRates([]TaxRate{{Country: "BR", TaxClass: "reduced", Rate: "10"}})
+	require.NoError(t, err)
+	table, err := NewTaxTable(rates)
+	require.NoError(t, err)
+
+	t.Run("should tax what is left after discounts and add it to the total", func(t *testing.T) {
+		order.ApplyTax(table.Calculate(order.TaxLines(), TaxLocation{Country: "BR"}, TaxSettings{}))
+		assert.Equal(t, brl("6"), order.Tax)
+		assert.Equal(t, brl("66"), order.Total)
+		assert.Equal(t, "10", item.TaxRate)
+		assert.Equal(t, "BR", order.TaxCountry)
+	})
+
+	t.Run("should leave the total alone when prices include tax", func(t *testing.T) {
+		order.ApplyTax(table.Calculate(order.TaxLines(), TaxLocation{Country: "BR"}, TaxSettings{PricesIncludeTax: true}))
+		assert.Equal(t, brl("5.45"), order.Tax)
+		assert.Equal(t, brl("60"), order.Total)
+		assert.True(t, order.PricesIncludeTax)
+	})
+}
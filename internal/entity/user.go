@@ -1,9 +1,36 @@
 package entity
 
 import (
+	"errors"
+	"slices"
+	"strings"
+
 	"github/GuilhermeHermes/GO_API/pkg/entity"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Roles carried in the "role" JWT claim.
+const (
+	RoleAdmin = "admin"
+	// RoleEditor manages the catalog: it sees and changes the status of
+	// unpublished products.
+	RoleEditor = "editor"
+	RoleUser   = "user"
+)
+
+// ProductEditorRoles are the roles that see draft and archived products
+// and may change the catalog: create, edit, publish, archive and schedule
+// products, and manage their prices, categories and variants.
+var ProductEditorRoles = []string{RoleAdmin, RoleEditor}
+
+// Roles lists every role a user may have.
+var Roles = []string{RoleAdmin, RoleEditor, RoleUser}
+
+var (
+	ErrUsernameRequired = errors.New("username is required")
+	ErrInvalidRole      = errors.New("role must be admin, editor or user")
 )
 
 type User struct {
@@ -14,9 +41,18 @@ type User struct {
 	Role      string    `json:"role"`
 	CreatedAt string    `json:"created_at"`
 	UpdatedAt string    `json:"updated_at"`
+	// Version goes up by one on every update; writes made against an older
+	// version are rejected.
+	Version int64 `json:"version" gorm:"not null;default:1"`
+	// DeletedAt is set once the account is deleted; the row stays until
+	// the trash is purged.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func NewUser(username, email, password, role string) (*User, error) {
+	if !slices.Contains(Roles, role) {
+		return nil, ErrInvalidRole
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -27,9 +63,21 @@ func NewUser(username, email, password, role string) (*User, error) {
 		Email:    email,
 		Password: string(hash),
 		Role:     role,
+		Version:  1,
 	}, nil
 }
 
+// Validate checks the fields a user can change after signing up.
+func (u *User) Validate() error {
+	if strings.TrimSpace(u.Username) == "" {
+		return ErrUsernameRequired
+	}
+	if !slices.Contains(Roles, u.Role) {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
 func (u *User) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	return err == nil
@@ -21,6 +21,8 @@ func TestNewUser(t *testing.T) {
 	assert.Equal(t, role, user.Role)
 	assert.NotEmpty(t, user.ID) // ID should be generated
 
+	_, err = NewUser(username, email, password, "superuser")
+	assert.ErrorIs(t, err, ErrInvalidRole)
 }
 
 func TestCheckPassword(t *testing.T) {
@@ -33,3 +35,19 @@ func TestCheckPassword(t *testing.T) {
 	// Check with incorrect password
 	assert.False(t, user.CheckPassword("wrongpassword"))
 }
+
+func TestUserValidate(t *testing.T) {
+	user, err := NewUser(username, email, password, role)
+	assert.Nil(t, err)
+	assert.NoError(t, user.Validate())
+
+	user.Role = RoleEditor
+	assert.NoError(t, user.Validate())
+
+	user.Role = "superuser"
+	assert.ErrorIs(t, user.Validate(), ErrInvalidRole)
+
+	user.Role = RoleAdmin
+	user.Username = "  "
+	assert.ErrorIs(t, user.Validate(), ErrUsernameRequired)
+}
@@ -0,0 +1,293 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var (
+	ErrOptionNameIsRequired = errors.New("option name is required")
+	ErrOptionHasNoValues    = errors.New("option needs at least one value")
+	ErrDuplicateOption      = errors.New("option names and values must be unique")
+	ErrInvalidSKU           = errors.New("SKU must be 1 to 64 letters, digits, '.', '_' or '-'")
+	ErrInvalidBarcode       = errors.New("barcode must be a GTIN-8, 12, 13 or 14 with a valid check digit")
+	ErrOptionMismatch       = errors.New("variant options must give one allowed value for every product option")
+	ErrPriceCurrency        = errors.New("price override must be in the product's currency")
+	ErrTooManyVariants      = errors.New("options would generate too many variants")
+)
+
+const (
+	maxOptionLength = 50
+	maxSKULength    = 64
+	// MaxVariants caps how many combinations one product's options may
+	// generate.
+	MaxVariants = 500
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]*$`)
+
+// ProductOption is an axis a product varies on, such as size or color,
+// with its values in display order.
+type ProductOption struct {
+	ProductID entity.ID `json:"-" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"primaryKey;type:varchar(50)"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	Values    []string  `json:"values" gorm:"serializer:json;not null"`
+}
+
+// NormalizeOptions trims option names and values, lowercases names and
+// numbers the options in the given order. Values keep their case but must
+// be unique ignoring it.
+func NormalizeOptions(productID entity.ID, options []ProductOption) ([]ProductOption, error) {
+	normalized := make([]ProductOption, 0, len(options))
+	names := map[string]bool{}
+	for i, option := range options {
+		name := strings.ToLower(strings.TrimSpace(option.Name))
+		if name == "" || len(name) > maxOptionLength {
+			return nil, ErrOptionNameIsRequired
+		}
+		if names[name] {
+			return nil, ErrDuplicateOption
+		}
+		names[name] = true
+
+		values := make([]string, 0, len(option.Values))
+		seen := map[string]bool{}
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || len(value) > maxOptionLength {
+				return nil, fmt.Errorf("option %q: %w", name, ErrOptionHasNoValues)
+			}
+			if seen[strings.ToLower(value)] {
+				return nil, ErrDuplicateOption
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("option %q: %w", name, ErrOptionHasNoValues)
+		}
+		normalized = append(normalized, ProductOption{ProductID: productID, Name: name, Position: i, Values: values})
+	}
+	return normalized, nil
+}
+
+// ProductVariant is one sellable combination of a product's option values.
+// Without a price override it sells at the product's price.
+type ProductVariant struct {
+	ID        entity.ID `json:"id"`
+	ProductID entity.ID `json:"product_id" gorm:"index;not null;uniqueIndex:idx_variant_options"`
+	SKU       string    `json:"sku" gorm:"uniqueIndex;type:varchar(64);not null"`
+	Barcode   string    `json:"barcode,omitempty" gorm:"type:varchar(14)"`
+	// Options maps option names to the chosen values, e.g. size=M.
+	Options map[string]string `json:"options" gorm:"serializer:json;not null"`
+	// OptionsKey is the canonical form of Options, unique per product.
+	OptionsKey    string    `json:"-" gorm:"not null;uniqueIndex:idx_variant_options"`
+	PriceAmount   *int64    `json:"-"`
+	PriceCurrency string    `json:"-" gorm:"type:varchar(3)"`
+	Position      int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewProductVariant builds a variant of product; price may be nil to use
+// the product's price.
+func NewProductVariant(product *Product, options []ProductOption, values map[string]string, sku, barcode string, price *entity.Money) (*ProductVariant, error) {
+	variant := &ProductVariant{
+		ID:        entity.NewID(),
+		ProductID: product.ID,
+		SKU:       NormalizeSKU(sku),
+		Barcode:   strings.TrimSpace(barcode),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := variant.SetOptions(options, values); err != nil {
+		return nil, err
+	}
+	if err := variant.SetPriceOverride(product, price); err != nil {
+		return nil, err
+	}
+	if err := variant.Validate(); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// NormalizeSKU trims and uppercases a SKU.
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+func (v *ProductVariant) Validate() error {
+	if len(v.SKU) > maxSKULength || !skuPattern.MatchString(v.SKU) {
+		return ErrInvalidSKU
+	}
+	if v.Barcode != "" && !ValidGTIN(v.Barcode) {
+		return ErrInvalidBarcode
+	}
+	return nil
+}
+
+// SetOptions matches values against the product's options, storing each
+// value with the option's own spelling.
+func (v *ProductVariant) SetOptions(options []ProductOption, values map[string]string) error {
+	if len(values) != len(options) {
+		return ErrOptionMismatch
+	}
+	chosen := make(map[string]string, len(options))
+	for _, option := range options {
+		value, ok := values[option.Name]
+		if !ok {
+			return ErrOptionMismatch
+		}
+		i := slices.IndexFunc(option.Values, func(allowed string) bool {
+			return strings.EqualFold(allowed, strings.TrimSpace(value))
+		})
+		if i < 0 {
+			return ErrOptionMismatch
+		}
+		chosen[option.Name] = option.Values[i]
+	}
+	v.Options = chosen
+	v.OptionsKey = OptionsKey(chosen)
+	return nil
+}
+
+// PriceOverride returns the variant's own price, or nil.
+func (v *ProductVariant) PriceOverride() *entity.Money {
+	if v.PriceAmount == nil {
+		return nil
+	}
+	return &entity.Money{Amount: *v.PriceAmount, Currency: v.PriceCurrency}
+}
+
+// SetPriceOverride sets or, with nil, clears the variant's own price. It
+// must be positive and in the product's currency.
+func (v *ProductVariant) SetPriceOverride(product *Product, price *entity.Money) error {
+	if price == nil {
+		v.PriceAmount, v.PriceCurrency = nil, ""
+		return nil
+	}
+	if err := price.Validate(); err != nil {
+		return err
+	}
+	if !price.IsPositive() {
+		return ErrPriceMustBePositive
+	}
+	if price.Currency != product.Price.Currency {
+		return ErrPriceCurrency
+	}
+	amount := price.Amount
+	v.PriceAmount, v.PriceCurrency = &amount, price.Currency
+	return nil
+}
+
+// EffectivePrice is the price override, or the product's price without one.
+func (v *ProductVariant) EffectivePrice(product *Product) entity.Money {
+	if override := v.PriceOverride(); override != nil {
+		return *override
+	}
+	return product.Price
+}
+
+// OptionsKey renders option values as "color=Red;size=M", sorted by name
+// and case-insensitive, so equal combinations share one key.
+func OptionsKey(values map[string]string) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strings.ToLower(values[name]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// GenerateVariants builds one variant per combination of option values,
+// in option order, with SKUs like PREFIX-RED-M. An empty prefix is
+// derived from the product name.
+func GenerateVariants(product *Product, options []ProductOption, skuPrefix string) ([]*ProductVariant, error) {
+	total := 1
+	for _, option := range options {
+		total *= len(option.Values)
+		if total > MaxVariants {
+			return nil, ErrTooManyVariants
+		}
+	}
+
+	prefix := NormalizeSKU(Slugify(skuPrefix))
+	if prefix == "" {
+		prefix = NormalizeSKU(Slugify(product.Name))
+	}
+	if prefix == "" {
+		prefix = NormalizeSKU(product.ID.String()[:8])
+	}
+
+	combinations := []map[string]string{{}}
+	for _, option := range options {
+		next := make([]map[string]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				values := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					values[k] = v
+				}
+				values[option.Name] = value
+				next = append(next, values)
+			}
+		}
+		combinations = next
+	}
+
+	variants := make([]*ProductVariant, 0, len(combinations))
+	for i, values := range combinations {
+		sku := prefix
+		for _, option := range options {
+			sku += "-" + NormalizeSKU(Slugify(values[option.Name]))
+		}
+		if len(sku) > maxSKULength {
+			sku = sku[:maxSKULength]
+		}
+		variant, err := NewProductVariant(product, options, values, sku, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", sku, err)
+		}
+		variant.Position = i
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// ValidGTIN reports whether code is a GTIN-8, 12 (UPC-A), 13 (EAN-13) or
+// 14 with a correct check digit.
+func ValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Weights alternate 1, 3, 1... from the check digit leftwards.
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
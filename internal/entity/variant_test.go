@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShirt(t *testing.T) (*Product, []ProductOption) {
+	product, err := NewProduct("Basic Tee", "", entity.MustParseMoney("49.90", "BRL"))
+	require.NoError(t, err)
+	options, err := NormalizeOptions(product.ID, []ProductOption{
+		{Name: " Color ", Values: []string{"Red", "Blue"}},
+		{Name: "size", Values: []string{"S", "M", "L"}},
+	})
+	require.NoError(t, err)
+	return product, options
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	_, options := newShirt(t)
+	assert.Equal(t, "color", options[0].Name)
+	assert.Equal(t, 1, options[1].Position)
+
+	_, err := NormalizeOptions(entity.NewID(), []ProductOption{{Name: "size", Values: []string{"M", "m"}}})
+	assert.ErrorIs(t, err, ErrDuplicateOption)
+
+	_, err = NormalizeOptions(entity.NewID(), []ProductOption{{Name: "size"}})
+	assert.ErrorIs(t, err, ErrOptionHasNoValues)
+}
+
+func TestGenerateVariants(t *testing.T) {
+	product, options := newShirt(t)
+
+	variants, err := GenerateVariants(product, options, "")
+	require.NoError(t, err)
+	require.Len(t, variants, 6)
+	assert.Equal(t, "BASIC-TEE-RED-S", variants[0].SKU)
+	assert.Equal(t, "BASIC-TEE-BLUE-L", variants[5].SKU)
+	assert.Equal(t, map[string]string{"color": "Blue", "size": "L"}, variants[5].Options)
+	assert.Equal(t, "color=blue;size=l", variants[5].OptionsKey)
+
+	variants, err = GenerateVariants(product, options, "tee 01")
+	require.NoError(t, err)
+	assert.Equal(t, "TEE-01-RED-S", variants[0].SKU)
+}
+
+func TestProductVariant(t *testing.T) {
+	product, options := newShirt(t)
+
+	t.Run("should match option values ignoring case", func(t *testing.T) {
+		variant, err := NewProductVariant(product, options, map[string]string{"color": "red", "size": "m"}, "tee-red-m", "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "TEE-RED-M", variant.SKU)
+		assert.Equal(t, map[string]string{"color": "Red", "size": "M"}, variant.Options)
+		assert.Equal(t, product.Price, variant.EffectivePrice(product))
+	})
+
+	t.Run("should reject missing or unknown values", func(t *testing.T) {
+		_, err := NewProductVariant(product, options, map[string]string{"color": "Red"}, "X", "", nil)
+		assert.Equal(t, ErrOptionMismatch, err)
+		_, err = NewProductVariant(product, options, map[string]string{"color": "Green", "size": "M"}, "X", "", nil)
+		assert.Equal(t, ErrOptionMismatch, err)
+	})
+
+	t.Run("should validate SKU and barcode", func(t *testing.T) {
+		values := map[string]string{"color": "Red", "size": "M"}
+		_, err := NewProductVariant(product, options, values, "no spaces", "", nil)
+		assert.Equal(t, ErrInvalidSKU, err)
+		_, err = NewProductVariant(product, options, values, "X", "7891234567890", nil)
+		assert.Equal(t, ErrInvalidBarcode, err)
+		variant, err := NewProductVariant(product, options, values, "X", "7891234567895", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "7891234567895", variant.Barcode)
+	})
+
+	t.Run("should use the price override", func(t *testing.T) {
+		values := map[string]string{"color": "Red", "size": "L"}
+		price := entity.MustParseMoney("59.90", "BRL")
+		variant, err := NewProductVariant(product, options, values, "X", "", &price)
+		require.NoError(t, err)
+		assert.Equal(t, price, variant.EffectivePrice(product))
+
+		usd := entity.MustParseMoney("10", "USD")
+		_, err = NewProductVariant(product, options, values, "X", "", &usd)
+		assert.Equal(t, ErrPriceCurrency, err)
+	})
+}
+
+func TestValidGTIN(t *testing.T) {
+	assert.True(t, ValidGTIN("96385074"))
+	assert.True(t, ValidGTIN("036000291452"))
+	assert.True(t, ValidGTIN("4006381333931"))
+	assert.False(t, ValidGTIN("4006381333932"))
+	assert.False(t, ValidGTIN("40063813339a1"))
+	assert.False(t, ValidGTIN("123"))
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+const (
+	// MaxWishlists caps how many wishlists one user keeps.
+	MaxWishlists = 20
+	// MaxWishlistItems caps how many products one wishlist holds.
+	MaxWishlistItems = 200
+)
+
+var (
+	ErrWishlistName      = errors.New("a wishlist needs a name of at most 100 characters")
+	ErrWishlistLimit     = fmt.Errorf("a user keeps at most %d wishlists", MaxWishlists)
+	ErrWishlistFull      = fmt.Errorf("a wishlist holds at most %d products", MaxWishlistItems)
+	ErrAlreadyWishlisted = errors.New("product already in this wishlist")
+)
+
+// Wishlist is a named list of products a user wants to keep an eye on.
+// Sharing it gives it a random ShareToken that anyone can read it with.
+// Items live in the wishlist_items table; the repository loads them.
+type Wishlist struct {
+	ID         entity.ID       `json:"id"`
+	UserID     entity.ID       `json:"user_id" gorm:"index;not null"`
+	Name       string          `json:"name" gorm:"type:varchar(100);not null"`
+	ShareToken *string         `json:"share_token,omitempty" gorm:"uniqueIndex;type:varchar(64)"`
+	Items      []*WishlistItem `json:"items" gorm:"-"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func NewWishlist(userID entity.ID, name string) (*Wishlist, error) {
+	wishlist := &Wishlist{
+		ID:        entity.NewID(),
+		UserID:    userID,
+		Items:     []*WishlistItem{},
+		CreatedAt: time.Now(),
+	}
+	if err := wishlist.Rename(name); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (w *Wishlist) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return ErrWishlistName
+	}
+	w.Name = name
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// Share gives the wishlist a share token, keeping the one it has so links
+// already handed out keep working.
+func (w *Wishlist) Share() error {
+	if w.ShareToken != nil {
+		return nil
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	w.ShareToken = &token
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// Unshare drops the share token; old links stop working.
+func (w *Wishlist) Unshare() {
+	w.ShareToken = nil
+	w.UpdatedAt = time.Now()
+}
+
+// WishlistItem is a product on a wishlist. AddedPrice is what the product
+// cost when it was added, to show how the price moved since.
+type WishlistItem struct {
+	ID         entity.ID    `json:"id"`
+	WishlistID entity.ID    `json:"-" gorm:"not null;uniqueIndex:idx_wishlist_product"`
+	ProductID  entity.ID    `json:"product_id" gorm:"not null;uniqueIndex:idx_wishlist_product;index"`
+	AddedPrice entity.Money `json:"added_price" gorm:"embedded;embeddedPrefix:added_price_"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func NewWishlistItem(wishlistID entity.ID, product *Product) *WishlistItem {
+	return &WishlistItem{
+		ID:         entity.NewID(),
+		WishlistID: wishlistID,
+		ProductID:  product.ID,
+		AddedPrice: product.Price,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// PriceDrop records that a product on one of the user's wishlists got
+// cheaper.
+type PriceDrop struct {
+	ID        entity.ID    `json:"id"`
+	UserID    entity.ID    `json:"user_id" gorm:"index;not null"`
+	ProductID entity.ID    `json:"product_id" gorm:"index;not null"`
+	OldPrice  entity.Money `json:"old_price" gorm:"embedded;embeddedPrefix:old_price_"`
+	NewPrice  entity.Money `json:"new_price" gorm:"embedded;embeddedPrefix:new_price_"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func NewPriceDrop(userID, productID entity.ID, oldPrice, newPrice entity.Money) *PriceDrop {
+	return &PriceDrop{
+		ID:        entity.NewID(),
+		UserID:    userID,
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsPriceDrop reports whether a price going from oldPrice to newPrice got
+// cheaper. A change of currency is not a drop: the amounts do not compare.
+func IsPriceDrop(oldPrice, newPrice entity.Money) bool {
+	cmp, err := newPrice.Cmp(oldPrice)
+	return err == nil && cmp < 0
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWishlist(t *testing.T) {
+	t.Run("should need a short name", func(t *testing.T) {
+		_, err := NewWishlist(entity.NewID(), "  ")
+		assert.ErrorIs(t, err, ErrWishlistName)
+		_, err = NewWishlist(entity.NewID(), strings.Repeat("a", 101))
+		assert.ErrorIs(t, err, ErrWishlistName)
+
+		wishlist, err := NewWishlist(entity.NewID(), " Natal ")
+		require.NoError(t, err)
+		assert.Equal(t, "Natal", wishlist.Name)
+		assert.Nil(t, wishlist.ShareToken)
+	})
+}
+
+func TestWishlist_Share(t *testing.T) {
+	wishlist, err := NewWishlist(entity.NewID(), "Natal")
+	require.NoError(t, err)
+
+	t.Run("should keep the token once shared", func(t *testing.T) {
+		require.NoError(t, wishlist.Share())
+		require.NotNil(t, wishlist.ShareToken)
+		token := *wishlist.ShareToken
+		assert.Len(t, token, 32)
+		require.NoError(t, wishlist.Share())
+		assert.Equal(t, token, *wishlist.ShareToken)
+	})
+
+	t.Run("should drop the token when unshared", func(t *testing.T) {
+		wishlist.Unshare()
+		assert.Nil(t, wishlist.ShareToken)
+	})
+}
+
+func TestIsPriceDrop(t *testing.T) {
+	t.Run("should only count cheaper prices in the same currency", func(t *testing.T) {
+		assert.True(t, IsPriceDrop(brl("100"), brl("99.99")))
+		assert.False(t, IsPriceDrop(brl("100"), brl("100")))
+		assert.False(t, IsPriceDrop(brl("100"), brl("150")))
+		assert.False(t, IsPriceDrop(brl("100"), entity.MustParseMoney("10", "USD")))
+	})
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type AddressRepository struct {
+	DB *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) *AddressRepository {
+	return &AddressRepository{DB: db}
+}
+
+// Create adds address to its user's book. The first address becomes the
+// default for both shipping and billing; a new default takes the flag
+// from the old one.
+func (r *AddressRepository) Create(address *entity.Address) error {
+	if address == nil {
+		return errors.New("address cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Address{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= entity.MaxAddresses {
+			return entity.ErrAddressBookFull
+		}
+		if count == 0 {
+			address.DefaultShipping, address.DefaultBilling = true, true
+		}
+		if err := clearDefaults(tx, address); err != nil {
+			return err
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// FindByUser returns the user's addresses, oldest first.
+func (r *AddressRepository) FindByUser(userID string) ([]*entity.Address, error) {
+	var addresses []*entity.Address
+	err := r.DB.Where("user_id = ?", userID).Order("created_at, id").Find(&addresses).Error
+	return addresses, err
+}
+
+// FindByID only finds addresses in userID's book.
+func (r *AddressRepository) FindByID(userID, id string) (*entity.Address, error) {
+	var address entity.Address
+	if err := r.DB.Where("user_id = ? AND id = ?", userID, id).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// FindDefaults returns the user's default shipping and billing addresses;
+// either is nil when the user has none.
+func (r *AddressRepository) FindDefaults(userID string) (shipping, billing *entity.Address, err error) {
+	var addresses []*entity.Address
+	err = r.DB.Where("user_id = ? AND (default_shipping = ? OR default_billing = ?)", userID, true, true).
+		Find(&addresses).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, address := range addresses {
+		if address.DefaultShipping {
+			shipping = address
+		}
+		if address.DefaultBilling {
+			billing = address
+		}
+	}
+	return shipping, billing, nil
+}
+
+// Update saves address, moving the default flags to it when it sets them.
+func (r *AddressRepository) Update(address *entity.Address) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaults(tx, address); err != nil {
+			return err
+		}
+		result := tx.Model(address).Where("user_id = ?", address.UserID).Select("*").Updates(address)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Delete removes an address from userID's book. Orders keep their copies.
+func (r *AddressRepository) Delete(userID, id string) error {
+	result := r.DB.Where("user_id = ? AND id = ?", userID, id).Delete(&entity.Address{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// clearDefaults takes the default flags address sets away from the user's
+// other addresses.
+func clearDefaults(tx *gorm.DB, address *entity.Address) error {
+	others := tx.Model(&entity.Address{}).Where("user_id = ? AND id <> ?", address.UserID, address.ID)
+	if address.DefaultShipping {
+		if err := others.Session(&gorm.Session{}).UpdateColumn("default_shipping", false).Error; err != nil {
+			return err
+		}
+	}
+	if address.DefaultBilling {
+		if err := others.Session(&gorm.Session{}).UpdateColumn("default_billing", false).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAddressRepository(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.Address{}))
+	repo := NewAddressRepository(orders.DB)
+	userID := pkgentity.NewID()
+
+	newAddress := func(label, postalCode string) *entity.Address {
+		address, err := entity.NewAddress(userID, label, entity.PostalAddress{
+			Recipient: "Ana", Line1: "Av. Paulista, 1000", City: "São Paulo", Region: "SP", PostalCode: postalCode, Country: "BR",
+		})
+		require.NoError(t, err)
+		return address
+	}
+	home := newAddress("Casa", "01310100")
+	require.NoError(t, repo.Create(home))
+	work := newAddress("Trabalho", "04538-133")
+	require.NoError(t, repo.Create(work))
+
+	t.Run("should make the first address the default for both", func(t *testing.T) {
+		shipping, billing, err := repo.FindDefaults(userID.String())
+		require.NoError(t, err)
+		assert.Equal(t, home.ID, shipping.ID)
+		assert.Equal(t, home.ID, billing.ID)
+		assert.Equal(t, "01310-100", shipping.PostalCode)
+	})
+
+	t.Run("should move a default to the address that takes it", func(t *testing.T) {
+		work.DefaultShipping = true
+		require.NoError(t, repo.Update(work))
+		shipping, billing, err := repo.FindDefaults(userID.String())
+		require.NoError(t, err)
+		assert.Equal(t, work.ID, shipping.ID)
+		assert.Equal(t, home.ID, billing.ID)
+	})
+
+	t.Run("should keep other users out", func(t *testing.T) {
+		_, err := repo.FindByID(pkgentity.NewID().String(), home.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		stranger := *home
+		stranger.UserID = pkgentity.NewID()
+		assert.ErrorIs(t, repo.Update(&stranger), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.Delete(stranger.UserID.String(), home.ID.String()), gorm.ErrRecordNotFound)
+
+		addresses, err := repo.FindByUser(userID.String())
+		require.NoError(t, err)
+		require.Len(t, addresses, 2)
+		assert.Equal(t, "Casa", addresses[0].Label)
+	})
+
+	t.Run("should cap the address book", func(t *testing.T) {
+		for i := 2; i < entity.MaxAddresses; i++ {
+			require.NoError(t, repo.Create(newAddress("", "01310100")))
+		}
+		assert.ErrorIs(t, repo.Create(newAddress("", "01310100")), entity.ErrAddressBookFull)
+	})
+}
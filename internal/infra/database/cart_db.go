@@ -0,0 +1,156 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+type CartRepository struct {
+	DB *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) *CartRepository {
+	return &CartRepository{DB: db}
+}
+
+// FindOrCreateUserCart returns the user's cart, creating an empty one the
+// first time.
+func (r *CartRepository) FindOrCreateUserCart(userID pkgentity.ID) (*entity.Cart, error) {
+	cart, err := r.findCart(r.DB, "user_id = ?", userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		cart = entity.NewUserCart(userID)
+		if err := r.DB.Create(cart).Error; err != nil {
+			return nil, err
+		}
+		return cart, nil
+	}
+	return cart, err
+}
+
+// FindByGuestToken returns the guest cart with token, or
+// gorm.ErrRecordNotFound.
+func (r *CartRepository) FindByGuestToken(token string) (*entity.Cart, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.findCart(r.DB, "guest_token = ?", token)
+}
+
+func (r *CartRepository) Create(cart *entity.Cart) error {
+	if cart == nil {
+		return errors.New("cart cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(cart).Error; err != nil {
+			return err
+		}
+		return saveCartItems(tx, cart)
+	})
+}
+
+// Save stores the cart's lines, replacing what was stored before, and its
+// coupon code, if the cart is still at the version it was read at; a cart
+// changed in the meantime gives ErrVersionConflict and is left alone.
+func (r *CartRepository) Save(cart *entity.Cart) error {
+	if cart == nil {
+		return errors.New("cart cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return saveCartItems(tx, cart)
+	})
+}
+
+// Delete removes a cart and its lines.
+func (r *CartRepository) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.CartItem{}, "cart_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Cart{}, "id = ?", id).Error
+	})
+}
+
+// MergeGuestCart moves the lines of the guest cart with token into the
+// user's cart and deletes the guest cart. An unknown token is ignored.
+func (r *CartRepository) MergeGuestCart(token string, userID pkgentity.ID) (*entity.Cart, error) {
+	var cart *entity.Cart
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		guest, err := r.findCart(tx, "guest_token = ?", token)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		cart, err = r.findCart(tx, "user_id = ?", userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cart = entity.NewUserCart(userID)
+			err = tx.Create(cart).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		cart.Merge(guest)
+		if err := tx.Delete(&entity.CartItem{}, "cart_id = ?", guest.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity.Cart{}, "id = ?", guest.ID).Error; err != nil {
+			return err
+		}
+		return saveCartItems(tx, cart)
+	})
+	return cart, err
+}
+
+func (r *CartRepository) findCart(db *gorm.DB, query string, args ...any) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := db.Where(query, args...).First(&cart).Error; err != nil {
+		return nil, err
+	}
+	cart.Items = []*entity.CartItem{}
+	if err := db.Where("cart_id = ?", cart.ID).Order("created_at").Find(&cart.Items).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func saveCartItems(tx *gorm.DB, cart *entity.Cart) error {
+	if err := bumpCartVersion(tx, cart, map[string]any{"coupon_code": cart.CouponCode}); err != nil {
+		return err
+	}
+	if err := tx.Delete(&entity.CartItem{}, "cart_id = ?", cart.ID).Error; err != nil {
+		return err
+	}
+	if len(cart.Items) == 0 {
+		return nil
+	}
+	for _, item := range cart.Items {
+		item.CartID = cart.ID
+	}
+	return tx.Create(&cart.Items).Error
+}
+
+// bumpCartVersion writes columns and moves cart to its next version if it
+// is still at the one it was read at, or returns ErrVersionConflict.
+func bumpCartVersion(tx *gorm.DB, cart *entity.Cart, columns map[string]any) error {
+	now := time.Now()
+	columns["updated_at"] = now
+	columns["version"] = cart.Version + 1
+	result := tx.Model(&entity.Cart{}).Where("id = ? AND version = ?", cart.ID, cart.Version).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	cart.Version++
+	cart.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCartTestDB(t *testing.T) *CartRepository {
+	db := setupProductTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entity.Cart{}, &entity.CartItem{}))
+	return NewCartRepository(db)
+}
+
+func addToCart(t *testing.T, cart *entity.Cart, product *entity.Product, quantity int64) {
+	item, err := entity.NewCartItem(product, nil, quantity)
+	require.NoError(t, err)
+	require.NoError(t, cart.Add(item))
+}
+
+func TestCart_Save(t *testing.T) {
+	repo := setupCartTestDB(t)
+	userID := pkgentity.NewID()
+	product := createTestProduct(t)
+
+	cart, err := repo.FindOrCreateUserCart(userID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	addToCart(t, cart, product, 2)
+	require.NoError(t, repo.Save(cart))
+
+	t.Run("should load the saved lines with their snapshots", func(t *testing.T) {
+		found, err := repo.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		assert.Equal(t, cart.ID, found.ID)
+		require.Len(t, found.Items, 1)
+		assert.Equal(t, product.Price, found.Items[0].UnitPrice)
+		assert.Equal(t, int64(2), found.Items[0].Quantity)
+	})
+
+	t.Run("should drop removed lines", func(t *testing.T) {
+		require.NoError(t, cart.Remove(cart.Items[0].ID))
+		require.NoError(t, repo.Save(cart))
+		found, err := repo.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Items)
+	})
+
+	t.Run("should refuse to save over a newer version", func(t *testing.T) {
+		first, err := repo.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		second, err := repo.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+
+		addToCart(t, first, product, 1)
+		require.NoError(t, repo.Save(first))
+		addToCart(t, second, createTestProduct(t), 1)
+		assert.ErrorIs(t, repo.Save(second), ErrVersionConflict)
+
+		found, err := repo.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		require.Len(t, found.Items, 1)
+		assert.Equal(t, product.ID, found.Items[0].ProductID)
+		assert.Equal(t, first.Version, found.Version)
+	})
+}
+
+func TestCart_MergeGuestCart(t *testing.T) {
+	repo := setupCartTestDB(t)
+	userID := pkgentity.NewID()
+	product := createTestProduct(t)
+	other := createTestProduct(t)
+
+	cart, err := repo.FindOrCreateUserCart(userID)
+	require.NoError(t, err)
+	addToCart(t, cart, product, 1)
+	require.NoError(t, repo.Save(cart))
+
+	guest, err := entity.NewGuestCart()
+	require.NoError(t, err)
+	addToCart(t, guest, product, 2)
+	addToCart(t, guest, other, 1)
+	require.NoError(t, repo.Create(guest))
+
+	merged, err := repo.MergeGuestCart(*guest.GuestToken, userID)
+	require.NoError(t, err)
+	require.Len(t, merged.Items, 2)
+	assert.Equal(t, int64(3), merged.Items[0].Quantity)
+
+	t.Run("should delete the guest cart", func(t *testing.T) {
+		_, err := repo.FindByGuestToken(*guest.GuestToken)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should ignore an unknown token", func(t *testing.T) {
+		merged, err := repo.MergeGuestCart("unknown", userID)
+		require.NoError(t, err)
+		assert.Nil(t, merged)
+	})
+}
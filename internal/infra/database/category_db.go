@@ -0,0 +1,231 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSlugTaken           = errors.New("slug already in use")
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrCategoryCycle       = errors.New("a category cannot be moved under its own descendant")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+// descendantsQuery walks the tree down from one category, itself included.
+// WITH RECURSIVE works the same on Postgres and SQLite.
+const descendantsQuery = `WITH RECURSIVE tree(id) AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION ALL
+	SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+) SELECT id FROM tree`
+
+type CategoryRepository struct {
+	DB *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{DB: db}
+}
+
+func (r *CategoryRepository) Create(category *entity.Category) error {
+	if category == nil {
+		return errors.New("category cannot be nil")
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	if err := r.checkSlug(category); err != nil {
+		return err
+	}
+	if err := r.checkParent(category); err != nil {
+		return err
+	}
+	return r.DB.Create(category).Error
+}
+
+func (r *CategoryRepository) FindByID(id string) (*entity.Category, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+
+	var category entity.Category
+	if err := r.DB.Where("id = ?", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) FindBySlug(slug string) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindAll returns every category, siblings in display order.
+func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
+	var categories []*entity.Category
+	err := r.DB.Order("position").Order("name").Find(&categories).Error
+	return categories, err
+}
+
+// Update saves category, refusing a slug already in use or a parent that
+// would close a loop.
+func (r *CategoryRepository) Update(category *entity.Category) error {
+	if category == nil {
+		return errors.New("category cannot be nil")
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	if err := r.checkSlug(category); err != nil {
+		return err
+	}
+	if err := r.checkParent(category); err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		descendants, err := r.DescendantIDs(category.ID.String())
+		if err != nil {
+			return err
+		}
+		for _, id := range descendants {
+			if id == category.ParentID.String() {
+				return ErrCategoryCycle
+			}
+		}
+	}
+
+	category.UpdatedAt = time.Now()
+	return r.DB.Save(category).Error
+}
+
+// Delete removes a leaf category and its product assignments.
+func (r *CategoryRepository) Delete(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&entity.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+		if err := tx.Delete(&entity.ProductCategory{}, "category_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.Category{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// DescendantIDs returns id and the IDs of every category below it.
+func (r *CategoryRepository) DescendantIDs(id string) ([]string, error) {
+	var ids []string
+	err := r.DB.Raw(descendantsQuery, id).Scan(&ids).Error
+	return ids, err
+}
+
+// SetProductCategories replaces the categories a product is assigned to.
+func (r *CategoryRepository) SetProductCategories(productID string, categoryIDs []string) error {
+	pid, err := pkgentity.ParseID(productID)
+	if err != nil {
+		return err
+	}
+
+	links := make([]entity.ProductCategory, 0, len(categoryIDs))
+	seen := map[string]bool{}
+	for _, id := range categoryIDs {
+		cid, err := pkgentity.ParseID(id)
+		if err != nil {
+			return ErrCategoryNotFound
+		}
+		if !seen[cid.String()] {
+			seen[cid.String()] = true
+			links = append(links, entity.ProductCategory{ProductID: pid, CategoryID: cid})
+		}
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if len(links) > 0 {
+			var found int64
+			if err := tx.Model(&entity.Category{}).Where("id IN ?", categoryIDs).Count(&found).Error; err != nil {
+				return err
+			}
+			if int(found) != len(links) {
+				return ErrCategoryNotFound
+			}
+		}
+		if err := tx.Delete(&entity.ProductCategory{}, "product_id = ?", productID).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+// FindCategoryIDsByProducts maps each product ID to its category IDs.
+// Products without categories are absent.
+func (r *CategoryRepository) FindCategoryIDsByProducts(productIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	var links []entity.ProductCategory
+	if err := r.DB.Where("product_id IN ?", productIDs).Find(&links).Error; err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		pid := link.ProductID.String()
+		result[pid] = append(result[pid], link.CategoryID.String())
+	}
+	return result, nil
+}
+
+func (r *CategoryRepository) checkSlug(category *entity.Category) error {
+	var count int64
+	err := r.DB.Model(&entity.Category{}).
+		Where("slug = ? AND id <> ?", category.Slug, category.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+func (r *CategoryRepository) checkParent(category *entity.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	var count int64
+	if err := r.DB.Model(&entity.Category{}).Where("id = ?", *category.ParentID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCategoryTestDB(t *testing.T) *gorm.DB {
+	db := setupProductTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entity.Category{}))
+	return db
+}
+
+func createTestCategory(t *testing.T, repo *CategoryRepository, name string, parent *entity.Category) *entity.Category {
+	var parentID *pkgentity.ID
+	if parent != nil {
+		parentID = &parent.ID
+	}
+	category, err := entity.NewCategory(name, "", parentID, 0)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(category))
+	return category
+}
+
+func TestCategory_Create(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+	createTestCategory(t, repo, "Books", nil)
+
+	t.Run("should reject a duplicate slug", func(t *testing.T) {
+		category, err := entity.NewCategory("More Books", "books", nil, 0)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.Create(category), ErrSlugTaken)
+	})
+
+	t.Run("should reject an unknown parent", func(t *testing.T) {
+		missing := pkgentity.NewID()
+		category, err := entity.NewCategory("Orphan", "", &missing, 0)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.Create(category), ErrCategoryNotFound)
+	})
+}
+
+func TestCategory_Tree(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+
+	electronics := createTestCategory(t, repo, "Electronics", nil)
+	computers := createTestCategory(t, repo, "Computers", electronics)
+	laptops := createTestCategory(t, repo, "Laptops", computers)
+	phones := createTestCategory(t, repo, "Phones", electronics)
+	books := createTestCategory(t, repo, "Books", nil)
+
+	t.Run("should list descendants", func(t *testing.T) {
+		ids, err := repo.DescendantIDs(electronics.ID.String())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			electronics.ID.String(), computers.ID.String(), laptops.ID.String(), phones.ID.String(),
+		}, ids)
+	})
+
+	t.Run("should build breadcrumbs", func(t *testing.T) {
+		all, err := repo.FindAll()
+		require.NoError(t, err)
+		tree := entity.NewCategoryTree(all)
+
+		var names []string
+		for _, c := range tree.Path(laptops.ID) {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, []string{"Electronics", "Computers", "Laptops"}, names)
+		assert.Len(t, tree.Roots(), 2)
+	})
+
+	t.Run("should refuse to move a category under its descendant", func(t *testing.T) {
+		computers.ParentID = &laptops.ID
+		assert.ErrorIs(t, repo.Update(computers), ErrCategoryCycle)
+		computers.ParentID = &electronics.ID
+	})
+
+	t.Run("should move a category", func(t *testing.T) {
+		phones.ParentID = &books.ID
+		require.NoError(t, repo.Update(phones))
+
+		ids, err := repo.DescendantIDs(books.ID.String())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{books.ID.String(), phones.ID.String()}, ids)
+	})
+
+	t.Run("should refuse to delete a category with children", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(electronics.ID.String()), ErrCategoryHasChildren)
+		require.NoError(t, repo.Delete(laptops.ID.String()))
+		assert.ErrorIs(t, repo.Delete(laptops.ID.String()), gorm.ErrRecordNotFound)
+	})
+}
+
+func TestCategory_ProductAssignment(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+	products := NewProductRepository(db)
+
+	parent := createTestCategory(t, repo, "Parent", nil)
+	child := createTestCategory(t, repo, "Child", parent)
+
+	inChild := createTestProduct(t)
+	require.NoError(t, products.Create(inChild))
+	inParent := createTestProduct(t)
+	require.NoError(t, products.Create(inParent))
+	unassigned := createTestProduct(t)
+	require.NoError(t, products.Create(unassigned))
+
+	require.NoError(t, repo.SetProductCategories(inChild.ID.String(), []string{child.ID.String()}))
+	require.NoError(t, repo.SetProductCategories(inParent.ID.String(), []string{parent.ID.String(), parent.ID.String()}))
+
+	t.Run("should reject unknown categories", func(t *testing.T) {
+		err := repo.SetProductCategories(inChild.ID.String(), []string{pkgentity.NewID().String()})
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("should map products to categories", func(t *testing.T) {
+		byProduct, err := repo.FindCategoryIDsByProducts([]string{inChild.ID.String(), unassigned.ID.String()})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{inChild.ID.String(): {child.ID.String()}}, byProduct)
+	})
+
+	t.Run("should filter products by category", func(t *testing.T) {
+		found, err := products.List(ProductQuery{Page: 1, Limit: 10, Filter: ProductFilter{CategoryIDs: []string{parent.ID.String()}}})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, inParent.ID, found[0].ID)
+
+		ids, err := repo.DescendantIDs(parent.ID.String())
+		require.NoError(t, err)
+		found, err = products.List(ProductQuery{Page: 1, Limit: 10, Filter: ProductFilter{CategoryIDs: ids}})
+		require.NoError(t, err)
+		assert.Len(t, found, 2)
+	})
+
+	t.Run("should clear assignments", func(t *testing.T) {
+		require.NoError(t, repo.SetProductCategories(inChild.ID.String(), nil))
+		byProduct, err := repo.FindCategoryIDsByProducts([]string{inChild.ID.String()})
+		require.NoError(t, err)
+		assert.Empty(t, byProduct)
+	})
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository struct {
+	DB *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) *CouponRepository {
+	return &CouponRepository{DB: db}
+}
+
+func (r *CouponRepository) Create(coupon *entity.Coupon) error {
+	if coupon == nil {
+		return errors.New("coupon cannot be nil")
+	}
+	return r.DB.Create(coupon).Error
+}
+
+// FindByCode matches code case-insensitively.
+func (r *CouponRepository) FindByCode(code string) (*entity.Coupon, error) {
+	var coupon entity.Coupon
+	if err := r.DB.Where("code = ?", entity.NormalizeCouponCode(code)).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// FindAll returns one page of coupons, newest first.
+func (r *CouponRepository) FindAll(page, limit int) ([]*entity.Coupon, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var coupons []*entity.Coupon
+	err := r.DB.Order("created_at DESC, id").Limit(limit).Offset((page - 1) * limit).Find(&coupons).Error
+	return coupons, err
+}
+
+// Delete removes the coupon with code. Orders keep the code they used.
+func (r *CouponRepository) Delete(code string) error {
+	result := r.DB.Where("code = ?", entity.NormalizeCouponCode(code)).Delete(&entity.Coupon{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountRedemptions returns how many orders of userID used the coupon.
+func (r *CouponRepository) CountRedemptions(couponID, userID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&entity.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+// redeemCoupon records that order used its coupon. Both the code's total
+// and the user's own uses are counted with conditional updates, so
+// concurrent orders cannot go over MaxUses or MaxUsesPerUser; it returns
+// entity.ErrCouponUsedUp or entity.ErrCouponUserLimit when a limit was
+// reached since the order was priced.
+func redeemCoupon(tx *gorm.DB, order *entity.Order) error {
+	if order.CouponID == nil {
+		return nil
+	}
+	result := tx.Model(&entity.Coupon{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", order.CouponID).
+		UpdateColumn("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrCouponUsedUp
+	}
+
+	var coupon entity.Coupon
+	if err := tx.Where("id = ?", order.CouponID).First(&coupon).Error; err != nil {
+		return err
+	}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.CouponUsage{CouponID: coupon.ID, UserID: order.UserID}).Error
+	if err != nil {
+		return err
+	}
+	usage := tx.Model(&entity.CouponUsage{}).Where("coupon_id = ? AND user_id = ?", coupon.ID, order.UserID)
+	if coupon.MaxUsesPerUser > 0 {
+		usage = usage.Where("uses < ?", coupon.MaxUsesPerUser)
+	}
+	result = usage.UpdateColumn("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrCouponUserLimit
+	}
+
+	return tx.Create(&entity.CouponRedemption{
+		ID:        pkgentity.NewID(),
+		CouponID:  coupon.ID,
+		UserID:    order.UserID,
+		OrderID:   order.ID,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// releaseCoupon gives back the use a cancelled order made of its coupon.
+func releaseCoupon(tx *gorm.DB, order *entity.Order) error {
+	if order.CouponID == nil {
+		return nil
+	}
+	result := tx.Where("order_id = ?", order.ID).Delete(&entity.CouponRedemption{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	err := tx.Model(&entity.CouponUsage{}).
+		Where("coupon_id = ? AND user_id = ? AND uses > 0", order.CouponID, order.UserID).
+		UpdateColumn("uses", gorm.Expr("uses - 1")).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&entity.Coupon{}).
+		Where("id = ? AND uses > 0", order.CouponID).
+		UpdateColumn("uses", gorm.Expr("uses - 1")).Error
+}
+
+type PromotionRepository struct {
+	DB *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{DB: db}
+}
+
+func (r *PromotionRepository) Create(promotion *entity.Promotion) error {
+	if promotion == nil {
+		return errors.New("promotion cannot be nil")
+	}
+	return r.DB.Create(promotion).Error
+}
+
+// FindAll returns every promotion, oldest first, the order they apply in.
+func (r *PromotionRepository) FindAll() ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	err := r.DB.Order("created_at, id").Find(&promotions).Error
+	return promotions, err
+}
+
+// FindActive returns the promotions valid at now, oldest first.
+func (r *PromotionRepository) FindActive(now time.Time) ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	err := r.DB.
+		Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Order("created_at, id").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *PromotionRepository) Delete(id string) error {
+	result := r.DB.Where("id = ?", id).Delete(&entity.Promotion{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDiscountTestDB(t *testing.T) (*CouponRepository, *PromotionRepository, *OrderRepository) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.Coupon{}, &entity.CouponRedemption{}, &entity.CouponUsage{}, &entity.Promotion{}))
+	return NewCouponRepository(orders.DB), NewPromotionRepository(orders.DB), orders
+}
+
+// newCouponOrder creates an order priced with coupon.
+func newCouponOrder(t *testing.T, userID pkgentity.ID, product *entity.Product, coupon *entity.Coupon) *entity.Order {
+	order := newTestOrder(t, userID, product)
+	breakdown, err := entity.ApplyDiscounts(order.DiscountLines(), nil, coupon, time.Now())
+	require.NoError(t, err)
+	order.ApplyDiscounts(breakdown, coupon)
+	return order
+}
+
+func TestCoupon_FindByCode(t *testing.T) {
+	coupons, _, _ := setupDiscountTestDB(t)
+	coupon, err := entity.NewPercentCoupon("SUMMER", 10)
+	require.NoError(t, err)
+	coupon.Scope.CategoryIDs = []string{pkgentity.NewID().String()}
+	require.NoError(t, coupons.Create(coupon))
+
+	t.Run("should find codes case-insensitively with their scope", func(t *testing.T) {
+		found, err := coupons.FindByCode("summer")
+		require.NoError(t, err)
+		assert.Equal(t, coupon.ID, found.ID)
+		assert.Equal(t, coupon.Scope.CategoryIDs, found.Scope.CategoryIDs)
+	})
+
+	t.Run("should delete by code", func(t *testing.T) {
+		require.NoError(t, coupons.Delete("Summer"))
+		_, err := coupons.FindByCode("SUMMER")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, coupons.Delete("SUMMER"), gorm.ErrRecordNotFound)
+	})
+}
+
+func TestCoupon_Redemption(t *testing.T) {
+	coupons, _, orders := setupDiscountTestDB(t)
+	product := createTestProduct(t)
+	coupon, err := entity.NewPercentCoupon("ONCE", 10)
+	require.NoError(t, err)
+	coupon.MaxUses, coupon.MaxUsesPerUser = 2, 1
+	require.NoError(t, coupons.Create(coupon))
+	alice, bob, carol := pkgentity.NewID(), pkgentity.NewID(), pkgentity.NewID()
+
+	t.Run("should count a use and store the discount with the order", func(t *testing.T) {
+		order := newCouponOrder(t, alice, product, coupon)
+		require.NoError(t, orders.Create(order))
+
+		found, err := orders.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "ONCE", found.CouponCode)
+		assert.Equal(t, order.Discount, found.Discount)
+		assert.Equal(t, order.Discounts, found.Discounts)
+		assert.Equal(t, order.Items[0].Discount, found.Items[0].Discount)
+
+		stored, err := coupons.FindByCode("ONCE")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stored.Uses)
+		count, err := coupons.CountRedemptions(coupon.ID.String(), alice.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should refuse a second use by the same user", func(t *testing.T) {
+		order := newCouponOrder(t, alice, product, coupon)
+		assert.ErrorIs(t, orders.Create(order), entity.ErrCouponUserLimit)
+		_, err := orders.FindByID(order.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should stop at the code's limit", func(t *testing.T) {
+		require.NoError(t, orders.Create(newCouponOrder(t, bob, product, coupon)))
+		assert.ErrorIs(t, orders.Create(newCouponOrder(t, carol, product, coupon)), entity.ErrCouponUsedUp)
+	})
+
+	t.Run("should give the use back when the order is cancelled", func(t *testing.T) {
+		order, err := orders.FindAll(OrderFilter{UserID: bob.String()}, 1, 1)
+		require.NoError(t, err)
+		transition, err := order[0].Transition(entity.OrderCancelled, nil, "")
+		require.NoError(t, err)
+		require.NoError(t, orders.SaveTransition(order[0], transition))
+
+		stored, err := coupons.FindByCode("ONCE")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stored.Uses)
+		assert.NoError(t, orders.Create(newCouponOrder(t, carol, product, coupon)))
+
+		var usage entity.CouponUsage
+		require.NoError(t, orders.DB.Where("coupon_id = ? AND user_id = ?", coupon.ID, bob).First(&usage).Error)
+		assert.Equal(t, int64(0), usage.Uses)
+	})
+}
+
+func TestCoupon_ConcurrentRedemptions(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "coupons.db") + "?_busy_timeout=10000&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+	coupons, orders := NewCouponRepository(db), NewOrderRepository(db)
+	product := createTestProduct(t)
+	coupon, err := entity.NewPercentCoupon("TWICE", 10)
+	require.NoError(t, err)
+	coupon.MaxUsesPerUser = 2
+	require.NoError(t, coupons.Create(coupon))
+	alice := pkgentity.NewID()
+	const workers = 20
+
+	t.Run("should never let a user go over their limit", func(t *testing.T) {
+		var placed atomic.Int64
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := orders.Create(newCouponOrder(t, alice, product, coupon))
+				if err == nil {
+					placed.Add(1)
+					return
+				}
+				assert.ErrorIs(t, err, entity.ErrCouponUserLimit)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(2), placed.Load())
+		count, err := coupons.CountRedemptions(coupon.ID.String(), alice.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		stored, err := coupons.FindByCode("TWICE")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stored.Uses)
+	})
+}
+
+func TestPromotion_FindActive(t *testing.T) {
+	_, promotions, _ := setupDiscountTestDB(t)
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	running, err := entity.NewBuyXGetY("running", 2, 1, 0)
+	require.NoError(t, err)
+	running.Window.EndsAt = &future
+	over, err := entity.NewBuyXGetY("over", 2, 1, 0)
+	require.NoError(t, err)
+	over.Window.EndsAt = &past
+	upcoming, err := entity.NewBuyXGetY("upcoming", 2, 1, 0)
+	require.NoError(t, err)
+	upcoming.Window.StartsAt = &future
+	for _, promotion := range []*entity.Promotion{running, over, upcoming} {
+		require.NoError(t, promotions.Create(promotion))
+	}
+
+	t.Run("should only return promotions valid now", func(t *testing.T) {
+		active, err := promotions.FindActive(now)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		assert.Equal(t, "running", active[0].Name)
+
+		all, err := promotions.FindAll()
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("should delete a promotion", func(t *testing.T) {
+		require.NoError(t, promotions.Delete(over.ID.String()))
+		assert.ErrorIs(t, promotions.Delete(over.ID.String()), gorm.ErrRecordNotFound)
+	})
+}
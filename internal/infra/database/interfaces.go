@@ -1,6 +1,11 @@
 package database
 
-import "github/GuilhermeHermes/GO_API/internal/entity"
+import (
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+)
 
 type UserDB interface {
 	Create(user *entity.User) error
@@ -9,12 +14,170 @@ type UserDB interface {
 	Update(user *entity.User) error
 	Delete(id string) error
 	Exists(email string) (bool, error)
+	Purge(deletedBefore time.Time) (int, error)
+}
+
+type AddressDB interface {
+	Create(address *entity.Address) error
+	FindByUser(userID string) ([]*entity.Address, error)
+	FindByID(userID, id string) (*entity.Address, error)
+	FindDefaults(userID string) (shipping, billing *entity.Address, err error)
+	Update(address *entity.Address) error
+	Delete(userID, id string) error
 }
 
 type ProductDB interface {
 	Create(product *entity.Product) error
 	FindByID(id string) (*entity.Product, error)
 	FindAll(page int, limit int, sort string) ([]*entity.Product, error)
+	List(query ProductQuery) ([]*entity.Product, error)
+	ListByCursor(query ProductCursorQuery) (*ProductPage, error)
+	CountProducts(filter ProductFilter) (int64, error)
+	Facets(filter ProductFilter, bounds []pkgentity.Money) (*ProductFacets, error)
 	Update(product *entity.Product) error
+	Delete(id string, version int64) error
+	FindDeleted(page, limit int) ([]*entity.Product, error)
+	Restore(id string) error
+	Purge(deletedBefore time.Time) (int, error)
+	As(actorID pkgentity.ID) ProductDB
+	FindRevisions(productID string, page, limit int) ([]*entity.ProductRevision, error)
+	FindAsOf(id string, at time.Time) (*entity.Product, error)
+	Revert(product *entity.Product, revision int64) error
+	ChangeStatus(product *entity.Product, next entity.ProductStatus) error
+	PublishScheduled(now time.Time) (int, error)
+}
+
+// ProductSearcher only finds published products; it is the storefront's
+// search.
+type ProductSearcher interface {
+	Search(query string, page int, limit int) ([]*ProductSearchResult, error)
+}
+
+type PriceListDB interface {
+	SetPrice(price *entity.ProductPrice) error
+	DeletePrice(productID, currency string) error
+	FindPrices(productID string) ([]*entity.ProductPrice, error)
+	FindPricesIn(productIDs []string, currency string) (map[string]pkgentity.Money, error)
+}
+
+type ExchangeRateDB interface {
+	Replace(rates []*entity.ExchangeRate) error
+	FindAll() ([]*entity.ExchangeRate, error)
+	Load() (*entity.ExchangeRates, error)
+}
+
+type TaxRateDB interface {
+	Replace(rates []*entity.TaxRate) error
+	FindAll() ([]*entity.TaxRate, error)
+	Load() (*entity.TaxTable, error)
+}
+
+type ShippingDB interface {
+	CreateZone(zone *entity.ShippingZone) error
+	FindZones() ([]*entity.ShippingZone, error)
+	FindZone(id string) (*entity.ShippingZone, error)
+	DeleteZone(id string) error
+	CreateMethod(method *entity.ShippingMethod) error
+	DeleteMethod(zoneID, id string) error
+}
+
+type ReviewDB interface {
+	Create(review *entity.Review) error
+	FindByID(id string) (*entity.Review, error)
+	FindByProduct(productID string, page, limit int) ([]*entity.Review, error)
+	FindByStatus(status entity.ReviewStatus, page, limit int) ([]*entity.Review, error)
+	Update(review *entity.Review) error
+	Delete(id string) error
+	HasPurchased(userID, productID string) (bool, error)
+	RatingSummary(productID string) (entity.RatingSummary, error)
+}
+
+type WishlistDB interface {
+	Create(wishlist *entity.Wishlist) error
+	FindByUser(userID string) ([]*entity.Wishlist, error)
+	FindByID(userID, id string) (*entity.Wishlist, error)
+	FindByShareToken(token string) (*entity.Wishlist, error)
+	Update(wishlist *entity.Wishlist) error
+	Delete(userID, id string) error
+	AddItem(item *entity.WishlistItem) error
+	RemoveItem(wishlistID, productID string) error
+	FindPriceDrops(userID string, page, limit int) ([]*entity.PriceDrop, error)
+}
+
+type CategoryDB interface {
+	Create(category *entity.Category) error
+	FindByID(id string) (*entity.Category, error)
+	FindBySlug(slug string) (*entity.Category, error)
+	FindAll() ([]*entity.Category, error)
+	Update(category *entity.Category) error
+	Delete(id string) error
+	DescendantIDs(id string) ([]string, error)
+	SetProductCategories(productID string, categoryIDs []string) error
+	FindCategoryIDsByProducts(productIDs []string) (map[string][]string, error)
+}
+
+type VariantDB interface {
+	FindOptions(productID string) ([]entity.ProductOption, error)
+	ReplaceOptions(productID string, options []entity.ProductOption, generated []*entity.ProductVariant) ([]*entity.ProductVariant, error)
+	FindVariants(productID string) ([]*entity.ProductVariant, error)
+	FindVariant(productID, id string) (*entity.ProductVariant, error)
+	FindVariantByID(id string) (*entity.ProductVariant, error)
+	FindBySKU(sku string) (*entity.ProductVariant, error)
+	CreateVariant(variant *entity.ProductVariant) error
+	UpdateVariant(variant *entity.ProductVariant) error
+	DeleteVariant(productID, id string) error
+}
+
+type InventoryDB interface {
+	CreateWarehouse(warehouse *entity.Warehouse) error
+	FindWarehouses() ([]*entity.Warehouse, error)
+	FindWarehouseByCode(code string) (*entity.Warehouse, error)
+	StockLevels(variantID string) ([]*entity.StockLevel, error)
+	Adjust(variantID, warehouseID pkgentity.ID, delta int64, reason string) (*entity.StockLevel, error)
+	Reserve(userID *pkgentity.ID, variantID pkgentity.ID, warehouseID *pkgentity.ID, quantity int64, ttl time.Duration, reference string) (*entity.StockReservation, error)
+	Commit(id string) (*entity.StockReservation, error)
+	Release(id string) (*entity.StockReservation, error)
+	FindReservation(id string) (*entity.StockReservation, error)
+	ExpireReservations(now time.Time) (int, error)
+	Movements(variantID string, limit int) ([]*entity.StockMovement, error)
+}
+
+type CartDB interface {
+	FindOrCreateUserCart(userID pkgentity.ID) (*entity.Cart, error)
+	FindByGuestToken(token string) (*entity.Cart, error)
+	Create(cart *entity.Cart) error
+	Save(cart *entity.Cart) error
+	Delete(id string) error
+	MergeGuestCart(token string, userID pkgentity.ID) (*entity.Cart, error)
+}
+
+type OrderDB interface {
+	Create(order *entity.Order) error
+	CreateFromCart(order *entity.Order, cart *entity.Cart) error
+	FindByID(id string) (*entity.Order, error)
+	FindAll(filter OrderFilter, page, limit int) ([]*entity.Order, error)
+	SaveTransition(order *entity.Order, transition *entity.OrderTransition) error
+}
+
+type PaymentDB interface {
+	Create(intent *entity.PaymentIntent) error
+	Update(intent *entity.PaymentIntent, order *entity.Order, transition *entity.OrderTransition) error
+	FindByID(id string) (*entity.PaymentIntent, error)
+	FindByReference(provider, reference string) (*entity.PaymentIntent, error)
+	FindByOrder(orderID string) ([]*entity.PaymentIntent, error)
+}
+
+type CouponDB interface {
+	Create(coupon *entity.Coupon) error
+	FindByCode(code string) (*entity.Coupon, error)
+	FindAll(page, limit int) ([]*entity.Coupon, error)
+	Delete(code string) error
+	CountRedemptions(couponID, userID string) (int64, error)
+}
+
+type PromotionDB interface {
+	Create(promotion *entity.Promotion) error
+	FindAll() ([]*entity.Promotion, error)
+	FindActive(now time.Time) ([]*entity.Promotion, error)
 	Delete(id string) error
 }
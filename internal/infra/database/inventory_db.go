@@ -0,0 +1,458 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock    = errors.New("insufficient stock")
+	ErrReservationExpired   = errors.New("reservation expired")
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrWarehouseCodeTaken   = errors.New("warehouse code already in use")
+	ErrReservationLimit     = fmt.Errorf("cannot hold more than %d units of a SKU in reservations", entity.MaxReservedPerUser)
+)
+
+type InventoryRepository struct {
+	DB *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{DB: db}
+}
+
+func (r *InventoryRepository) CreateWarehouse(warehouse *entity.Warehouse) error {
+	if warehouse == nil {
+		return errors.New("warehouse cannot be nil")
+	}
+	if err := warehouse.Validate(); err != nil {
+		return err
+	}
+	var count int64
+	if err := r.DB.Model(&entity.Warehouse{}).Where("code = ?", warehouse.Code).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrWarehouseCodeTaken
+	}
+	return r.DB.Create(warehouse).Error
+}
+
+func (r *InventoryRepository) FindWarehouses() ([]*entity.Warehouse, error) {
+	var warehouses []*entity.Warehouse
+	err := r.DB.Order("code").Find(&warehouses).Error
+	return warehouses, err
+}
+
+// FindWarehouseByCode ignores the code's case.
+func (r *InventoryRepository) FindWarehouseByCode(code string) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	err := r.DB.Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// StockLevels returns a variant's stock in every warehouse that has held it.
+func (r *InventoryRepository) StockLevels(variantID string) ([]*entity.StockLevel, error) {
+	var levels []*entity.StockLevel
+	err := r.DB.Where("variant_id = ?", variantID).Order("warehouse_id").Find(&levels).Error
+	return levels, err
+}
+
+// Adjust adds delta units (negative to remove) to a variant's stock in a
+// warehouse. Stock on hand cannot drop below what is reserved.
+func (r *InventoryRepository) Adjust(variantID, warehouseID pkgentity.ID, delta int64, reason string) (*entity.StockLevel, error) {
+	if delta == 0 {
+		return nil, errors.New("delta cannot be zero")
+	}
+
+	var level *entity.StockLevel
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.StockLevel{VariantID: variantID, WarehouseID: warehouseID, UpdatedAt: time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		level, err = moveStock(tx, variantID, warehouseID, entity.StockMovement{
+			Kind: entity.MovementAdjust, OnHandDelta: delta, Reason: reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+// Reserve holds quantity units for ttl on behalf of userID, which is nil
+// for reservations the store makes itself. Without a warehouse it picks
+// the one with the most units available. Expired reservations on the
+// stock are released first so their units count as available. A user may
+// hold at most entity.MaxReservedPerUser units of the variant; the check
+// runs after the stock row is updated, so concurrent reservations on it
+// wait for each other and see each other's units.
+func (r *InventoryRepository) Reserve(userID *pkgentity.ID, variantID pkgentity.ID, warehouseID *pkgentity.ID, quantity int64, ttl time.Duration, reference string) (*entity.StockReservation, error) {
+	if quantity <= 0 {
+		return nil, entity.ErrQuantityMustBePositive
+	}
+
+	now := time.Now()
+	if err := r.expireStale(variantID, warehouseID, now); err != nil {
+		return nil, err
+	}
+	target := warehouseID
+	if target == nil {
+		var err error
+		if target, err = pickWarehouse(r.DB, variantID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	reservation := &entity.StockReservation{
+		ID:          pkgentity.NewID(),
+		UserID:      userID,
+		VariantID:   variantID,
+		WarehouseID: *target,
+		Quantity:    quantity,
+		Status:      entity.ReservationActive,
+		ExpiresAt:   now.Add(ttl),
+		Reference:   reference,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := moveStock(tx, variantID, *target, entity.StockMovement{
+			Kind: entity.MovementReserve, ReservedDelta: quantity, ReservationID: &reservation.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		if userID == nil {
+			return nil
+		}
+		var held int64
+		err = tx.Model(&entity.StockReservation{}).
+			Where("user_id = ? AND variant_id = ? AND status = ? AND expires_at > ?",
+				userID, variantID, entity.ReservationActive, now).
+			Select("COALESCE(SUM(quantity), 0)").Scan(&held).Error
+		if err != nil {
+			return err
+		}
+		if held > entity.MaxReservedPerUser {
+			return ErrReservationLimit
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Commit turns a reservation into a sale: its units leave the warehouse.
+// A reservation past its expiry is expired instead and ErrReservationExpired
+// returned.
+func (r *InventoryRepository) Commit(id string) (*entity.StockReservation, error) {
+	return r.finish(id, entity.ReservationCommitted)
+}
+
+// Release gives a reservation's units back to the available stock.
+func (r *InventoryRepository) Release(id string) (*entity.StockReservation, error) {
+	return r.finish(id, entity.ReservationReleased)
+}
+
+func (r *InventoryRepository) finish(id string, status entity.ReservationStatus) (*entity.StockReservation, error) {
+	var res entity.StockReservation
+	if err := r.DB.Where("id = ?", id).First(&res).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if res.Expired(now) {
+		if _, err := r.expire(&res, now); err != nil {
+			return nil, err
+		}
+		return &res, ErrReservationExpired
+	}
+
+	movement := entity.StockMovement{Kind: entity.MovementRelease, ReservedDelta: -res.Quantity, ReservationID: &res.ID}
+	if status == entity.ReservationCommitted {
+		movement.Kind = entity.MovementCommit
+		movement.OnHandDelta = -res.Quantity
+	}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		finished, err := setReservationStatus(tx, &res, status, now, "expires_at > ?", now)
+		if err != nil {
+			return err
+		}
+		if !finished {
+			return ErrReservationNotActive
+		}
+		_, err = moveStock(tx, res.VariantID, res.WarehouseID, movement)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FindReservation returns a reservation, reporting an active one past its
+// expiry as expired even before the sweeper gets to it.
+func (r *InventoryRepository) FindReservation(id string) (*entity.StockReservation, error) {
+	var reservation entity.StockReservation
+	if err := r.DB.Where("id = ?", id).First(&reservation).Error; err != nil {
+		return nil, err
+	}
+	if reservation.Expired(time.Now()) {
+		reservation.Status = entity.ReservationExpired
+	}
+	return &reservation, nil
+}
+
+// ExpireReservations releases every active reservation past its expiry as
+// of now and returns how many it expired.
+func (r *InventoryRepository) ExpireReservations(now time.Time) (int, error) {
+	var stale []*entity.StockReservation
+	err := r.DB.Where("status = ? AND expires_at <= ?", entity.ReservationActive, now).Find(&stale).Error
+	if err != nil {
+		return 0, err
+	}
+	return r.expireAll(stale, now)
+}
+
+// Movements returns a variant's ledger, newest first. limit <= 0 returns
+// everything.
+func (r *InventoryRepository) Movements(variantID string, limit int) ([]*entity.StockMovement, error) {
+	var movements []*entity.StockMovement
+	query := r.DB.Where("variant_id = ?", variantID).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&movements).Error
+	return movements, err
+}
+
+// expireStale expires the active reservations past their expiry on a
+// variant's stock, in one warehouse or in all of them.
+func (r *InventoryRepository) expireStale(variantID pkgentity.ID, warehouseID *pkgentity.ID, now time.Time) error {
+	query := r.DB.Where("variant_id = ? AND status = ? AND expires_at <= ?", variantID, entity.ReservationActive, now)
+	if warehouseID != nil {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+	var stale []*entity.StockReservation
+	if err := query.Find(&stale).Error; err != nil {
+		return err
+	}
+	_, err := r.expireAll(stale, now)
+	return err
+}
+
+func (r *InventoryRepository) expireAll(stale []*entity.StockReservation, now time.Time) (int, error) {
+	expired := 0
+	for _, res := range stale {
+		ok, err := r.expire(res, now)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expire marks an active reservation past its expiry as expired and gives
+// its units back. It reports false if the reservation was finished or
+// expired by someone else first.
+func (r *InventoryRepository) expire(res *entity.StockReservation, now time.Time) (bool, error) {
+	expired := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		expired, err = setReservationStatus(tx, res, entity.ReservationExpired, now, "expires_at <= ?", now)
+		if err != nil || !expired {
+			return err
+		}
+		_, err = moveStock(tx, res.VariantID, res.WarehouseID, entity.StockMovement{
+			Kind: entity.MovementExpire, ReservedDelta: -res.Quantity, ReservationID: &res.ID,
+		})
+		return err
+	})
+	return expired, err
+}
+
+// pickWarehouse chooses the warehouse with the most units available. The
+// choice is only a hint: moveStock checks the units are still there.
+func pickWarehouse(db *gorm.DB, variantID pkgentity.ID, quantity int64) (*pkgentity.ID, error) {
+	var levels []*entity.StockLevel
+	if err := db.Where("variant_id = ?", variantID).Find(&levels).Error; err != nil {
+		return nil, err
+	}
+
+	var best *entity.StockLevel
+	for _, level := range levels {
+		if level.Available() < quantity {
+			continue
+		}
+		if best == nil || level.Available() > best.Available() ||
+			(level.Available() == best.Available() && level.WarehouseID.String() < best.WarehouseID.String()) {
+			best = level
+		}
+	}
+	if best == nil {
+		return nil, ErrInsufficientStock
+	}
+	return &best.WarehouseID, nil
+}
+
+// setReservationStatus moves an active reservation to status if cond holds
+// for it, and reports whether it did. Checking the status in the UPDATE
+// itself means only one of two concurrent commits, releases or expiries of
+// a reservation wins.
+func setReservationStatus(tx *gorm.DB, res *entity.StockReservation, status entity.ReservationStatus, now time.Time, cond string, args ...any) (bool, error) {
+	result := tx.Model(&entity.StockReservation{}).
+		Where("id = ? AND status = ?", res.ID, entity.ReservationActive).
+		Where(cond, args...).
+		Updates(map[string]any{"status": status, "updated_at": now})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	res.Status, res.UpdatedAt = status, now
+	return true, nil
+}
+
+// moveStock applies movement's deltas to a stock row and appends the
+// movement to the ledger with the levels it left. The UPDATE only matches
+// while the units on hand still cover the reserved ones afterwards, so
+// concurrent writers cannot oversell; otherwise it returns
+// ErrInsufficientStock. It must be the transaction's first write to the
+// row, which then stays locked until the transaction ends.
+func moveStock(tx *gorm.DB, variantID, warehouseID pkgentity.ID, movement entity.StockMovement) (*entity.StockLevel, error) {
+	now := time.Now()
+	result := tx.Model(&entity.StockLevel{}).
+		Where("variant_id = ? AND warehouse_id = ?", variantID, warehouseID).
+		Where("on_hand + ? >= reserved + ?", movement.OnHandDelta, movement.ReservedDelta).
+		Updates(map[string]any{
+			"on_hand":    gorm.Expr("on_hand + ?", movement.OnHandDelta),
+			"reserved":   gorm.Expr("reserved + ?", movement.ReservedDelta),
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInsufficientStock
+	}
+
+	var level entity.StockLevel
+	if err := tx.Where("variant_id = ? AND warehouse_id = ?", variantID, warehouseID).First(&level).Error; err != nil {
+		return nil, err
+	}
+	movement.ID = pkgentity.NewID()
+	movement.VariantID = variantID
+	movement.WarehouseID = warehouseID
+	movement.OnHand = level.OnHand
+	movement.Reserved = level.Reserved
+	movement.CreatedAt = now
+	if err := tx.Create(&movement).Error; err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// orderReference is the Reference of the reservations made for an order.
+func orderReference(orderID pkgentity.ID) string {
+	return "order:" + orderID.String()
+}
+
+// takeOrderStock reserves and commits the units of every order line with a
+// variant, in the order's transaction, so the order is only stored if the
+// stock is there. Lines without a variant are not stocked. A short line
+// gives an error wrapping ErrInsufficientStock and naming the item.
+func takeOrderStock(tx *gorm.DB, order *entity.Order) error {
+	now := time.Now()
+	for _, item := range order.Items {
+		if item.VariantID == nil {
+			continue
+		}
+		inventory := &InventoryRepository{DB: tx}
+		if err := inventory.expireStale(*item.VariantID, nil, now); err != nil {
+			return err
+		}
+		warehouseID, err := pickWarehouse(tx, *item.VariantID, item.Quantity)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, item.Name)
+		}
+
+		reservation := &entity.StockReservation{
+			ID:          pkgentity.NewID(),
+			UserID:      &order.UserID,
+			VariantID:   *item.VariantID,
+			WarehouseID: *warehouseID,
+			Quantity:    item.Quantity,
+			Status:      entity.ReservationCommitted,
+			ExpiresAt:   now,
+			Reference:   orderReference(order.ID),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		_, err = moveStock(tx, reservation.VariantID, reservation.WarehouseID, entity.StockMovement{
+			Kind: entity.MovementReserve, ReservedDelta: item.Quantity, ReservationID: &reservation.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, item.Name)
+		}
+		_, err = moveStock(tx, reservation.VariantID, reservation.WarehouseID, entity.StockMovement{
+			Kind: entity.MovementCommit, OnHandDelta: -item.Quantity, ReservedDelta: -item.Quantity, ReservationID: &reservation.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returnOrderStock puts the units takeOrderStock took for an order back in
+// their warehouses. Each reservation only comes back once.
+func returnOrderStock(tx *gorm.DB, order *entity.Order) error {
+	var reservations []*entity.StockReservation
+	err := tx.Where("reference = ? AND status = ?", orderReference(order.ID), entity.ReservationCommitted).
+		Find(&reservations).Error
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, res := range reservations {
+		result := tx.Model(&entity.StockReservation{}).
+			Where("id = ? AND status = ?", res.ID, entity.ReservationCommitted).
+			Updates(map[string]any{"status": entity.ReservationReturned, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		_, err := moveStock(tx, res.VariantID, res.WarehouseID, entity.StockMovement{
+			Kind: entity.MovementRestock, OnHandDelta: res.Quantity, ReservationID: &res.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package database
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupInventoryTestDB(t *testing.T) (*InventoryRepository, pkgentity.ID, *entity.Warehouse) {
+	db := setupProductTestDB(t)
+	// Goroutines must share the one ":memory:" database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return setupInventory(t, db)
+}
+
+// setupInventoryFileDB keeps the inventory in a file, so concurrent
+// transactions run on separate connections as they would in production.
+func setupInventoryFileDB(t *testing.T) (*InventoryRepository, pkgentity.ID, *entity.Warehouse) {
+	dsn := filepath.Join(t.TempDir(), "inventory.db") + "?_busy_timeout=10000&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	return setupInventory(t, db)
+}
+
+func setupInventory(t *testing.T, db *gorm.DB) (*InventoryRepository, pkgentity.ID, *entity.Warehouse) {
+	require.NoError(t, db.AutoMigrate(&entity.Warehouse{}, &entity.StockLevel{}, &entity.StockReservation{}, &entity.StockMovement{}))
+
+	repo := NewInventoryRepository(db)
+	warehouse, err := entity.NewWarehouse("sp-01", "São Paulo")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateWarehouse(warehouse))
+	return repo, pkgentity.NewID(), warehouse
+}
+
+func stockOf(t *testing.T, repo *InventoryRepository, variantID pkgentity.ID) *entity.StockLevel {
+	levels, err := repo.StockLevels(variantID.String())
+	require.NoError(t, err)
+	require.Len(t, levels, 1)
+	return levels[0]
+}
+
+func TestInventory_ReserveCommitRelease(t *testing.T) {
+	repo, variantID, warehouse := setupInventoryTestDB(t)
+
+	_, err := repo.Adjust(variantID, warehouse.ID, 10, "initial count")
+	require.NoError(t, err)
+
+	t.Run("should reserve from the warehouse with stock", func(t *testing.T) {
+		owner := pkgentity.NewID()
+		res, err := repo.Reserve(&owner, variantID, nil, 4, time.Minute, "cart-1")
+		require.NoError(t, err)
+		assert.Equal(t, warehouse.ID, res.WarehouseID)
+		found, err := repo.FindReservation(res.ID.String())
+		require.NoError(t, err)
+		require.NotNil(t, found.UserID)
+		assert.Equal(t, owner, *found.UserID)
+
+		level := stockOf(t, repo, variantID)
+		assert.Equal(t, int64(10), level.OnHand)
+		assert.Equal(t, int64(6), level.Available())
+
+		committed, err := repo.Commit(res.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReservationCommitted, committed.Status)
+		level = stockOf(t, repo, variantID)
+		assert.Equal(t, int64(6), level.OnHand)
+		assert.Equal(t, int64(0), level.Reserved)
+
+		_, err = repo.Release(res.ID.String())
+		assert.ErrorIs(t, err, ErrReservationNotActive)
+	})
+
+	t.Run("should refuse to oversell", func(t *testing.T) {
+		_, err := repo.Reserve(nil, variantID, &warehouse.ID, 7, time.Minute, "")
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		_, err = repo.Adjust(variantID, warehouse.ID, -7, "shrinkage")
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+	})
+
+	t.Run("should give released units back", func(t *testing.T) {
+		res, err := repo.Reserve(nil, variantID, &warehouse.ID, 6, time.Minute, "")
+		require.NoError(t, err)
+		_, err = repo.Release(res.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), stockOf(t, repo, variantID).Available())
+	})
+
+	t.Run("should record every movement", func(t *testing.T) {
+		movements, err := repo.Movements(variantID.String(), 0)
+		require.NoError(t, err)
+		kinds := make([]entity.MovementKind, 0, len(movements))
+		for _, m := range movements {
+			kinds = append(kinds, m.Kind)
+		}
+		assert.ElementsMatch(t, []entity.MovementKind{
+			entity.MovementAdjust, entity.MovementReserve, entity.MovementCommit,
+			entity.MovementReserve, entity.MovementRelease,
+		}, kinds)
+	})
+}
+
+func TestInventory_Expiry(t *testing.T) {
+	repo, variantID, warehouse := setupInventoryTestDB(t)
+	_, err := repo.Adjust(variantID, warehouse.ID, 5, "")
+	require.NoError(t, err)
+
+	t.Run("should not commit an expired reservation", func(t *testing.T) {
+		res, err := repo.Reserve(nil, variantID, nil, 5, -time.Second, "")
+		require.NoError(t, err)
+
+		found, err := repo.FindReservation(res.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReservationExpired, found.Status)
+
+		_, err = repo.Commit(res.ID.String())
+		assert.ErrorIs(t, err, ErrReservationExpired)
+		assert.Equal(t, int64(5), stockOf(t, repo, variantID).Available())
+	})
+
+	t.Run("should count expired units as available", func(t *testing.T) {
+		_, err := repo.Reserve(nil, variantID, nil, 5, -time.Second, "")
+		require.NoError(t, err)
+		res, err := repo.Reserve(nil, variantID, nil, 5, time.Minute, "")
+		require.NoError(t, err)
+		_, err = repo.Release(res.ID.String())
+		require.NoError(t, err)
+	})
+
+	t.Run("should sweep expired reservations", func(t *testing.T) {
+		_, err := repo.Reserve(nil, variantID, nil, 2, time.Minute, "")
+		require.NoError(t, err)
+
+		expired, err := repo.ExpireReservations(time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 0, expired)
+
+		expired, err = repo.ExpireReservations(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, expired)
+		assert.Equal(t, int64(0), stockOf(t, repo, variantID).Reserved)
+	})
+}
+
+func TestInventory_ReservationLimit(t *testing.T) {
+	repo, variantID, warehouse := setupInventoryTestDB(t)
+	_, err := repo.Adjust(variantID, warehouse.ID, 3*entity.MaxReservedPerUser, "")
+	require.NoError(t, err)
+	hoarder := pkgentity.NewID()
+
+	t.Run("should cap the units a user holds of a SKU", func(t *testing.T) {
+		first, err := repo.Reserve(&hoarder, variantID, nil, entity.MaxReservedPerUser-1, time.Minute, "")
+		require.NoError(t, err)
+		_, err = repo.Reserve(&hoarder, variantID, nil, 2, time.Minute, "")
+		assert.ErrorIs(t, err, ErrReservationLimit)
+		assert.Equal(t, int64(entity.MaxReservedPerUser-1), stockOf(t, repo, variantID).Reserved)
+
+		_, err = repo.Reserve(&hoarder, variantID, nil, 1, time.Minute, "")
+		require.NoError(t, err)
+		_, err = repo.Release(first.ID.String())
+		require.NoError(t, err)
+		_, err = repo.Reserve(&hoarder, variantID, nil, 2, time.Minute, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not cap other users or the store", func(t *testing.T) {
+		other := pkgentity.NewID()
+		_, err := repo.Reserve(&other, variantID, nil, entity.MaxReservedPerUser, time.Minute, "")
+		require.NoError(t, err)
+		_, err = repo.Reserve(nil, variantID, nil, entity.MaxReservedPerUser+1, time.Minute, "")
+		assert.NoError(t, err)
+	})
+}
+
+func TestInventory_ConcurrentReservations(t *testing.T) {
+	repo, variantID, warehouse := setupInventoryFileDB(t)
+	const stock, workers = 25, 60
+	_, err := repo.Adjust(variantID, warehouse.ID, stock, "")
+	require.NoError(t, err)
+
+	t.Run("should never reserve more than on hand", func(t *testing.T) {
+		var reserved atomic.Int64
+		var wg sync.WaitGroup
+		ids := make(chan string, workers)
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := repo.Reserve(nil, variantID, nil, 1, time.Minute, "")
+				if err == nil {
+					reserved.Add(1)
+					ids <- res.ID.String()
+					return
+				}
+				assert.ErrorIs(t, err, ErrInsufficientStock)
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		assert.Equal(t, int64(stock), reserved.Load())
+		level := stockOf(t, repo, variantID)
+		assert.Equal(t, int64(stock), level.Reserved)
+
+		// Commit and release the same reservations at once: exactly one
+		// of each pair wins.
+		var committed, released atomic.Int64
+		for id := range ids {
+			for _, finish := range []func(string) (*entity.StockReservation, error){repo.Commit, repo.Release} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					res, err := finish(id)
+					if err != nil {
+						assert.ErrorIs(t, err, ErrReservationNotActive)
+						return
+					}
+					if res.Status == entity.ReservationCommitted {
+						committed.Add(1)
+					} else {
+						released.Add(1)
+					}
+				}()
+			}
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(stock), committed.Load()+released.Load())
+		level = stockOf(t, repo, variantID)
+		assert.Equal(t, int64(0), level.Reserved)
+		assert.Equal(t, int64(stock)-committed.Load(), level.OnHand)
+	})
+
+	t.Run("should keep the ledger consistent with the levels", func(t *testing.T) {
+		movements, err := repo.Movements(variantID.String(), 0)
+		require.NoError(t, err)
+		var onHand, reserved int64
+		for _, m := range movements {
+			onHand += m.OnHandDelta
+			reserved += m.ReservedDelta
+		}
+		level := stockOf(t, repo, variantID)
+		assert.Equal(t, level.OnHand, onHand)
+		assert.Equal(t, level.Reserved, reserved)
+	})
+}
+
+func TestInventory_Warehouses(t *testing.T) {
+	repo, _, _ := setupInventoryTestDB(t)
+
+	duplicate, err := entity.NewWarehouse("SP-01", "Again")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.CreateWarehouse(duplicate), ErrWarehouseCodeTaken)
+
+	found, err := repo.FindWarehouseByCode("sp-01")
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", found.Name)
+
+	_, err = repo.FindWarehouseByCode("nope")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
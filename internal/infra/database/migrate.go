@@ -0,0 +1,135 @@
+package database
+
+import (
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+// Migrate creates or updates every table the API uses, plus the
+// dialect-specific objects (indexes, triggers) GORM cannot express.
+func Migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(
+		&entity.User{}, &entity.Address{},
+		&entity.Product{}, &entity.ProductTag{}, &entity.ProductRevision{}, &entity.ProductPrice{}, &entity.ExchangeRate{},
+		&entity.Category{}, &entity.ProductCategory{},
+		&entity.ProductOption{}, &entity.ProductVariant{},
+		&entity.Warehouse{}, &entity.StockLevel{}, &entity.StockReservation{}, &entity.StockMovement{},
+		&entity.Cart{}, &entity.CartItem{},
+		&entity.Order{}, &entity.OrderItem{}, &entity.OrderTransition{}, &entity.PaymentIntent{},
+		&entity.Coupon{}, &entity.CouponRedemption{}, &entity.CouponUsage{}, &entity.Promotion{},
+		&entity.TaxRate{},
+		&entity.ShippingZone{}, &entity.ShippingMethod{},
+		&entity.Review{},
+		&entity.Wishlist{}, &entity.WishlistItem{}, &entity.PriceDrop{},
+	)
+	if err != nil {
+		return err
+	}
+	if err := migrateLegacyProductPrices(db); err != nil {
+		return err
+	}
+	if err := backfillProductRevisions(db); err != nil {
+		return err
+	}
+	if err := db.Exec(backfillCouponUsages).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(activePaymentIndex).Error; err != nil {
+		return err
+	}
+	return setupProductSearch(db)
+}
+
+// activePaymentIndex lets an order have only one payment intent that is
+// in flight, authorized or captured, so two concurrent payments cannot
+// both charge it. GORM cannot declare partial indexes.
+const activePaymentIndex = `CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_intents_active_order
+	ON payment_intents (order_id) WHERE status IN ('pending', 'authorized', 'captured')`
+
+// backfillCouponUsages seeds the per-user coupon counters from the
+// redemptions recorded before they existed. Pairs that already have a
+// counter are kept, so it is safe to run on every start.
+const backfillCouponUsages = `INSERT INTO coupon_usages (coupon_id, user_id, uses)
+	SELECT coupon_id, user_id, COUNT(*) FROM coupon_redemptions
+	WHERE NOT EXISTS (SELECT 1 FROM coupon_usages
+		WHERE coupon_usages.coupon_id = coupon_redemptions.coupon_id
+		AND coupon_usages.user_id = coupon_redemptions.user_id)
+	GROUP BY coupon_id, user_id`
+
+// migrateLegacyProductPrices converts the old floating point products.price
+// column into price_amount (minor units) and price_currency, assuming
+// DefaultCurrency, then drops it. The conversion runs in Go so rounding is
+// the same on every dialect.
+func migrateLegacyProductPrices(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("products", "price") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var legacy []struct {
+			ID    string
+			Price float64
+		}
+		err := tx.Table("products").Select("id, price").
+			Where("price_currency = '' AND price IS NOT NULL").
+			Scan(&legacy).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range legacy {
+			price, err := pkgentity.MoneyFromFloat(row.Price, pkgentity.DefaultCurrency)
+			if err != nil {
+				return err
+			}
+			err = tx.Table("products").Where("id = ?", row.ID).Updates(map[string]any{
+				"price_amount":   price.Amount,
+				"price_currency": price.Currency,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec("ALTER TABLE products DROP COLUMN price").Error
+	})
+}
+
+// backfillProductRevisions gives every product without a history, such as
+// those created before it was recorded, a created revision from its
+// current row, so FindAsOf and Revert have a revision to start from.
+// Products already in the trash also get their deleted revision.
+func backfillProductRevisions(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var products []*entity.Product
+		err := tx.Unscoped().
+			Where("NOT EXISTS (SELECT 1 FROM product_revisions WHERE product_revisions.product_id = products.id)").
+			Find(&products).Error
+		if err != nil {
+			return err
+		}
+		if err := loadProductTags(tx, products); err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			created := entity.NewProductRevision(product, entity.RevisionCreated, nil, nil)
+			created.CreatedAt = product.CreatedAt
+			if err := recordRevision(tx, created); err != nil {
+				return err
+			}
+			if !product.DeletedAt.Valid {
+				continue
+			}
+			unchanged := entity.NewProductSnapshot(product)
+			deleted := entity.NewProductRevision(product, entity.RevisionDeleted, &unchanged, nil)
+			deleted.CreatedAt = product.DeletedAt.Time
+			if err := recordRevision(tx, deleted); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrate_LegacyProductPrices(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// The products table as it was when prices were float64.
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+		id text PRIMARY KEY, name text, description text, price real,
+		created_at datetime, updated_at datetime
+	)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO products (id, name, description, price, created_at, updated_at)
+		VALUES ('8d3c9bde-51c2-4b33-9f41-6f0f8f5b2a10', 'Legacy', '', 19.99, '2024-01-01 00:00:00+00:00', '2024-01-01 00:00:00+00:00'),
+		       ('0b5a2c1e-7f44-4c9a-8a0e-2d9b6f7e1c33', 'Rounding', '', 0.285, '2024-01-01 00:00:00+00:00', '2024-01-01 00:00:00+00:00')`).Error)
+
+	require.NoError(t, Migrate(db))
+	assert.False(t, db.Migrator().HasColumn("products", "price"))
+
+	repo := NewProductRepository(db)
+	legacy, err := repo.FindByID("8d3c9bde-51c2-4b33-9f41-6f0f8f5b2a10")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1999), legacy.Price.Amount)
+	assert.Equal(t, "BRL", legacy.Price.Currency)
+
+	rounded, err := repo.FindByID("0b5a2c1e-7f44-4c9a-8a0e-2d9b6f7e1c33")
+	require.NoError(t, err)
+	assert.Equal(t, "0.29", rounded.Price.Decimal())
+
+	// Running it again is a no-op.
+	require.NoError(t, Migrate(db))
+}
+
+func TestMigrate_BackfillsProductRevisions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	// Products saved straight to the table, as before history was recorded.
+	live := createTestProduct(t)
+	require.NoError(t, db.Create(live).Error)
+	trashed := createTestProduct(t)
+	require.NoError(t, db.Create(trashed).Error)
+	require.NoError(t, db.Delete(trashed).Error)
+
+	require.NoError(t, Migrate(db))
+	repo := NewProductRepository(db)
+
+	revisions, err := repo.FindRevisions(live.ID.String(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, entity.RevisionCreated, revisions[0].Action)
+	assert.Equal(t, live.Name, revisions[0].Snapshot.Name)
+	assert.Equal(t, live.Version, revisions[0].Version)
+
+	then, err := repo.FindAsOf(live.ID.String(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, live.Name, then.Name)
+
+	revisions, err = repo.FindRevisions(trashed.ID.String(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, entity.RevisionDeleted, revisions[0].Action)
+
+	t.Run("should backfill only once", func(t *testing.T) {
+		require.NoError(t, Migrate(db))
+		revisions, err := repo.FindRevisions(live.ID.String(), 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("should keep revision numbers unique per product", func(t *testing.T) {
+		assert.True(t, db.Migrator().HasIndex(&entity.ProductRevision{}, "idx_product_revision"))
+		duplicate := entity.NewProductRevision(live, entity.RevisionUpdated, nil, nil)
+		duplicate.Revision = 1
+		assert.Error(t, db.Create(duplicate).Error)
+	})
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// ErrOrderStatusChanged is returned when an order's status changed between
+// reading the order and saving a transition.
+var ErrOrderStatusChanged = errors.New("order status changed concurrently")
+
+// OrderFilter narrows FindAll; zero fields match everything.
+type OrderFilter struct {
+	UserID string
+	Status entity.OrderStatus
+}
+
+type OrderRepository struct {
+	DB *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{DB: db}
+}
+
+// Create stores order and takes the stock of every line with a variant
+// from the warehouses; a line short of stock fails the whole order with
+// ErrInsufficientStock.
+func (r *OrderRepository) Create(order *entity.Order) error {
+	if order == nil {
+		return errors.New("order cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return createOrder(tx, order)
+	})
+}
+
+// CreateFromCart stores order and empties cart in the same transaction, so
+// a cart is never both ordered and left full. A cart changed since it was
+// read gives ErrVersionConflict, so lines added meanwhile are not lost. Like
+// Create, it takes the stock of every line with a variant, or fails with
+// ErrInsufficientStock.
+func (r *OrderRepository) CreateFromCart(order *entity.Order, cart *entity.Cart) error {
+	if order == nil || cart == nil {
+		return errors.New("order and cart cannot be nil")
+	}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := createOrder(tx, order); err != nil {
+			return err
+		}
+		if err := bumpCartVersion(tx, cart, map[string]any{}); err != nil {
+			return err
+		}
+		return tx.Delete(&entity.CartItem{}, "cart_id = ?", cart.ID).Error
+	})
+	if err == nil {
+		cart.Items = []*entity.CartItem{}
+	}
+	return err
+}
+
+func (r *OrderRepository) FindByID(id string) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.DB.Where("id = ?", id).First(&order).Error; err != nil {
+		return nil, err
+	}
+	orders := []*entity.Order{&order}
+	if err := r.loadItems(orders); err != nil {
+		return nil, err
+	}
+	order.Transitions = []*entity.OrderTransition{}
+	err := r.DB.Where("order_id = ?", order.ID).Order("created_at").Find(&order.Transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindAll returns one page of the orders matching filter, newest first,
+// with their items but not their transitions.
+func (r *OrderRepository) FindAll(filter OrderFilter, page, limit int) ([]*entity.Order, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	query := r.DB.Model(&entity.Order{})
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var orders []*entity.Order
+	err := query.Order("created_at DESC, id").Limit(limit).Offset((page - 1) * limit).Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// SaveTransition stores a status change made with Order.Transition. The
+// update only applies if the stored status is still transition.From;
+// otherwise it returns ErrOrderStatusChanged. Cancelling an order gives
+// back its coupon use and its stock.
+func (r *OrderRepository) SaveTransition(order *entity.Order, transition *entity.OrderTransition) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return saveOrderTransition(tx, order, transition)
+	})
+}
+
+func (r *OrderRepository) loadItems(orders []*entity.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[string]*entity.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		order.Items = []*entity.OrderItem{}
+		byID[order.ID.String()] = order
+		ids = append(ids, order.ID.String())
+	}
+
+	var items []*entity.OrderItem
+	if err := r.DB.Where("order_id IN ?", ids).Order("position").Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		order := byID[item.OrderID.String()]
+		order.Items = append(order.Items, item)
+	}
+	return nil
+}
+
+func createOrder(tx *gorm.DB, order *entity.Order) error {
+	if err := tx.Create(order).Error; err != nil {
+		return err
+	}
+	if err := redeemCoupon(tx, order); err != nil {
+		return err
+	}
+	for i, item := range order.Items {
+		item.OrderID = order.ID
+		item.Position = i
+	}
+	if err := tx.Create(&order.Items).Error; err != nil {
+		return err
+	}
+	return takeOrderStock(tx, order)
+}
+
+func saveOrderTransition(tx *gorm.DB, order *entity.Order, transition *entity.OrderTransition) error {
+	result := tx.Model(&entity.Order{}).
+		Where("id = ? AND status = ?", order.ID, transition.From).
+		Updates(map[string]any{"status": transition.To, "updated_at": order.UpdatedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderStatusChanged
+	}
+	if transition.To == entity.OrderCancelled {
+		if err := releaseCoupon(tx, order); err != nil {
+			return err
+		}
+		if err := returnOrderStock(tx, order); err != nil {
+			return err
+		}
+	}
+	return tx.Create(transition).Error
+}
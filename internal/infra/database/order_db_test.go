@@ -0,0 +1,199 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupOrderTestDB(t *testing.T) (*OrderRepository, *CartRepository) {
+	db := setupProductTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&entity.Cart{}, &entity.CartItem{},
+		&entity.Order{}, &entity.OrderItem{}, &entity.OrderTransition{},
+	))
+	return NewOrderRepository(db), NewCartRepository(db)
+}
+
+func newTestOrder(t *testing.T, userID pkgentity.ID, products ...*entity.Product) *entity.Order {
+	items := make([]*entity.OrderItem, 0, len(products))
+	for _, product := range products {
+		item, err := entity.NewOrderItem(product, nil, 2)
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+	order, err := entity.NewOrder(userID, items)
+	require.NoError(t, err)
+	return order
+}
+
+func TestOrder_Create(t *testing.T) {
+	repo, carts := setupOrderTestDB(t)
+	userID := pkgentity.NewID()
+	product := createTestProduct(t)
+
+	t.Run("should store the order with its lines in order", func(t *testing.T) {
+		other, err := entity.NewProduct("Other", "", pkgentity.MustParseMoney("1", "BRL"))
+		require.NoError(t, err)
+		order := newTestOrder(t, userID, product, other)
+		require.NoError(t, repo.Create(order))
+
+		found, err := repo.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderPending, found.Status)
+		assert.Equal(t, order.Total, found.Total)
+		require.Len(t, found.Items, 2)
+		assert.Equal(t, product.Name, found.Items[0].Name)
+		assert.Equal(t, "Other", found.Items[1].Name)
+		assert.Empty(t, found.Transitions)
+	})
+
+	t.Run("should empty the cart it was placed from", func(t *testing.T) {
+		cart, err := carts.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		item, err := entity.NewCartItem(product, nil, 1)
+		require.NoError(t, err)
+		require.NoError(t, cart.Add(item))
+		require.NoError(t, carts.Save(cart))
+
+		require.NoError(t, repo.CreateFromCart(newTestOrder(t, userID, product), cart))
+		assert.Empty(t, cart.Items)
+
+		cart, err = carts.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+	})
+
+	t.Run("should not order a cart changed since it was read", func(t *testing.T) {
+		stale, err := carts.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		cart, err := carts.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		item, err := entity.NewCartItem(product, nil, 1)
+		require.NoError(t, err)
+		require.NoError(t, cart.Add(item))
+		require.NoError(t, carts.Save(cart))
+
+		order := newTestOrder(t, userID, product)
+		assert.ErrorIs(t, repo.CreateFromCart(order, stale), ErrVersionConflict)
+		_, err = repo.FindByID(order.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		cart, err = carts.FindOrCreateUserCart(userID)
+		require.NoError(t, err)
+		assert.Len(t, cart.Items, 1)
+
+		cart.Items = []*entity.CartItem{}
+		require.NoError(t, carts.Save(cart))
+	})
+
+	t.Run("should list a user's orders newest first", func(t *testing.T) {
+		require.NoError(t, repo.Create(newTestOrder(t, pkgentity.NewID(), product)))
+
+		orders, err := repo.FindAll(OrderFilter{UserID: userID.String()}, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Len(t, orders[0].Items, 1)
+		assert.Len(t, orders[1].Items, 2)
+
+		orders, err = repo.FindAll(OrderFilter{Status: entity.OrderPending}, 2, 2)
+		require.NoError(t, err)
+		assert.Len(t, orders, 1)
+	})
+
+	t.Run("should return ErrRecordNotFound for an unknown order", func(t *testing.T) {
+		_, err := repo.FindByID(pkgentity.NewID().String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestOrder_SaveTransition(t *testing.T) {
+	repo, _ := setupOrderTestDB(t)
+	order := newTestOrder(t, pkgentity.NewID(), createTestProduct(t))
+	require.NoError(t, repo.Create(order))
+	adminID := pkgentity.NewID()
+
+	t.Run("should store the status and the history", func(t *testing.T) {
+		transition, err := order.Transition(entity.OrderPaid, &adminID, "paid by pix")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveTransition(order, transition))
+
+		found, err := repo.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderPaid, found.Status)
+		require.Len(t, found.Transitions, 1)
+		assert.Equal(t, entity.OrderPending, found.Transitions[0].From)
+		assert.Equal(t, "paid by pix", found.Transitions[0].Note)
+		assert.Equal(t, adminID, *found.Transitions[0].ActorID)
+	})
+
+	t.Run("should refuse a transition from a stale status", func(t *testing.T) {
+		stale, err := repo.FindByID(order.ID.String())
+		require.NoError(t, err)
+
+		transition, err := order.Transition(entity.OrderFulfilled, &adminID, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveTransition(order, transition))
+
+		transition, err = stale.Transition(entity.OrderRefunded, &adminID, "")
+		require.NoError(t, err)
+		assert.Equal(t, ErrOrderStatusChanged, repo.SaveTransition(stale, transition))
+
+		found, err := repo.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderFulfilled, found.Status)
+		assert.Len(t, found.Transitions, 2)
+	})
+}
+
+func TestOrder_Stock(t *testing.T) {
+	repo, _ := setupOrderTestDB(t)
+	require.NoError(t, repo.DB.AutoMigrate(&entity.Warehouse{}))
+	inventory := NewInventoryRepository(repo.DB)
+	warehouse, err := entity.NewWarehouse("sp-01", "São Paulo")
+	require.NoError(t, err)
+	require.NoError(t, inventory.CreateWarehouse(warehouse))
+
+	product := createTestProduct(t)
+	variant := &entity.ProductVariant{ID: pkgentity.NewID(), ProductID: product.ID, SKU: "MUG-01", Options: map[string]string{}}
+	newOrder := func(t *testing.T, quantity int64) *entity.Order {
+		item, err := entity.NewOrderItem(product, variant, quantity)
+		require.NoError(t, err)
+		order, err := entity.NewOrder(pkgentity.NewID(), []*entity.OrderItem{item})
+		require.NoError(t, err)
+		return order
+	}
+
+	t.Run("should refuse an order for a SKU out of stock", func(t *testing.T) {
+		order := newOrder(t, 1)
+		assert.ErrorIs(t, repo.Create(order), ErrInsufficientStock)
+		_, err := repo.FindByID(order.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	_, err = inventory.Adjust(variant.ID, warehouse.ID, 3, "initial count")
+	require.NoError(t, err)
+	first := newOrder(t, 2)
+
+	t.Run("should take the ordered units from the warehouse", func(t *testing.T) {
+		require.NoError(t, repo.Create(first))
+		assert.Equal(t, int64(1), stockOf(t, inventory, variant.ID).OnHand)
+		assert.Equal(t, int64(0), stockOf(t, inventory, variant.ID).Reserved)
+		assert.ErrorIs(t, repo.Create(newOrder(t, 2)), ErrInsufficientStock)
+	})
+
+	t.Run("should put the units back when the order is cancelled", func(t *testing.T) {
+		transition, err := first.Transition(entity.OrderCancelled, nil, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveTransition(first, transition))
+		assert.Equal(t, int64(3), stockOf(t, inventory, variant.ID).OnHand)
+
+		movements, err := inventory.Movements(variant.ID.String(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.MovementRestock, movements[0].Kind)
+	})
+}
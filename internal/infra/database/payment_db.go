@@ -0,0 +1,86 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type PaymentRepository struct {
+	DB *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{DB: db}
+}
+
+// ErrPaymentInProgress is returned when an order already has a payment
+// intent that is pending, authorized or captured.
+var ErrPaymentInProgress = errors.New("order already has a payment in progress, authorized or captured")
+
+// Create stores a new intent, or returns ErrPaymentInProgress if the order
+// already has an open one. The check is the idx_payment_intents_active_order
+// unique index, so it holds for concurrent requests too.
+func (r *PaymentRepository) Create(intent *entity.PaymentIntent) error {
+	if intent == nil {
+		return errors.New("payment intent cannot be nil")
+	}
+	err := r.DB.Create(intent).Error
+	if err == nil {
+		return nil
+	}
+	var open int64
+	countErr := r.DB.Model(&entity.PaymentIntent{}).
+		Where("order_id = ? AND status IN ?", intent.OrderID, openPaymentStatuses).
+		Count(&open).Error
+	if countErr == nil && open > 0 {
+		return ErrPaymentInProgress
+	}
+	return err
+}
+
+// openPaymentStatuses are the statuses idx_payment_intents_active_order
+// allows once per order.
+var openPaymentStatuses = []entity.PaymentStatus{entity.PaymentPending, entity.PaymentAuthorized, entity.PaymentCaptured}
+
+// Update stores intent and, when transition is not nil, the order status
+// change it caused, in one transaction. The transition is refused with
+// ErrOrderStatusChanged like in OrderRepository.SaveTransition.
+func (r *PaymentRepository) Update(intent *entity.PaymentIntent, order *entity.Order, transition *entity.OrderTransition) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(intent).Error; err != nil {
+			return err
+		}
+		if transition == nil {
+			return nil
+		}
+		return saveOrderTransition(tx, order, transition)
+	})
+}
+
+func (r *PaymentRepository) FindByID(id string) (*entity.PaymentIntent, error) {
+	var intent entity.PaymentIntent
+	if err := r.DB.Where("id = ?", id).First(&intent).Error; err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// FindByReference finds the intent a gateway knows by reference.
+func (r *PaymentRepository) FindByReference(provider, reference string) (*entity.PaymentIntent, error) {
+	var intent entity.PaymentIntent
+	err := r.DB.Where("provider = ? AND reference = ?", provider, reference).First(&intent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// FindByOrder lists an order's payment intents, oldest first.
+func (r *PaymentRepository) FindByOrder(orderID string) ([]*entity.PaymentIntent, error) {
+	var intents []*entity.PaymentIntent
+	err := r.DB.Where("order_id = ?", orderID).Order("created_at").Find(&intents).Error
+	return intents, err
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayment_Update(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.PaymentIntent{}))
+	repo := NewPaymentRepository(orders.DB)
+
+	order := newTestOrder(t, pkgentity.NewID(), createTestProduct(t))
+	require.NoError(t, orders.Create(order))
+	intent, err := entity.NewPaymentIntent(order, "fake")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(intent))
+
+	t.Run("should save the intent and the order transition together", func(t *testing.T) {
+		require.NoError(t, intent.Authorized("ref_1"))
+		require.NoError(t, intent.Capture())
+		transition, err := order.Transition(entity.OrderPaid, nil, "payment captured")
+		require.NoError(t, err)
+		require.NoError(t, repo.Update(intent, order, transition))
+
+		found, err := repo.FindByReference("fake", "ref_1")
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentCaptured, found.Status)
+		assert.Equal(t, order.Total, found.Amount)
+
+		stored, err := orders.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderPaid, stored.Status)
+	})
+
+	t.Run("should roll back the intent when the order moved on", func(t *testing.T) {
+		stale, err := orders.FindByID(order.ID.String())
+		require.NoError(t, err)
+		transition, err := order.Transition(entity.OrderFulfilled, nil, "")
+		require.NoError(t, err)
+		require.NoError(t, orders.SaveTransition(order, transition))
+
+		require.NoError(t, intent.Refund(intent.Amount))
+		transition, err = stale.Transition(entity.OrderRefunded, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, ErrOrderStatusChanged, repo.Update(intent, stale, transition))
+
+		found, err := repo.FindByID(intent.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentCaptured, found.Status)
+
+		intents, err := repo.FindByOrder(order.ID.String())
+		require.NoError(t, err)
+		assert.Len(t, intents, 1)
+	})
+}
+
+func TestPayment_Create(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.PaymentIntent{}))
+	require.NoError(t, orders.DB.Exec(activePaymentIndex).Error)
+	repo := NewPaymentRepository(orders.DB)
+
+	order := newTestOrder(t, pkgentity.NewID(), createTestProduct(t))
+	require.NoError(t, orders.Create(order))
+	newIntent := func() *entity.PaymentIntent {
+		intent, err := entity.NewPaymentIntent(order, "fake")
+		require.NoError(t, err)
+		return intent
+	}
+
+	t.Run("should allow one open intent per order", func(t *testing.T) {
+		first := newIntent()
+		require.NoError(t, repo.Create(first))
+		assert.ErrorIs(t, repo.Create(newIntent()), ErrPaymentInProgress)
+
+		require.NoError(t, first.Fail("ref_1", "card_declined"))
+		require.NoError(t, repo.Update(first, order, nil))
+		require.NoError(t, repo.Create(newIntent()))
+		assert.ErrorIs(t, repo.Create(newIntent()), ErrPaymentInProgress)
+
+		intents, err := repo.FindByOrder(order.ID.String())
+		require.NoError(t, err)
+		assert.Len(t, intents, 2)
+	})
+}
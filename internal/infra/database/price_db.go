@@ -0,0 +1,114 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceListRepository struct {
+	DB *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) *PriceListRepository {
+	return &PriceListRepository{DB: db}
+}
+
+// SetPrice creates or replaces the product's price in price.Currency.
+func (r *PriceListRepository) SetPrice(price *entity.ProductPrice) error {
+	if price == nil {
+		return errors.New("price cannot be nil")
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount"}),
+	}).Create(price).Error
+}
+
+// DeletePrice removes an explicit price, returning gorm.ErrRecordNotFound
+// when there was none.
+func (r *PriceListRepository) DeletePrice(productID, currency string) error {
+	result := r.DB.Where("product_id = ? AND currency = ?", productID, currency).Delete(&entity.ProductPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *PriceListRepository) FindPrices(productID string) ([]*entity.ProductPrice, error) {
+	if strings.TrimSpace(productID) == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+
+	var prices []*entity.ProductPrice
+	err := r.DB.Where("product_id = ?", productID).Order("currency").Find(&prices).Error
+	return prices, err
+}
+
+// FindPricesIn returns the explicit prices in currency for the given
+// products, keyed by product ID. Products without one are absent.
+func (r *PriceListRepository) FindPricesIn(productIDs []string, currency string) (map[string]pkgentity.Money, error) {
+	result := make(map[string]pkgentity.Money, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	var prices []*entity.ProductPrice
+	err := r.DB.Where("product_id IN ? AND currency = ?", productIDs, currency).Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range prices {
+		result[p.ProductID.String()] = p.Money()
+	}
+	return result, nil
+}
+
+type ExchangeRateRepository struct {
+	DB *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{DB: db}
+}
+
+// Replace swaps the whole rate table for rates in one transaction.
+func (r *ExchangeRateRepository) Replace(rates []*entity.ExchangeRate) error {
+	for _, rate := range rates {
+		if err := rate.Validate(); err != nil {
+			return err
+		}
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entity.ExchangeRate{}).Error; err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return nil
+		}
+		return tx.Create(rates).Error
+	})
+}
+
+func (r *ExchangeRateRepository) FindAll() ([]*entity.ExchangeRate, error) {
+	var rates []*entity.ExchangeRate
+	err := r.DB.Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+// Load returns a converter over the current rate table.
+func (r *ExchangeRateRepository) Load() (*entity.ExchangeRates, error) {
+	rates, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewExchangeRates(rates)
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupPriceTestDB(t *testing.T) *gorm.DB {
+	db := setupProductTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entity.ExchangeRate{}))
+	return db
+}
+
+func TestPriceList(t *testing.T) {
+	db := setupPriceTestDB(t)
+	products := NewProductRepository(db)
+	prices := NewPriceListRepository(db)
+
+	product := createTestProduct(t)
+	require.NoError(t, products.Create(product))
+	other := createTestProduct(t)
+	require.NoError(t, products.Create(other))
+
+	usd := func(amount string) *entity.ProductPrice {
+		p, err := entity.NewProductPrice(product, pkgentity.MustParseMoney(amount, "USD"))
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("should set and replace a price", func(t *testing.T) {
+		require.NoError(t, prices.SetPrice(usd("19.90")))
+		require.NoError(t, prices.SetPrice(usd("21.00")))
+
+		found, err := prices.FindPrices(product.ID.String())
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "21.00", found[0].Money().Decimal())
+	})
+
+	t.Run("should find prices for several products", func(t *testing.T) {
+		byID, err := prices.FindPricesIn([]string{product.ID.String(), other.ID.String()}, "USD")
+		require.NoError(t, err)
+		assert.Len(t, byID, 1)
+		assert.Equal(t, "21.00", byID[product.ID.String()].Decimal())
+	})
+
+	t.Run("should delete a price", func(t *testing.T) {
+		require.NoError(t, prices.DeletePrice(product.ID.String(), "USD"))
+		assert.ErrorIs(t, prices.DeletePrice(product.ID.String(), "USD"), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should drop prices when the product is purged", func(t *testing.T) {
+		require.NoError(t, prices.SetPrice(usd("5.00")))
+		require.NoError(t, products.Delete(product.ID.String(), product.Version))
+		found, err := prices.FindPrices(product.ID.String())
+		require.NoError(t, err)
+		assert.Len(t, found, 1, "the trash keeps the prices for a restore")
+		_, err = products.Purge(time.Now().Add(time.Second))
+		require.NoError(t, err)
+
+		found, err = prices.FindPrices(product.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
+
+func TestExchangeRateRepository(t *testing.T) {
+	db := setupPriceTestDB(t)
+	repo := NewExchangeRateRepository(db)
+
+	rates, err := entity.ParseExchangeRates(map[string]string{"USD": "0.2", "EUR": "0.18"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Replace(rates))
+
+	rates, err = entity.ParseExchangeRates(map[string]string{"USD": "0.19"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Replace(rates))
+
+	all, err := repo.FindAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "0.19", all[0].Rate)
+
+	x, err := repo.Load()
+	require.NoError(t, err)
+	m, err := x.Convert(pkgentity.MustParseMoney("100", "BRL"), "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "19.00", m.Decimal())
+
+	_, err = x.Convert(pkgentity.MustParseMoney("100", "BRL"), "EUR")
+	assert.ErrorIs(t, err, entity.ErrRateNotFound)
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrCursorSortMissing = errors.New("cursor pagination only supports sorting by created_at")
+)
+
+// ProductCursor points at a row in the (created_at, id) keyset order. It is
+// handed to clients as an opaque token.
+type ProductCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	// Backward is set on "prev" cursors: the page ends right before the row.
+	Backward bool `json:"b,omitempty"`
+	// Desc records the sort direction the cursor was issued for.
+	Desc bool `json:"d,omitempty"`
+}
+
+func (c ProductCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeProductCursor(token string) (*ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c ProductCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type ProductCursorQuery struct {
+	Filter ProductFilter
+	// Desc sorts newest first.
+	Desc   bool
+	Cursor *ProductCursor
+	Limit  int
+	// WithTotal also counts every row matching Filter.
+	WithTotal bool
+}
+
+type ProductPage struct {
+	Products []*entity.Product
+	Next     *ProductCursor
+	Prev     *ProductCursor
+	Total    *int64
+}
+
+// CursorSortDesc reports the created_at direction for a parsed sort, or
+// ErrCursorSortMissing when the sort uses any other field.
+func CursorSortDesc(fields []SortField) (bool, error) {
+	switch {
+	case len(fields) == 0:
+		return false, nil
+	case len(fields) == 1 && fields[0].Field == "created_at":
+		return fields[0].Desc, nil
+	}
+	return false, ErrCursorSortMissing
+}
+
+// ListByCursor pages through products with keyset pagination on
+// (created_at, id), which stays stable while rows are inserted.
+func (p *ProductRepository) ListByCursor(q ProductCursorQuery) (*ProductPage, error) {
+	if q.Limit <= 0 {
+		return nil, errors.New("limit must be greater than 0")
+	}
+	if err := q.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	if q.Cursor != nil && q.Cursor.Desc != q.Desc {
+		return nil, ErrInvalidCursor
+	}
+
+	page := &ProductPage{}
+	if q.WithTotal {
+		total, err := p.CountProducts(q.Filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	backward := q.Cursor != nil && q.Cursor.Backward
+	// Walking backward means scanning the opposite direction and reversing.
+	scanDesc := q.Desc != backward
+
+	op := ">"
+	if scanDesc {
+		op = "<"
+	}
+	query := q.Filter.apply(p.DB.Model(&entity.Product{}))
+	if q.Cursor != nil {
+		query = query.Where(
+			"((created_at "+op+" ?) OR (created_at = ? AND id "+op+" ?))",
+			q.Cursor.CreatedAt, q.Cursor.CreatedAt, q.Cursor.ID,
+		)
+	}
+	query = query.Order(clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: "created_at"}, Desc: scanDesc},
+		{Column: clause.Column{Name: "id"}, Desc: scanDesc},
+	}})
+
+	var products []*entity.Product
+	if err := query.Limit(q.Limit + 1).Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(products) > q.Limit
+	if hasMore {
+		products = products[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(products)
+	}
+	if err := loadProductTags(p.DB, products); err != nil {
+		return nil, err
+	}
+	page.Products = products
+	if len(products) == 0 {
+		return page, nil
+	}
+
+	first, last := products[0], products[len(products)-1]
+	if backward {
+		page.Prev = cursorFor(first, true, q.Desc, hasMore)
+		page.Next = cursorFor(last, false, q.Desc, true)
+	} else {
+		page.Prev = cursorFor(first, true, q.Desc, q.Cursor != nil)
+		page.Next = cursorFor(last, false, q.Desc, hasMore)
+	}
+	return page, nil
+}
+
+func cursorFor(product *entity.Product, backward, desc, ok bool) *ProductCursor {
+	if !ok {
+		return nil
+	}
+	return &ProductCursor{CreatedAt: product.CreatedAt, ID: product.ID.String(), Backward: backward, Desc: desc}
+}
+
+// CountProducts counts the products matching filter.
+func (p *ProductRepository) CountProducts(filter ProductFilter) (int64, error) {
+	var total int64
+	err := filter.apply(p.DB.Model(&entity.Product{})).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCursorProducts(t *testing.T, repo *ProductRepository, n int) []*entity.Product {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var products []*entity.Product
+	for i := 0; i < n; i++ {
+		product, err := entity.NewProduct(fmt.Sprintf("p%d", i), "", brl("10"))
+		require.NoError(t, err)
+		// Pairs share a timestamp so the id tie-breaker is exercised.
+		product.CreatedAt = base.Add(time.Duration(i/2) * time.Minute)
+		require.NoError(t, repo.Create(product))
+		products = append(products, product)
+	}
+	return products
+}
+
+func collectNames(products []*entity.Product) []string {
+	names := make([]string, 0, len(products))
+	for _, p := range products {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestProduct_ListByCursor(t *testing.T) {
+	t.Run("should walk forward and backward without gaps or duplicates", func(t *testing.T) {
+		repo := NewProductRepository(setupProductTestDB(t))
+		createCursorProducts(t, repo, 5)
+
+		all, err := repo.List(ProductQuery{Page: 1, Limit: 10})
+		require.NoError(t, err)
+
+		var seen []string
+		var pages []*ProductPage
+		var cursor *ProductCursor
+		for {
+			page, err := repo.ListByCursor(ProductCursorQuery{Cursor: cursor, Limit: 2})
+			require.NoError(t, err)
+			pages = append(pages, page)
+			seen = append(seen, collectNames(page.Products)...)
+			if page.Next == nil {
+				break
+			}
+			cursor = page.Next
+		}
+		assert.Equal(t, collectNames(all), seen)
+		require.Len(t, pages, 3)
+		assert.Nil(t, pages[0].Prev)
+
+		back, err := repo.ListByCursor(ProductCursorQuery{Cursor: pages[2].Prev, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, collectNames(pages[1].Products), collectNames(back.Products))
+
+		back, err = repo.ListByCursor(ProductCursorQuery{Cursor: back.Prev, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, collectNames(pages[0].Products), collectNames(back.Products))
+		assert.Nil(t, back.Prev)
+	})
+
+	t.Run("should not repeat rows when products are inserted between pages", func(t *testing.T) {
+		repo := NewProductRepository(setupProductTestDB(t))
+		createCursorProducts(t, repo, 4)
+
+		first, err := repo.ListByCursor(ProductCursorQuery{Desc: true, Limit: 2})
+		require.NoError(t, err)
+
+		newest, err := entity.NewProduct("newest", "", brl("10"))
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(newest))
+
+		second, err := repo.ListByCursor(ProductCursorQuery{Desc: true, Cursor: first.Next, Limit: 2})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"p3", "p2"}, collectNames(first.Products))
+		assert.ElementsMatch(t, []string{"p1", "p0"}, collectNames(second.Products))
+	})
+
+	t.Run("should count the filtered total when asked", func(t *testing.T) {
+		repo := NewProductRepository(setupProductTestDB(t))
+		createCursorProducts(t, repo, 5)
+
+		page, err := repo.ListByCursor(ProductCursorQuery{
+			Filter: ProductFilter{NameContains: "p1"}, Limit: 2, WithTotal: true,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, page.Total)
+		assert.Equal(t, int64(1), *page.Total)
+	})
+
+	t.Run("should reject a cursor issued for the other direction", func(t *testing.T) {
+		repo := NewProductRepository(setupProductTestDB(t))
+		createCursorProducts(t, repo, 3)
+
+		page, err := repo.ListByCursor(ProductCursorQuery{Limit: 1})
+		require.NoError(t, err)
+
+		_, err = repo.ListByCursor(ProductCursorQuery{Desc: true, Cursor: page.Next, Limit: 1})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestProductCursor_EncodeDecode(t *testing.T) {
+	cursor := ProductCursor{
+		CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC),
+		ID:        "3f6c1f0e-1c2b-4a5d-9e8f-0a1b2c3d4e5f",
+		Backward:  true,
+	}
+
+	decoded, err := DecodeProductCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, decoded.Backward)
+
+	_, err = DecodeProductCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -2,15 +2,25 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
 
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict is returned by the Update methods of versioned
+// records when the stored version is no longer the one the caller read.
+var ErrVersionConflict = errors.New("record was changed since it was read")
+
 type ProductRepository struct {
 	DB *gorm.DB
+	// Actor is recorded in the history as the author of the changes made
+	// through the repository; see As.
+	Actor *pkgentity.ID
 }
 
 func NewProductRepository(db *gorm.DB) *ProductRepository {
@@ -24,11 +34,22 @@ func (r *ProductRepository) Create(product *entity.Product) error {
 	if strings.TrimSpace(product.Name) == "" {
 		return entity.ErrNameIsRequired
 	}
-	if product.Price <= 0 {
+	if !product.Price.IsPositive() {
 		return entity.ErrPriceIsRequired
 	}
+	if product.Version == 0 {
+		product.Version = 1
+	}
 
-	return r.DB.Create(product).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		if err := saveProductTags(tx, product); err != nil {
+			return err
+		}
+		return recordRevision(tx, entity.NewProductRevision(product, entity.RevisionCreated, nil, r.Actor))
+	})
 }
 
 func (p *ProductRepository) FindByID(id string) (*entity.Product, error) {
@@ -40,50 +61,225 @@ func (p *ProductRepository) FindByID(id string) (*entity.Product, error) {
 	if err := p.DB.Where("id = ?", id).First(&product).Error; err != nil {
 		return nil, err
 	}
+	if err := loadProductTags(p.DB, []*entity.Product{&product}); err != nil {
+		return nil, err
+	}
 	return &product, nil
 }
 
 func (p *ProductRepository) FindAll(page int, limit int, sort string) ([]*entity.Product, error) {
-	var products []*entity.Product
-
 	if sort != "" && sort != "asc" && sort != "desc" {
 		return nil, errors.New("sort must be 'asc' or 'desc'")
 	}
 
-	if page <= 0 || limit <= 0 {
+	sortFields, err := ParseProductSort(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.List(ProductQuery{Sort: sortFields, Page: page, Limit: limit})
+}
+
+// List returns one page of products matching q.Filter, ordered by q.Sort
+// (created_at ascending when empty).
+func (p *ProductRepository) List(q ProductQuery) ([]*entity.Product, error) {
+	var products []*entity.Product
+
+	if q.Page <= 0 || q.Limit <= 0 {
 		return nil, errors.New("page and limit must be greater than 0")
 	}
+	if err := q.Filter.Validate(); err != nil {
+		return nil, err
+	}
 
-	if sort == "" {
-		sort = "asc"
+	sortFields := q.Sort
+	if len(sortFields) == 0 {
+		sortFields = []SortField{{Field: "created_at"}}
+	}
+	for _, field := range sortFields {
+		if _, ok := productSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSortField, field.Field)
+		}
 	}
 
-	query := p.DB.Order("created_at " + sort)
+	query := q.Filter.apply(p.DB.Model(&entity.Product{}))
+	query = applySort(query, sortFields)
 
-	offset := (page - 1) * limit
-	query = query.Limit(limit).Offset(offset)
+	offset := (q.Page - 1) * q.Limit
+	query = query.Limit(q.Limit).Offset(offset)
 
 	if err := query.Find(&products).Error; err != nil {
 		return nil, err
 	}
+	if err := loadProductTags(p.DB, products); err != nil {
+		return nil, err
+	}
 
 	return products, nil
 }
 
+// Update saves the product if it is still at the version it was read at,
+// and moves it to the next version; a product changed in the meantime
+// gives ErrVersionConflict and is left alone. When its price goes down,
+// users who have it on a wishlist get a price drop recorded.
 func (p *ProductRepository) Update(product *entity.Product) error {
+	return p.update(product, entity.RevisionUpdated, nil)
+}
+
+// update saves product and records action in its history; updates that
+// change nothing are left out of it.
+func (p *ProductRepository) update(product *entity.Product, action entity.RevisionAction, revertOf *int64) error {
 	if product == nil {
 		return errors.New("product cannot be nil")
 	}
-	_, err := p.FindByID(product.ID.String())
+	existing, err := p.FindByID(product.ID.String())
 	if err != nil {
 		return err
 	}
-	return p.DB.Save(product).Error
+	if existing.Version != product.Version {
+		return ErrVersionConflict
+	}
+	version := product.Version
+	err = p.DB.Transaction(func(tx *gorm.DB) error {
+		product.Version = version + 1
+		result := tx.Model(product).Where("version = ?", version).Select("*").Updates(product)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := saveProductTags(tx, product); err != nil {
+			return err
+		}
+		if err := recordPriceDrops(tx, product.ID, existing.Price, product.Price); err != nil {
+			return err
+		}
+		before := entity.NewProductSnapshot(existing)
+		revision := entity.NewProductRevision(product, action, &before, p.Actor)
+		if action == entity.RevisionUpdated && len(revision.Changes) == 0 {
+			return nil
+		}
+		revision.RevertOf = revertOf
+		return recordRevision(tx, revision)
+	})
+	if err != nil {
+		product.Version = version
+	}
+	return err
 }
 
-func (p *ProductRepository) Delete(id string) error {
+// Delete moves the product to the trash. Its prices, categories, tags,
+// variants and wishlist entries stay so Restore can bring it back whole;
+// Purge removes them with the product. A product no longer at version
+// gives ErrVersionConflict and stays where it is.
+func (p *ProductRepository) Delete(id string, version int64) error {
 	if strings.TrimSpace(id) == "" {
 		return errors.New("id cannot be empty")
 	}
-	return p.DB.Delete(&entity.Product{}, "id = ?", id).Error
+	product, err := p.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if product.Version != version {
+		return ErrVersionConflict
+	}
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entity.Product{}, "id = ? AND version = ?", id, version)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		unchanged := entity.NewProductSnapshot(product)
+		return recordRevision(tx, entity.NewProductRevision(product, entity.RevisionDeleted, &unchanged, p.Actor))
+	})
+}
+
+// FindDeleted returns one page of the trash, most recently deleted first.
+func (p *ProductRepository) FindDeleted(page, limit int) ([]*entity.Product, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var products []*entity.Product
+	err := p.DB.Unscoped().Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC, id").Limit(limit).Offset((page - 1) * limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := loadProductTags(p.DB, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Restore takes a product out of the trash.
+func (p *ProductRepository) Restore(id string) error {
+	var product entity.Product
+	if err := p.DB.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&product).Error; err != nil {
+		return err
+	}
+	if err := loadProductTags(p.DB, []*entity.Product{&product}); err != nil {
+		return err
+	}
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&entity.Product{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		unchanged := entity.NewProductSnapshot(&product)
+		return recordRevision(tx, entity.NewProductRevision(&product, entity.RevisionRestored, &unchanged, p.Actor))
+	})
+}
+
+// Purge permanently removes the products deleted before deletedBefore,
+// with everything that hangs off them: prices, tags, categories, variants
+// and their stock, reviews, cart and wishlist lines, price drops and the
+// history. Orders keep their own copy of what was bought. It returns how
+// many products it removed.
+func (p *ProductRepository) Purge(deletedBefore time.Time) (int, error) {
+	var ids []string
+	err := p.DB.Unscoped().Model(&entity.Product{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	err = p.DB.Transaction(func(tx *gorm.DB) error {
+		// Stock is kept per variant, so it goes before the variants do.
+		var variantIDs []string
+		err := tx.Model(&entity.ProductVariant{}).Where("product_id IN ?", ids).Pluck("id", &variantIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(variantIDs) > 0 {
+			for _, model := range []any{&entity.StockLevel{}, &entity.StockReservation{}, &entity.StockMovement{}} {
+				if err := tx.Where("variant_id IN ?", variantIDs).Delete(model).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for _, model := range []any{
+			&entity.ProductPrice{}, &entity.ProductCategory{}, &entity.ProductTag{},
+			&entity.ProductOption{}, &entity.ProductVariant{}, &entity.WishlistItem{},
+			&entity.PriceDrop{}, &entity.Review{}, &entity.CartItem{},
+			&entity.ProductRevision{},
+		} {
+			if err := tx.Where("product_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&entity.Product{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
 }
@@ -1,9 +1,12 @@
 package database
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,14 +18,23 @@ func setupProductTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&entity.Product{})
+	err = db.AutoMigrate(&entity.Product{}, &entity.ProductPrice{}, &entity.ProductCategory{}, &entity.ProductTag{},
+		&entity.ProductRevision{},
+		&entity.ProductOption{}, &entity.ProductVariant{},
+		&entity.Wishlist{}, &entity.WishlistItem{}, &entity.PriceDrop{},
+		&entity.Review{}, &entity.CartItem{},
+		&entity.StockLevel{}, &entity.StockReservation{}, &entity.StockMovement{})
 	require.NoError(t, err)
 
 	return db
 }
 
+func brl(amount string) pkgentity.Money {
+	return pkgentity.MustParseMoney(amount, "BRL")
+}
+
 func createTestProduct(t *testing.T) *entity.Product {
-	product, err := entity.NewProduct("testproduct", "descricao x", 100.0)
+	product, err := entity.NewProduct("testproduct", "descricao x", brl("100.00"))
 	require.NoError(t, err)
 	return product
 }
@@ -213,6 +225,27 @@ func TestProduct_Update(t *testing.T) {
 		foundProduct, err := productRepo.FindByID(product.ID.String())
 		require.NoError(t, err)
 		assert.Equal(t, "updated name", foundProduct.Name)
+		assert.Equal(t, int64(2), foundProduct.Version)
+	})
+	t.Run("should reject updates to a stale version", func(t *testing.T) {
+		db := setupProductTestDB(t)
+		productRepo := NewProductRepository(db)
+		product := createTestProduct(t)
+		require.NoError(t, productRepo.Create(product))
+		stale, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+
+		product.Name = "first editor"
+		require.NoError(t, productRepo.Update(product))
+
+		stale.Name = "second editor"
+		assert.ErrorIs(t, productRepo.Update(stale), ErrVersionConflict)
+		assert.Equal(t, int64(1), stale.Version)
+
+		foundProduct, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "first editor", foundProduct.Name)
+		assert.Equal(t, int64(2), foundProduct.Version)
 	})
 }
 
@@ -224,7 +257,7 @@ func TestProduct_Delete(t *testing.T) {
 		err := productRepo.Create(product)
 		require.NoError(t, err)
 
-		err = productRepo.Delete(product.ID.String())
+		err = productRepo.Delete(product.ID.String(), product.Version)
 		require.NoError(t, err)
 
 		foundProduct, err := productRepo.FindByID(product.ID.String())
@@ -235,8 +268,284 @@ func TestProduct_Delete(t *testing.T) {
 		db := setupProductTestDB(t)
 		productRepo := NewProductRepository(db)
 
-		err := productRepo.Delete("non-existent-id")
+		err := productRepo.Delete("non-existent-id", 1)
 		assert.Error(t, err)
 		assert.Equal(t, gorm.ErrRecordNotFound, err)
 	})
+	t.Run("should keep a product changed since it was read", func(t *testing.T) {
+		db := setupProductTestDB(t)
+		productRepo := NewProductRepository(db)
+		product := createTestProduct(t)
+		require.NoError(t, productRepo.Create(product))
+		stale := product.Version
+		product.Name = "Renamed"
+		require.NoError(t, productRepo.Update(product))
+
+		assert.ErrorIs(t, productRepo.Delete(product.ID.String(), stale), ErrVersionConflict)
+		_, err := productRepo.FindByID(product.ID.String())
+		assert.NoError(t, err)
+	})
+}
+
+func TestProduct_Trash(t *testing.T) {
+	db := setupProductTestDB(t)
+	productRepo := NewProductRepository(db)
+	product := createTestProduct(t)
+	require.NoError(t, productRepo.Create(product))
+	require.NoError(t, productRepo.Delete(product.ID.String(), product.Version))
+
+	t.Run("should hide deleted products from normal queries", func(t *testing.T) {
+		_, err := productRepo.FindByID(product.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		products, err := productRepo.List(ProductQuery{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, products)
+		assert.ErrorIs(t, productRepo.Delete(product.ID.String(), product.Version), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should list and restore the trash", func(t *testing.T) {
+		trash, err := productRepo.FindDeleted(1, 10)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.True(t, trash[0].DeletedAt.Valid)
+
+		require.NoError(t, productRepo.Restore(product.ID.String()))
+		assert.ErrorIs(t, productRepo.Restore(product.ID.String()), gorm.ErrRecordNotFound)
+		_, err = productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+	})
+
+	t.Run("should purge only products deleted before the cutoff", func(t *testing.T) {
+		require.NoError(t, productRepo.Delete(product.ID.String(), product.Version))
+		purged, err := productRepo.Purge(time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		purged, err = productRepo.Purge(time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		trash, err := productRepo.FindDeleted(1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+}
+
+func TestProduct_PurgeDependents(t *testing.T) {
+	db := setupProductTestDB(t)
+	productRepo := NewProductRepository(db)
+	inventory := NewInventoryRepository(db)
+	warehouse, err := entity.NewWarehouse("sp-01", "São Paulo")
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entity.Warehouse{}))
+	require.NoError(t, inventory.CreateWarehouse(warehouse))
+
+	// addDependents gives product a row in every table that refers to it
+	// or to its variant, and returns the variant's ID.
+	addDependents := func(t *testing.T, product *entity.Product) pkgentity.ID {
+		userID := pkgentity.NewID()
+		variant := &entity.ProductVariant{
+			ID: pkgentity.NewID(), ProductID: product.ID, SKU: "SKU-" + product.ID.String(),
+			Options: map[string]string{}, OptionsKey: "",
+		}
+		review, err := entity.NewReview(product.ID, userID, 5, "Great", "")
+		require.NoError(t, err)
+		for _, row := range []any{
+			variant, review,
+			&entity.ProductPrice{ProductID: product.ID, Currency: "USD", Amount: 200},
+			&entity.ProductTag{ProductID: product.ID, Tag: "sale"},
+			&entity.ProductCategory{ProductID: product.ID, CategoryID: pkgentity.NewID()},
+			&entity.ProductOption{ProductID: product.ID, Name: "size", Values: []string{"M"}},
+			&entity.CartItem{
+				ID: pkgentity.NewID(), CartID: pkgentity.NewID(), LineKey: product.ID.String(),
+				ProductID: product.ID, Name: product.Name, UnitPrice: product.Price, Quantity: 1,
+			},
+			entity.NewWishlistItem(pkgentity.NewID(), product),
+			entity.NewPriceDrop(userID, product.ID, brl("20"), product.Price),
+		} {
+			require.NoError(t, db.Create(row).Error)
+		}
+		_, err = inventory.Adjust(variant.ID, warehouse.ID, 5, "")
+		require.NoError(t, err)
+		_, err = inventory.Reserve(nil, variant.ID, nil, 1, time.Minute, "")
+		require.NoError(t, err)
+		return variant.ID
+	}
+	// remaining counts, per table, the rows left for a product and its
+	// variant.
+	remaining := func(t *testing.T, productID, variantID pkgentity.ID) map[string]int64 {
+		counts := map[string]int64{}
+		for _, model := range []any{
+			&entity.ProductPrice{}, &entity.ProductCategory{}, &entity.ProductTag{},
+			&entity.ProductOption{}, &entity.ProductVariant{}, &entity.WishlistItem{},
+			&entity.PriceDrop{}, &entity.Review{}, &entity.CartItem{}, &entity.ProductRevision{},
+		} {
+			var count int64
+			require.NoError(t, db.Model(model).Where("product_id = ?", productID).Count(&count).Error)
+			counts[fmt.Sprintf("%T", model)] = count
+		}
+		for _, model := range []any{&entity.StockLevel{}, &entity.StockReservation{}, &entity.StockMovement{}} {
+			var count int64
+			require.NoError(t, db.Model(model).Where("variant_id = ?", variantID).Count(&count).Error)
+			counts[fmt.Sprintf("%T", model)] = count
+		}
+		return counts
+	}
+
+	purged, kept := createTestProduct(t), createTestProduct(t)
+	for _, product := range []*entity.Product{purged, kept} {
+		require.NoError(t, productRepo.Create(product))
+	}
+	purgedVariant, keptVariant := addDependents(t, purged), addDependents(t, kept)
+	require.NoError(t, productRepo.Delete(purged.ID.String(), purged.Version))
+
+	t.Run("should leave no rows referring to purged products or their variants", func(t *testing.T) {
+		count, err := productRepo.Purge(time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		for table, left := range remaining(t, purged.ID, purgedVariant) {
+			assert.Zero(t, left, table)
+		}
+		for table, left := range remaining(t, kept.ID, keptVariant) {
+			assert.NotZero(t, left, table)
+		}
+	})
+}
+
+func TestProduct_List(t *testing.T) {
+	setup := func(t *testing.T) (*ProductRepository, []*entity.Product) {
+		db := setupProductTestDB(t)
+		productRepo := NewProductRepository(db)
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		var products []*entity.Product
+		for i, p := range []struct {
+			name  string
+			price string
+		}{
+			{"Blue shirt", "50"},
+			{"Red shirt", "30"},
+			{"Green hat", "30"},
+			{"Blue hat", "80"},
+		} {
+			product, err := entity.NewProduct(p.name, "", brl(p.price))
+			require.NoError(t, err)
+			product.CreatedAt = base.AddDate(0, 0, i)
+			product.UpdatedAt = product.CreatedAt
+			require.NoError(t, productRepo.Create(product))
+			products = append(products, product)
+		}
+		return productRepo, products
+	}
+	names := func(products []*entity.Product) []string {
+		var out []string
+		for _, p := range products {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	ptr := func(amount string) *pkgentity.Money { m := brl(amount); return &m }
+
+	t.Run("should filter by price range", func(t *testing.T) {
+		productRepo, _ := setup(t)
+
+		found, err := productRepo.List(ProductQuery{
+			Filter: ProductFilter{MinPrice: ptr("30"), MaxPrice: ptr("50")},
+			Page:   1, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Blue shirt", "Red shirt", "Green hat"}, names(found))
+	})
+
+	t.Run("should filter by name substring case-insensitively", func(t *testing.T) {
+		productRepo, _ := setup(t)
+
+		found, err := productRepo.List(ProductQuery{Filter: ProductFilter{NameContains: "BLUE"}, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Blue shirt", "Blue hat"}, names(found))
+	})
+
+	t.Run("should treat LIKE wildcards literally", func(t *testing.T) {
+		productRepo, _ := setup(t)
+
+		found, err := productRepo.List(ProductQuery{Filter: ProductFilter{NameContains: "%"}, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("should filter by creation date range", func(t *testing.T) {
+		productRepo, products := setup(t)
+
+		from, to := products[1].CreatedAt, products[2].CreatedAt
+		found, err := productRepo.List(ProductQuery{
+			Filter: ProductFilter{CreatedAfter: &from, CreatedBefore: &to},
+			Page:   1, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Red shirt", "Green hat"}, names(found))
+	})
+
+	t.Run("should filter by IDs", func(t *testing.T) {
+		productRepo, products := setup(t)
+
+		found, err := productRepo.List(ProductQuery{
+			Filter: ProductFilter{IDs: []string{products[0].ID.String(), products[3].ID.String()}},
+			Page:   1, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Blue shirt", "Blue hat"}, names(found))
+	})
+
+	t.Run("should sort by multiple fields", func(t *testing.T) {
+		productRepo, _ := setup(t)
+		sort, err := ParseProductSort("-price,name")
+		require.NoError(t, err)
+
+		found, err := productRepo.List(ProductQuery{Sort: sort, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Blue hat", "Blue shirt", "Green hat", "Red shirt"}, names(found))
+	})
+
+	t.Run("should sort prices within each currency", func(t *testing.T) {
+		productRepo, _ := setup(t)
+		for _, p := range []struct{ name, price string }{{"Cap", "10"}, {"Scarf", "90"}} {
+			product, err := entity.NewProduct(p.name, "", pkgentity.MustParseMoney(p.price, "USD"))
+			require.NoError(t, err)
+			require.NoError(t, productRepo.Create(product))
+		}
+		sort, err := ParseProductSort("price,name")
+		require.NoError(t, err)
+
+		found, err := productRepo.List(ProductQuery{Sort: sort, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Green hat", "Red shirt", "Blue shirt", "Blue hat", "Cap", "Scarf"}, names(found))
+	})
+
+	t.Run("should reject an inverted price range", func(t *testing.T) {
+		productRepo, _ := setup(t)
+
+		_, err := productRepo.List(ProductQuery{
+			Filter: ProductFilter{MinPrice: ptr("50"), MaxPrice: ptr("10")},
+			Page:   1, Limit: 10,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseProductSort(t *testing.T) {
+	fields, err := ParseProductSort("-price, name")
+	require.NoError(t, err)
+	assert.Equal(t, []SortField{{Field: "price", Desc: true}, {Field: "name"}}, fields)
+
+	fields, err = ParseProductSort("desc")
+	require.NoError(t, err)
+	assert.Equal(t, []SortField{{Field: "created_at", Desc: true}}, fields)
+
+	_, err = ParseProductSort("password")
+	assert.ErrorIs(t, err, ErrInvalidSortField)
+
+	_, err = ParseProductSort("name,-name")
+	assert.ErrorIs(t, err, ErrInvalidSortField)
+
+	_, err = ParseProductSort("price;DROP TABLE products")
+	assert.ErrorIs(t, err, ErrInvalidSortField)
 }
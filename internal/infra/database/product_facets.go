@@ -0,0 +1,121 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type FacetCount struct {
+	Value string
+	Count int64
+}
+
+// PriceBucket counts products priced in [Min, Max). Min is nil for the
+// first bucket and Max for the last.
+type PriceBucket struct {
+	Min   *pkgentity.Money
+	Max   *pkgentity.Money
+	Count int64
+}
+
+type ProductFacets struct {
+	Total      int64
+	Tags       []FacetCount
+	Categories []FacetCount
+	// Prices only counts products priced in the bounds' currency.
+	Prices []PriceBucket
+}
+
+// Facets counts the products matching filter by tag, by directly assigned
+// category and by price bucket. bounds split the price range: n bounds
+// make n+1 buckets; they must share a currency and be increasing.
+func (p *ProductRepository) Facets(filter ProductFilter, bounds []pkgentity.Money) (*ProductFacets, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i].Currency != bounds[0].Currency || bounds[i].Amount <= bounds[i-1].Amount {
+			return nil, errors.New("price buckets must be increasing and in one currency")
+		}
+	}
+
+	facets := &ProductFacets{}
+	var err error
+	if facets.Total, err = p.CountProducts(filter); err != nil {
+		return nil, err
+	}
+
+	matching := filter.apply(p.DB.Model(&entity.Product{})).Select("id")
+
+	err = p.DB.Model(&entity.ProductTag{}).
+		Select("tag AS value, COUNT(*) AS count").
+		Where("product_id IN (?)", matching).
+		Group("tag").
+		Order("count DESC, value").
+		Scan(&facets.Tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.DB.Model(&entity.ProductCategory{}).
+		Select("category_id AS value, COUNT(*) AS count").
+		Where("product_id IN (?)", matching).
+		Group("category_id").
+		Order("count DESC, value").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bounds) > 0 {
+		if facets.Prices, err = p.priceBuckets(filter, bounds); err != nil {
+			return nil, err
+		}
+	}
+	return facets, nil
+}
+
+func (p *ProductRepository) priceBuckets(filter ProductFilter, bounds []pkgentity.Money) ([]PriceBucket, error) {
+	// CASE WHEN price_amount < b0 THEN 0 WHEN price_amount < b1 THEN 1 ... ELSE n END
+	var cases strings.Builder
+	args := make([]any, 0, len(bounds))
+	cases.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&cases, " WHEN price_amount < ? THEN %d", i)
+		args = append(args, bound.Amount)
+	}
+	fmt.Fprintf(&cases, " ELSE %d END", len(bounds))
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := filter.apply(p.DB.Model(&entity.Product{})).
+		Where("price_currency = ?", bounds[0].Currency).
+		Select(cases.String()+" AS bucket, COUNT(*) AS count", args...).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]PriceBucket, len(bounds)+1)
+	for i := range buckets {
+		if i > 0 {
+			buckets[i].Min = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			buckets[i].Max = &bounds[i]
+		}
+	}
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(buckets) {
+			buckets[row.Bucket].Count = row.Count
+		}
+	}
+	return buckets, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTaggedProduct(t *testing.T, repo *ProductRepository, name string, price pkgentity.Money, tags ...string) *entity.Product {
+	product, err := entity.NewProduct(name, "", price)
+	require.NoError(t, err)
+	require.NoError(t, product.SetTags(tags))
+	require.NoError(t, repo.Create(product))
+	return product
+}
+
+func TestProduct_Tags(t *testing.T) {
+	db := setupProductTestDB(t)
+	repo := NewProductRepository(db)
+
+	shirt := createTaggedProduct(t, repo, "Shirt", brl("30"), "Summer", "clearance")
+	createTaggedProduct(t, repo, "Shorts", brl("60"), "summer")
+	createTaggedProduct(t, repo, "Coat", brl("300"), "winter", "clearance")
+	createTaggedProduct(t, repo, "Socks", brl("10"))
+
+	t.Run("should load tags with the product", func(t *testing.T) {
+		found, err := repo.FindByID(shirt.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"clearance", "summer"}, found.Tags)
+	})
+
+	t.Run("should replace tags on update", func(t *testing.T) {
+		shirt.Tags = []string{"summer", "new"}
+		require.NoError(t, repo.Update(shirt))
+
+		found, err := repo.FindByID(shirt.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"new", "summer"}, found.Tags)
+
+		shirt.Tags = []string{"summer", "clearance"}
+		require.NoError(t, repo.Update(shirt))
+	})
+
+	names := func(products []*entity.Product) []string {
+		var result []string
+		for _, p := range products {
+			result = append(result, p.Name)
+		}
+		return result
+	}
+
+	t.Run("should match any tag", func(t *testing.T) {
+		found, err := repo.List(ProductQuery{Page: 1, Limit: 10, Sort: []SortField{{Field: "name"}},
+			Filter: ProductFilter{Tags: []string{"summer", "winter"}}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Coat", "Shirt", "Shorts"}, names(found))
+	})
+
+	t.Run("should match all tags", func(t *testing.T) {
+		found, err := repo.List(ProductQuery{Page: 1, Limit: 10,
+			Filter: ProductFilter{Tags: []string{"summer", "clearance"}, AllTags: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Shirt"}, names(found))
+		assert.Equal(t, []string{"clearance", "summer"}, found[0].Tags)
+	})
+
+	t.Run("should count facets", func(t *testing.T) {
+		facets, err := repo.Facets(ProductFilter{}, []pkgentity.Money{brl("50"), brl("100")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), facets.Total)
+		assert.Equal(t, []FacetCount{{"clearance", 2}, {"summer", 2}, {"winter", 1}}, facets.Tags)
+
+		require.Len(t, facets.Prices, 3)
+		assert.Nil(t, facets.Prices[0].Min)
+		assert.Equal(t, int64(2), facets.Prices[0].Count) // 10, 30
+		assert.Equal(t, int64(1), facets.Prices[1].Count) // 60
+		assert.Equal(t, int64(1), facets.Prices[2].Count) // 300
+		assert.Nil(t, facets.Prices[2].Max)
+	})
+
+	t.Run("should count facets within the filter", func(t *testing.T) {
+		facets, err := repo.Facets(ProductFilter{Tags: []string{"clearance"}}, []pkgentity.Money{brl("100")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), facets.Total)
+		assert.Equal(t, []FacetCount{{"clearance", 2}, {"summer", 1}, {"winter", 1}}, facets.Tags)
+		assert.Equal(t, int64(1), facets.Prices[0].Count)
+		assert.Equal(t, int64(1), facets.Prices[1].Count)
+	})
+
+	t.Run("should reject unordered buckets", func(t *testing.T) {
+		_, err := repo.Facets(ProductFilter{}, []pkgentity.Money{brl("100"), brl("50")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// As returns a repository that records actorID as the author of the
+// changes made through it.
+func (p *ProductRepository) As(actorID pkgentity.ID) ProductDB {
+	return &ProductRepository{DB: p.DB, Actor: &actorID}
+}
+
+// FindRevisions returns one page of a product's history, newest first.
+// Deleted products keep theirs until they are purged.
+func (p *ProductRepository) FindRevisions(productID string, page, limit int) ([]*entity.ProductRevision, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var revisions []*entity.ProductRevision
+	err := p.DB.Where("product_id = ?", productID).
+		Order("revision DESC").Limit(limit).Offset((page - 1) * limit).
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// FindAsOf rebuilds the product as it was at the given time from its
+// history, status included. A product not created yet, or in the trash,
+// at that time is not found.
+func (p *ProductRepository) FindAsOf(id string, at time.Time) (*entity.Product, error) {
+	var latest, first entity.ProductRevision
+	// Times are stored in local time; sqlite compares them as text.
+	err := p.DB.Where("product_id = ? AND created_at <= ?", id, at.Local()).
+		Order("revision DESC").First(&latest).Error
+	if err != nil {
+		return nil, err
+	}
+	if latest.Action == entity.RevisionDeleted {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := p.DB.Where("product_id = ?", id).Order("revision").First(&first).Error; err != nil {
+		return nil, err
+	}
+
+	product := &entity.Product{
+		ID:        latest.ProductID,
+		Version:   latest.Version,
+		CreatedAt: first.CreatedAt,
+		UpdatedAt: latest.CreatedAt,
+	}
+	latest.Snapshot.ApplyTo(product)
+	product.Status = latest.Snapshot.Status
+	product.PublishAt, product.UnpublishAt = latest.Snapshot.PublishAt, latest.Snapshot.UnpublishAt
+	if product.Status == "" {
+		product.Status = entity.ProductPublished
+	}
+	return product, nil
+}
+
+// Revert puts product back the way it was at revision, as a new revision
+// that says which one it went back to. As with Update, product must still
+// be at the version it was read at.
+func (p *ProductRepository) Revert(product *entity.Product, revision int64) error {
+	var target entity.ProductRevision
+	err := p.DB.Where("product_id = ? AND revision = ?", product.ID, revision).First(&target).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRevisionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	target.Snapshot.ApplyTo(product)
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	return p.update(product, entity.RevisionReverted, &revision)
+}
+
+// recordRevision numbers revision after the product's last one and saves
+// it.
+func recordRevision(tx *gorm.DB, revision *entity.ProductRevision) error {
+	var last int64
+	err := tx.Model(&entity.ProductRevision{}).Where("product_id = ?", revision.ProductID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	revision.Revision = last + 1
+	return tx.Create(revision).Error
+}
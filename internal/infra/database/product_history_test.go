@@ -0,0 +1,89 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestProduct_History(t *testing.T) {
+	db := setupProductTestDB(t)
+	productRepo := NewProductRepository(db)
+	editor := pkgentity.NewID()
+	product := createTestProduct(t)
+	require.NoError(t, productRepo.As(editor).Create(product))
+	created := time.Now()
+
+	product.Price = brl("80.00")
+	require.NoError(t, productRepo.As(editor).Update(product))
+	require.NoError(t, productRepo.Update(product))
+	product.Name = "renamed"
+	require.NoError(t, productRepo.Update(product))
+
+	t.Run("should record one revision per change, newest first", func(t *testing.T) {
+		revisions, err := productRepo.FindRevisions(product.ID.String(), 1, 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+
+		assert.Equal(t, int64(3), revisions[0].Revision)
+		assert.Equal(t, []entity.FieldChange{{Field: "name", Old: "testproduct", New: "renamed"}}, revisions[0].Changes)
+		assert.Nil(t, revisions[0].ActorID)
+
+		assert.Equal(t, entity.RevisionUpdated, revisions[1].Action)
+		require.Len(t, revisions[1].Changes, 1)
+		assert.Equal(t, "price", revisions[1].Changes[0].Field)
+		assert.Equal(t, map[string]any{"amount": "80.00", "currency": "BRL"}, revisions[1].Changes[0].New)
+		assert.Equal(t, &editor, revisions[1].ActorID)
+
+		assert.Equal(t, entity.RevisionCreated, revisions[2].Action)
+		assert.Equal(t, int64(1), revisions[2].Revision)
+	})
+
+	t.Run("should rebuild the product at a point in time", func(t *testing.T) {
+		then, err := productRepo.FindAsOf(product.ID.String(), created)
+		require.NoError(t, err)
+		assert.Equal(t, "testproduct", then.Name)
+		assert.Equal(t, brl("100.00"), then.Price)
+		assert.Equal(t, int64(1), then.Version)
+
+		_, err = productRepo.FindAsOf(product.ID.String(), created.Add(-time.Hour))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should revert to a revision as a new one", func(t *testing.T) {
+		current, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		require.NoError(t, productRepo.As(editor).Revert(current, 1))
+		assert.Equal(t, "testproduct", current.Name)
+		assert.Equal(t, brl("100.00"), current.Price)
+
+		revisions, err := productRepo.FindRevisions(product.ID.String(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RevisionReverted, revisions[0].Action)
+		assert.Equal(t, int64(1), *revisions[0].RevertOf)
+		assert.Len(t, revisions[0].Changes, 2)
+
+		assert.ErrorIs(t, productRepo.Revert(current, 99), ErrRevisionNotFound)
+	})
+
+	t.Run("should record deletes and restores, and hide deleted products in the past", func(t *testing.T) {
+		current, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		require.NoError(t, productRepo.Delete(product.ID.String(), current.Version))
+		_, err = productRepo.FindAsOf(product.ID.String(), time.Now())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, productRepo.Restore(product.ID.String()))
+		revisions, err := productRepo.FindRevisions(product.ID.String(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RevisionRestored, revisions[0].Action)
+		assert.Equal(t, entity.RevisionDeleted, revisions[1].Action)
+		assert.Empty(t, revisions[1].Changes)
+	})
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidSortField = errors.New("invalid sort field")
+
+// productSortColumns whitelists the fields products can be sorted by,
+// mapping the public name to the column. Amounts in different currencies
+// cannot be compared, so sorting by price groups products by currency
+// first, in code order, and orders the amounts within each currency.
+var productSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "price_amount",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// ProductFilter narrows a product listing. A price bound only matches
+// products priced in the bound's currency.
+type ProductFilter struct {
+	MinPrice      *entity.Money
+	MaxPrice      *entity.Money
+	NameContains  string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	IDs           []string
+	// CategoryIDs matches products assigned to any of these categories.
+	CategoryIDs []string
+	// Tags matches products carrying any of these tags, or all of them
+	// when AllTags is set. Tags are expected normalized.
+	Tags    []string
+	AllTags bool
+	// Statuses matches products in any of these statuses; empty matches
+	// every status.
+	Statuses []string
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+type ProductQuery struct {
+	Filter ProductFilter
+	Sort   []SortField
+	Page   int
+	Limit  int
+}
+
+// ParseProductSort parses a comma separated list of fields, each optionally
+// prefixed with "-" for descending order, e.g. "-price,name". The legacy
+// values "asc" and "desc" sort by created_at.
+func ParseProductSort(s string) ([]SortField, error) {
+	switch s {
+	case "":
+		return nil, nil
+	case "asc":
+		return []SortField{{Field: "created_at"}}, nil
+	case "desc":
+		return []SortField{{Field: "created_at", Desc: true}}, nil
+	}
+
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := productSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSortField, field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%w: %q given twice", ErrInvalidSortField, field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (f ProductFilter) Validate() error {
+	if f.MinPrice != nil && f.MaxPrice != nil {
+		cmp, err := f.MinPrice.Cmp(*f.MaxPrice)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			return errors.New("min price cannot be greater than max price")
+		}
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && f.CreatedAfter.After(*f.CreatedBefore) {
+		return errors.New("created_after cannot be after created_before")
+	}
+	if f.UpdatedAfter != nil && f.UpdatedBefore != nil && f.UpdatedAfter.After(*f.UpdatedBefore) {
+		return errors.New("updated_after cannot be after updated_before")
+	}
+	return nil
+}
+
+func (f ProductFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.MinPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount >= ?", f.MinPrice.Currency, f.MinPrice.Amount)
+	}
+	if f.MaxPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount <= ?", f.MaxPrice.Currency, f.MaxPrice.Amount)
+	}
+	if f.NameContains != "" {
+		query = query.Where("LOWER(name) LIKE ? ESCAPE '\\'", "%"+escapeLike(strings.ToLower(f.NameContains))+"%")
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("created_at <= ?", *f.CreatedBefore)
+	}
+	if f.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *f.UpdatedAfter)
+	}
+	if f.UpdatedBefore != nil {
+		query = query.Where("updated_at <= ?", *f.UpdatedBefore)
+	}
+	if len(f.IDs) > 0 {
+		query = query.Where("id IN ?", f.IDs)
+	}
+	if len(f.CategoryIDs) > 0 {
+		query = query.Where("id IN (SELECT product_id FROM product_categories WHERE category_id IN ?)", f.CategoryIDs)
+	}
+	if len(f.Statuses) > 0 {
+		query = query.Where("status IN ?", f.Statuses)
+	}
+	if len(f.Tags) > 0 {
+		if f.AllTags {
+			query = query.Where("id IN (SELECT product_id FROM product_tags WHERE tag IN ? GROUP BY product_id HAVING COUNT(*) = ?)",
+				f.Tags, len(f.Tags))
+		} else {
+			query = query.Where("id IN (SELECT product_id FROM product_tags WHERE tag IN ?)", f.Tags)
+		}
+	}
+	return query
+}
+
+// applySort orders by the given fields, always ending with id so pages are
+// stable when the sort keys tie.
+func applySort(query *gorm.DB, fields []SortField) *gorm.DB {
+	for _, field := range fields {
+		if field.Field == "price" {
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: "price_currency"}})
+		}
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: productSortColumns[field.Field]},
+			Desc:   field.Desc,
+		})
+	}
+	return query.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
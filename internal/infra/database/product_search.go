@@ -0,0 +1,325 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// highlightOpen and highlightClose mark matches while the text is still
+// raw; renderHighlight swaps them for HighlightStart and HighlightEnd once
+// the text is HTML-escaped. They are private-use characters, so product
+// text does not contain them.
+const (
+	highlightOpen  = "\uE000"
+	highlightClose = "\uE001"
+)
+
+var highlightMarkers = strings.NewReplacer(highlightOpen, HighlightStart, highlightClose, HighlightEnd)
+
+// renderHighlight HTML-escapes text marked with highlightOpen and
+// highlightClose and turns the markers into <mark> tags, so highlights are
+// safe to render as HTML.
+func renderHighlight(text string) string {
+	return highlightMarkers.Replace(html.EscapeString(text))
+}
+
+var ErrEmptySearchQuery = errors.New("search query cannot be empty")
+
+type ProductSearchResult struct {
+	Product              *entity.Product
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+type searchRow struct {
+	entity.Product       `gorm:"embedded"`
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+func (r searchRow) result() *ProductSearchResult {
+	product := r.Product
+	return &ProductSearchResult{
+		Product:              &product,
+		Rank:                 r.Rank,
+		NameHighlight:        r.NameHighlight,
+		DescriptionHighlight: r.DescriptionHighlight,
+	}
+}
+
+// NewProductSearcher returns the full-text searcher for the database
+// dialect behind db.
+func NewProductSearcher(db *gorm.DB) (ProductSearcher, error) {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return &PostgresProductSearcher{DB: db}, nil
+	case "sqlite":
+		return &SQLiteProductSearcher{DB: db}, nil
+	}
+	return nil, fmt.Errorf("full-text search is not supported on %q", db.Dialector.Name())
+}
+
+// setupProductSearch creates the dialect-specific search index. It must run
+// after the products table exists.
+func setupProductSearch(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return (&PostgresProductSearcher{DB: db}).setup()
+	case "sqlite":
+		return (&SQLiteProductSearcher{DB: db}).setup()
+	}
+	return nil
+}
+
+// searchTerms splits a free-text query into lower-cased words, dropping
+// anything that is not a letter or digit so terms are safe to embed in
+// tsquery/FTS5/LIKE syntax.
+func searchTerms(q string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return fields
+}
+
+func validateSearch(q string, page, limit int) ([]string, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+	return terms, nil
+}
+
+// PostgresProductSearcher ranks products with a weighted tsvector (name
+// over description) and prefix tsquery terms.
+type PostgresProductSearcher struct {
+	DB *gorm.DB
+}
+
+func (s *PostgresProductSearcher) setup() error {
+	stmts := []string{
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+			) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+	}
+	for _, stmt := range stmts {
+		if err := s.DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PostgresProductSearcher) Search(q string, page, limit int) ([]*ProductSearchResult, error) {
+	terms, err := validateSearch(q, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	tsquery := strings.Join(terms, " & ")
+	headline := fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", highlightOpen, highlightClose)
+
+	var rows []searchRow
+	err = s.DB.Raw(`
+		SELECT products.*,
+			ts_rank(products.search_vector, q) AS rank,
+			ts_headline('simple', products.name, q, ?) AS name_highlight,
+			ts_headline('simple', products.description, q, ?) AS description_highlight
+		FROM products, to_tsquery('simple', ?) AS q
+		WHERE products.search_vector @@ q AND products.deleted_at IS NULL
+			AND products.status = 'published'
+		ORDER BY rank DESC, products.created_at DESC
+		LIMIT ? OFFSET ?`,
+		headline, headline, tsquery, limit, (page-1)*limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ProductSearchResult, 0, len(rows))
+	for _, row := range rows {
+		row.NameHighlight = renderHighlight(row.NameHighlight)
+		row.DescriptionHighlight = renderHighlight(row.DescriptionHighlight)
+		results = append(results, row.result())
+	}
+	if err := loadResultTags(s.DB, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SQLiteProductSearcher uses an FTS5 index kept in sync by triggers.
+// go-sqlite3 only ships FTS5 when built with -tags sqlite_fts5; without it
+// the searcher falls back to LIKE matching on word prefixes, ranked by
+// where the terms matched.
+type SQLiteProductSearcher struct {
+	DB *gorm.DB
+}
+
+func (s *SQLiteProductSearcher) setup() error {
+	err := s.DB.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(
+		name, description, content='products', content_rowid='rowid',
+		tokenize='unicode61 remove_diacritics 2'
+	)`).Error
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return nil
+		}
+		return err
+	}
+
+	stmts := []string{
+		`CREATE TRIGGER IF NOT EXISTS products_fts_ai AFTER INSERT ON products BEGIN
+			INSERT INTO products_fts(rowid, name, description) VALUES (new.rowid, new.name, new.description);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS products_fts_ad AFTER DELETE ON products BEGIN
+			INSERT INTO products_fts(products_fts, rowid, name, description) VALUES ('delete', old.rowid, old.name, old.description);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS products_fts_au AFTER UPDATE ON products BEGIN
+			INSERT INTO products_fts(products_fts, rowid, name, description) VALUES ('delete', old.rowid, old.name, old.description);
+			INSERT INTO products_fts(rowid, name, description) VALUES (new.rowid, new.name, new.description);
+		END`,
+		`INSERT INTO products_fts(products_fts) VALUES ('rebuild')`,
+	}
+	for _, stmt := range stmts {
+		if err := s.DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteProductSearcher) hasFTS5() bool {
+	return s.DB.Migrator().HasTable("products_fts")
+}
+
+func (s *SQLiteProductSearcher) Search(q string, page, limit int) ([]*ProductSearchResult, error) {
+	terms, err := validateSearch(q, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if !s.hasFTS5() {
+		return s.searchLike(terms, page, limit)
+	}
+
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = `"` + term + `"*`
+	}
+
+	var rows []searchRow
+	// bm25 is lower-is-better, so negate it to keep "higher rank is better"
+	// across dialects. Name matches weigh ten times description matches.
+	err = s.DB.Raw(`
+		SELECT products.*,
+			-bm25(products_fts, 10.0, 1.0) AS rank,
+			highlight(products_fts, 0, ?, ?) AS name_highlight,
+			highlight(products_fts, 1, ?, ?) AS description_highlight
+		FROM products_fts
+		JOIN products ON products.rowid = products_fts.rowid
+		WHERE products_fts MATCH ? AND products.deleted_at IS NULL
+			AND products.status = 'published'
+		ORDER BY rank DESC, products.created_at DESC
+		LIMIT ? OFFSET ?`,
+		highlightOpen, highlightClose, highlightOpen, highlightClose,
+		strings.Join(match, " "), limit, (page-1)*limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ProductSearchResult, 0, len(rows))
+	for _, row := range rows {
+		row.NameHighlight = renderHighlight(row.NameHighlight)
+		row.DescriptionHighlight = renderHighlight(row.DescriptionHighlight)
+		results = append(results, row.result())
+	}
+	if err := loadResultTags(s.DB, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *SQLiteProductSearcher) searchLike(terms []string, page, limit int) ([]*ProductSearchResult, error) {
+	var rankParts []string
+	var rankArgs []any
+	query := s.DB.Model(&entity.Product{}).Where("status = ?", entity.ProductPublished)
+	for _, term := range terms {
+		prefix := "% " + term + "%"
+		rankParts = append(rankParts,
+			"(CASE WHEN ' ' || LOWER(name) LIKE ? THEN 2 ELSE 0 END)",
+			"(CASE WHEN ' ' || LOWER(description) LIKE ? THEN 1 ELSE 0 END)",
+		)
+		rankArgs = append(rankArgs, prefix, prefix)
+		query = query.Where("(' ' || LOWER(name) LIKE ? OR ' ' || LOWER(description) LIKE ?)", prefix, prefix)
+	}
+
+	var rows []searchRow
+	err := query.
+		Select("products.*, ("+strings.Join(rankParts, " + ")+") AS rank", rankArgs...).
+		Order("rank DESC, created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ProductSearchResult, 0, len(rows))
+	for _, row := range rows {
+		row.NameHighlight = highlightPrefixes(row.Name, terms)
+		row.DescriptionHighlight = highlightPrefixes(row.Description, terms)
+		results = append(results, row.result())
+	}
+	if err := loadResultTags(s.DB, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func loadResultTags(db *gorm.DB, results []*ProductSearchResult) error {
+	products := make([]*entity.Product, 0, len(results))
+	for _, r := range results {
+		products = append(products, r.Product)
+	}
+	return loadProductTags(db, products)
+}
+
+// highlightPrefixes wraps every word of text that starts with one of terms,
+// HTML-escaping the rest.
+func highlightPrefixes(text string, terms []string) string {
+	if text == "" {
+		return text
+	}
+	sorted := append([]string(nil), terms...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, term := range sorted {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	re := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}])((?:` + strings.Join(quoted, "|") + `)[\p{L}\p{N}]*)`)
+	return renderHighlight(re.ReplaceAllString(text, "${1}"+highlightOpen+"${2}"+highlightClose))
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSearchTestDB(t *testing.T) (*gorm.DB, ProductSearcher) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	searcher, err := NewProductSearcher(db)
+	require.NoError(t, err)
+	return db, searcher
+}
+
+func createSearchProduct(t *testing.T, repo *ProductRepository, name, description string) *entity.Product {
+	product, err := entity.NewProduct(name, description, brl("10"))
+	require.NoError(t, err)
+	require.NoError(t, product.Transition(entity.ProductPublished))
+	require.NoError(t, repo.Create(product))
+	return product
+}
+
+func TestProductSearch(t *testing.T) {
+	t.Run("should rank name matches above description matches", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		inDescription := createSearchProduct(t, repo, "Mouse", "Works great with any keyboard")
+		inName := createSearchProduct(t, repo, "Mechanical Keyboard", "Blue switches")
+		createSearchProduct(t, repo, "Monitor", "27 inch")
+
+		results, err := searcher.Search("keyboard", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, inName.ID, results[0].Product.ID)
+		assert.Equal(t, inDescription.ID, results[1].Product.ID)
+		assert.Greater(t, results[0].Rank, results[1].Rank)
+	})
+
+	t.Run("should match word prefixes and highlight them", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		createSearchProduct(t, repo, "Mechanical Keyboard", "Blue switches")
+
+		results, err := searcher.Search("keyb", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Mechanical <mark>Keyboard</mark>", results[0].NameHighlight)
+		assert.Equal(t, "Blue switches", results[0].DescriptionHighlight)
+	})
+
+	t.Run("should require every term to match", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		createSearchProduct(t, repo, "Mechanical Keyboard", "Blue switches")
+		createSearchProduct(t, repo, "Membrane Keyboard", "Quiet")
+
+		results, err := searcher.Search("keyboard blue", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Mechanical Keyboard", results[0].Product.Name)
+	})
+
+	t.Run("should see updates and deletes", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		product := createSearchProduct(t, repo, "Old name", "")
+
+		product.Name = "Shiny lamp"
+		require.NoError(t, repo.Update(product))
+		results, err := searcher.Search("shiny", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		require.NoError(t, repo.Delete(product.ID.String(), product.Version))
+		results, err = searcher.Search("shiny", 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("should HTML-escape the highlighted text", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		createSearchProduct(t, repo, "Lamp <script>alert(1)</script>", "Lamp & shade")
+
+		results, err := searcher.Search("lamp", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "<mark>Lamp</mark> &lt;script&gt;alert(1)&lt;/script&gt;", results[0].NameHighlight)
+		assert.Equal(t, "<mark>Lamp</mark> &amp; shade", results[0].DescriptionHighlight)
+	})
+
+	t.Run("should only find published products", func(t *testing.T) {
+		db, searcher := setupSearchTestDB(t)
+		repo := NewProductRepository(db)
+		product := createSearchProduct(t, repo, "Shiny lamp", "")
+		draft, err := entity.NewProduct("Shiny desk", "", brl("10"))
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(draft))
+
+		require.NoError(t, repo.ChangeStatus(product, entity.ProductArchived))
+		results, err := searcher.Search("shiny", 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("should reject queries without terms", func(t *testing.T) {
+		_, searcher := setupSearchTestDB(t)
+
+		_, err := searcher.Search(" *** ", 1, 10)
+		assert.Equal(t, ErrEmptySearchQuery, err)
+	})
+}
+
+func TestHighlightPrefixes(t *testing.T) {
+	assert.Equal(t, "<mark>Caneca</mark> de <mark>café</mark>", highlightPrefixes("Caneca de café", []string{"can", "caf"}))
+	assert.Equal(t, "Escape", highlightPrefixes("Escape", []string{"cap"}))
+	assert.Equal(t, "&lt;b&gt;<mark>Bold</mark>&lt;/b&gt; &amp; co", highlightPrefixes("<b>Bold</b> & co", []string{"bold"}))
+}
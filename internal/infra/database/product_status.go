@@ -0,0 +1,69 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// ChangeStatus moves product to status next and saves it, recording the
+// transition in its history. As with Update, product must still be at the
+// version it was read at.
+func (p *ProductRepository) ChangeStatus(product *entity.Product, next entity.ProductStatus) error {
+	if product == nil {
+		return errors.New("product cannot be nil")
+	}
+	status, publishAt, unpublishAt := product.Status, product.PublishAt, product.UnpublishAt
+	if err := product.Transition(next); err != nil {
+		return err
+	}
+	if err := p.update(product, entity.RevisionStatusChanged, nil); err != nil {
+		product.Status, product.PublishAt, product.UnpublishAt = status, publishAt, unpublishAt
+		return err
+	}
+	return nil
+}
+
+// PublishScheduled publishes the drafts whose publish_at has come and
+// archives the published products whose unpublish_at has, returning how
+// many it changed. A product edited in the meantime is left for the next
+// run.
+func (p *ProductRepository) PublishScheduled(now time.Time) (int, error) {
+	var ids []string
+	// Schedule times are stored in UTC; sqlite compares them as text.
+	now = now.UTC()
+	err := p.DB.Model(&entity.Product{}).
+		Where("(status = ? AND publish_at <= ?) OR (status = ? AND unpublish_at <= ?)",
+			entity.ProductDraft, now, entity.ProductPublished, now).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, id := range ids {
+		product, err := p.FindByID(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		next, due := product.DueTransition(now)
+		if !due {
+			continue
+		}
+		err = p.ChangeStatus(product, next)
+		if errors.Is(err, ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
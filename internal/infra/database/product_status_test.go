@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProduct_Status(t *testing.T) {
+	db := setupProductTestDB(t)
+	productRepo := NewProductRepository(db)
+
+	t.Run("should save transitions and record them in the history", func(t *testing.T) {
+		product := createTestProduct(t)
+		require.NoError(t, productRepo.Create(product))
+		stale, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		require.NoError(t, productRepo.ChangeStatus(product, entity.ProductPublished))
+		assert.Equal(t, int64(2), product.Version)
+
+		found, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.ProductPublished, found.Status)
+
+		revisions, err := productRepo.FindRevisions(product.ID.String(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RevisionStatusChanged, revisions[0].Action)
+		assert.Equal(t, []entity.FieldChange{{Field: "status", Old: "draft", New: "published"}}, revisions[0].Changes)
+
+		assert.ErrorIs(t, productRepo.ChangeStatus(stale, entity.ProductArchived), ErrVersionConflict)
+		assert.Equal(t, entity.ProductDraft, stale.Status)
+		assert.ErrorIs(t, productRepo.ChangeStatus(product, entity.ProductPublished), entity.ErrInvalidProductTransition)
+	})
+
+	t.Run("should filter listings by status", func(t *testing.T) {
+		products, err := productRepo.List(ProductQuery{
+			Filter: ProductFilter{Statuses: []string{string(entity.ProductDraft)}}, Page: 1, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, products)
+
+		count, err := productRepo.CountProducts(ProductFilter{Statuses: []string{string(entity.ProductPublished)}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should publish and archive products when their time comes", func(t *testing.T) {
+		now := time.Now()
+		soon, later := now.Add(time.Minute), now.Add(time.Hour)
+		product := createTestProduct(t)
+		require.NoError(t, product.SetSchedule(&soon, &later))
+		require.NoError(t, productRepo.Create(product))
+
+		changed, err := productRepo.PublishScheduled(now)
+		require.NoError(t, err)
+		assert.Zero(t, changed)
+
+		changed, err = productRepo.PublishScheduled(soon)
+		require.NoError(t, err)
+		assert.Equal(t, 1, changed)
+		found, err := productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.ProductPublished, found.Status)
+		assert.Nil(t, found.PublishAt)
+		require.NotNil(t, found.UnpublishAt)
+
+		changed, err = productRepo.PublishScheduled(later)
+		require.NoError(t, err)
+		assert.Equal(t, 1, changed)
+		found, err = productRepo.FindByID(product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, entity.ProductArchived, found.Status)
+		assert.Nil(t, found.UnpublishAt)
+
+		revisions, err := productRepo.FindRevisions(product.ID.String(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RevisionStatusChanged, revisions[0].Action)
+		assert.Nil(t, revisions[0].ActorID)
+	})
+
+	t.Run("should rebuild the status at a point in time", func(t *testing.T) {
+		product := createTestProduct(t)
+		require.NoError(t, productRepo.Create(product))
+		drafted := time.Now()
+		require.NoError(t, productRepo.ChangeStatus(product, entity.ProductPublished))
+
+		then, err := productRepo.FindAsOf(product.ID.String(), drafted)
+		require.NoError(t, err)
+		assert.Equal(t, entity.ProductDraft, then.Status)
+	})
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// loadProductTags fills Tags on products with one query.
+func loadProductTags(db *gorm.DB, products []*entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID.String())
+		p.Tags = []string{}
+	}
+
+	var rows []entity.ProductTag
+	if err := db.Where("product_id IN ?", ids).Order("tag").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[string]*entity.Product, len(products))
+	for _, p := range products {
+		byID[p.ID.String()] = p
+	}
+	for _, row := range rows {
+		if p := byID[row.ProductID.String()]; p != nil {
+			p.Tags = append(p.Tags, row.Tag)
+		}
+	}
+	return nil
+}
+
+// saveProductTags replaces the stored tags of product with product.Tags.
+func saveProductTags(tx *gorm.DB, product *entity.Product) error {
+	tags, err := entity.NormalizeTags(product.Tags)
+	if err != nil {
+		return err
+	}
+	product.Tags = tags
+
+	if err := tx.Delete(&entity.ProductTag{}, "product_id = ?", product.ID).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]entity.ProductTag, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, entity.ProductTag{ProductID: product.ID, Tag: tag})
+	}
+	return tx.Create(&rows).Error
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+var ErrAlreadyReviewed = errors.New("you have already reviewed this product")
+
+// purchasedStatuses are the order statuses that make a review a verified
+// purchase: the order was paid and has not been refunded.
+var purchasedStatuses = []entity.OrderStatus{entity.OrderPaid, entity.OrderFulfilled, entity.OrderCompleted}
+
+type ReviewRepository struct {
+	DB *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{DB: db}
+}
+
+// Create refuses a second review of the same product by the same user.
+// The unique index backs this up against concurrent writers.
+func (r *ReviewRepository) Create(review *entity.Review) error {
+	if review == nil {
+		return errors.New("review cannot be nil")
+	}
+	var count int64
+	err := r.DB.Model(&entity.Review{}).
+		Where("product_id = ? AND user_id = ?", review.ProductID, review.UserID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyReviewed
+	}
+	return r.DB.Create(review).Error
+}
+
+func (r *ReviewRepository) FindByID(id string) (*entity.Review, error) {
+	var review entity.Review
+	if err := r.DB.Where("id = ?", id).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// FindByProduct returns one page of the product's approved reviews,
+// newest first.
+func (r *ReviewRepository) FindByProduct(productID string, page, limit int) ([]*entity.Review, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var reviews []*entity.Review
+	err := r.DB.Where("product_id = ? AND status = ?", productID, entity.ReviewApproved).
+		Order("created_at DESC, id").Limit(limit).Offset((page - 1) * limit).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// FindByStatus returns one page of reviews in status, oldest first, so the
+// moderation queue is worked through in the order reviews came in.
+func (r *ReviewRepository) FindByStatus(status entity.ReviewStatus, page, limit int) ([]*entity.Review, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var reviews []*entity.Review
+	err := r.DB.Where("status = ?", status).
+		Order("updated_at, id").Limit(limit).Offset((page - 1) * limit).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *ReviewRepository) Update(review *entity.Review) error {
+	if review == nil {
+		return errors.New("review cannot be nil")
+	}
+	result := r.DB.Model(review).Where("id = ?", review.ID).Select("*").Updates(review)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Delete(id string) error {
+	result := r.DB.Where("id = ?", id).Delete(&entity.Review{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// HasPurchased reports whether userID has a paid, unrefunded order with
+// productID in it.
+func (r *ReviewRepository) HasPurchased(userID, productID string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&entity.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND order_items.product_id = ? AND orders.status IN ?", userID, productID, purchasedStatuses).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RatingSummary aggregates the product's approved reviews.
+func (r *ReviewRepository) RatingSummary(productID string) (entity.RatingSummary, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := r.DB.Model(&entity.Review{}).
+		Select("rating, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, entity.ReviewApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return entity.RatingSummary{}, err
+	}
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Rating] = row.Count
+	}
+	return entity.NewRatingSummary(counts), nil
+}
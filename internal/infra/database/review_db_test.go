@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestReviewRepository(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.Review{}))
+	repo := NewReviewRepository(orders.DB)
+	product := createTestProduct(t)
+	require.NoError(t, NewProductRepository(orders.DB).Create(product))
+
+	newReview := func(rating int) *entity.Review {
+		review, err := entity.NewReview(product.ID, pkgentity.NewID(), rating, "Título", "")
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(review))
+		return review
+	}
+	five, four, one := newReview(5), newReview(4), newReview(1)
+
+	t.Run("should allow one review per user and product", func(t *testing.T) {
+		again, err := entity.NewReview(product.ID, five.UserID, 3, "De novo", "")
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.Create(again), ErrAlreadyReviewed)
+	})
+
+	t.Run("should queue pending reviews oldest first", func(t *testing.T) {
+		queue, err := repo.FindByStatus(entity.ReviewPending, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, queue, 3)
+		assert.Equal(t, five.ID, queue[0].ID)
+	})
+
+	t.Run("should only show and count approved reviews", func(t *testing.T) {
+		adminID := pkgentity.NewID()
+		for _, review := range []*entity.Review{five, four} {
+			require.NoError(t, review.Moderate(entity.ReviewApproved, adminID, ""))
+			require.NoError(t, repo.Update(review))
+		}
+		require.NoError(t, one.Moderate(entity.ReviewRejected, adminID, "spam"))
+		require.NoError(t, repo.Update(one))
+
+		reviews, err := repo.FindByProduct(product.ID.String(), 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, reviews, 2)
+
+		summary, err := repo.RatingSummary(product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), summary.Count)
+		assert.Equal(t, 4.5, summary.Average)
+		assert.Equal(t, int64(0), summary.Distribution[1])
+
+		found, err := repo.FindByID(one.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "spam", found.ModerationNote)
+	})
+
+	t.Run("should know who bought the product", func(t *testing.T) {
+		buyer := pkgentity.NewID()
+		order := newTestOrder(t, buyer, product)
+		require.NoError(t, orders.Create(order))
+
+		bought, err := repo.HasPurchased(buyer.String(), product.ID.String())
+		require.NoError(t, err)
+		assert.False(t, bought, "a pending order is not a purchase")
+
+		transition, err := order.Transition(entity.OrderPaid, nil, "")
+		require.NoError(t, err)
+		require.NoError(t, orders.SaveTransition(order, transition))
+		bought, err = repo.HasPurchased(buyer.String(), product.ID.String())
+		require.NoError(t, err)
+		assert.True(t, bought)
+	})
+
+	t.Run("should delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(one.ID.String()))
+		assert.ErrorIs(t, repo.Delete(one.ID.String()), gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type ShippingRepository struct {
+	DB *gorm.DB
+}
+
+func NewShippingRepository(db *gorm.DB) *ShippingRepository {
+	return &ShippingRepository{DB: db}
+}
+
+func (r *ShippingRepository) CreateZone(zone *entity.ShippingZone) error {
+	if zone == nil {
+		return errors.New("shipping zone cannot be nil")
+	}
+	return r.DB.Create(zone).Error
+}
+
+// FindZones returns every zone with its methods, oldest first.
+func (r *ShippingRepository) FindZones() ([]*entity.ShippingZone, error) {
+	var zones []*entity.ShippingZone
+	if err := r.DB.Order("created_at, id").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	var methods []*entity.ShippingMethod
+	if err := r.DB.Order("created_at, id").Find(&methods).Error; err != nil {
+		return nil, err
+	}
+	byZone := make(map[string][]*entity.ShippingMethod, len(zones))
+	for _, method := range methods {
+		byZone[method.ZoneID.String()] = append(byZone[method.ZoneID.String()], method)
+	}
+	for _, zone := range zones {
+		zone.Methods = byZone[zone.ID.String()]
+		if zone.Methods == nil {
+			zone.Methods = []*entity.ShippingMethod{}
+		}
+	}
+	return zones, nil
+}
+
+func (r *ShippingRepository) FindZone(id string) (*entity.ShippingZone, error) {
+	var zone entity.ShippingZone
+	if err := r.DB.Where("id = ?", id).First(&zone).Error; err != nil {
+		return nil, err
+	}
+	zone.Methods = []*entity.ShippingMethod{}
+	if err := r.DB.Where("zone_id = ?", zone.ID).Order("created_at, id").Find(&zone.Methods).Error; err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// DeleteZone removes the zone and its methods. Orders keep the method
+// name and cost they were shipped with.
+func (r *ShippingRepository) DeleteZone(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", id).Delete(&entity.ShippingMethod{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&entity.ShippingZone{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// CreateMethod adds method to its zone, which must exist.
+func (r *ShippingRepository) CreateMethod(method *entity.ShippingMethod) error {
+	if method == nil {
+		return errors.New("shipping method cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", method.ZoneID).First(&entity.ShippingZone{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(method).Error
+	})
+}
+
+func (r *ShippingRepository) DeleteMethod(zoneID, id string) error {
+	result := r.DB.Where("zone_id = ? AND id = ?", zoneID, id).Delete(&entity.ShippingMethod{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
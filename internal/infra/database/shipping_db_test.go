@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestShippingRepository(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.ShippingZone{}, &entity.ShippingMethod{}))
+	repo := NewShippingRepository(orders.DB)
+
+	zone, err := entity.NewShippingZone("Sudeste", []entity.ShippingArea{{Country: "BR", Region: "SP"}, {Country: "BR", Region: "RJ"}})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateZone(zone))
+	method, err := entity.NewWeightShipping(zone.ID, "Correio", []entity.WeightTier{
+		{UpToGrams: 1000, Cost: pkgentity.MustParseMoney("12", "BRL")},
+	}, 6000)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateMethod(method))
+
+	t.Run("should load zones with their areas and methods", func(t *testing.T) {
+		zones, err := repo.FindZones()
+		require.NoError(t, err)
+		require.Len(t, zones, 1)
+		assert.Len(t, zones[0].Areas, 2)
+		require.Len(t, zones[0].Methods, 1)
+		assert.Equal(t, method.Tiers, zones[0].Methods[0].Tiers)
+		assert.Equal(t, int64(6000), zones[0].Methods[0].VolumetricDivisor)
+	})
+
+	t.Run("should not add methods to missing zones", func(t *testing.T) {
+		orphan, err := entity.NewFlatShipping(pkgentity.NewID(), "Orphan", pkgentity.MustParseMoney("5", "BRL"))
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.CreateMethod(orphan), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should delete a zone with its methods", func(t *testing.T) {
+		assert.ErrorIs(t, repo.DeleteMethod(zone.ID.String(), pkgentity.NewID().String()), gorm.ErrRecordNotFound)
+		require.NoError(t, repo.DeleteZone(zone.ID.String()))
+		_, err := repo.FindZone(zone.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		var count int64
+		require.NoError(t, orders.DB.Model(&entity.ShippingMethod{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type TaxRateRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaxRateRepository(db *gorm.DB) *TaxRateRepository {
+	return &TaxRateRepository{DB: db}
+}
+
+// Replace swaps the whole tax table for rates in one transaction.
+func (r *TaxRateRepository) Replace(rates []*entity.TaxRate) error {
+	for _, rate := range rates {
+		if err := rate.Validate(); err != nil {
+			return err
+		}
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entity.TaxRate{}).Error; err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return nil
+		}
+		return tx.Create(rates).Error
+	})
+}
+
+func (r *TaxRateRepository) FindAll() ([]*entity.TaxRate, error) {
+	var rates []*entity.TaxRate
+	err := r.DB.Order("country").Order("region").Order("tax_class").Find(&rates).Error
+	return rates, err
+}
+
+// Load returns a lookup table over the current rates.
+func (r *TaxRateRepository) Load() (*entity.TaxTable, error) {
+	rates, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewTaxTable(rates)
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxRateRepository(t *testing.T) {
+	orders, _ := setupOrderTestDB(t)
+	require.NoError(t, orders.DB.AutoMigrate(&entity.TaxRate{}))
+	repo := NewTaxRateRepository(orders.DB)
+
+	rates, err := entity.ParseTaxRates([]entity.TaxRate{
+		{Country: "BR", TaxClass: "standard", Rate: "17"},
+		{Country: "BR", Region: "SP", TaxClass: "standard", Rate: "18"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Replace(rates))
+
+	t.Run("should keep country-wide and regional rates apart", func(t *testing.T) {
+		all, err := repo.FindAll()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, "", all[0].Region)
+		assert.Equal(t, "SP", all[1].Region)
+
+		table, err := repo.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "18", table.Rate(entity.TaxLocation{Country: "BR", Region: "SP"}, "standard"))
+	})
+
+	t.Run("should replace the whole table", func(t *testing.T) {
+		rates, err := entity.ParseTaxRates([]entity.TaxRate{{Country: "PT", TaxClass: "standard", Rate: "23"}})
+		require.NoError(t, err)
+		require.NoError(t, repo.Replace(rates))
+
+		all, err := repo.FindAll()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, "PT", all[0].Country)
+	})
+
+	t.Run("should store an order's tax with it", func(t *testing.T) {
+		product := createTestProduct(t)
+		order := newTestOrder(t, pkgentity.NewID(), product)
+		table, err := repo.Load()
+		require.NoError(t, err)
+		order.ApplyTax(table.Calculate(order.TaxLines(), entity.TaxLocation{Country: "PT"}, entity.TaxSettings{}))
+		require.NoError(t, orders.Create(order))
+
+		found, err := orders.FindByID(order.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, order.Tax, found.Tax)
+		assert.Equal(t, order.Total, found.Total)
+		assert.Equal(t, "PT", found.TaxCountry)
+		assert.Equal(t, "23", found.Items[0].TaxRate)
+		assert.Equal(t, entity.DefaultTaxClass, found.Items[0].TaxClass)
+	})
+}
@@ -35,8 +35,9 @@ func (u *UserRepository) Create(user *entity.User) error {
 	normalizedEmail := strings.ToLower(strings.TrimSpace(user.Email))
 	user.Email = normalizedEmail
 
+	// Deleted accounts keep their email until they are purged
 	var existingUser entity.User
-	err := u.DB.Where("email = ?", normalizedEmail).First(&existingUser).Error
+	err := u.DB.Unscoped().Where("email = ?", normalizedEmail).First(&existingUser).Error
 	if err == nil {
 		return errors.New("email already exists")
 	}
@@ -50,6 +51,9 @@ func (u *UserRepository) Create(user *entity.User) error {
 		user.CreatedAt = now
 	}
 	user.UpdatedAt = now
+	if user.Version == 0 {
+		user.Version = 1
+	}
 
 	return u.DB.Create(user).Error
 }
@@ -80,6 +84,9 @@ func (u *UserRepository) FindByID(id string) (*entity.User, error) {
 	return &user, nil
 }
 
+// Update saves the user if they are still at the version they were read
+// at, and moves them to the next version. A user changed in the meantime
+// gives ErrVersionConflict and is left alone.
 func (u *UserRepository) Update(user *entity.User) error {
 	if user == nil {
 		return errors.New("user cannot be nil")
@@ -89,17 +96,75 @@ func (u *UserRepository) Update(user *entity.User) error {
 	user.UpdatedAt = time.Now().Format(time.RFC3339)
 	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
 
-	return u.DB.Save(user).Error
+	version := user.Version
+	user.Version = version + 1
+	result := u.DB.Model(user).Where("version = ?", version).Select("*").Updates(user)
+	if result.Error != nil {
+		user.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		user.Version = version
+		if _, err := u.FindByID(user.ID.String()); err != nil {
+			return err
+		}
+		return ErrVersionConflict
+	}
+	return nil
 }
 
+// Delete soft-deletes the user: they can no longer be found or log in,
+// and Purge removes the account for good later.
 func (u *UserRepository) Delete(id string) error {
 	if strings.TrimSpace(id) == "" {
 		return errors.New("id cannot be empty")
 	}
 
-	return u.DB.Delete(&entity.User{}, "id = ?", id).Error
+	result := u.DB.Delete(&entity.User{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently removes the users deleted before deletedBefore with
+// their address books, carts and wishlists, and returns how many it
+// removed. Orders and reviews keep the user's ID.
+func (u *UserRepository) Purge(deletedBefore time.Time) (int, error) {
+	var ids []string
+	err := u.DB.Unscoped().Model(&entity.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	err = u.DB.Transaction(func(tx *gorm.DB) error {
+		carts := tx.Model(&entity.Cart{}).Select("id").Where("user_id IN ?", ids)
+		if err := tx.Where("cart_id IN (?)", carts).Delete(&entity.CartItem{}).Error; err != nil {
+			return err
+		}
+		wishlists := tx.Model(&entity.Wishlist{}).Select("id").Where("user_id IN ?", ids)
+		if err := tx.Where("wishlist_id IN (?)", wishlists).Delete(&entity.WishlistItem{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []any{&entity.Cart{}, &entity.Wishlist{}, &entity.PriceDrop{}, &entity.Address{}} {
+			if err := tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&entity.User{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
 }
 
+// Exists also counts deleted accounts, whose email stays taken until they
+// are purged.
 func (u *UserRepository) Exists(email string) (bool, error) {
 	if strings.TrimSpace(email) == "" {
 		return false, errors.New("email cannot be empty")
@@ -109,6 +174,6 @@ func (u *UserRepository) Exists(email string) (bool, error) {
 	normalizedEmail := strings.ToLower(strings.TrimSpace(email))
 
 	var count int64
-	err := u.DB.Model(&entity.User{}).Where("email = ?", normalizedEmail).Count(&count).Error
+	err := u.DB.Unscoped().Model(&entity.User{}).Where("email = ?", normalizedEmail).Count(&count).Error
 	return count > 0, err
 }
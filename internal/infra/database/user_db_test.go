@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github/GuilhermeHermes/GO_API/internal/entity"
 
@@ -203,6 +204,74 @@ func TestUser_Integration(t *testing.T) {
 	})
 }
 
+func TestUser_Update(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := NewUserRepository(db)
+	user := createTestUser(t)
+	require.NoError(t, userRepo.Create(user))
+
+	t.Run("should bump the version on update", func(t *testing.T) {
+		user.Username = "renamed"
+		require.NoError(t, userRepo.Update(user))
+
+		found, err := userRepo.FindByID(user.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", found.Username)
+		assert.Equal(t, int64(2), found.Version)
+	})
+
+	t.Run("should reject updates to a stale version", func(t *testing.T) {
+		stale := *user
+		stale.Version = 1
+		stale.Username = "stale"
+		assert.ErrorIs(t, userRepo.Update(&stale), ErrVersionConflict)
+		assert.Equal(t, int64(1), stale.Version)
+
+		found, err := userRepo.FindByID(user.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", found.Username)
+	})
+
+	t.Run("should return not found for unknown users", func(t *testing.T) {
+		missing := createTestUserWithEmail(t, "missing@example.com")
+		assert.ErrorIs(t, userRepo.Update(missing), gorm.ErrRecordNotFound)
+	})
+}
+
+func TestUser_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entity.Address{}, &entity.Cart{}, &entity.CartItem{},
+		&entity.Wishlist{}, &entity.WishlistItem{}, &entity.PriceDrop{}))
+	userRepo := NewUserRepository(db)
+	user := createTestUser(t)
+	require.NoError(t, userRepo.Create(user))
+	_, err := NewCartRepository(db).FindOrCreateUserCart(user.ID)
+	require.NoError(t, err)
+
+	t.Run("should hide deleted users but keep their email taken", func(t *testing.T) {
+		require.NoError(t, userRepo.Delete(user.ID.String()))
+		assert.ErrorIs(t, userRepo.Delete(user.ID.String()), gorm.ErrRecordNotFound)
+
+		_, err := userRepo.FindByEmail(user.Email)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		exists, err := userRepo.Exists(user.Email)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Error(t, userRepo.Create(createTestUser(t)))
+	})
+
+	t.Run("should purge deleted users with their carts", func(t *testing.T) {
+		purged, err := userRepo.Purge(time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		var carts int64
+		require.NoError(t, db.Model(&entity.Cart{}).Count(&carts).Error)
+		assert.Zero(t, carts)
+		require.NoError(t, userRepo.Create(createTestUser(t)))
+	})
+}
+
 func BenchmarkUser_Create(b *testing.B) {
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	db.AutoMigrate(&entity.User{})
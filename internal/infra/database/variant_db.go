@@ -0,0 +1,207 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSKUTaken            = errors.New("SKU already in use")
+	ErrVariantOptionsTaken = errors.New("a variant with these options already exists")
+)
+
+type VariantRepository struct {
+	DB *gorm.DB
+}
+
+func NewVariantRepository(db *gorm.DB) *VariantRepository {
+	return &VariantRepository{DB: db}
+}
+
+// FindOptions returns the product's options in display order.
+func (r *VariantRepository) FindOptions(productID string) ([]entity.ProductOption, error) {
+	var options []entity.ProductOption
+	err := r.DB.Where("product_id = ?", productID).Order("position").Find(&options).Error
+	return options, err
+}
+
+// ReplaceOptions stores new options for a product and reconciles its
+// variants with generated: variants whose option combination still exists
+// are kept as they are (SKU, price and barcode included), the others are
+// removed, and generated variants for new combinations are created.
+func (r *VariantRepository) ReplaceOptions(productID string, options []entity.ProductOption, generated []*entity.ProductVariant) ([]*entity.ProductVariant, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.ProductOption{}, "product_id = ?", productID).Error; err != nil {
+			return err
+		}
+		if len(options) > 0 {
+			if err := tx.Create(&options).Error; err != nil {
+				return err
+			}
+		}
+
+		var existing []*entity.ProductVariant
+		if err := tx.Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+			return err
+		}
+		wanted := make(map[string]*entity.ProductVariant, len(generated))
+		for _, v := range generated {
+			wanted[v.OptionsKey] = v
+		}
+		// Stale variants go first so their SKUs can be reused.
+		kept := make(map[string]bool, len(existing))
+		for _, old := range existing {
+			v, ok := wanted[old.OptionsKey]
+			if !ok {
+				if err := tx.Delete(old).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			kept[old.OptionsKey] = true
+			if old.Position != v.Position {
+				if err := tx.Model(old).Update("position", v.Position).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, v := range generated {
+			if kept[v.OptionsKey] {
+				continue
+			}
+			if err := checkSKU(tx, v); err != nil {
+				return err
+			}
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindVariants(productID)
+}
+
+// FindVariants returns the product's variants in display order.
+func (r *VariantRepository) FindVariants(productID string) ([]*entity.ProductVariant, error) {
+	var variants []*entity.ProductVariant
+	err := r.DB.Where("product_id = ?", productID).Order("position").Order("sku").Find(&variants).Error
+	return variants, err
+}
+
+// FindVariant returns a variant only if it belongs to the product.
+func (r *VariantRepository) FindVariant(productID, id string) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	if err := r.DB.Where("product_id = ? AND id = ?", productID, id).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *VariantRepository) FindVariantByID(id string) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	if err := r.DB.Where("id = ?", id).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// FindBySKU looks a variant up by SKU, ignoring case.
+func (r *VariantRepository) FindBySKU(sku string) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	if err := r.DB.Where("sku = ?", entity.NormalizeSKU(sku)).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *VariantRepository) CreateVariant(variant *entity.ProductVariant) error {
+	if variant == nil {
+		return errors.New("variant cannot be nil")
+	}
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkSKU(tx, variant); err != nil {
+			return err
+		}
+		if err := checkVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		return tx.Create(variant).Error
+	})
+}
+
+func (r *VariantRepository) UpdateVariant(variant *entity.ProductVariant) error {
+	if variant == nil {
+		return errors.New("variant cannot be nil")
+	}
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkSKU(tx, variant); err != nil {
+			return err
+		}
+		if err := checkVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		variant.UpdatedAt = time.Now()
+		return tx.Save(variant).Error
+	})
+}
+
+// DeleteVariant removes a variant of the product, returning
+// gorm.ErrRecordNotFound when there is none.
+func (r *VariantRepository) DeleteVariant(productID, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return errors.New("id cannot be empty")
+	}
+	result := r.DB.Delete(&entity.ProductVariant{}, "product_id = ? AND id = ?", productID, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// checkSKU refuses a SKU used by another variant. The unique index backs
+// this up against concurrent writers.
+func checkSKU(tx *gorm.DB, variant *entity.ProductVariant) error {
+	var count int64
+	err := tx.Model(&entity.ProductVariant{}).
+		Where("sku = ? AND id <> ?", variant.SKU, variant.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSKUTaken
+	}
+	return nil
+}
+
+func checkVariantOptions(tx *gorm.DB, variant *entity.ProductVariant) error {
+	var count int64
+	err := tx.Model(&entity.ProductVariant{}).
+		Where("product_id = ? AND options_key = ? AND id <> ?", variant.ProductID, variant.OptionsKey, variant.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrVariantOptionsTaken
+	}
+	return nil
+}
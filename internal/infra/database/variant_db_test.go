@@ -0,0 +1,105 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setOptions(t *testing.T, repo *VariantRepository, product *entity.Product, options []entity.ProductOption) []*entity.ProductVariant {
+	options, err := entity.NormalizeOptions(product.ID, options)
+	require.NoError(t, err)
+	generated, err := entity.GenerateVariants(product, options, "")
+	require.NoError(t, err)
+	variants, err := repo.ReplaceOptions(product.ID.String(), options, generated)
+	require.NoError(t, err)
+	return variants
+}
+
+func TestVariant_ReplaceOptions(t *testing.T) {
+	db := setupProductTestDB(t)
+	products := NewProductRepository(db)
+	repo := NewVariantRepository(db)
+
+	product := createTestProduct(t)
+	require.NoError(t, products.Create(product))
+
+	variants := setOptions(t, repo, product, []entity.ProductOption{
+		{Name: "size", Values: []string{"S", "M"}},
+	})
+	require.Len(t, variants, 2)
+	assert.Equal(t, "TESTPRODUCT-S", variants[0].SKU)
+
+	t.Run("should keep variants whose combination survives", func(t *testing.T) {
+		medium := variants[1]
+		medium.Barcode = "4006381333931"
+		require.NoError(t, repo.UpdateVariant(medium))
+
+		updated := setOptions(t, repo, product, []entity.ProductOption{
+			{Name: "size", Values: []string{"M", "L"}},
+		})
+		require.Len(t, updated, 2)
+		assert.Equal(t, medium.ID, updated[0].ID)
+		assert.Equal(t, "4006381333931", updated[0].Barcode)
+		assert.Equal(t, "TESTPRODUCT-L", updated[1].SKU)
+
+		options, err := repo.FindOptions(product.ID.String())
+		require.NoError(t, err)
+		require.Len(t, options, 1)
+		assert.Equal(t, []string{"M", "L"}, options[0].Values)
+	})
+
+	t.Run("should remove variants when the product is purged", func(t *testing.T) {
+		require.NoError(t, products.Delete(product.ID.String(), product.Version))
+		_, err := products.Purge(time.Now().Add(time.Second))
+		require.NoError(t, err)
+		remaining, err := repo.FindVariants(product.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
+
+func TestVariant_SKU(t *testing.T) {
+	db := setupProductTestDB(t)
+	products := NewProductRepository(db)
+	repo := NewVariantRepository(db)
+
+	product := createTestProduct(t)
+	require.NoError(t, products.Create(product))
+	options, err := entity.NormalizeOptions(product.ID, []entity.ProductOption{{Name: "color", Values: []string{"Red", "Blue"}}})
+	require.NoError(t, err)
+
+	red, err := entity.NewProductVariant(product, options, map[string]string{"color": "Red"}, "SHIRT-RED", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateVariant(red))
+
+	t.Run("should find a variant by SKU ignoring case", func(t *testing.T) {
+		found, err := repo.FindBySKU("shirt-red")
+		require.NoError(t, err)
+		assert.Equal(t, red.ID, found.ID)
+		assert.Equal(t, map[string]string{"color": "Red"}, found.Options)
+	})
+
+	t.Run("should reject a duplicate SKU", func(t *testing.T) {
+		blue, err := entity.NewProductVariant(product, options, map[string]string{"color": "Blue"}, "shirt-red", "", nil)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.CreateVariant(blue), ErrSKUTaken)
+	})
+
+	t.Run("should reject a duplicate combination", func(t *testing.T) {
+		again, err := entity.NewProductVariant(product, options, map[string]string{"color": "red"}, "SHIRT-RED-2", "", nil)
+		require.NoError(t, err)
+		assert.ErrorIs(t, repo.CreateVariant(again), ErrVariantOptionsTaken)
+	})
+
+	t.Run("should return not found when deleting another product's variant", func(t *testing.T) {
+		other := createTestProduct(t)
+		assert.ErrorIs(t, repo.DeleteVariant(other.ID.String(), red.ID.String()), gorm.ErrRecordNotFound)
+		require.NoError(t, repo.DeleteVariant(product.ID.String(), red.ID.String()))
+	})
+}
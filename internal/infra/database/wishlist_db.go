@@ -0,0 +1,190 @@
+package database
+
+import (
+	"errors"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+type WishlistRepository struct {
+	DB *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) *WishlistRepository {
+	return &WishlistRepository{DB: db}
+}
+
+// Create adds a wishlist, up to entity.MaxWishlists per user.
+func (r *WishlistRepository) Create(wishlist *entity.Wishlist) error {
+	if wishlist == nil {
+		return errors.New("wishlist cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Wishlist{}).Where("user_id = ?", wishlist.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= entity.MaxWishlists {
+			return entity.ErrWishlistLimit
+		}
+		return tx.Create(wishlist).Error
+	})
+}
+
+// FindByUser returns the user's wishlists with their items, oldest first.
+func (r *WishlistRepository) FindByUser(userID string) ([]*entity.Wishlist, error) {
+	var wishlists []*entity.Wishlist
+	if err := r.DB.Where("user_id = ?", userID).Order("created_at, id").Find(&wishlists).Error; err != nil {
+		return nil, err
+	}
+	if err := loadWishlistItems(r.DB, wishlists); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
+
+// FindByID only finds userID's wishlists.
+func (r *WishlistRepository) FindByID(userID, id string) (*entity.Wishlist, error) {
+	return r.findOne(r.DB.Where("user_id = ? AND id = ?", userID, id))
+}
+
+// FindByShareToken finds a shared wishlist, whoever owns it.
+func (r *WishlistRepository) FindByShareToken(token string) (*entity.Wishlist, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.findOne(r.DB.Where("share_token = ?", token))
+}
+
+func (r *WishlistRepository) findOne(query *gorm.DB) (*entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	if err := query.First(&wishlist).Error; err != nil {
+		return nil, err
+	}
+	if err := loadWishlistItems(r.DB, []*entity.Wishlist{&wishlist}); err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+// Update saves the wishlist's name and share token; items are added and
+// removed with AddItem and RemoveItem.
+func (r *WishlistRepository) Update(wishlist *entity.Wishlist) error {
+	result := r.DB.Model(wishlist).Where("user_id = ?", wishlist.UserID).Select("*").Updates(wishlist)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete removes one of userID's wishlists with its items.
+func (r *WishlistRepository) Delete(userID, id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND id = ?", userID, id).Delete(&entity.Wishlist{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("wishlist_id = ?", id).Delete(&entity.WishlistItem{}).Error
+	})
+}
+
+// AddItem puts a product on a wishlist, up to entity.MaxWishlistItems.
+func (r *WishlistRepository) AddItem(item *entity.WishlistItem) error {
+	if item == nil {
+		return errors.New("wishlist item cannot be nil")
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var count, same int64
+		items := tx.Model(&entity.WishlistItem{}).Where("wishlist_id = ?", item.WishlistID)
+		if err := items.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if err := items.Session(&gorm.Session{}).Where("product_id = ?", item.ProductID).Count(&same).Error; err != nil {
+			return err
+		}
+		if same > 0 {
+			return entity.ErrAlreadyWishlisted
+		}
+		if count >= entity.MaxWishlistItems {
+			return entity.ErrWishlistFull
+		}
+		return tx.Create(item).Error
+	})
+}
+
+func (r *WishlistRepository) RemoveItem(wishlistID, productID string) error {
+	result := r.DB.Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).Delete(&entity.WishlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindPriceDrops returns one page of the price drops recorded for userID,
+// newest first.
+func (r *WishlistRepository) FindPriceDrops(userID string, page, limit int) ([]*entity.PriceDrop, error) {
+	if page <= 0 || limit <= 0 {
+		return nil, errors.New("page and limit must be greater than 0")
+	}
+	var drops []*entity.PriceDrop
+	err := r.DB.Where("user_id = ?", userID).
+		Order("created_at DESC, id").Limit(limit).Offset((page - 1) * limit).
+		Find(&drops).Error
+	return drops, err
+}
+
+func loadWishlistItems(db *gorm.DB, wishlists []*entity.Wishlist) error {
+	if len(wishlists) == 0 {
+		return nil
+	}
+	byID := make(map[pkgentity.ID]*entity.Wishlist, len(wishlists))
+	ids := make([]pkgentity.ID, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		wishlist.Items = []*entity.WishlistItem{}
+		byID[wishlist.ID] = wishlist
+		ids = append(ids, wishlist.ID)
+	}
+	var items []*entity.WishlistItem
+	if err := db.Where("wishlist_id IN ?", ids).Order("created_at, id").Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		wishlist := byID[item.WishlistID]
+		wishlist.Items = append(wishlist.Items, item)
+	}
+	return nil
+}
+
+// recordPriceDrops records a price drop for every user with productID on
+// a wishlist, once per user however many of their lists have it.
+func recordPriceDrops(tx *gorm.DB, productID pkgentity.ID, oldPrice, newPrice pkgentity.Money) error {
+	if !entity.IsPriceDrop(oldPrice, newPrice) {
+		return nil
+	}
+	var userIDs []pkgentity.ID
+	err := tx.Model(&entity.Wishlist{}).
+		Distinct("wishlists.user_id").
+		Joins("JOIN wishlist_items ON wishlist_items.wishlist_id = wishlists.id").
+		Where("wishlist_items.product_id = ?", productID).
+		Pluck("wishlists.user_id", &userIDs).Error
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+	drops := make([]*entity.PriceDrop, 0, len(userIDs))
+	for _, userID := range userIDs {
+		drops = append(drops, entity.NewPriceDrop(userID, productID, oldPrice, newPrice))
+	}
+	return tx.Create(drops).Error
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestWishlistRepository(t *testing.T) {
+	db := setupProductTestDB(t)
+	repo := NewWishlistRepository(db)
+	products := NewProductRepository(db)
+	userID := pkgentity.NewID()
+
+	product := createTestProduct(t)
+	require.NoError(t, products.Create(product))
+	birthday, err := entity.NewWishlist(userID, "Aniversário")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(birthday))
+	later, err := entity.NewWishlist(userID, "Depois")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(later))
+
+	t.Run("should add each product once", func(t *testing.T) {
+		require.NoError(t, repo.AddItem(entity.NewWishlistItem(birthday.ID, product)))
+		assert.ErrorIs(t, repo.AddItem(entity.NewWishlistItem(birthday.ID, product)), entity.ErrAlreadyWishlisted)
+		require.NoError(t, repo.AddItem(entity.NewWishlistItem(later.ID, product)))
+
+		wishlists, err := repo.FindByUser(userID.String())
+		require.NoError(t, err)
+		require.Len(t, wishlists, 2)
+		assert.Equal(t, "Aniversário", wishlists[0].Name)
+		require.Len(t, wishlists[0].Items, 1)
+		assert.Equal(t, brl("100.00"), wishlists[0].Items[0].AddedPrice)
+	})
+
+	t.Run("should find shared wishlists by token only", func(t *testing.T) {
+		_, err := repo.FindByShareToken("")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, birthday.Share())
+		require.NoError(t, repo.Update(birthday))
+		shared, err := repo.FindByShareToken(*birthday.ShareToken)
+		require.NoError(t, err)
+		assert.Equal(t, birthday.ID, shared.ID)
+		assert.Len(t, shared.Items, 1)
+
+		birthday.Unshare()
+		require.NoError(t, repo.Update(birthday))
+		_, err = repo.FindByShareToken(*shared.ShareToken)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should record one price drop per user when the price goes down", func(t *testing.T) {
+		product.Price = brl("120.00")
+		require.NoError(t, products.Update(product))
+		product.Price = brl("80.00")
+		require.NoError(t, products.Update(product))
+
+		drops, err := repo.FindPriceDrops(userID.String(), 1, 10)
+		require.NoError(t, err)
+		require.Len(t, drops, 1)
+		assert.Equal(t, brl("120.00"), drops[0].OldPrice)
+		assert.Equal(t, brl("80.00"), drops[0].NewPrice)
+	})
+
+	t.Run("should keep other users out", func(t *testing.T) {
+		_, err := repo.FindByID(pkgentity.NewID().String(), birthday.ID.String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.Delete(pkgentity.NewID().String(), birthday.ID.String()), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should delete wishlists and their items", func(t *testing.T) {
+		require.NoError(t, repo.RemoveItem(later.ID.String(), product.ID.String()))
+		assert.ErrorIs(t, repo.RemoveItem(later.ID.String(), product.ID.String()), gorm.ErrRecordNotFound)
+		require.NoError(t, repo.Delete(userID.String(), birthday.ID.String()))
+
+		var count int64
+		require.NoError(t, db.Model(&entity.WishlistItem{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
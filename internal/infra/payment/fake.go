@@ -0,0 +1,177 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// Payment method tokens FakeProvider understands. Any other token is
+// declined with "invalid_payment_method".
+const (
+	FakeTokenApproved          = "tok_approved"
+	FakeTokenDeclined          = "tok_declined"
+	FakeTokenInsufficientFunds = "tok_insufficient_funds"
+)
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of a fake webhook body.
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakeProvider is an in-process gateway for tests and local development.
+// Its answers depend only on the payment method token and the idempotency
+// key, and it signs webhooks with a shared secret like a real gateway.
+type FakeProvider struct {
+	secret []byte
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	byKey    map[string]*Authorization
+	issued   int
+}
+
+type fakePayment struct {
+	authorized entity.Money
+	captured   int64
+	refunded   int64
+}
+
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{
+		secret:   []byte(webhookSecret),
+		payments: map[string]*fakePayment{},
+		byKey:    map[string]*Authorization{},
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	if err := req.Amount.Validate(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if req.IdempotencyKey != "" {
+		if auth, ok := p.byKey[req.IdempotencyKey]; ok {
+			return authorizationResult(auth)
+		}
+	}
+
+	p.issued++
+	auth := &Authorization{Reference: p.reference(req.IdempotencyKey)}
+	switch req.PaymentMethod {
+	case FakeTokenApproved:
+		auth.Approved = true
+		p.payments[auth.Reference] = &fakePayment{authorized: req.Amount}
+	case FakeTokenDeclined:
+		auth.DeclineCode = "card_declined"
+	case FakeTokenInsufficientFunds:
+		auth.DeclineCode = "insufficient_funds"
+	default:
+		auth.DeclineCode = "invalid_payment_method"
+	}
+	if req.IdempotencyKey != "" {
+		p.byKey[req.IdempotencyKey] = auth
+	}
+	return authorizationResult(auth)
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, reference string, amount entity.Money) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok {
+		return ErrUnknownPayment
+	}
+	if amount.Currency != payment.authorized.Currency || payment.captured+amount.Amount > payment.authorized.Amount {
+		return ErrAmountExceeded
+	}
+	payment.captured += amount.Amount
+	return nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, reference string, amount entity.Money) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok {
+		return ErrUnknownPayment
+	}
+	if amount.Currency != payment.authorized.Currency || payment.refunded+amount.Amount > payment.captured {
+		return ErrAmountExceeded
+	}
+	payment.refunded += amount.Amount
+	return nil
+}
+
+// fakeWebhook is the body of a fake webhook request.
+type fakeWebhook struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	Reference string           `json:"reference"`
+	Amount    entity.Money     `json:"amount"`
+}
+
+// VerifyWebhook rejects every webhook when the provider has no secret,
+// since anyone could sign one.
+func (p *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || len(p.secret) == 0 || !hmac.Equal(signature, p.sign(payload)) {
+		return nil, ErrInvalidSignature
+	}
+	var body fakeWebhook
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("decoding webhook: %w", err)
+	}
+	event := WebhookEvent(body)
+	return &event, nil
+}
+
+// SignedWebhook builds the request body and headers the fake gateway would
+// send for event, to simulate notifications in tests and local development.
+func (p *FakeProvider) SignedWebhook(event WebhookEvent) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(fakeWebhook(event))
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(FakeSignatureHeader, hex.EncodeToString(p.sign(payload)))
+	return payload, header, nil
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// reference derives the payment reference from the idempotency key, so
+// the same key always yields the same reference, or numbers it otherwise.
+func (p *FakeProvider) reference(idempotencyKey string) string {
+	if idempotencyKey == "" {
+		return fmt.Sprintf("fake_%08d", p.issued)
+	}
+	sum := sha256.Sum256([]byte(idempotencyKey))
+	return "fake_" + hex.EncodeToString(sum[:12])
+}
+
+func authorizationResult(auth *Authorization) (*Authorization, error) {
+	result := *auth
+	if !result.Approved {
+		return &result, ErrDeclined
+	}
+	return &result, nil
+}
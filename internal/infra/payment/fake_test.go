@@ -0,0 +1,67 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := NewFakeProvider("secret")
+	amount := entity.MustParseMoney("100", "BRL")
+
+	t.Run("should authorize, capture and refund up to the limits", func(t *testing.T) {
+		auth, err := provider.Authorize(ctx, AuthorizeRequest{Amount: amount, PaymentMethod: FakeTokenApproved, IdempotencyKey: "intent-1"})
+		require.NoError(t, err)
+		assert.True(t, auth.Approved)
+
+		again, err := provider.Authorize(ctx, AuthorizeRequest{Amount: amount, PaymentMethod: FakeTokenApproved, IdempotencyKey: "intent-1"})
+		require.NoError(t, err)
+		assert.Equal(t, auth.Reference, again.Reference)
+
+		assert.Equal(t, ErrAmountExceeded, provider.Refund(ctx, auth.Reference, amount))
+		assert.Equal(t, ErrAmountExceeded, provider.Capture(ctx, auth.Reference, entity.MustParseMoney("100.01", "BRL")))
+		require.NoError(t, provider.Capture(ctx, auth.Reference, amount))
+		require.NoError(t, provider.Refund(ctx, auth.Reference, entity.MustParseMoney("40", "BRL")))
+		require.NoError(t, provider.Refund(ctx, auth.Reference, entity.MustParseMoney("60", "BRL")))
+		assert.Equal(t, ErrAmountExceeded, provider.Refund(ctx, auth.Reference, entity.MustParseMoney("0.01", "BRL")))
+		assert.Equal(t, ErrUnknownPayment, provider.Capture(ctx, "fake_nope", amount))
+	})
+
+	t.Run("should decline by token", func(t *testing.T) {
+		auth, err := provider.Authorize(ctx, AuthorizeRequest{Amount: amount, PaymentMethod: FakeTokenInsufficientFunds})
+		assert.Equal(t, ErrDeclined, err)
+		assert.Equal(t, "insufficient_funds", auth.DeclineCode)
+
+		auth, err = provider.Authorize(ctx, AuthorizeRequest{Amount: amount, PaymentMethod: "tok_whatever"})
+		assert.Equal(t, ErrDeclined, err)
+		assert.Equal(t, "invalid_payment_method", auth.DeclineCode)
+		assert.Equal(t, ErrUnknownPayment, provider.Capture(ctx, auth.Reference, amount))
+	})
+
+	t.Run("should verify signed webhooks", func(t *testing.T) {
+		event := WebhookEvent{ID: "evt_1", Type: EventCaptured, Reference: "fake_1", Amount: amount}
+		payload, header, err := provider.SignedWebhook(event)
+		require.NoError(t, err)
+
+		verified, err := provider.VerifyWebhook(payload, header)
+		require.NoError(t, err)
+		assert.Equal(t, event, *verified)
+
+		_, err = NewFakeProvider("other").VerifyWebhook(payload, header)
+		assert.Equal(t, ErrInvalidSignature, err)
+		_, err = provider.VerifyWebhook(append(payload, ' '), header)
+		assert.Equal(t, ErrInvalidSignature, err)
+
+		unsigned := NewFakeProvider("")
+		payload, header, err = unsigned.SignedWebhook(event)
+		require.NoError(t, err)
+		_, err = unsigned.VerifyWebhook(payload, header)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+}
@@ -0,0 +1,79 @@
+// Package payment talks to payment gateways. Handlers only see the
+// PaymentProvider interface, so a real gateway adapter can replace the
+// in-process FakeProvider without touching them.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var (
+	// ErrDeclined is returned by Authorize when the gateway refuses the
+	// payment; Authorization.DeclineCode says why.
+	ErrDeclined = errors.New("payment declined")
+	// ErrInvalidSignature is returned by VerifyWebhook for a payload the
+	// gateway did not sign.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnknownPayment is returned for a reference the gateway never issued.
+	ErrUnknownPayment = errors.New("unknown payment")
+	// ErrAmountExceeded is returned when capturing more than was authorized
+	// or refunding more than was captured.
+	ErrAmountExceeded = errors.New("amount exceeds what the payment allows")
+)
+
+// AuthorizeRequest asks the gateway to hold Amount on PaymentMethod, a
+// token the gateway's client-side SDK issued. IdempotencyKey makes
+// retries return the first authorization instead of charging twice.
+type AuthorizeRequest struct {
+	Amount         entity.Money
+	PaymentMethod  string
+	IdempotencyKey string
+	Description    string
+}
+
+// Authorization is the gateway's answer to AuthorizeRequest. Reference
+// identifies the payment in later calls and in webhooks.
+type Authorization struct {
+	Reference   string
+	Approved    bool
+	DeclineCode string
+}
+
+type WebhookEventType string
+
+const (
+	EventCaptured WebhookEventType = "payment.captured"
+	EventFailed   WebhookEventType = "payment.failed"
+	EventRefunded WebhookEventType = "payment.refunded"
+)
+
+// WebhookEvent is a verified notification from the gateway. Amount is the
+// amount captured for EventCaptured and the total refunded so far for
+// EventRefunded, so repeated or reordered refund events are harmless.
+type WebhookEvent struct {
+	ID        string
+	Type      WebhookEventType
+	Reference string
+	Amount    entity.Money
+}
+
+// PaymentProvider is what a payment gateway adapter implements.
+type PaymentProvider interface {
+	// Name identifies the gateway; it is stored with each payment.
+	Name() string
+	// Authorize holds the amount on the payment method. A declined payment
+	// returns the authorization together with ErrDeclined.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	// Capture charges up to the authorized amount.
+	Capture(ctx context.Context, reference string, amount entity.Money) error
+	// Refund gives back up to the captured amount; it may be called more
+	// than once for partial refunds.
+	Refund(ctx context.Context, reference string, amount entity.Money) error
+	// VerifyWebhook checks that a webhook request came from the gateway and
+	// decodes it.
+	VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+}
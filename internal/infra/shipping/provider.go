@@ -0,0 +1,43 @@
+// Package shipping prices parcels. Handlers only see the
+// ShippingRateProvider interface, so a carrier's rate API can replace the
+// built-in TableRateProvider without touching them.
+package shipping
+
+import (
+	"context"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// RateRequest asks for the ways to ship Items to Destination. Subtotal is
+// what the goods cost after discounts, for free shipping thresholds.
+type RateRequest struct {
+	Destination entity.ShippingDestination
+	Items       []entity.ShippingItem
+	Subtotal    pkgentity.Money
+}
+
+// ShippingRateProvider is what a source of shipping rates implements.
+type ShippingRateProvider interface {
+	// Name identifies the provider.
+	Name() string
+	// Rates returns the options available for the request, cheapest first.
+	// No options is not an error: the destination may just not be served.
+	Rates(ctx context.Context, req RateRequest) ([]entity.ShippingRate, error)
+}
+
+// Find returns the option with methodID among the rates provider offers for
+// req, or entity.ErrShippingUnavailable.
+func Find(ctx context.Context, provider ShippingRateProvider, req RateRequest, methodID string) (*entity.ShippingRate, error) {
+	rates, err := provider.Rates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, rate := range rates {
+		if rate.MethodID.String() == methodID {
+			return &rate, nil
+		}
+	}
+	return nil, entity.ErrShippingUnavailable
+}
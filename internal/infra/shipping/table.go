@@ -0,0 +1,40 @@
+package shipping
+
+import (
+	"context"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+)
+
+// ZoneSource lists the shipping zones with their methods.
+// database.ShippingDB satisfies it.
+type ZoneSource interface {
+	FindZones() ([]*entity.ShippingZone, error)
+}
+
+// TableRateProvider quotes from the zones and methods the store keeps: the
+// zone covering the destination most closely offers its methods that apply
+// to the parcel.
+type TableRateProvider struct {
+	zones ZoneSource
+}
+
+func NewTableRateProvider(zones ZoneSource) *TableRateProvider {
+	return &TableRateProvider{zones: zones}
+}
+
+func (p *TableRateProvider) Name() string {
+	return "table"
+}
+
+func (p *TableRateProvider) Rates(ctx context.Context, req RateRequest) ([]entity.ShippingRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	zones, err := p.zones.FindZones()
+	if err != nil {
+		return nil, err
+	}
+	zone := entity.MatchShippingZone(zones, req.Destination)
+	return entity.QuoteShipping(zone, req.Items, req.Subtotal), nil
+}
@@ -0,0 +1,85 @@
+package shipping
+
+import (
+	"context"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticZones []*entity.ShippingZone
+
+func (z staticZones) FindZones() ([]*entity.ShippingZone, error) {
+	return z, nil
+}
+
+func TestTableRateProvider(t *testing.T) {
+	ctx := context.Background()
+	brl := func(amount string) pkgentity.Money { return pkgentity.MustParseMoney(amount, "BRL") }
+
+	brazil, err := entity.NewShippingZone("Brasil", []entity.ShippingArea{{Country: "BR"}})
+	require.NoError(t, err)
+	standard, err := entity.NewFlatShipping(brazil.ID, "Standard", brl("25"))
+	require.NoError(t, err)
+	free, err := entity.NewFreeShipping(brazil.ID, "Free", brl("200"))
+	require.NoError(t, err)
+	brazil.Methods = []*entity.ShippingMethod{standard, free}
+
+	capital, err := entity.NewShippingZone("São Paulo capital", []entity.ShippingArea{{Country: "BR", Region: "SP", PostalPrefix: "01"}})
+	require.NoError(t, err)
+	courier, err := entity.NewWeightShipping(capital.ID, "Courier", []entity.WeightTier{
+		{UpToGrams: 1000, Cost: brl("10")},
+		{UpToGrams: 5000, Cost: brl("18")},
+	}, 0)
+	require.NoError(t, err)
+	capital.Methods = []*entity.ShippingMethod{courier}
+
+	provider := NewTableRateProvider(staticZones{brazil, capital})
+	items := []entity.ShippingItem{{WeightGrams: 600, Quantity: 2}}
+
+	t.Run("should quote the closest zone's methods", func(t *testing.T) {
+		dest, err := entity.NewShippingDestination("br", "sp", "01310-100")
+		require.NoError(t, err)
+		rates, err := provider.Rates(ctx, RateRequest{Destination: dest, Items: items, Subtotal: brl("50")})
+		require.NoError(t, err)
+		require.Len(t, rates, 1)
+		assert.Equal(t, "Courier", rates[0].Name)
+		assert.Equal(t, brl("18"), rates[0].Cost)
+	})
+
+	t.Run("should offer free shipping over the threshold, cheapest first", func(t *testing.T) {
+		dest, err := entity.NewShippingDestination("BR", "RJ", "20000-000")
+		require.NoError(t, err)
+		rates, err := provider.Rates(ctx, RateRequest{Destination: dest, Items: items, Subtotal: brl("50")})
+		require.NoError(t, err)
+		require.Len(t, rates, 1)
+		assert.Equal(t, "Standard", rates[0].Name)
+
+		rates, err = provider.Rates(ctx, RateRequest{Destination: dest, Items: items, Subtotal: brl("200")})
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "Free", rates[0].Name)
+		assert.True(t, rates[0].Cost.IsZero())
+	})
+
+	t.Run("should find a chosen method or report it unavailable", func(t *testing.T) {
+		dest, err := entity.NewShippingDestination("BR", "", "")
+		require.NoError(t, err)
+		req := RateRequest{Destination: dest, Items: items, Subtotal: brl("50")}
+		rate, err := Find(ctx, provider, req, standard.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, brl("25"), rate.Cost)
+		_, err = Find(ctx, provider, req, free.ID.String())
+		assert.ErrorIs(t, err, entity.ErrShippingUnavailable)
+
+		us, err := entity.NewShippingDestination("US", "", "")
+		require.NoError(t, err)
+		rates, err := provider.Rates(ctx, RateRequest{Destination: us, Items: items, Subtotal: brl("50")})
+		require.NoError(t, err)
+		assert.Empty(t, rates)
+	})
+}
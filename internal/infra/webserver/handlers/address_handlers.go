@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type AddressHandler struct {
+	AddressDB database.AddressDB
+}
+
+func NewAddressHandler(addresses database.AddressDB) *AddressHandler {
+	return &AddressHandler{AddressDB: addresses}
+}
+
+// GetAddresses lista os endereços do usuário logado
+func (h *AddressHandler) GetAddresses(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	addresses, err := h.AddressDB.FindByUser(userID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		response = append(response, toAddressResponse(address))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateAddress adiciona um endereço ao catálogo do usuário; o primeiro vira
+// o padrão de entrega e de cobrança
+func (h *AddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	address, err := entity.NewAddress(userID, req.Label, toPostalAddress(req.PostalAddress))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.DefaultShipping != nil {
+		address.DefaultShipping = *req.DefaultShipping
+	}
+	if req.DefaultBilling != nil {
+		address.DefaultBilling = *req.DefaultBilling
+	}
+	if err := h.AddressDB.Create(address); err != nil {
+		writeAddressError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAddressResponse(address))
+}
+
+// GetAddress busca um endereço do usuário logado
+func (h *AddressHandler) GetAddress(w http.ResponseWriter, r *http.Request) {
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAddressResponse(address))
+}
+
+// UpdateAddress substitui um endereço; pedidos já feitos mantêm a cópia que
+// guardaram
+func (h *AddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	address.Label = req.Label
+	address.PostalAddress = toPostalAddress(req.PostalAddress)
+	if req.DefaultShipping != nil {
+		address.DefaultShipping = *req.DefaultShipping
+	}
+	if req.DefaultBilling != nil {
+		address.DefaultBilling = *req.DefaultBilling
+	}
+	if err := address.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	address.UpdatedAt = time.Now()
+	if err := h.AddressDB.Update(address); err != nil {
+		writeAddressError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAddressResponse(address))
+}
+
+// DeleteAddress remove um endereço do usuário logado
+func (h *AddressHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := h.AddressDB.Delete(userID.String(), chi.URLParam(r, "id")); err != nil {
+		writeAddressError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findAddress loads the address in the URL from the logged-in user's book.
+func (h *AddressHandler) findAddress(w http.ResponseWriter, r *http.Request) (*entity.Address, bool) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	address, err := h.AddressDB.FindByID(userID.String(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeAddressError(w, err)
+		return nil, false
+	}
+	return address, true
+}
+
+func writeAddressError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Address not found", http.StatusNotFound)
+	case errors.Is(err, entity.ErrAddressBookFull):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func toPostalAddress(p dto.PostalAddress) entity.PostalAddress {
+	return entity.PostalAddress{
+		Recipient:  p.Recipient,
+		Line1:      p.Line1,
+		Line2:      p.Line2,
+		City:       p.City,
+		Region:     p.Region,
+		PostalCode: p.PostalCode,
+		Country:    p.Country,
+		Phone:      p.Phone,
+	}
+}
+
+func toPostalAddressResponse(p entity.PostalAddress) dto.PostalAddress {
+	return dto.PostalAddress{
+		Recipient:  p.Recipient,
+		Line1:      p.Line1,
+		Line2:      p.Line2,
+		City:       p.City,
+		Region:     p.Region,
+		PostalCode: p.PostalCode,
+		Country:    p.Country,
+		Phone:      p.Phone,
+	}
+}
+
+func toAddressResponse(address *entity.Address) dto.AddressResponse {
+	return dto.AddressResponse{
+		ID:              address.ID.String(),
+		Label:           address.Label,
+		PostalAddress:   toPostalAddressResponse(address.PostalAddress),
+		DefaultShipping: address.DefaultShipping,
+		DefaultBilling:  address.DefaultBilling,
+		CreatedAt:       address.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:       address.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
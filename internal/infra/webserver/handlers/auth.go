@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/jwtauth"
+)
+
+// RequireRole only lets through requests whose JWT "role" claim is one of
+// roles. It must run after jwtauth.Verifier and jwtauth.Authenticator.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !slices.Contains(roles, roleFromContext(r)) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func roleFromContext(r *http.Request) string {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return ""
+	}
+	role, _ := claims["role"].(string)
+	return role
+}
+
+// OptionalAuth lets requests without a JWT through, for routes that also
+// serve guests, but still rejects an invalid or expired one. It must run
+// after jwtauth.Verifier.
+func OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := jwtauth.FromContext(r.Context())
+		if err != nil && !errors.Is(err, jwtauth.ErrNoTokenFound) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userIDFromContext returns the user ID in the JWT "sub" claim, if the
+// request carries a valid token.
+func userIDFromContext(r *http.Request) (pkgentity.ID, bool) {
+	token, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil || token == nil {
+		return pkgentity.ID{}, false
+	}
+	sub, _ := claims["sub"].(string)
+	id, err := pkgentity.ParseID(sub)
+	if err != nil {
+		return pkgentity.ID{}, false
+	}
+	return id, true
+}
@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/internal/infra/shipping"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// CartTokenHeader carries a guest cart's token, both ways: the server sets
+// it when it creates a guest cart and guests send it back on later calls
+// and on login, to merge the cart into the user's.
+const CartTokenHeader = "X-Cart-Token"
+
+var errUnknownItem = errors.New("product, variant or SKU not found")
+
+type CartHandler struct {
+	CartDB    database.CartDB
+	ProductDB database.ProductDB
+	VariantDB database.VariantDB
+	Pricer    *DiscountPricer
+	Tax       *TaxCalculator
+	Shipping  shipping.ShippingRateProvider
+}
+
+func NewCartHandler(carts database.CartDB, products database.ProductDB, variants database.VariantDB, pricer *DiscountPricer, tax *TaxCalculator, rates shipping.ShippingRateProvider) *CartHandler {
+	return &CartHandler{
+		CartDB:    carts,
+		ProductDB: products,
+		VariantDB: variants,
+		Pricer:    pricer,
+		Tax:       tax,
+		Shipping:  rates,
+	}
+}
+
+// GetCart devolve o carrinho do usuário logado ou do convidado (X-Cart-Token),
+// com o imposto de ?country= e ?region= ou do local padrão da loja
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		cart = &entity.Cart{}
+	}
+
+	h.writeCart(w, r, cart, http.StatusOK)
+}
+
+// QuoteShipping lista as opções de frete do carrinho para o destino em
+// ?country=, ?region= e ?postal_code=, da mais barata para a mais cara
+func (h *CartHandler) QuoteShipping(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	dest, err := entity.NewShippingDestination(values.Get("country"), values.Get("region"), values.Get("postal_code"))
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		cart = &entity.Cart{}
+	}
+
+	breakdown, _, err := h.priceCart(cart)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	items := make([]entity.ShippingItem, 0, len(cart.Items))
+	var weight int64
+	for _, line := range cart.Items {
+		product, err := h.ProductDB.FindByID(line.ProductID.String())
+		if err != nil {
+			writeOrderError(w, fmt.Errorf("%w: %s", errItemUnavailable, line.Name))
+			return
+		}
+		items = append(items, entity.ShippingItem{WeightGrams: product.WeightGrams, Dimensions: product.Dimensions, Quantity: line.Quantity})
+		weight += product.WeightGrams * line.Quantity
+	}
+	rates, err := h.Shipping.Rates(r.Context(), shipping.RateRequest{Destination: dest, Items: items, Subtotal: breakdown.Total})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	response := dto.ShippingQuoteResponse{
+		Country:     dest.Country,
+		Region:      dest.Region,
+		PostalCode:  dest.PostalCode,
+		WeightGrams: weight,
+		Subtotal:    breakdown.Total,
+		Rates:       make([]dto.ShippingRateResponse, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		response.Rates = append(response.Rates, dto.ShippingRateResponse{
+			MethodID: rate.MethodID.String(),
+			Name:     rate.Name,
+			Kind:     string(rate.Kind),
+			ZoneID:   rate.ZoneID.String(),
+			ZoneName: rate.ZoneName,
+			Cost:     rate.Cost,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddCartItem adiciona um produto ou variante ao carrinho, criando um
+// carrinho de convidado quando não há login nem X-Cart-Token
+func (h *CartHandler) AddCartItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, variant, err := findItem(r, h.ProductDB, h.VariantDB, req.SKU, req.ProductID, req.VariantID)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+	item, err := entity.NewCartItem(product, variant, req.Quantity)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	isNew := cart == nil
+	if isNew {
+		if cart, err = entity.NewGuestCart(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := cart.Add(item); err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	if isNew {
+		err = h.CartDB.Create(cart)
+		w.Header().Set(CartTokenHeader, *cart.GuestToken)
+	} else {
+		err = h.CartDB.Save(cart)
+	}
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	h.writeCart(w, r, cart, http.StatusCreated)
+}
+
+// UpdateCartItem muda a quantidade de um item; quantidade 0 remove o item
+func (h *CartHandler) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.changeCart(w, r, func(cart *entity.Cart, itemID pkgentity.ID) error {
+		return cart.SetQuantity(itemID, req.Quantity)
+	})
+}
+
+// RemoveCartItem remove um item do carrinho
+func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
+	h.changeCart(w, r, func(cart *entity.Cart, itemID pkgentity.ID) error {
+		return cart.Remove(itemID)
+	})
+}
+
+// ClearCart esvazia o carrinho
+func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	cart.Items = []*entity.CartItem{}
+	if err := h.CartDB.Save(cart); err != nil {
+		writeCartError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApplyCoupon aplica um cupom ao carrinho, se ele for válido para os itens
+func (h *CartHandler) ApplyCoupon(w http.ResponseWriter, r *http.Request) {
+	var req dto.ApplyCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		http.Error(w, entity.ErrCouponNotApplicable.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, coupon, err := h.Pricer.Price(cart.DiscountLines(), req.Code, cart.UserID)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+	cart.CouponCode = coupon.Code
+	if err := h.CartDB.Save(cart); err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	h.writeCart(w, r, cart, http.StatusOK)
+}
+
+// RemoveCoupon tira o cupom do carrinho
+func (h *CartHandler) RemoveCoupon(w http.ResponseWriter, r *http.Request) {
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	cart.CouponCode = ""
+	if err := h.CartDB.Save(cart); err != nil {
+		writeCartError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CartHandler) changeCart(w http.ResponseWriter, r *http.Request, change func(cart *entity.Cart, itemID pkgentity.ID) error) {
+	itemID, err := pkgentity.ParseID(chi.URLParam(r, "itemID"))
+	if err != nil {
+		http.Error(w, "Cart item not found", http.StatusNotFound)
+		return
+	}
+	cart, ok := h.findCart(w, r)
+	if !ok {
+		return
+	}
+	if cart == nil {
+		http.Error(w, "Cart item not found", http.StatusNotFound)
+		return
+	}
+
+	if err := change(cart, itemID); err != nil {
+		writeCartError(w, err)
+		return
+	}
+	if err := h.CartDB.Save(cart); err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	h.writeCart(w, r, cart, http.StatusOK)
+}
+
+// findCart returns the logged-in user's cart or the guest cart named by
+// CartTokenHeader. It returns a nil cart for a guest without a token.
+func (h *CartHandler) findCart(w http.ResponseWriter, r *http.Request) (*entity.Cart, bool) {
+	if userID, ok := userIDFromContext(r); ok {
+		cart, err := h.CartDB.FindOrCreateUserCart(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return nil, false
+		}
+		return cart, true
+	}
+
+	token := r.Header.Get(CartTokenHeader)
+	if token == "" {
+		return nil, true
+	}
+	cart, err := h.CartDB.FindByGuestToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Cart not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return cart, true
+}
+
+// findItem looks up the product, and variant if any, that a cart or order
+// line names by SKU or by product and variant ID. Products the user cannot
+// see are unknown to them.
+func findItem(r *http.Request, products database.ProductDB, variants database.VariantDB, sku, productID, variantID string) (*entity.Product, *entity.ProductVariant, error) {
+	if sku != "" {
+		variant, err := variants.FindBySKU(sku)
+		if err != nil {
+			return nil, nil, errUnknownItem
+		}
+		product, err := products.FindByID(variant.ProductID.String())
+		if err != nil || !visibleTo(r, product) {
+			return nil, nil, errUnknownItem
+		}
+		return product, variant, nil
+	}
+
+	product, err := products.FindByID(productID)
+	if err != nil || !visibleTo(r, product) {
+		return nil, nil, errUnknownItem
+	}
+	if variantID != "" {
+		variant, err := variants.FindVariant(product.ID.String(), variantID)
+		if err != nil {
+			return nil, nil, errUnknownItem
+		}
+		return product, variant, nil
+	}
+
+	found, err := variants.FindVariants(product.ID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(found) > 0 {
+		return nil, nil, entity.ErrVariantRequired
+	}
+	return product, nil, nil
+}
+
+func writeCartError(w http.ResponseWriter, err error) {
+	if status, ok := couponErrorStatus(err); ok {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	switch {
+	case errors.Is(err, errUnknownItem), errors.Is(err, entity.ErrVariantRequired),
+		errors.Is(err, entity.ErrQuantityLimit), isTaxLocationError(err),
+		errors.Is(err, entity.ErrDestinationRequired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrCartFull), errors.Is(err, entity.ErrCartCurrency):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, database.ErrVersionConflict):
+		http.Error(w, "Cart was changed by another request; retry", http.StatusConflict)
+	case errors.Is(err, entity.ErrCartItemNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeCart prices the cart with its coupon and taxes it where ?country=
+// and ?region= say. A coupon that no longer applies is left out of the
+// totals and reported in coupon_error; the shopper removes it or changes
+// the cart.
+func (h *CartHandler) writeCart(w http.ResponseWriter, r *http.Request, cart *entity.Cart, status int) {
+	loc, err := h.Tax.Location(r.URL.Query().Get("country"), r.URL.Query().Get("region"))
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+	breakdown, couponError, err := h.priceCart(cart)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tax, err := h.Tax.Calculate(cart.TaxLines(breakdown), loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := toCartResponse(cart, breakdown, tax)
+	response.CouponError = couponError
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// priceCart applies the active promotions and the cart's coupon. A coupon
+// that no longer applies is left out and its error returned as a string.
+func (h *CartHandler) priceCart(cart *entity.Cart) (*entity.DiscountBreakdown, string, error) {
+	breakdown, _, err := h.Pricer.Price(cart.DiscountLines(), cart.CouponCode, cart.UserID)
+	var couponError string
+	if _, ok := couponErrorStatus(err); ok {
+		couponError = err.Error()
+		breakdown, _, err = h.Pricer.Price(cart.DiscountLines(), "", nil)
+	}
+	return breakdown, couponError, err
+}
+
+func toCartResponse(cart *entity.Cart, breakdown *entity.DiscountBreakdown, tax *entity.TaxBreakdown) dto.CartResponse {
+	response := dto.CartResponse{
+		Items:            make([]dto.CartItemResponse, 0, len(cart.Items)),
+		ItemCount:        cart.ItemCount(),
+		CouponCode:       cart.CouponCode,
+		Subtotal:         breakdown.Subtotal,
+		Discounts:        toAppliedDiscountResponses(breakdown.Discounts),
+		Discount:         breakdown.Discount,
+		TaxCountry:       tax.Location.Country,
+		TaxRegion:        tax.Location.Region,
+		PricesIncludeTax: tax.PricesIncludeTax,
+		Tax:              tax.Tax,
+		Total:            tax.Gross,
+	}
+	if cart.ID != (pkgentity.ID{}) {
+		response.ID = cart.ID.String()
+	}
+	for _, item := range cart.Items {
+		line := dto.CartItemResponse{
+			ID:        item.ID.String(),
+			ProductID: item.ProductID.String(),
+			SKU:       item.SKU,
+			Name:      item.Name,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			TaxClass:  item.TaxClass,
+		}
+		if item.VariantID != nil {
+			line.VariantID = item.VariantID.String()
+		}
+		if priced := breakdown.Line(item.ID.String()); priced != nil {
+			line.Subtotal = priced.Subtotal
+			line.Discounts = toAppliedDiscountResponses(priced.Discounts)
+			line.Discount = priced.Discount
+			line.Total = priced.Total
+		}
+		if taxed := tax.Line(item.ID.String()); taxed != nil {
+			line.TaxRate = taxed.Rate
+			line.Tax = taxed.Tax
+		}
+		response.Items = append(response.Items, line)
+	}
+	return response
+}
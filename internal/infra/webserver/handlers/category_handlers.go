@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type CategoryHandler struct {
+	CategoryDB database.CategoryDB
+	ProductDB  database.ProductDB
+}
+
+func NewCategoryHandler(db database.CategoryDB, products database.ProductDB) *CategoryHandler {
+	return &CategoryHandler{
+		CategoryDB: db,
+		ProductDB:  products,
+	}
+}
+
+// findCategory accepts either a category ID or its slug.
+func findCategory(db database.CategoryDB, key string) (*entity.Category, error) {
+	if _, err := pkgentity.ParseID(key); err == nil {
+		return db.FindByID(key)
+	}
+	return db.FindBySlug(key)
+}
+
+// CreateCategory cria uma categoria, opcionalmente dentro de outra
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parentID, err := parseParentID(req.ParentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	category, err := entity.NewCategory(req.Name, req.Slug, parentID, req.Position)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.CategoryDB.Create(category); err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	h.writeCategory(w, category, http.StatusCreated)
+}
+
+// GetCategoryTree devolve todas as categorias aninhadas, na ordem de exibição
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.CategoryDB.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tree := entity.NewCategoryTree(categories)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toCategoryTreeNodes(tree, tree.Roots()))
+}
+
+// GetCategory busca uma categoria por ID ou slug
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := findCategory(h.CategoryDB, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeCategory(w, category, http.StatusOK)
+}
+
+// UpdateCategory renomeia, move ou reordena uma categoria
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := findCategory(h.CategoryDB, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	var req dto.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parentID, err := parseParentID(req.ParentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	category.Name = strings.TrimSpace(req.Name)
+	if strings.TrimSpace(req.Slug) != "" {
+		category.Slug = req.Slug
+	}
+	category.ParentID = parentID
+	category.Position = req.Position
+	if err := category.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.CategoryDB.Update(category); err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	h.writeCategory(w, category, http.StatusOK)
+}
+
+// DeleteCategory remove uma categoria sem subcategorias
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := findCategory(h.CategoryDB, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.CategoryDB.Delete(category.ID.String()); err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetProductCategories substitui as categorias de um produto
+func (h *CategoryHandler) SetProductCategories(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	var req dto.SetProductCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.CategoryDB.SetProductCategories(product.ID.String(), req.CategoryIDs); err != nil {
+		if errors.Is(err, database.ErrCategoryNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := []dto.ProductResponse{{ID: product.ID.String()}}
+	if err := addBreadcrumbs(h.CategoryDB, responses); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := dto.ProductCategoriesResponse{ProductID: product.ID.String(), Breadcrumbs: responses[0].Breadcrumbs}
+	if response.Breadcrumbs == nil {
+		response.Breadcrumbs = [][]dto.CategoryRef{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CategoryHandler) writeCategory(w http.ResponseWriter, category *entity.Category, status int) {
+	categories, err := h.CategoryDB.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(toCategoryResponse(category, entity.NewCategoryTree(categories)))
+}
+
+func parseParentID(raw *string) (*pkgentity.ID, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+	id, err := pkgentity.ParseID(*raw)
+	if err != nil {
+		return nil, errors.New("invalid parent_id")
+	}
+	return &id, nil
+}
+
+func writeCategoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, database.ErrSlugTaken), errors.Is(err, database.ErrCategoryHasChildren):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, database.ErrCategoryNotFound), errors.Is(err, database.ErrCategoryCycle),
+		errors.Is(err, entity.ErrCategoryOwnParent):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Category not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func toCategoryRefs(path []*entity.Category) []dto.CategoryRef {
+	refs := make([]dto.CategoryRef, 0, len(path))
+	for _, c := range path {
+		refs = append(refs, dto.CategoryRef{ID: c.ID.String(), Name: c.Name, Slug: c.Slug})
+	}
+	return refs
+}
+
+func toCategoryResponse(c *entity.Category, tree *entity.CategoryTree) dto.CategoryResponse {
+	response := dto.CategoryResponse{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		Slug:      c.Slug,
+		Position:  c.Position,
+		Path:      toCategoryRefs(tree.Path(c.ID)),
+		CreatedAt: c.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt: c.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if c.ParentID != nil {
+		parent := c.ParentID.String()
+		response.ParentID = &parent
+	}
+	return response
+}
+
+func toCategoryTreeNodes(tree *entity.CategoryTree, categories []*entity.Category) []dto.CategoryTreeNode {
+	nodes := make([]dto.CategoryTreeNode, 0, len(categories))
+	for _, c := range categories {
+		nodes = append(nodes, dto.CategoryTreeNode{
+			ID:       c.ID.String(),
+			Name:     c.Name,
+			Slug:     c.Slug,
+			Position: c.Position,
+			Children: toCategoryTreeNodes(tree, tree.Children(c.ID)),
+		})
+	}
+	return nodes
+}
+
+// addBreadcrumbs fills each response's Breadcrumbs, one root-to-leaf path
+// per assigned category, sorted by their slugs for a stable order.
+func addBreadcrumbs(db database.CategoryDB, responses []dto.ProductResponse) error {
+	ids := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		ids = append(ids, resp.ID)
+	}
+	byProduct, err := db.FindCategoryIDsByProducts(ids)
+	if err != nil || len(byProduct) == 0 {
+		return err
+	}
+
+	categories, err := db.FindAll()
+	if err != nil {
+		return err
+	}
+	tree := entity.NewCategoryTree(categories)
+
+	for i := range responses {
+		var paths [][]dto.CategoryRef
+		for _, categoryID := range byProduct[responses[i].ID] {
+			id, err := pkgentity.ParseID(categoryID)
+			if err != nil {
+				continue
+			}
+			if path := tree.Path(id); len(path) > 0 {
+				paths = append(paths, toCategoryRefs(path))
+			}
+		}
+		sort.Slice(paths, func(a, b int) bool { return breadcrumbKey(paths[a]) < breadcrumbKey(paths[b]) })
+		responses[i].Breadcrumbs = paths
+	}
+	return nil
+}
+
+func breadcrumbKey(path []dto.CategoryRef) string {
+	slugs := make([]string, 0, len(path))
+	for _, ref := range path {
+		slugs = append(slugs, ref.Slug)
+	}
+	return strings.Join(slugs, "/")
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+const (
+	PriceSourceList      = "list"
+	PriceSourceConverted = "converted"
+)
+
+// requestedCurrency reads ?currency= or, failing that, the Accept-Currency
+// header ("USD, EUR;q=0.8"). It returns "" when neither names a supported
+// currency; only an invalid ?currency= is an error.
+func requestedCurrency(r *http.Request) (string, error) {
+	if code := r.URL.Query().Get("currency"); code != "" {
+		currency, err := pkgentity.NormalizeCurrency(code)
+		if err != nil {
+			return "", err
+		}
+		return currency, nil
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Currency"), ",") {
+		code, params, _ := strings.Cut(part, ";")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if _, err := pkgentity.CurrencyExponent(code); err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = code, q
+		}
+	}
+	return best, nil
+}
+
+// priceLocalizer rewrites product responses into a requested currency,
+// preferring explicit price-list entries over exchange-rate conversion.
+type priceLocalizer struct {
+	Prices database.PriceListDB
+	Rates  database.ExchangeRateDB
+}
+
+func (l priceLocalizer) localize(responses []dto.ProductResponse, currency string) error {
+	if currency == "" {
+		return nil
+	}
+
+	var ids []string
+	for _, resp := range responses {
+		if resp.Price.Currency != currency {
+			ids = append(ids, resp.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	explicit, err := l.Prices.FindPricesIn(ids, currency)
+	if err != nil {
+		return err
+	}
+
+	var rates *entity.ExchangeRates
+	for i := range responses {
+		resp := &responses[i]
+		if resp.Price.Currency == currency {
+			continue
+		}
+		base := resp.Price
+		if price, ok := explicit[resp.ID]; ok {
+			resp.Price, resp.PriceSource = price, PriceSourceList
+		} else {
+			if rates == nil {
+				if rates, err = l.Rates.Load(); err != nil {
+					return err
+				}
+			}
+			if resp.Price, err = rates.Convert(base, currency); err != nil {
+				return err
+			}
+			resp.PriceSource = PriceSourceConverted
+		}
+		resp.BasePrice = &base
+	}
+	return nil
+}
+
+// writeCurrencyError answers 406 when a product cannot be priced in the
+// requested currency, 400 for a bad code and 500 otherwise.
+func writeCurrencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrRateNotFound):
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+	case errors.Is(err, pkgentity.ErrInvalidCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnknownCoupon   = errors.New("coupon not found")
+	errUnknownScopeIDs = errors.New("product_ids and category_ids must name existing products and categories")
+)
+
+// DiscountPricer prices cart and order lines with the active promotions and
+// a coupon, so a cart shows the same discounts its order gets.
+type DiscountPricer struct {
+	CouponDB    database.CouponDB
+	PromotionDB database.PromotionDB
+	CategoryDB  database.CategoryDB
+}
+
+func NewDiscountPricer(coupons database.CouponDB, promotions database.PromotionDB, categories database.CategoryDB) *DiscountPricer {
+	return &DiscountPricer{
+		CouponDB:    coupons,
+		PromotionDB: promotions,
+		CategoryDB:  categories,
+	}
+}
+
+// Price applies the active promotions and, unless code is empty, the
+// coupon with code to lines. The coupon's per-user limit is only checked
+// when userID is not nil.
+func (p *DiscountPricer) Price(lines []entity.DiscountLine, code string, userID *pkgentity.ID) (*entity.DiscountBreakdown, *entity.Coupon, error) {
+	now := time.Now()
+	productIDs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	categoryIDs, err := p.CategoryDB.FindCategoryIDsByProducts(productIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range lines {
+		lines[i].CategoryIDs = categoryIDs[lines[i].ProductID]
+	}
+
+	promotions, err := p.PromotionDB.FindActive(now)
+	if err != nil {
+		return nil, nil, err
+	}
+	descendants := map[string][]string{}
+	for _, promotion := range promotions {
+		if promotion.Scope.CategoryIDs, err = p.expandCategories(promotion.Scope.CategoryIDs, descendants); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var coupon *entity.Coupon
+	if code != "" {
+		if coupon, err = p.CouponDB.FindByCode(code); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errUnknownCoupon
+			}
+			return nil, nil, err
+		}
+		var uses int64
+		if userID != nil {
+			if uses, err = p.CouponDB.CountRedemptions(coupon.ID.String(), userID.String()); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := coupon.CheckUsage(uses); err != nil {
+			return nil, nil, err
+		}
+		if coupon.Scope.CategoryIDs, err = p.expandCategories(coupon.Scope.CategoryIDs, descendants); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	breakdown, err := entity.ApplyDiscounts(lines, promotions, coupon, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return breakdown, coupon, nil
+}
+
+// expandCategories adds the subcategories of ids, so a discount on a
+// category covers the products filed under its children too.
+func (p *DiscountPricer) expandCategories(ids []string, descendants map[string][]string) ([]string, error) {
+	expanded := slices.Clone(ids)
+	for _, id := range ids {
+		found, ok := descendants[id]
+		if !ok {
+			var err error
+			if found, err = p.CategoryDB.DescendantIDs(id); err != nil {
+				return nil, err
+			}
+			descendants[id] = found
+		}
+		for _, descendant := range found {
+			if !slices.Contains(expanded, descendant) {
+				expanded = append(expanded, descendant)
+			}
+		}
+	}
+	return expanded, nil
+}
+
+// couponErrorStatus maps the reasons a coupon is refused to a status code.
+func couponErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, errUnknownCoupon), errors.Is(err, entity.ErrCouponNotActive),
+		errors.Is(err, entity.ErrCouponMinTotal), errors.Is(err, entity.ErrCouponNotApplicable),
+		errors.Is(err, entity.ErrCouponCurrency):
+		return http.StatusBadRequest, true
+	case errors.Is(err, entity.ErrCouponUsedUp), errors.Is(err, entity.ErrCouponUserLimit):
+		return http.StatusConflict, true
+	}
+	return 0, false
+}
+
+type DiscountHandler struct {
+	CouponDB    database.CouponDB
+	PromotionDB database.PromotionDB
+	ProductDB   database.ProductDB
+	CategoryDB  database.CategoryDB
+}
+
+func NewDiscountHandler(coupons database.CouponDB, promotions database.PromotionDB, products database.ProductDB, categories database.CategoryDB) *DiscountHandler {
+	return &DiscountHandler{
+		CouponDB:    coupons,
+		PromotionDB: promotions,
+		ProductDB:   products,
+		CategoryDB:  categories,
+	}
+}
+
+// CreateCoupon cria um cupom de desconto percentual ou de valor fixo
+func (h *DiscountHandler) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var coupon *entity.Coupon
+	var err error
+	switch entity.DiscountType(req.Type) {
+	case entity.DiscountPercent:
+		coupon, err = entity.NewPercentCoupon(req.Code, req.PercentOff)
+	case entity.DiscountFixed:
+		if req.AmountOff == nil {
+			err = entity.ErrInvalidDiscount
+			break
+		}
+		coupon, err = entity.NewFixedCoupon(req.Code, *req.AmountOff)
+	default:
+		err = errors.New("type must be percent or fixed")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.MinTotal != nil {
+		coupon.MinTotal = *req.MinTotal
+	}
+	coupon.Scope = entity.DiscountScope{ProductIDs: req.ProductIDs, CategoryIDs: req.CategoryIDs}
+	coupon.Window = entity.ValidityWindow{StartsAt: req.StartsAt, EndsAt: req.EndsAt}
+	coupon.MaxUses, coupon.MaxUsesPerUser = req.MaxUses, req.MaxUsesPerUser
+	if err := coupon.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkScope(w, coupon.Scope) {
+		return
+	}
+
+	if _, err := h.CouponDB.FindByCode(coupon.Code); err == nil {
+		http.Error(w, "coupon code already exists", http.StatusConflict)
+		return
+	}
+	if err := h.CouponDB.Create(coupon); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toCouponResponse(coupon))
+}
+
+// GetCoupons lista os cupons, do mais recente
+func (h *DiscountHandler) GetCoupons(w http.ResponseWriter, r *http.Request) {
+	page, limit := 1, defaultPageLimit
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxPageLimit)
+	}
+
+	coupons, err := h.CouponDB.FindAll(page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.CouponResponse, 0, len(coupons))
+	for _, coupon := range coupons {
+		response = append(response, toCouponResponse(coupon))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetCoupon busca um cupom pelo código, com quantas vezes já foi usado
+func (h *DiscountHandler) GetCoupon(w http.ResponseWriter, r *http.Request) {
+	coupon, err := h.CouponDB.FindByCode(chi.URLParam(r, "code"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Coupon not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toCouponResponse(coupon))
+}
+
+// DeleteCoupon remove um cupom; pedidos que o usaram continuam com o desconto
+func (h *DiscountHandler) DeleteCoupon(w http.ResponseWriter, r *http.Request) {
+	if err := h.CouponDB.Delete(chi.URLParam(r, "code")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Coupon not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreatePromotion cria uma promoção automática "leve X, ganhe Y"
+func (h *DiscountHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := entity.NewBuyXGetY(req.Name, req.BuyQuantity, req.GetQuantity, req.PercentOff)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	promotion.Scope = entity.DiscountScope{ProductIDs: req.ProductIDs, CategoryIDs: req.CategoryIDs}
+	promotion.Window = entity.ValidityWindow{StartsAt: req.StartsAt, EndsAt: req.EndsAt}
+	if err := promotion.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkScope(w, promotion.Scope) {
+		return
+	}
+	if err := h.PromotionDB.Create(promotion); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toPromotionResponse(promotion))
+}
+
+// GetPromotions lista as promoções na ordem em que são aplicadas
+func (h *DiscountHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.PromotionDB.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		response = append(response, toPromotionResponse(promotion))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeletePromotion encerra uma promoção
+func (h *DiscountHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	if err := h.PromotionDB.Delete(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Promotion not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkScope answers 400 unless every product and category in scope exists.
+func (h *DiscountHandler) checkScope(w http.ResponseWriter, scope entity.DiscountScope) bool {
+	for _, id := range scope.ProductIDs {
+		if _, err := h.ProductDB.FindByID(id); err != nil {
+			http.Error(w, errUnknownScopeIDs.Error(), http.StatusBadRequest)
+			return false
+		}
+	}
+	for _, id := range scope.CategoryIDs {
+		if _, err := h.CategoryDB.FindByID(id); err != nil {
+			http.Error(w, errUnknownScopeIDs.Error(), http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+func toCouponResponse(coupon *entity.Coupon) dto.CouponResponse {
+	response := dto.CouponResponse{
+		ID:             coupon.ID.String(),
+		Code:           coupon.Code,
+		Type:           string(coupon.Type),
+		PercentOff:     coupon.PercentOff,
+		ProductIDs:     nonNil(coupon.Scope.ProductIDs),
+		CategoryIDs:    nonNil(coupon.Scope.CategoryIDs),
+		MaxUses:        coupon.MaxUses,
+		MaxUsesPerUser: coupon.MaxUsesPerUser,
+		Uses:           coupon.Uses,
+		StartsAt:       formatOptionalTime(coupon.Window.StartsAt),
+		EndsAt:         formatOptionalTime(coupon.Window.EndsAt),
+		CreatedAt:      coupon.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if coupon.Type == entity.DiscountFixed {
+		amountOff := coupon.AmountOff
+		response.AmountOff = &amountOff
+	}
+	if coupon.MinTotal.Amount > 0 {
+		minTotal := coupon.MinTotal
+		response.MinTotal = &minTotal
+	}
+	return response
+}
+
+func toPromotionResponse(promotion *entity.Promotion) dto.PromotionResponse {
+	return dto.PromotionResponse{
+		ID:          promotion.ID.String(),
+		Name:        promotion.Name,
+		BuyQuantity: promotion.BuyQuantity,
+		GetQuantity: promotion.GetQuantity,
+		PercentOff:  promotion.PercentOff,
+		ProductIDs:  nonNil(promotion.Scope.ProductIDs),
+		CategoryIDs: nonNil(promotion.Scope.CategoryIDs),
+		StartsAt:    formatOptionalTime(promotion.Window.StartsAt),
+		EndsAt:      formatOptionalTime(promotion.Window.EndsAt),
+		CreatedAt:   promotion.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+func toAppliedDiscountResponses(discounts []entity.AppliedDiscount) []dto.AppliedDiscountResponse {
+	response := make([]dto.AppliedDiscountResponse, 0, len(discounts))
+	for _, discount := range discounts {
+		response = append(response, dto.AppliedDiscountResponse{
+			Kind:     string(discount.Kind),
+			SourceID: discount.SourceID,
+			Source:   discount.Source,
+			Amount:   discount.Amount,
+		})
+	}
+	return response
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+)
+
+// etag is the entity tag of a resource at version: the version quoted,
+// e.g. "3".
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch reports whether the request's If-Match header, if any, names
+// version. A missing header or "*" matches. If-Match uses the strong
+// comparison, so weak W/ tags never match.
+func ifMatch(r *http.Request, version int64) bool {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return true
+	}
+	want := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch answers 412 when the request's If-Match does not name
+// version, the resource's current one.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, version int64) bool {
+	if !ifMatch(r, version) {
+		w.Header().Set("ETag", etag(version))
+		http.Error(w, "Resource was modified; fetch it again and retry", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// writeUpdateError answers 412 when a write lost the race against another
+// one made since the resource was read.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, database.ErrVersionConflict) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// RequireIfMatch rejects requests without an If-Match header with 428, so
+// clients cannot overwrite changes they have not seen.
+func RequireIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") == "" {
+			http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultReservationTTL applies when neither the request nor the
+	// server configuration sets one.
+	DefaultReservationTTL = 15 * time.Minute
+	// maxReservationTTL and maxReservationQuantity keep one shopper from
+	// holding a SKU's stock for long.
+	maxReservationTTL      = time.Hour
+	maxReservationQuantity = entity.MaxReservedPerUser
+	maxMovements           = 500
+)
+
+type InventoryHandler struct {
+	ProductDB   database.ProductDB
+	VariantDB   database.VariantDB
+	InventoryDB database.InventoryDB
+	// ReservationTTL is how long reservations last unless the request asks
+	// for another duration.
+	ReservationTTL time.Duration
+}
+
+func NewInventoryHandler(products database.ProductDB, variants database.VariantDB, inventory database.InventoryDB, reservationTTL time.Duration) *InventoryHandler {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
+	return &InventoryHandler{
+		ProductDB:      products,
+		VariantDB:      variants,
+		InventoryDB:    inventory,
+		ReservationTTL: reservationTTL,
+	}
+}
+
+// CreateWarehouse cadastra um depósito
+func (h *InventoryHandler) CreateWarehouse(w http.ResponseWriter, r *http.Request) {
+	var req dto.WarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := entity.NewWarehouse(req.Code, req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.InventoryDB.CreateWarehouse(warehouse); err != nil {
+		if errors.Is(err, database.ErrWarehouseCodeTaken) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWarehouseResponse(warehouse))
+}
+
+// GetWarehouses lista os depósitos
+func (h *InventoryHandler) GetWarehouses(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.InventoryDB.FindWarehouses()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.WarehouseResponse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		response = append(response, toWarehouseResponse(warehouse))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetStock mostra o estoque de um SKU por depósito
+func (h *InventoryHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	_, variant, err := findItem(r, h.ProductDB, h.VariantDB, chi.URLParam(r, "sku"), "", "")
+	if err != nil {
+		http.Error(w, "SKU not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeStock(w, variant)
+}
+
+// AdjustStock soma ou subtrai unidades do estoque de um SKU em um depósito
+func (h *InventoryHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	variant, err := h.VariantDB.FindBySKU(chi.URLParam(r, "sku"))
+	if err != nil {
+		http.Error(w, "SKU not found", http.StatusNotFound)
+		return
+	}
+
+	var req dto.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Delta == 0 {
+		http.Error(w, "delta cannot be zero", http.StatusBadRequest)
+		return
+	}
+	warehouse, err := h.InventoryDB.FindWarehouseByCode(req.Warehouse)
+	if err != nil {
+		http.Error(w, "warehouse not found", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.InventoryDB.Adjust(variant.ID, warehouse.ID, req.Delta, req.Reason); err != nil {
+		writeInventoryError(w, err)
+		return
+	}
+
+	h.writeStock(w, variant)
+}
+
+// GetStockMovements lista as movimentações de estoque de um SKU, da mais recente
+func (h *InventoryHandler) GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	variant, err := h.VariantDB.FindBySKU(chi.URLParam(r, "sku"))
+	if err != nil {
+		http.Error(w, "SKU not found", http.StatusNotFound)
+		return
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxMovements)
+	}
+	movements, err := h.InventoryDB.Movements(variant.ID.String(), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	codes, err := h.warehouseCodes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.StockMovementResponse, 0, len(movements))
+	for _, m := range movements {
+		movement := dto.StockMovementResponse{
+			ID:            m.ID.String(),
+			Warehouse:     codes[m.WarehouseID],
+			Kind:          string(m.Kind),
+			OnHandDelta:   m.OnHandDelta,
+			ReservedDelta: m.ReservedDelta,
+			OnHand:        m.OnHand,
+			Reserved:      m.Reserved,
+			Reason:        m.Reason,
+			CreatedAt:     m.CreatedAt.Format(time.RFC3339Nano),
+		}
+		if m.ReservationID != nil {
+			movement.ReservationID = m.ReservationID.String()
+		}
+		response = append(response, movement)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateReservation reserva unidades de um SKU por um tempo limitado em
+// nome do usuário autenticado
+func (h *InventoryHandler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Quantity <= 0 {
+		http.Error(w, entity.ErrQuantityMustBePositive.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Quantity > maxReservationQuantity {
+		http.Error(w, fmt.Sprintf("quantity cannot exceed %d", maxReservationQuantity), http.StatusBadRequest)
+		return
+	}
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ttl := h.ReservationTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+		if ttl <= 0 || ttl > maxReservationTTL {
+			http.Error(w, fmt.Sprintf("ttl_seconds must be between 1 and %d", int64(maxReservationTTL/time.Second)), http.StatusBadRequest)
+			return
+		}
+	}
+
+	_, variant, err := findItem(r, h.ProductDB, h.VariantDB, req.SKU, "", "")
+	if err != nil {
+		http.Error(w, "SKU not found", http.StatusBadRequest)
+		return
+	}
+	var warehouseID *pkgentity.ID
+	if req.Warehouse != "" {
+		warehouse, err := h.InventoryDB.FindWarehouseByCode(req.Warehouse)
+		if err != nil {
+			http.Error(w, "warehouse not found", http.StatusBadRequest)
+			return
+		}
+		warehouseID = &warehouse.ID
+	}
+
+	reservation, err := h.InventoryDB.Reserve(&userID, variant.ID, warehouseID, req.Quantity, ttl, req.Reference)
+	if err != nil {
+		writeInventoryError(w, err)
+		return
+	}
+
+	h.writeReservation(w, reservation, http.StatusCreated)
+}
+
+// GetReservation busca uma reserva do usuário; admins veem todas
+func (h *InventoryHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := h.findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeReservation(w, reservation, http.StatusOK)
+}
+
+// CommitReservation confirma a reserva e baixa as unidades do estoque (admin)
+func (h *InventoryHandler) CommitReservation(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.InventoryDB.Commit(chi.URLParam(r, "id"))
+	if err != nil {
+		writeInventoryError(w, err)
+		return
+	}
+
+	h.writeReservation(w, reservation, http.StatusOK)
+}
+
+// ReleaseReservation cancela a reserva e devolve as unidades ao estoque
+// disponível; só o dono da reserva ou um admin
+func (h *InventoryHandler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.findReservation(w, r); !ok {
+		return
+	}
+	reservation, err := h.InventoryDB.Release(chi.URLParam(r, "id"))
+	if err != nil {
+		writeInventoryError(w, err)
+		return
+	}
+
+	h.writeReservation(w, reservation, http.StatusOK)
+}
+
+// findReservation loads the reservation in the URL, answering 404 unless
+// the caller made it or is an admin.
+func (h *InventoryHandler) findReservation(w http.ResponseWriter, r *http.Request) (*entity.StockReservation, bool) {
+	reservation, err := h.InventoryDB.FindReservation(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Reservation not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	userID, _ := userIDFromContext(r)
+	owner := reservation.UserID != nil && *reservation.UserID == userID
+	if !owner && roleFromContext(r) != entity.RoleAdmin {
+		http.Error(w, "Reservation not found", http.StatusNotFound)
+		return nil, false
+	}
+	return reservation, true
+}
+
+func (h *InventoryHandler) writeStock(w http.ResponseWriter, variant *entity.ProductVariant) {
+	levels, err := h.InventoryDB.StockLevels(variant.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	codes, err := h.warehouseCodes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.StockResponse{
+		SKU:        variant.SKU,
+		VariantID:  variant.ID.String(),
+		Warehouses: make([]dto.WarehouseStock, 0, len(levels)),
+	}
+	for _, level := range levels {
+		response.OnHand += level.OnHand
+		response.Reserved += level.Reserved
+		response.Warehouses = append(response.Warehouses, dto.WarehouseStock{
+			Warehouse: codes[level.WarehouseID],
+			OnHand:    level.OnHand,
+			Reserved:  level.Reserved,
+			Available: level.Available(),
+		})
+	}
+	response.Available = response.OnHand - response.Reserved
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *InventoryHandler) writeReservation(w http.ResponseWriter, reservation *entity.StockReservation, status int) {
+	codes, err := h.warehouseCodes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := dto.ReservationResponse{
+		ID:        reservation.ID.String(),
+		Warehouse: codes[reservation.WarehouseID],
+		Quantity:  reservation.Quantity,
+		Status:    string(reservation.Status),
+		ExpiresAt: reservation.ExpiresAt.Format(time.RFC3339Nano),
+		Reference: reservation.Reference,
+		CreatedAt: reservation.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if variant, err := h.VariantDB.FindVariantByID(reservation.VariantID.String()); err == nil {
+		response.SKU = variant.SKU
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *InventoryHandler) warehouseCodes() (map[pkgentity.ID]string, error) {
+	warehouses, err := h.InventoryDB.FindWarehouses()
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[pkgentity.ID]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		codes[warehouse.ID] = warehouse.Code
+	}
+	return codes, nil
+}
+
+func writeInventoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, database.ErrInsufficientStock), errors.Is(err, database.ErrReservationNotActive),
+		errors.Is(err, database.ErrReservationLimit):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, database.ErrReservationExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Reservation not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func toWarehouseResponse(warehouse *entity.Warehouse) dto.WarehouseResponse {
+	return dto.WarehouseResponse{ID: warehouse.ID.String(), Code: warehouse.Code, Name: warehouse.Name}
+}
@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/internal/infra/shipping"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	errItemUnavailable = errors.New("item no longer available")
+	errUnknownAddress  = errors.New("address not found")
+	errPaymentOpen     = errors.New("order has an open payment")
+)
+
+type OrderHandler struct {
+	OrderDB   database.OrderDB
+	CartDB    database.CartDB
+	ProductDB database.ProductDB
+	VariantDB database.VariantDB
+	Pricer    *DiscountPricer
+	Tax       *TaxCalculator
+	Shipping  shipping.ShippingRateProvider
+	AddressDB database.AddressDB
+	PaymentDB database.PaymentDB
+}
+
+func NewOrderHandler(orders database.OrderDB, carts database.CartDB, products database.ProductDB, variants database.VariantDB, pricer *DiscountPricer, tax *TaxCalculator, rates shipping.ShippingRateProvider, addresses database.AddressDB, payments database.PaymentDB) *OrderHandler {
+	return &OrderHandler{
+		OrderDB:   orders,
+		CartDB:    carts,
+		ProductDB: products,
+		VariantDB: variants,
+		Pricer:    pricer,
+		Tax:       tax,
+		Shipping:  rates,
+		AddressDB: addresses,
+		PaymentDB: payments,
+	}
+}
+
+// CreateOrder cria um pedido com os itens enviados ou, sem itens, com o
+// carrinho do usuário, que fica vazio. Guarda uma cópia dos endereços de
+// entrega e cobrança e aplica promoções, o cupom, o frete escolhido e o imposto
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	co, err := h.checkout(userID, req)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	if len(req.Items) > 0 {
+		items := make([]*entity.OrderItem, 0, len(req.Items))
+		for _, line := range req.Items {
+			product, variant, err := findItem(r, h.ProductDB, h.VariantDB, line.SKU, line.ProductID, line.VariantID)
+			if err != nil {
+				writeOrderError(w, err)
+				return
+			}
+			item, err := entity.NewOrderItem(product, variant, line.Quantity)
+			if err != nil {
+				writeOrderError(w, err)
+				return
+			}
+			items = append(items, item)
+		}
+		order, err := entity.NewOrder(userID, items)
+		if err != nil {
+			writeOrderError(w, err)
+			return
+		}
+		if err := h.price(r.Context(), order, req.CouponCode, co); err != nil {
+			writeOrderError(w, err)
+			return
+		}
+		if err := h.OrderDB.Create(order); err != nil {
+			writeOrderError(w, err)
+			return
+		}
+		writeOrder(w, order, http.StatusCreated)
+		return
+	}
+
+	cart, err := h.CartDB.FindOrCreateUserCart(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	items, err := h.orderItemsFromCart(r, cart)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	order, err := entity.NewOrder(userID, items)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	code := req.CouponCode
+	if code == "" {
+		code = cart.CouponCode
+	}
+	if err := h.price(r.Context(), order, code, co); err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	if err := h.OrderDB.CreateFromCart(order, cart); err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	writeOrder(w, order, http.StatusCreated)
+}
+
+// GetOrders lista os pedidos do usuário, do mais recente; admins veem os de
+// todos com ?all=true
+func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	values := r.URL.Query()
+
+	filter := database.OrderFilter{UserID: userID.String()}
+	if all, _ := strconv.ParseBool(values.Get("all")); all {
+		if roleFromContext(r) != entity.RoleAdmin {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		filter.UserID = values.Get("user_id")
+	}
+	if status := values.Get("status"); status != "" {
+		var err error
+		if filter.Status, err = entity.ParseOrderStatus(status); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	page, limit := 1, defaultPageLimit
+	if p, err := strconv.Atoi(values.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(values.Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxPageLimit)
+	}
+
+	orders, err := h.OrderDB.FindAll(filter, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		response = append(response, toOrderResponse(order))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetOrder busca um pedido do usuário (admins veem qualquer pedido)
+func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	order, ok := findOrder(h.OrderDB, w, r)
+	if !ok {
+		return
+	}
+
+	writeOrder(w, order, http.StatusOK)
+}
+
+// TransitionOrder muda o status de um pedido, se a máquina de estados permitir
+func (h *OrderHandler) TransitionOrder(w http.ResponseWriter, r *http.Request) {
+	var req dto.OrderTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := entity.ParseOrderStatus(req.Status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	order, ok := findOrder(h.OrderDB, w, r)
+	if !ok {
+		return
+	}
+
+	var actorID *pkgentity.ID
+	if id, ok := userIDFromContext(r); ok {
+		actorID = &id
+	}
+	transition, err := order.Transition(status, actorID, req.Note)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	if err := h.checkPayments(order, status); err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	if err := h.OrderDB.SaveTransition(order, transition); err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	writeOrder(w, order, http.StatusOK)
+}
+
+// checkPayments refuses to cancel an order whose payment is authorized, or
+// to mark refunded one whose payment is still captured: the money would
+// stay with the gateway while the order says otherwise. Money is given back
+// through /payments/{id}/refund, which moves the order itself.
+func (h *OrderHandler) checkPayments(order *entity.Order, status entity.OrderStatus) error {
+	if status != entity.OrderCancelled && status != entity.OrderRefunded {
+		return nil
+	}
+	intents, err := h.PaymentDB.FindByOrder(order.ID.String())
+	if err != nil {
+		return err
+	}
+	for _, intent := range intents {
+		switch {
+		case status == entity.OrderCancelled && intent.Status == entity.PaymentAuthorized:
+			return fmt.Errorf("%w: payment %s is authorized; capture it and refund it through /payments/%s/refund",
+				errPaymentOpen, intent.ID, intent.ID)
+		case status == entity.OrderRefunded && intent.Status == entity.PaymentCaptured:
+			return fmt.Errorf("%w: payment %s is captured; refund it through /payments/%s/refund",
+				errPaymentOpen, intent.ID, intent.ID)
+		}
+	}
+	return nil
+}
+
+// findOrder loads the order in the URL, answering 404 to users asking for
+// someone else's.
+func findOrder(orders database.OrderDB, w http.ResponseWriter, r *http.Request) (*entity.Order, bool) {
+	order, err := orders.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if userID, _ := userIDFromContext(r); order.UserID != userID && roleFromContext(r) != entity.RoleAdmin {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return nil, false
+	}
+	return order, true
+}
+
+// checkout is where an order goes and how it is shipped, worked out from
+// the request and the user's address book before the order is priced.
+type checkout struct {
+	loc             entity.TaxLocation
+	dest            *entity.ShippingDestination
+	methodID        string
+	shippingAddress *entity.PostalAddress
+	billingAddress  *entity.PostalAddress
+}
+
+// checkout resolves the addresses req names, or the user's defaults, and
+// the tax location and shipping destination. Country in req wins over the
+// shipping address.
+func (h *OrderHandler) checkout(userID pkgentity.ID, req dto.CreateOrderRequest) (*checkout, error) {
+	defaultShipping, defaultBilling, err := h.AddressDB.FindDefaults(userID.String())
+	if err != nil {
+		return nil, err
+	}
+	co := &checkout{methodID: req.ShippingMethodID}
+	for _, pick := range []struct {
+		id       string
+		fallback *entity.Address
+		snapshot **entity.PostalAddress
+	}{
+		{req.ShippingAddressID, defaultShipping, &co.shippingAddress},
+		{req.BillingAddressID, defaultBilling, &co.billingAddress},
+	} {
+		address := pick.fallback
+		if pick.id != "" {
+			if address, err = h.AddressDB.FindByID(userID.String(), pick.id); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, fmt.Errorf("%w: %s", errUnknownAddress, pick.id)
+				}
+				return nil, err
+			}
+		}
+		if address != nil {
+			*pick.snapshot = address.Snapshot()
+		}
+	}
+
+	country, region, postalCode := req.Country, req.Region, req.PostalCode
+	if country == "" && co.shippingAddress != nil {
+		country, region, postalCode = co.shippingAddress.Country, co.shippingAddress.Region, co.shippingAddress.PostalCode
+	}
+	if co.loc, err = h.Tax.Location(country, region); err != nil {
+		return nil, err
+	}
+	if co.methodID != "" {
+		dest, err := entity.NewShippingDestination(country, region, postalCode)
+		if err != nil {
+			return nil, err
+		}
+		co.dest = &dest
+	}
+	return co, nil
+}
+
+// price copies the addresses onto order, applies the active promotions and
+// the coupon with code, if any, charges the chosen shipping method and then
+// taxes the goods.
+func (h *OrderHandler) price(ctx context.Context, order *entity.Order, code string, co *checkout) error {
+	order.ShippingAddress, order.BillingAddress = co.shippingAddress, co.billingAddress
+	breakdown, coupon, err := h.Pricer.Price(order.DiscountLines(), code, &order.UserID)
+	if err != nil {
+		return err
+	}
+	order.ApplyDiscounts(breakdown, coupon)
+
+	if co.dest != nil {
+		rate, err := shipping.Find(ctx, h.Shipping, shipping.RateRequest{
+			Destination: *co.dest,
+			Items:       order.ShippingItems(),
+			Subtotal:    order.GoodsTotal(),
+		}, co.methodID)
+		if err != nil {
+			return err
+		}
+		order.ApplyShipping(rate)
+	}
+
+	tax, err := h.Tax.Calculate(order.TaxLines(), co.loc)
+	if err != nil {
+		return err
+	}
+	order.ApplyTax(tax)
+	return nil
+}
+
+// orderItemsFromCart prices the cart's lines again at the catalog's current
+// prices, since the cart only holds the prices from when they were added.
+func (h *OrderHandler) orderItemsFromCart(r *http.Request, cart *entity.Cart) ([]*entity.OrderItem, error) {
+	items := make([]*entity.OrderItem, 0, len(cart.Items))
+	for _, line := range cart.Items {
+		product, err := h.ProductDB.FindByID(line.ProductID.String())
+		if err != nil || !visibleTo(r, product) {
+			return nil, fmt.Errorf("%w: %s", errItemUnavailable, line.Name)
+		}
+		var variant *entity.ProductVariant
+		if line.VariantID != nil {
+			if variant, err = h.VariantDB.FindVariantByID(line.VariantID.String()); err != nil {
+				return nil, fmt.Errorf("%w: %s", errItemUnavailable, line.Name)
+			}
+		}
+		item, err := entity.NewOrderItem(product, variant, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func writeOrderError(w http.ResponseWriter, err error) {
+	if status, ok := couponErrorStatus(err); ok {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	switch {
+	case errors.Is(err, errUnknownItem), errors.Is(err, entity.ErrVariantRequired),
+		errors.Is(err, entity.ErrQuantityLimit), errors.Is(err, entity.ErrEmptyOrder),
+		errors.Is(err, entity.ErrCartFull), errors.Is(err, entity.ErrInvalidOrderStatus),
+		errors.Is(err, errUnknownAddress), errors.Is(err, entity.ErrDestinationRequired),
+		isTaxLocationError(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrOrderCurrency), errors.Is(err, errItemUnavailable),
+		errors.Is(err, entity.ErrInvalidTransition), errors.Is(err, database.ErrOrderStatusChanged),
+		errors.Is(err, entity.ErrShippingUnavailable), errors.Is(err, database.ErrInsufficientStock),
+		errors.Is(err, errPaymentOpen):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, database.ErrVersionConflict):
+		http.Error(w, "Cart was changed by another request; retry", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeOrder(w http.ResponseWriter, order *entity.Order, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(toOrderResponse(order))
+}
+
+func toOrderResponse(order *entity.Order) dto.OrderResponse {
+	response := dto.OrderResponse{
+		ID:               order.ID.String(),
+		UserID:           order.UserID.String(),
+		Status:           string(order.Status),
+		NextStatuses:     []string{},
+		Items:            make([]dto.OrderItemResponse, 0, len(order.Items)),
+		CouponCode:       order.CouponCode,
+		Subtotal:         order.Subtotal,
+		Discounts:        toAppliedDiscountResponses(order.Discounts),
+		Discount:         order.Discount,
+		TaxCountry:       order.TaxCountry,
+		TaxRegion:        order.TaxRegion,
+		PricesIncludeTax: order.PricesIncludeTax,
+		Tax:              order.Tax,
+		ShippingMethod:   order.ShippingMethod,
+		Shipping:         order.Shipping,
+		Total:            order.Total,
+		CreatedAt:        order.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:        order.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if order.ShippingMethodID != nil {
+		response.ShippingMethodID = order.ShippingMethodID.String()
+	}
+	if order.ShippingAddress != nil {
+		address := toPostalAddressResponse(*order.ShippingAddress)
+		response.ShippingAddress = &address
+	}
+	if order.BillingAddress != nil {
+		address := toPostalAddressResponse(*order.BillingAddress)
+		response.BillingAddress = &address
+	}
+	for _, next := range order.Status.NextStatuses() {
+		response.NextStatuses = append(response.NextStatuses, string(next))
+	}
+	for _, item := range order.Items {
+		line := dto.OrderItemResponse{
+			ID:        item.ID.String(),
+			ProductID: item.ProductID.String(),
+			SKU:       item.SKU,
+			Name:      item.Name,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			Subtotal:  item.Subtotal(),
+			Discounts: toAppliedDiscountResponses(item.Discounts),
+			Discount:  item.Discount,
+			TaxClass:  item.TaxClass,
+			TaxRate:   item.TaxRate,
+			Tax:       item.Tax,
+			Total:     item.Total(),
+		}
+		if item.VariantID != nil {
+			line.VariantID = item.VariantID.String()
+		}
+		response.Items = append(response.Items, line)
+	}
+	for _, t := range order.Transitions {
+		transition := dto.OrderTransitionResponse{
+			From:      string(t.From),
+			To:        string(t.To),
+			Note:      t.Note,
+			CreatedAt: t.CreatedAt.Format(time.RFC3339Nano),
+		}
+		if t.ActorID != nil {
+			transition.ActorID = t.ActorID.String()
+		}
+		response.Transitions = append(response.Transitions, transition)
+	}
+	return response
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// linkHeader builds an RFC 8288 Link header value. Each relation maps to
+// the query parameters to set on top of the current request's query.
+type linkHeader struct {
+	r     *http.Request
+	links []string
+}
+
+func newLinkHeader(r *http.Request) *linkHeader {
+	return &linkHeader{r: r}
+}
+
+func (l *linkHeader) add(rel string, params map[string]string) {
+	q := url.Values{}
+	for k, v := range l.r.URL.Query() {
+		q[k] = v
+	}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u := url.URL{Path: l.r.URL.Path, RawQuery: q.Encode()}
+	l.links = append(l.links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+}
+
+func (l *linkHeader) write(w http.ResponseWriter) {
+	if len(l.links) > 0 {
+		w.Header().Set("Link", strings.Join(l.links, ", "))
+	}
+}
+
+func wantsTotal(r *http.Request) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get("include_total"))
+	return v
+}
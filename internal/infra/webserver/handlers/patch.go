@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/patch"
+)
+
+// acceptPatch lists the patch formats PATCH endpoints take.
+const acceptPatch = patch.MergePatchType + ", " + patch.JSONPatchType
+
+// decodePatched applies the request body, a JSON Merge Patch or JSON Patch
+// as its Content-Type says, to doc and decodes the result into out. It
+// answers 415, 409 or 400 itself and returns false when that fails.
+func decodePatched(w http.ResponseWriter, r *http.Request, doc, out any) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	current, err := json.Marshal(doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+
+	patched, err := patch.Apply(mediaType, current, body)
+	switch {
+	case errors.Is(err, patch.ErrUnsupportedMediaType):
+		w.Header().Set("Accept-Patch", acceptPatch)
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return false
+	case errors.Is(err, patch.ErrTestFailed):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return false
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/internal/infra/payment"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// maxWebhookBytes bounds the webhook bodies read before verification.
+const maxWebhookBytes = 1 << 20
+
+type PaymentHandler struct {
+	PaymentDB database.PaymentDB
+	OrderDB   database.OrderDB
+	Provider  payment.PaymentProvider
+}
+
+func NewPaymentHandler(payments database.PaymentDB, orders database.OrderDB, provider payment.PaymentProvider) *PaymentHandler {
+	return &PaymentHandler{
+		PaymentDB: payments,
+		OrderDB:   orders,
+		Provider:  provider,
+	}
+}
+
+// CreatePayment autoriza o pagamento de um pedido pendente e, salvo
+// manual_capture, já o captura e marca o pedido como pago
+func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreatePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.PaymentMethod == "" {
+		http.Error(w, "payment_method is required", http.StatusBadRequest)
+		return
+	}
+	order, ok := findOrder(h.OrderDB, w, r)
+	if !ok {
+		return
+	}
+
+	intent, err := entity.NewPaymentIntent(order, h.Provider.Name())
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	if err := h.PaymentDB.Create(intent); err != nil {
+		if errors.Is(err, database.ErrPaymentInProgress) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	auth, err := h.Provider.Authorize(r.Context(), payment.AuthorizeRequest{
+		Amount:         intent.Amount,
+		PaymentMethod:  req.PaymentMethod,
+		IdempotencyKey: intent.ID.String(),
+		Description:    "order " + order.ID.String(),
+	})
+	if err != nil {
+		reference, code := "", "gateway_error"
+		if errors.Is(err, payment.ErrDeclined) && auth != nil {
+			reference, code = auth.Reference, auth.DeclineCode
+		}
+		intent.Fail(reference, code)
+		if err := h.PaymentDB.Update(intent, order, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writePaymentError(w, err)
+		return
+	}
+	intent.Authorized(auth.Reference)
+	if err := h.PaymentDB.Update(intent, order, nil); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// A failed capture leaves the payment authorized for an admin to
+	// capture later.
+	if !req.ManualCapture {
+		transition, err := h.capture(r, intent, order)
+		if err != nil {
+			writePaymentError(w, err)
+			return
+		}
+		if err := h.PaymentDB.Update(intent, order, transition); err != nil {
+			writePaymentError(w, err)
+			return
+		}
+	}
+
+	writePayment(w, intent, http.StatusCreated)
+}
+
+// GetOrderPayments lista as tentativas de pagamento de um pedido
+func (h *PaymentHandler) GetOrderPayments(w http.ResponseWriter, r *http.Request) {
+	order, ok := findOrder(h.OrderDB, w, r)
+	if !ok {
+		return
+	}
+	intents, err := h.PaymentDB.FindByOrder(order.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.PaymentResponse, 0, len(intents))
+	for _, intent := range intents {
+		response = append(response, toPaymentResponse(intent))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CapturePayment captura um pagamento autorizado e marca o pedido como pago
+func (h *PaymentHandler) CapturePayment(w http.ResponseWriter, r *http.Request) {
+	intent, order, ok := h.findPayment(w, r)
+	if !ok {
+		return
+	}
+
+	transition, err := h.capture(r, intent, order)
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	if err := h.PaymentDB.Update(intent, order, transition); err != nil {
+		writePaymentError(w, err)
+		return
+	}
+
+	writePayment(w, intent, http.StatusOK)
+}
+
+// RefundPayment devolve todo o valor capturado ou parte dele; o pedido passa
+// a reembolsado quando tudo foi devolvido
+func (h *PaymentHandler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefundPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	intent, order, ok := h.findPayment(w, r)
+	if !ok {
+		return
+	}
+
+	amount := intent.Amount
+	amount.Amount -= intent.Refunded
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if err := intent.Refund(amount); err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	if err := h.Provider.Refund(r.Context(), intent.Reference, amount); err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	if err := h.PaymentDB.Update(intent, order, refundTransition(intent, order)); err != nil {
+		writePaymentError(w, err)
+		return
+	}
+
+	writePayment(w, intent, http.StatusOK)
+}
+
+// PaymentWebhook recebe as notificações do gateway de pagamento, verificando
+// a assinatura; notificações repetidas não mudam nada e capturas de pedidos
+// que já não estão pendentes são estornadas
+func (h *PaymentHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	event, err := h.Provider.VerifyWebhook(payload, r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	intent, err := h.PaymentDB.FindByReference(h.Provider.Name(), event.Reference)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Payment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	order, err := h.OrderDB.FindByID(intent.OrderID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var transition *entity.OrderTransition
+	switch event.Type {
+	case payment.EventCaptured:
+		if intent.Status != entity.PaymentAuthorized {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		intent.Capture()
+		if order.Status != entity.OrderPending {
+			h.refundLateCapture(r, intent, order)
+			break
+		}
+		transition = paidTransition(intent, order)
+	case payment.EventFailed:
+		if intent.Status != entity.PaymentPending && intent.Status != entity.PaymentAuthorized {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		intent.Fail("", "failed")
+	case payment.EventRefunded:
+		// Refunds made through the API are already recorded.
+		if event.Amount.Currency != intent.Amount.Currency || event.Amount.Amount <= intent.Refunded {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		missing := intent.RefundedMoney()
+		missing.Amount = event.Amount.Amount - intent.Refunded
+		if err := intent.Refund(missing); err != nil {
+			writePaymentError(w, err)
+			return
+		}
+		transition = refundTransition(intent, order)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.PaymentDB.Update(intent, order, transition); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// refundLateCapture gives back a capture the gateway reported after the
+// order stopped waiting for payment, as when it was cancelled meanwhile.
+// If the refund fails the payment stays captured, for an admin to refund
+// through /payments/{id}/refund.
+func (h *PaymentHandler) refundLateCapture(r *http.Request, intent *entity.PaymentIntent, order *entity.Order) {
+	if err := h.Provider.Refund(r.Context(), intent.Reference, intent.Amount); err != nil {
+		log.Printf("payment %s captured for %s order %s could not be refunded: %v", intent.ID, order.Status, order.ID, err)
+		return
+	}
+	intent.Refund(intent.Amount)
+}
+
+// capture charges an authorized intent and returns the order's move to
+// paid. The order must still be pending.
+func (h *PaymentHandler) capture(r *http.Request, intent *entity.PaymentIntent, order *entity.Order) (*entity.OrderTransition, error) {
+	if order.Status != entity.OrderPending {
+		return nil, entity.ErrOrderNotPayable
+	}
+	if intent.Status != entity.PaymentAuthorized {
+		return nil, fmt.Errorf("%w: cannot capture a %s payment", entity.ErrPaymentStatus, intent.Status)
+	}
+	if err := h.Provider.Capture(r.Context(), intent.Reference, intent.Amount); err != nil {
+		return nil, err
+	}
+	if err := intent.Capture(); err != nil {
+		return nil, err
+	}
+	return paidTransition(intent, order), nil
+}
+
+func (h *PaymentHandler) findPayment(w http.ResponseWriter, r *http.Request) (*entity.PaymentIntent, *entity.Order, bool) {
+	intent, err := h.PaymentDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Payment not found", http.StatusNotFound)
+			return nil, nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	order, err := h.OrderDB.FindByID(intent.OrderID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return intent, order, true
+}
+
+// paidTransition moves a pending order to paid once intent is captured.
+func paidTransition(intent *entity.PaymentIntent, order *entity.Order) *entity.OrderTransition {
+	if order.Status != entity.OrderPending {
+		return nil
+	}
+	transition, _ := order.Transition(entity.OrderPaid, nil, fmt.Sprintf("payment %s captured", intent.ID))
+	return transition
+}
+
+// refundTransition moves the order to refunded once intent is fully
+// refunded, if the order's status allows it.
+func refundTransition(intent *entity.PaymentIntent, order *entity.Order) *entity.OrderTransition {
+	if intent.Status != entity.PaymentRefunded || !order.Status.CanTransition(entity.OrderRefunded) {
+		return nil
+	}
+	transition, _ := order.Transition(entity.OrderRefunded, nil, fmt.Sprintf("payment %s refunded", intent.ID))
+	return transition
+}
+
+func writePaymentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, payment.ErrDeclined):
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+	case errors.Is(err, entity.ErrRefundAmount), errors.Is(err, payment.ErrAmountExceeded):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrOrderNotPayable), errors.Is(err, entity.ErrPaymentStatus),
+		errors.Is(err, database.ErrOrderStatusChanged):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+func writePayment(w http.ResponseWriter, intent *entity.PaymentIntent, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(toPaymentResponse(intent))
+}
+
+func toPaymentResponse(intent *entity.PaymentIntent) dto.PaymentResponse {
+	return dto.PaymentResponse{
+		ID:          intent.ID.String(),
+		OrderID:     intent.OrderID.String(),
+		Provider:    intent.Provider,
+		Reference:   intent.Reference,
+		Status:      string(intent.Status),
+		Amount:      intent.Amount,
+		Refunded:    intent.RefundedMoney(),
+		FailureCode: intent.FailureCode,
+		CreatedAt:   intent.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:   intent.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type PriceHandler struct {
+	ProductDB database.ProductDB
+	Prices    database.PriceListDB
+	Rates     database.ExchangeRateDB
+}
+
+func NewPriceHandler(products database.ProductDB, prices database.PriceListDB, rates database.ExchangeRateDB) *PriceHandler {
+	return &PriceHandler{
+		ProductDB: products,
+		Prices:    prices,
+		Rates:     rates,
+	}
+}
+
+// GetProductPrices lista o preço base e os preços explícitos de um produto
+func (h *PriceHandler) GetProductPrices(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	prices, err := h.Prices.FindPrices(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toProductPricesResponse(product, prices))
+}
+
+// SetProductPrice define o preço de um produto em outra moeda
+func (h *PriceHandler) SetProductPrice(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	currency, err := pkgentity.NormalizeCurrency(chi.URLParam(r, "currency"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SetProductPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	amount, err := pkgentity.ParseMoney(req.Amount, currency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	price, err := entity.NewProductPrice(product, amount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Prices.SetPrice(price); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.GetProductPrices(w, r)
+}
+
+// DeleteProductPrice remove o preço explícito; a moeda volta a ser convertida
+func (h *PriceHandler) DeleteProductPrice(w http.ResponseWriter, r *http.Request) {
+	currency, err := pkgentity.NormalizeCurrency(chi.URLParam(r, "currency"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Prices.DeletePrice(chi.URLParam(r, "id"), currency); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Price not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetExchangeRates lista as cotações em relação à moeda base
+func (h *PriceHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.Rates.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toExchangeRatesResponse(rates))
+}
+
+// UpdateExchangeRates substitui a tabela de cotações (somente admin)
+func (h *PriceHandler) UpdateExchangeRates(w http.ResponseWriter, r *http.Request) {
+	var req dto.ExchangeRatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rates, err := entity.ParseExchangeRates(req.Rates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Rates.Replace(rates); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.GetExchangeRates(w, r)
+}
+
+func toProductPricesResponse(product *entity.Product, prices []*entity.ProductPrice) dto.ProductPricesResponse {
+	response := dto.ProductPricesResponse{
+		ProductID: product.ID.String(),
+		Base:      product.Price,
+		Prices:    make([]pkgentity.Money, 0, len(prices)),
+	}
+	for _, p := range prices {
+		response.Prices = append(response.Prices, p.Money())
+	}
+	return response
+}
+
+func toExchangeRatesResponse(rates []*entity.ExchangeRate) dto.ExchangeRatesResponse {
+	response := dto.ExchangeRatesResponse{
+		Base:  pkgentity.DefaultCurrency,
+		Rates: make(map[string]string, len(rates)),
+	}
+	var updatedAt time.Time
+	for _, rate := range rates {
+		response.Rates[rate.Currency] = rate.Rate
+		if rate.UpdatedAt.After(updatedAt) {
+			updatedAt = rate.UpdatedAt
+		}
+	}
+	if !updatedAt.IsZero() {
+		response.UpdatedAt = updatedAt.Format(time.RFC3339Nano)
+	}
+	return response
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// defaultPriceBuckets are the bucket bounds used without ?price_buckets=,
+// in major units of the bucket currency.
+var defaultPriceBuckets = []string{"50", "100", "200", "500", "1000"}
+
+// GetProductFacets conta os produtos por tag, categoria e faixa de preço,
+// aplicando os mesmos filtros de GET /products
+func (h *ProductHandler) GetProductFacets(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query, err := parseProductQuery(values)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bounds, err := parsePriceBuckets(values)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	restrictToVisible(r, &query.Filter)
+	facets, err := h.ProductDB.Facets(query.Filter, bounds)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.Categories.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := toProductFacetsResponse(facets, entity.NewCategoryTree(categories))
+	response.Currency = bounds[0].Currency
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parsePriceBuckets reads ?price_buckets=50,100,200 as decimals in
+// price_currency (default BRL).
+func parsePriceBuckets(values url.Values) ([]pkgentity.Money, error) {
+	raw := splitList(values["price_buckets"])
+	if len(raw) == 0 {
+		raw = defaultPriceBuckets
+	}
+
+	bounds := make([]pkgentity.Money, 0, len(raw))
+	for _, value := range raw {
+		bound, err := pkgentity.ParseMoney(value, values.Get("price_currency"))
+		if err != nil {
+			return nil, fmt.Errorf("price_buckets: %w", err)
+		}
+		if n := len(bounds); n > 0 && bound.Amount <= bounds[n-1].Amount {
+			return nil, fmt.Errorf("price_buckets must be increasing")
+		}
+		bounds = append(bounds, bound)
+	}
+	return bounds, nil
+}
+
+func toProductFacetsResponse(facets *database.ProductFacets, tree *entity.CategoryTree) dto.ProductFacetsResponse {
+	response := dto.ProductFacetsResponse{
+		Total:        facets.Total,
+		Tags:         make([]dto.TagFacet, 0, len(facets.Tags)),
+		Categories:   make([]dto.CategoryFacet, 0, len(facets.Categories)),
+		PriceBuckets: make([]dto.PriceBucketFacet, 0, len(facets.Prices)),
+	}
+	for _, tag := range facets.Tags {
+		response.Tags = append(response.Tags, dto.TagFacet{Tag: tag.Value, Count: tag.Count})
+	}
+	for _, facet := range facets.Categories {
+		id, err := pkgentity.ParseID(facet.Value)
+		if err != nil {
+			continue
+		}
+		category := tree.Get(id)
+		if category == nil {
+			continue
+		}
+		response.Categories = append(response.Categories, dto.CategoryFacet{
+			CategoryRef: dto.CategoryRef{ID: facet.Value, Name: category.Name, Slug: category.Slug},
+			Count:       facet.Count,
+		})
+	}
+	for _, bucket := range facets.Prices {
+		response.PriceBuckets = append(response.PriceBuckets, dto.PriceBucketFacet{
+			Min:   bucket.Min,
+			Max:   bucket.Max,
+			Count: bucket.Count,
+		})
+	}
+	return response
+}
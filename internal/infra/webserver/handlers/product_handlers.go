@@ -2,25 +2,76 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"github/GuilhermeHermes/GO_API/internal/dto"
 	"github/GuilhermeHermes/GO_API/internal/entity"
 	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
 type ProductHandler struct {
-	ProductDB database.ProductDB
+	ProductDB  database.ProductDB
+	Prices     database.PriceListDB
+	Rates      database.ExchangeRateDB
+	Categories database.CategoryDB
+	Reviews    database.ReviewDB
 }
 
-func NewProductHandler(db database.ProductDB) *ProductHandler {
+func NewProductHandler(db database.ProductDB, prices database.PriceListDB, rates database.ExchangeRateDB, categories database.CategoryDB, reviews database.ReviewDB) *ProductHandler {
 	return &ProductHandler{
-		ProductDB: db,
+		ProductDB:  db,
+		Prices:     prices,
+		Rates:      rates,
+		Categories: categories,
+		Reviews:    reviews,
 	}
 }
 
+func toProductResponse(p *entity.Product) dto.ProductResponse {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	response := dto.ProductResponse{
+		ID:          p.ID.String(),
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Tags:        tags,
+		TaxClass:    p.TaxClass,
+		WeightGrams: p.WeightGrams,
+		Dimensions: dto.Dimensions{
+			LengthMM: p.Dimensions.LengthMM,
+			WidthMM:  p.Dimensions.WidthMM,
+			HeightMM: p.Dimensions.HeightMM,
+		},
+		Status:       string(p.Status),
+		NextStatuses: []string{},
+		Version:      p.Version,
+		CreatedAt:    p.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:    p.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	for _, status := range p.Status.NextStatuses() {
+		response.NextStatuses = append(response.NextStatuses, string(status))
+	}
+	if p.PublishAt != nil {
+		response.PublishAt = p.PublishAt.Format(time.RFC3339Nano)
+	}
+	if p.UnpublishAt != nil {
+		response.UnpublishAt = p.UnpublishAt.Format(time.RFC3339Nano)
+	}
+	if p.DeletedAt.Valid {
+		response.DeletedAt = p.DeletedAt.Time.Format(time.RFC3339Nano)
+	}
+	return response
+}
+
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var product dto.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
@@ -28,23 +79,63 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	p, err := entity.NewProduct(product.Name, "description", product.Price)
+	p, err := entity.NewProduct(product.Name, product.Description, product.Price)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := p.SetTags(product.Tags); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if product.TaxClass != "" {
+		if err := p.SetTaxClass(product.TaxClass); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	setPackaging(p, product)
+	if err := p.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if err := h.ProductDB.Create(p); err != nil {
+	if err := h.productDB(r).Create(p); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("ETag", etag(p.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(p)
+	json.NewEncoder(w).Encode(toProductResponse(p))
+}
+
+// toProductResponses converts products, prices them in the currency asked
+// for with ?currency= or Accept-Currency and adds category breadcrumbs.
+func (h *ProductHandler) toProductResponses(r *http.Request, products []*entity.Product) ([]dto.ProductResponse, error) {
+	currency, err := requestedCurrency(r)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, toProductResponse(product))
+	}
+	localizer := priceLocalizer{Prices: h.Prices, Rates: h.Rates}
+	if err := localizer.localize(responses, currency); err != nil {
+		return nil, err
+	}
+	if err := addBreadcrumbs(h.Categories, responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
 }
 
-// GetProduct busca um produto por ID
+// GetProduct busca um produto por ID, com o preço na moeda pedida e a
+// nota média das avaliações aprovadas; com ?as_of=, como ele era naquele
+// momento. Quem não é editor só vê produtos publicados
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
@@ -52,53 +143,194 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		h.getProductAsOf(w, r, id, asOf)
+		return
+	}
+
 	product, err := h.ProductDB.FindByID(id)
-	if err != nil {
+	if err != nil || !visibleTo(r, product) {
 		http.Error(w, "Product not found", http.StatusNotFound)
 		return
 	}
 
+	responses, err := h.toProductResponses(r, []*entity.Product{product})
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+	rating, err := h.Reviews.RatingSummary(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responses[0].Rating = toRatingSummaryResponse(rating)
+
+	w.Header().Set("ETag", etag(product.Version))
+	w.Header().Set("Vary", "Accept-Currency")
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(product)
+	json.NewEncoder(w).Encode(responses[0])
 }
 
-// GetAllProducts lista todos os produtos com paginação
+// GetAllProducts lista os produtos com filtros e ordenação.
+// Por padrão usa paginação por offset (?page=) e devolve um array; com
+// ?cursor= usa paginação por cursor e devolve um envelope com next/prev.
 func (h *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-	sort := r.URL.Query().Get("sort")
+	query, err := parseProductQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	restrictToVisible(r, &query.Filter)
+	h.listProducts(w, r, query)
+}
 
-	// Valores padrão
-	page := 1
-	limit := 10
+// GetCategoryProducts lista os produtos de uma categoria (ID ou slug);
+// com ?include_descendants=true inclui as subcategorias.
+func (h *ProductHandler) GetCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	category, err := findCategory(h.Categories, chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
 
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
+	query, err := parseProductQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query.Filter.CategoryIDs = []string{category.ID.String()}
+	if includeDescendants, _ := strconv.ParseBool(r.URL.Query().Get("include_descendants")); includeDescendants {
+		if query.Filter.CategoryIDs, err = h.Categories.DescendantIDs(category.ID.String()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
+	restrictToVisible(r, &query.Filter)
+	h.listProducts(w, r, query)
+}
+
+// listProducts pages by offset and answers with an array unless the
+// request asks for cursor pages with ?cursor= (empty for the first page).
+func (h *ProductHandler) listProducts(w http.ResponseWriter, r *http.Request, query database.ProductQuery) {
+	if values := r.URL.Query(); values.Has("cursor") && !values.Has("page") {
+		h.listProductsByCursor(w, r, query)
+		return
+	}
+	h.listProductsByOffset(w, r, query)
+}
+
+func (h *ProductHandler) listProductsByOffset(w http.ResponseWriter, r *http.Request, query database.ProductQuery) {
+	products, err := h.ProductDB.List(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responses, err := h.toProductResponses(r, products)
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+
+	if wantsTotal(r) {
+		total, err := h.ProductDB.CountProducts(query.Filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
+		w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
 	}
 
-	if sort == "" {
-		sort = "asc"
+	links := newLinkHeader(r)
+	links.add("first", map[string]string{"page": "1"})
+	if query.Page > 1 {
+		links.add("prev", map[string]string{"page": strconv.Itoa(query.Page - 1)})
+	}
+	if len(products) == query.Limit {
+		links.add("next", map[string]string{"page": strconv.Itoa(query.Page + 1)})
 	}
+	links.write(w)
+
+	w.Header().Set("Vary", "Accept-Currency")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
 
-	products, err := h.ProductDB.FindAll(page, limit, sort)
+func (h *ProductHandler) listProductsByCursor(w http.ResponseWriter, r *http.Request, query database.ProductQuery) {
+	desc, err := database.CursorSortDesc(query.Sort)
 	if err != nil {
+		http.Error(w, err.Error()+"; use ?page= for other sorts", http.StatusBadRequest)
+		return
+	}
+
+	var cursor *database.ProductCursor
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		if cursor, err = database.DecodeProductCursor(token); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.ProductDB.ListByCursor(database.ProductCursorQuery{
+		Filter:    query.Filter,
+		Desc:      desc,
+		Cursor:    cursor,
+		Limit:     query.Limit,
+		WithTotal: wantsTotal(r),
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	data, err := h.toProductResponses(r, page.Products)
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+	response := dto.ProductListResponse{Data: data, Total: page.Total}
+
+	links := newLinkHeader(r)
+	links.add("first", map[string]string{"cursor": ""})
+	if page.Prev != nil {
+		response.PrevCursor = page.Prev.Encode()
+		links.add("prev", map[string]string{"cursor": response.PrevCursor})
+	}
+	if page.Next != nil {
+		response.NextCursor = page.Next.Encode()
+		links.add("next", map[string]string{"cursor": response.NextCursor})
+	}
+	links.write(w)
+
+	w.Header().Set("Vary", "Accept-Currency")
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(products)
+	json.NewEncoder(w).Encode(response)
 }
 
-// UpdateProduct atualiza um produto
+// setPackaging copies the weight and dimensions req sets onto p; callers
+// validate the product afterwards.
+func setPackaging(p *entity.Product, req dto.CreateProductRequest) {
+	if req.WeightGrams != nil {
+		p.WeightGrams = *req.WeightGrams
+	}
+	if req.Dimensions != nil {
+		p.Dimensions = entity.Dimensions{
+			LengthMM: req.Dimensions.LengthMM,
+			WidthMM:  req.Dimensions.WidthMM,
+			HeightMM: req.Dimensions.HeightMM,
+		}
+	}
+}
+
+// UpdateProduct atualiza um produto; com If-Match, só se ele não mudou
+// desde a versão informada
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
@@ -112,6 +344,9 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Product not found", http.StatusNotFound)
 		return
 	}
+	if !checkIfMatch(w, r, existingProduct.Version) {
+		return
+	}
 
 	var updateReq dto.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&updateReq); err != nil {
@@ -121,18 +356,130 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 
 	// Atualizar os campos
 	existingProduct.Name = updateReq.Name
+	existingProduct.Description = updateReq.Description
 	existingProduct.Price = updateReq.Price
+	if updateReq.Tags != nil {
+		if err := existingProduct.SetTags(updateReq.Tags); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if updateReq.TaxClass != "" {
+		if err := existingProduct.SetTaxClass(updateReq.TaxClass); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	setPackaging(existingProduct, updateReq)
 
-	if err := h.ProductDB.Update(existingProduct); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := existingProduct.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.productDB(r).Update(existingProduct); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(existingProduct.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toProductResponse(existingProduct))
+}
+
+// PatchProduct altera só os campos enviados, com JSON Merge Patch ou JSON
+// Patch; com If-Match, só se ele não mudou desde a versão informada
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, product.Version) {
 		return
 	}
 
+	var patched dto.UpdateProductRequest
+	if !decodePatched(w, r, toProductDocument(product), &patched) {
+		return
+	}
+	if err := applyProductDocument(product, patched); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.productDB(r).Update(product); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(existingProduct)
+	json.NewEncoder(w).Encode(toProductResponse(product))
+}
+
+// toProductDocument is the document product patches apply to.
+func toProductDocument(p *entity.Product) dto.UpdateProductRequest {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return dto.UpdateProductRequest{
+		Name:        &p.Name,
+		Description: &p.Description,
+		Price:       &p.Price,
+		Tags:        &tags,
+		TaxClass:    &p.TaxClass,
+		WeightGrams: &p.WeightGrams,
+		Dimensions: &dto.Dimensions{
+			LengthMM: p.Dimensions.LengthMM,
+			WidthMM:  p.Dimensions.WidthMM,
+			HeightMM: p.Dimensions.HeightMM,
+		},
+	}
 }
 
-// DeleteProduct deleta um produto
+// applyProductDocument sets p to a patched document and revalidates it.
+// Members the patch removed go back to their zero value, or the default
+// tax class.
+func applyProductDocument(p *entity.Product, doc dto.UpdateProductRequest) error {
+	updated := *p
+	updated.Name, updated.Description = "", ""
+	if doc.Name != nil {
+		updated.Name = *doc.Name
+	}
+	if doc.Description != nil {
+		updated.Description = *doc.Description
+	}
+	updated.Price = pkgentity.Money{}
+	if doc.Price != nil {
+		updated.Price = *doc.Price
+	}
+	var tags []string
+	if doc.Tags != nil {
+		tags = *doc.Tags
+	}
+	if err := updated.SetTags(tags); err != nil {
+		return err
+	}
+	taxClass := entity.DefaultTaxClass
+	if doc.TaxClass != nil {
+		taxClass = *doc.TaxClass
+	}
+	if err := updated.SetTaxClass(taxClass); err != nil {
+		return err
+	}
+	updated.WeightGrams, updated.Dimensions = 0, entity.Dimensions{}
+	setPackaging(&updated, dto.CreateProductRequest{WeightGrams: doc.WeightGrams, Dimensions: doc.Dimensions})
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	*p = updated
+	return nil
+}
+
+// DeleteProduct move um produto para a lixeira; com If-Match, só se ele
+// não mudou desde a versão informada
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
@@ -140,17 +487,69 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verificar se o produto existe
-	_, err := h.ProductDB.FindByID(id)
+	product, err := h.ProductDB.FindByID(id)
 	if err != nil {
 		http.Error(w, "Product not found", http.StatusNotFound)
 		return
 	}
+	if !checkIfMatch(w, r, product.Version) {
+		return
+	}
 
-	if err := h.ProductDB.Delete(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.productDB(r).Delete(id, product.Version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Product not found", http.StatusNotFound)
+			return
+		}
+		writeUpdateError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetTrash lista os produtos na lixeira, dos apagados mais recentemente
+// para os mais antigos
+func (h *ProductHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	page, limit := pageFromQuery(r.URL.Query())
+	products, err := h.ProductDB.FindDeleted(page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responses, err := h.toProductResponses(r, products)
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
+
+// RestoreProduct tira um produto da lixeira
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.productDB(r).Restore(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Product not in trash", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responses, err := h.toProductResponses(r, []*entity.Product{product})
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses[0])
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetProductHistory lista as revisões de um produto, das mais novas para
+// as mais antigas, com os campos alterados e quem alterou; só para editores,
+// já que as revisões guardam rascunhos e autores
+func (h *ProductHandler) GetProductHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	page, limit := pageFromQuery(r.URL.Query())
+
+	revisions, err := h.ProductDB.FindRevisions(id, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(revisions) == 0 && page == 1 {
+		if _, err := h.ProductDB.FindByID(id); err != nil {
+			http.Error(w, "Product not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	response := make([]dto.ProductRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		response = append(response, toProductRevisionResponse(revision))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevertProduct volta um produto ao estado de uma revisão, registrando
+// uma nova; com If-Match, só se ele não mudou desde a versão informada
+func (h *ProductHandler) RevertProduct(w http.ResponseWriter, r *http.Request) {
+	revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 64)
+	if err != nil || revision <= 0 {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, product.Version) {
+		return
+	}
+
+	if err := h.productDB(r).Revert(product, revision); err != nil {
+		if errors.Is(err, database.ErrRevisionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toProductResponse(product))
+}
+
+// getProductAsOf answers GET /products/{id}?as_of= with the product as it
+// was at that time, if it was published then or the user is an editor.
+func (h *ProductHandler) getProductAsOf(w http.ResponseWriter, r *http.Request, id, asOf string) {
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		http.Error(w, "as_of must be an RFC 3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	product, err := h.ProductDB.FindAsOf(id, at)
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found at that time", http.StatusNotFound)
+		return
+	}
+
+	responses, err := h.toProductResponses(r, []*entity.Product{product})
+	if err != nil {
+		writeCurrencyError(w, err)
+		return
+	}
+	w.Header().Set("Vary", "Accept-Currency")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses[0])
+}
+
+// productDB records the logged-in user as the author of the changes made
+// through it.
+func (h *ProductHandler) productDB(r *http.Request) database.ProductDB {
+	if userID, ok := userIDFromContext(r); ok {
+		return h.ProductDB.As(userID)
+	}
+	return h.ProductDB
+}
+
+func toProductRevisionResponse(revision *entity.ProductRevision) dto.ProductRevisionResponse {
+	changes := make([]dto.FieldChange, 0, len(revision.Changes))
+	for _, change := range revision.Changes {
+		changes = append(changes, dto.FieldChange{Field: change.Field, Old: change.Old, New: change.New})
+	}
+	product := &entity.Product{}
+	revision.Snapshot.ApplyTo(product)
+
+	response := dto.ProductRevisionResponse{
+		Revision:  revision.Revision,
+		Action:    string(revision.Action),
+		Version:   revision.Version,
+		RevertOf:  revision.RevertOf,
+		Changes:   changes,
+		Product:   toProductDocument(product),
+		CreatedAt: revision.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if revision.ActorID != nil {
+		response.ActorID = revision.ActorID.String()
+	}
+	return response
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	internalentity "github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// parseProductQuery translates the listing query string into a
+// database.ProductQuery, rejecting anything it cannot parse:
+//
+//	page, limit                     pagination (invalid values fall back to defaults)
+//	sort=-price,name                sort keys, "-" for descending (asc/desc sort by created_at)
+//	min_price, max_price            price range, inclusive, in price_currency (default BRL)
+//	name                            case-insensitive substring of the name
+//	created_after, created_before   RFC 3339 timestamps or YYYY-MM-DD dates
+//	updated_after, updated_before   same as above
+//	ids=a,b or id=a&id=b            restrict to these product IDs
+//	category_id=a&category_id=b     products directly in any of these categories
+//	tag=a&tag=b, tag_mode=any|all   products with any (default) or all of the tags
+//	status=draft,archived           products in any of these statuses (editors only)
+func parseProductQuery(values url.Values) (database.ProductQuery, error) {
+	q := database.ProductQuery{Page: 1, Limit: defaultPageLimit}
+
+	if p, err := strconv.Atoi(values.Get("page")); err == nil && p > 0 {
+		q.Page = p
+	}
+	if l, err := strconv.Atoi(values.Get("limit")); err == nil && l > 0 {
+		q.Limit = min(l, maxPageLimit)
+	}
+
+	sort, err := database.ParseProductSort(values.Get("sort"))
+	if err != nil {
+		return q, err
+	}
+	q.Sort = sort
+
+	f := &q.Filter
+	priceCurrency := values.Get("price_currency")
+	if f.MinPrice, err = parseMoneyParam(values, "min_price", priceCurrency); err != nil {
+		return q, err
+	}
+	if f.MaxPrice, err = parseMoneyParam(values, "max_price", priceCurrency); err != nil {
+		return q, err
+	}
+	f.NameContains = strings.TrimSpace(values.Get("name"))
+	if f.CreatedAfter, err = parseTimeParam(values, "created_after", false); err != nil {
+		return q, err
+	}
+	if f.CreatedBefore, err = parseTimeParam(values, "created_before", true); err != nil {
+		return q, err
+	}
+	if f.UpdatedAfter, err = parseTimeParam(values, "updated_after", false); err != nil {
+		return q, err
+	}
+	if f.UpdatedBefore, err = parseTimeParam(values, "updated_before", true); err != nil {
+		return q, err
+	}
+
+	if f.IDs, err = parseIDList(values, "id", "ids"); err != nil {
+		return q, err
+	}
+	if f.CategoryIDs, err = parseIDList(values, "category_id", "category_ids"); err != nil {
+		return q, err
+	}
+
+	if f.Tags, err = internalentity.NormalizeTags(splitList(values["tag"])); err != nil {
+		return q, err
+	}
+	for _, raw := range splitList(values["status"]) {
+		status, err := internalentity.ParseProductStatus(raw)
+		if err != nil {
+			return q, err
+		}
+		f.Statuses = append(f.Statuses, string(status))
+	}
+	switch values.Get("tag_mode") {
+	case "", "any":
+	case "all":
+		f.AllTags = true
+	default:
+		return q, fmt.Errorf("tag_mode must be 'any' or 'all'")
+	}
+
+	return q, f.Validate()
+}
+
+// parseIDList collects UUIDs given as repeated single params (id=a&id=b)
+// or comma separated lists (ids=a,b).
+func parseIDList(values url.Values, single, list string) ([]string, error) {
+	var ids []string
+	for _, id := range splitList(slices.Concat(values[single], values[list])) {
+		if _, err := entity.ParseID(id); err != nil {
+			return nil, fmt.Errorf("invalid %s %q", single, id)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// splitList splits comma separated values and drops empty entries.
+func splitList(raw []string) []string {
+	var items []string
+	for _, value := range raw {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func parseMoneyParam(values url.Values, name, currency string) (*entity.Money, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	m, err := entity.ParseMoney(raw, currency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &m, nil
+}
+
+// parseTimeParam accepts RFC 3339 timestamps or plain dates. A plain date
+// used as an upper bound covers the whole day.
+func parseTimeParam(values url.Values, name string, endOfDay bool) (*time.Time, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
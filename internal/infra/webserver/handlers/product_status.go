@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TransitionProduct muda o status de um produto entre rascunho, publicado
+// e arquivado (editores); com If-Match, só se ele não mudou desde a versão
+// informada
+func (h *ProductHandler) TransitionProduct(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProductTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := entity.ParseProductStatus(req.Status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, product.Version) {
+		return
+	}
+
+	if err := h.productDB(r).ChangeStatus(product, status); err != nil {
+		if errors.Is(err, entity.ErrInvalidProductTransition) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toProductResponse(product))
+}
+
+// ScheduleProduct define quando um produto é publicado e arquivado
+// automaticamente (editores); com If-Match, só se ele não mudou desde a
+// versão informada
+func (h *ProductHandler) ScheduleProduct(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProductScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, product.Version) {
+		return
+	}
+
+	if err := product.SetSchedule(req.PublishAt, req.UnpublishAt); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.productDB(r).Update(product); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toProductResponse(product))
+}
+
+// canSeeUnpublished reports whether the user is an editor, who sees draft
+// and archived products too.
+func canSeeUnpublished(r *http.Request) bool {
+	return slices.Contains(entity.ProductEditorRoles, roleFromContext(r))
+}
+
+func visibleTo(r *http.Request, product *entity.Product) bool {
+	return product.Status == entity.ProductPublished || canSeeUnpublished(r)
+}
+
+// restrictToVisible limits a listing to published products unless the
+// user is an editor, whatever statuses they asked for.
+func restrictToVisible(r *http.Request, filter *database.ProductFilter) {
+	if !canSeeUnpublished(r) {
+		filter.Statuses = []string{string(entity.ProductPublished)}
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type ReviewHandler struct {
+	ReviewDB  database.ReviewDB
+	ProductDB database.ProductDB
+	// VerifiedOnly only lets users who bought a product review it.
+	VerifiedOnly bool
+}
+
+func NewReviewHandler(reviews database.ReviewDB, products database.ProductDB, verifiedOnly bool) *ReviewHandler {
+	return &ReviewHandler{
+		ReviewDB:     reviews,
+		ProductDB:    products,
+		VerifiedOnly: verifiedOnly,
+	}
+}
+
+// CreateReview avalia um produto; a avaliação aguarda moderação antes de
+// aparecer
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	var req dto.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review, err := entity.NewReview(product.ID, userID, req.Rating, req.Title, req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.VerifiedPurchase, err = h.ReviewDB.HasPurchased(userID.String(), product.ID.String()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if h.VerifiedOnly && !review.VerifiedPurchase {
+		http.Error(w, "Only customers who bought this product can review it", http.StatusForbidden)
+		return
+	}
+	if err := h.ReviewDB.Create(review); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toReviewResponse(review))
+}
+
+// GetProductReviews lista as avaliações aprovadas de um produto, das mais
+// novas para as mais antigas
+func (h *ReviewHandler) GetProductReviews(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	page, limit := pageFromQuery(r.URL.Query())
+
+	reviews, err := h.ReviewDB.FindByProduct(product.ID.String(), page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeReviews(w, reviews)
+}
+
+// GetReviews lista a fila de moderação (?status=pending, o padrão) ou as
+// avaliações aprovadas ou rejeitadas
+func (h *ReviewHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	status := entity.ReviewPending
+	if s := values.Get("status"); s != "" {
+		var err error
+		if status, err = entity.ParseReviewStatus(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	page, limit := pageFromQuery(values)
+
+	reviews, err := h.ReviewDB.FindByStatus(status, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeReviews(w, reviews)
+}
+
+// UpdateReview reescreve a avaliação do usuário logado, que volta para a
+// fila de moderação
+func (h *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review, err := h.ReviewDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || review.UserID != userID {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+
+	if err := review.Edit(req.Rating, req.Title, req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.ReviewDB.Update(review); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toReviewResponse(review))
+}
+
+// DeleteReview remove uma avaliação; o autor remove a sua, admins removem
+// qualquer uma
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	review, err := h.ReviewDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || (review.UserID != userID && roleFromContext(r) != entity.RoleAdmin) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.ReviewDB.Delete(review.ID.String()); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ModerateReview aprova ou rejeita uma avaliação
+func (h *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.ModerateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review, err := h.ReviewDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	status, err := entity.ParseReviewStatus(req.Status)
+	if err == nil {
+		err = review.Moderate(status, adminID, req.Note)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.ReviewDB.Update(review); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toReviewResponse(review))
+}
+
+// pageFromQuery reads ?page= and ?limit=, falling back to the first page
+// of defaultPageLimit items and capping limit at maxPageLimit.
+func pageFromQuery(values url.Values) (page, limit int) {
+	page, limit = 1, defaultPageLimit
+	if p, err := strconv.Atoi(values.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(values.Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxPageLimit)
+	}
+	return page, limit
+}
+
+func writeReviews(w http.ResponseWriter, reviews []*entity.Review) {
+	response := make([]dto.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		response = append(response, toReviewResponse(review))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Review not found", http.StatusNotFound)
+	case errors.Is(err, database.ErrAlreadyReviewed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func toReviewResponse(review *entity.Review) dto.ReviewResponse {
+	response := dto.ReviewResponse{
+		ID:               review.ID.String(),
+		ProductID:        review.ProductID.String(),
+		UserID:           review.UserID.String(),
+		Rating:           review.Rating,
+		Title:            review.Title,
+		Body:             review.Body,
+		VerifiedPurchase: review.VerifiedPurchase,
+		Status:           string(review.Status),
+		ModerationNote:   review.ModerationNote,
+		CreatedAt:        review.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:        review.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if review.ModeratedAt != nil {
+		response.ModeratedAt = review.ModeratedAt.Format(time.RFC3339Nano)
+	}
+	return response
+}
+
+func toRatingSummaryResponse(summary entity.RatingSummary) *dto.RatingSummary {
+	distribution := make(map[string]int64, len(summary.Distribution))
+	for rating, count := range summary.Distribution {
+		distribution[strconv.Itoa(rating)] = count
+	}
+	return &dto.RatingSummary{Average: summary.Average, Count: summary.Count, Distribution: distribution}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+)
+
+type ProductSearchHandler struct {
+	Searcher database.ProductSearcher
+}
+
+func NewProductSearchHandler(searcher database.ProductSearcher) *ProductSearchHandler {
+	return &ProductSearchHandler{
+		Searcher: searcher,
+	}
+}
+
+// SearchProducts faz busca textual em nome e descrição, ordenada por relevância
+func (h *ProductSearchHandler) SearchProducts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "q parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	// Valores padrão
+	page := 1
+	limit := 20
+
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	results, err := h.Searcher.Search(q, page, limit)
+	if err != nil {
+		if errors.Is(err, database.ErrEmptySearchQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.ProductSearchResult, 0, len(results))
+	for _, result := range results {
+		response = append(response, dto.ProductSearchResult{
+			Product: toProductResponse(result.Product),
+			Rank:    result.Rank,
+			Highlights: dto.SearchHighlights{
+				Name:        result.NameHighlight,
+				Description: result.DescriptionHighlight,
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
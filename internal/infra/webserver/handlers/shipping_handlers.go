@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	pkgentity "github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type ShippingHandler struct {
+	ShippingDB database.ShippingDB
+}
+
+func NewShippingHandler(zones database.ShippingDB) *ShippingHandler {
+	return &ShippingHandler{ShippingDB: zones}
+}
+
+// CreateZone cria uma zona de frete com os países, regiões e faixas de CEP
+// que ela atende
+func (h *ShippingHandler) CreateZone(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateShippingZoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	areas := make([]entity.ShippingArea, 0, len(req.Areas))
+	for _, area := range req.Areas {
+		areas = append(areas, entity.ShippingArea{Country: area.Country, Region: area.Region, PostalPrefix: area.PostalPrefix})
+	}
+	zone, err := entity.NewShippingZone(req.Name, areas)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.ShippingDB.CreateZone(zone); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toShippingZoneResponse(zone))
+}
+
+// GetZones lista as zonas de frete com seus métodos
+func (h *ShippingHandler) GetZones(w http.ResponseWriter, r *http.Request) {
+	zones, err := h.ShippingDB.FindZones()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.ShippingZoneResponse, 0, len(zones))
+	for _, zone := range zones {
+		response = append(response, toShippingZoneResponse(zone))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetZone busca uma zona de frete com seus métodos
+func (h *ShippingHandler) GetZone(w http.ResponseWriter, r *http.Request) {
+	zone, ok := h.findZone(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toShippingZoneResponse(zone))
+}
+
+// DeleteZone remove uma zona de frete e seus métodos; pedidos mantêm o
+// frete que pagaram
+func (h *ShippingHandler) DeleteZone(w http.ResponseWriter, r *http.Request) {
+	if err := h.ShippingDB.DeleteZone(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Shipping zone not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateMethod adiciona a uma zona um método de frete fixo, por peso ou
+// grátis acima de um valor
+func (h *ShippingHandler) CreateMethod(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateShippingMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zone, ok := h.findZone(w, r)
+	if !ok {
+		return
+	}
+
+	var method *entity.ShippingMethod
+	var err error
+	switch entity.ShippingMethodKind(req.Kind) {
+	case entity.ShippingFlat:
+		if req.Cost == nil {
+			err = entity.ErrInvalidShippingCost
+			break
+		}
+		method, err = entity.NewFlatShipping(zone.ID, req.Name, *req.Cost)
+	case entity.ShippingWeight:
+		tiers := make([]entity.WeightTier, 0, len(req.Tiers))
+		for _, tier := range req.Tiers {
+			tiers = append(tiers, entity.WeightTier{UpToGrams: tier.UpToGrams, Cost: tier.Cost})
+		}
+		method, err = entity.NewWeightShipping(zone.ID, req.Name, tiers, req.VolumetricDivisor)
+	case entity.ShippingFreeOver:
+		if req.Threshold == nil {
+			err = entity.ErrInvalidShippingCost
+			break
+		}
+		method, err = entity.NewFreeShipping(zone.ID, req.Name, *req.Threshold)
+	default:
+		err = entity.ErrInvalidShippingKind
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.ShippingDB.CreateMethod(method); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toShippingMethodResponse(method))
+}
+
+// DeleteMethod remove um método de frete da zona
+func (h *ShippingHandler) DeleteMethod(w http.ResponseWriter, r *http.Request) {
+	if err := h.ShippingDB.DeleteMethod(chi.URLParam(r, "id"), chi.URLParam(r, "methodID")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Shipping method not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ShippingHandler) findZone(w http.ResponseWriter, r *http.Request) (*entity.ShippingZone, bool) {
+	zone, err := h.ShippingDB.FindZone(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Shipping zone not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return zone, true
+}
+
+func toShippingZoneResponse(zone *entity.ShippingZone) dto.ShippingZoneResponse {
+	response := dto.ShippingZoneResponse{
+		ID:        zone.ID.String(),
+		Name:      zone.Name,
+		Areas:     make([]dto.ShippingArea, 0, len(zone.Areas)),
+		Methods:   make([]dto.ShippingMethodResponse, 0, len(zone.Methods)),
+		CreatedAt: zone.CreatedAt.Format(time.RFC3339Nano),
+	}
+	for _, area := range zone.Areas {
+		response.Areas = append(response.Areas, dto.ShippingArea{Country: area.Country, Region: area.Region, PostalPrefix: area.PostalPrefix})
+	}
+	for _, method := range zone.Methods {
+		response.Methods = append(response.Methods, toShippingMethodResponse(method))
+	}
+	return response
+}
+
+// toShippingMethodResponse only fills in the fields method's kind uses.
+func toShippingMethodResponse(method *entity.ShippingMethod) dto.ShippingMethodResponse {
+	response := dto.ShippingMethodResponse{
+		ID:   method.ID.String(),
+		Name: method.Name,
+		Kind: string(method.Kind),
+	}
+	switch method.Kind {
+	case entity.ShippingFlat:
+		response.Cost = moneyPtr(method.Cost)
+	case entity.ShippingFreeOver:
+		response.Threshold = moneyPtr(method.Threshold)
+	case entity.ShippingWeight:
+		response.VolumetricDivisor = method.VolumetricDivisor
+		for _, tier := range method.Tiers {
+			response.Tiers = append(response.Tiers, dto.WeightTier{UpToGrams: tier.UpToGrams, Cost: tier.Cost})
+		}
+	}
+	return response
+}
+
+func moneyPtr(m pkgentity.Money) *pkgentity.Money {
+	return &m
+}
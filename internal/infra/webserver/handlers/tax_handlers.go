@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+)
+
+// TaxCalculator taxes cart and order lines with the stored rate table and
+// the store-wide settings.
+type TaxCalculator struct {
+	Rates    database.TaxRateDB
+	Settings entity.TaxSettings
+}
+
+func NewTaxCalculator(rates database.TaxRateDB, settings entity.TaxSettings) *TaxCalculator {
+	if settings.Rounding == "" {
+		settings.Rounding = entity.RoundHalfUp
+	}
+	return &TaxCalculator{Rates: rates, Settings: settings}
+}
+
+// Location validates the location a shopper asked for, falling back to
+// the store's default when they gave none.
+func (c *TaxCalculator) Location(country, region string) (entity.TaxLocation, error) {
+	if country == "" && region == "" {
+		return c.Settings.DefaultLocation, nil
+	}
+	return entity.NewTaxLocation(country, region)
+}
+
+// Calculate taxes lines at loc with the current rates.
+func (c *TaxCalculator) Calculate(lines []entity.TaxLine, loc entity.TaxLocation) (*entity.TaxBreakdown, error) {
+	table, err := c.Rates.Load()
+	if err != nil {
+		return nil, err
+	}
+	return table.Calculate(lines, loc, c.Settings), nil
+}
+
+// isTaxLocationError reports whether err is a malformed tax location.
+func isTaxLocationError(err error) bool {
+	return errors.Is(err, entity.ErrInvalidCountry) || errors.Is(err, entity.ErrInvalidRegion)
+}
+
+type TaxHandler struct {
+	Calculator *TaxCalculator
+	ProductDB  database.ProductDB
+	VariantDB  database.VariantDB
+}
+
+func NewTaxHandler(calculator *TaxCalculator, products database.ProductDB, variants database.VariantDB) *TaxHandler {
+	return &TaxHandler{
+		Calculator: calculator,
+		ProductDB:  products,
+		VariantDB:  variants,
+	}
+}
+
+// GetTaxRates lista a tabela de alíquotas e as regras de imposto da loja
+func (h *TaxHandler) GetTaxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.Calculator.Rates.FindAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTaxRatesResponse(rates, h.Calculator.Settings))
+}
+
+// UpdateTaxRates substitui a tabela de alíquotas (somente admin)
+func (h *TaxHandler) UpdateTaxRates(w http.ResponseWriter, r *http.Request) {
+	var req dto.TaxRatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows := make([]entity.TaxRate, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		rows = append(rows, entity.TaxRate{Country: rate.Country, Region: rate.Region, TaxClass: rate.TaxClass, Rate: rate.Rate})
+	}
+	rates, err := entity.ParseTaxRates(rows)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Calculator.Rates.Replace(rates); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.GetTaxRates(w, r)
+}
+
+// QuoteTax calcula o imposto de uma lista de itens, pelo preço atual do
+// catálogo e antes de descontos, no país e região pedidos
+func (h *TaxHandler) QuoteTax(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.TaxQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	loc, err := h.Calculator.Location(req.Country, req.Region)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items := make([]*entity.OrderItem, 0, len(req.Items))
+	for _, line := range req.Items {
+		product, variant, err := findItem(r, h.ProductDB, h.VariantDB, line.SKU, line.ProductID, line.VariantID)
+		if err != nil {
+			writeOrderError(w, err)
+			return
+		}
+		item, err := entity.NewOrderItem(product, variant, line.Quantity)
+		if err != nil {
+			writeOrderError(w, err)
+			return
+		}
+		items = append(items, item)
+	}
+	// An unsaved order checks the items the same way placing one would.
+	order, err := entity.NewOrder(userID, items)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+	breakdown, err := h.Calculator.Calculate(order.TaxLines(), loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTaxQuoteResponse(order, breakdown))
+}
+
+func toTaxRatesResponse(rates []*entity.TaxRate, settings entity.TaxSettings) dto.TaxRatesResponse {
+	response := dto.TaxRatesResponse{
+		PricesIncludeTax: settings.PricesIncludeTax,
+		Rounding:         string(settings.Rounding),
+		DefaultCountry:   settings.DefaultLocation.Country,
+		DefaultRegion:    settings.DefaultLocation.Region,
+		Rates:            make([]dto.TaxRateEntry, 0, len(rates)),
+	}
+	var updatedAt time.Time
+	for _, rate := range rates {
+		response.Rates = append(response.Rates, dto.TaxRateEntry{
+			Country:  rate.Country,
+			Region:   rate.Region,
+			TaxClass: rate.TaxClass,
+			Rate:     rate.Rate,
+		})
+		if rate.UpdatedAt.After(updatedAt) {
+			updatedAt = rate.UpdatedAt
+		}
+	}
+	if !updatedAt.IsZero() {
+		response.UpdatedAt = updatedAt.Format(time.RFC3339Nano)
+	}
+	return response
+}
+
+func toTaxQuoteResponse(order *entity.Order, breakdown *entity.TaxBreakdown) dto.TaxQuoteResponse {
+	response := dto.TaxQuoteResponse{
+		Country:          breakdown.Location.Country,
+		Region:           breakdown.Location.Region,
+		PricesIncludeTax: breakdown.PricesIncludeTax,
+		Lines:            make([]dto.TaxQuoteLine, 0, len(order.Items)),
+		Net:              breakdown.Net,
+		Tax:              breakdown.Tax,
+		Gross:            breakdown.Gross,
+	}
+	for _, item := range order.Items {
+		line := dto.TaxQuoteLine{
+			ProductID: item.ProductID.String(),
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Amount:    item.Subtotal(),
+			TaxClass:  item.TaxClass,
+		}
+		if item.VariantID != nil {
+			line.VariantID = item.VariantID.String()
+		}
+		if taxed := breakdown.Line(item.ID.String()); taxed != nil {
+			line.Rate = taxed.Rate
+			line.Net = taxed.Net
+			line.Tax = taxed.Tax
+			line.Gross = taxed.Gross
+		}
+		response.Lines = append(response.Lines, line)
+	}
+	return response
+}
@@ -10,18 +10,21 @@ import (
 	"github/GuilhermeHermes/GO_API/internal/entity"
 	"github/GuilhermeHermes/GO_API/internal/infra/database"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth"
 )
 
 type UserHandler struct {
 	UserDB        database.UserDB
+	CartDB        database.CartDB
 	Jwt           *jwtauth.JWTAuth
 	JwtExpiration int64
 }
 
-func NewUserHandler(db database.UserDB, jwt *jwtauth.JWTAuth, jwtExpiration int64) *UserHandler {
+func NewUserHandler(db database.UserDB, carts database.CartDB, jwt *jwtauth.JWTAuth, jwtExpiration int64) *UserHandler {
 	return &UserHandler{
 		UserDB:        db,
+		CartDB:        carts,
 		Jwt:           jwt,
 		JwtExpiration: jwtExpiration,
 	}
@@ -51,10 +54,19 @@ func (h *UserHandler) GetJwt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Juntar o carrinho de convidado ao carrinho do usuário
+	if token := r.Header.Get(CartTokenHeader); token != "" {
+		if _, err := h.CartDB.MergeGuestCart(token, existingUser.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Generate JWT token
 	_, tokenString, err := h.Jwt.Encode(map[string]interface{}{
-		"sub": existingUser.ID,
-		"exp": time.Now().Add(time.Duration(h.JwtExpiration) * time.Second).Unix(),
+		"sub":  existingUser.ID.String(),
+		"role": existingUser.Role,
+		"exp":  time.Now().Add(time.Duration(h.JwtExpiration) * time.Second).Unix(),
 	})
 	if err != nil {
 		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
@@ -62,14 +74,10 @@ func (h *UserHandler) GetJwt(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	accesstoken := map[string]string{
-		"token": tokenString,
-	}
-
-	json.NewEncoder(w).Encode(accesstoken)
+	json.NewEncoder(w).Encode(dto.GetJwtResponse{Token: tokenString})
 }
 
-// CreateUser cria um novo usuário
+// CreateUser cria um novo usuário, sempre com o papel "user"
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var userReq dto.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&userReq); err != nil {
@@ -92,7 +100,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Criar usuário
-	user, err := entity.NewUser(userReq.Username, userReq.Email, userReq.Password, userReq.Role)
+	user, err := entity.NewUser(userReq.Username, userReq.Email, userReq.Password, entity.RoleUser)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -113,6 +121,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		"username":   user.Username,
 		"email":      user.Email,
 		"role":       user.Role,
+		"version":    user.Version,
 		"created_at": user.CreatedAt,
 		"updated_at": user.UpdatedAt,
 	}
@@ -124,7 +133,10 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 // GetUserByEmail busca um usuário por email
 func (h *UserHandler) GetUserByEmail(w http.ResponseWriter, r *http.Request) {
-	email := r.URL.Query().Get("email")
+	email := chi.URLParam(r, "email")
+	if email == "" {
+		email = r.URL.Query().Get("email")
+	}
 	if email == "" {
 		http.Error(w, "Email parameter is required", http.StatusBadRequest)
 		return
@@ -142,10 +154,12 @@ func (h *UserHandler) GetUserByEmail(w http.ResponseWriter, r *http.Request) {
 		"username":   user.Username,
 		"email":      user.Email,
 		"role":       user.Role,
+		"version":    user.Version,
 		"created_at": user.CreatedAt,
 		"updated_at": user.UpdatedAt,
 	}
 
+	w.Header().Set("ETag", etag(user.Version))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(userResponse)
 }
@@ -178,15 +192,18 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		"username":   user.Username,
 		"email":      user.Email,
 		"role":       user.Role,
+		"version":    user.Version,
 		"created_at": user.CreatedAt,
 		"updated_at": user.UpdatedAt,
 	}
 
+	w.Header().Set("ETag", etag(user.Version))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(userResponse)
 }
 
-// UpdateUser atualiza um usuário
+// UpdateUser atualiza um usuário (o próprio ou um admin; só admins trocam
+// o papel); com If-Match, só se ele não mudou desde a versão informada
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Extrair ID da URL
 	path := r.URL.Path
@@ -202,10 +219,11 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verificar se o usuário existe
-	existingUser, err := h.UserDB.FindByID(id)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	existingUser, ok := h.findUser(w, r, id)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(w, r, existingUser.Version) {
 		return
 	}
 
@@ -220,11 +238,18 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		existingUser.Username = updateReq.Username
 	}
 	if updateReq.Role != "" {
+		if !canSetRole(w, r, existingUser, updateReq.Role) {
+			return
+		}
 		existingUser.Role = updateReq.Role
 	}
+	if err := existingUser.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	if err := h.UserDB.Update(existingUser); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeUpdateError(w, err)
 		return
 	}
 
@@ -234,15 +259,103 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		"username":   existingUser.Username,
 		"email":      existingUser.Email,
 		"role":       existingUser.Role,
+		"version":    existingUser.Version,
 		"created_at": existingUser.CreatedAt,
 		"updated_at": existingUser.UpdatedAt,
 	}
 
+	w.Header().Set("ETag", etag(existingUser.Version))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(userResponse)
 }
 
-// DeleteUser deleta um usuário
+// PatchUser altera o nome ou o papel de um usuário (o próprio ou um admin;
+// só admins trocam o papel) com JSON Merge Patch ou JSON Patch; com
+// If-Match, só se ele não mudou desde a versão informada
+func (h *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.findUser(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+	if !checkIfMatch(w, r, user.Version) {
+		return
+	}
+
+	var patched dto.UpdateUserRequest
+	doc := dto.UpdateUserRequest{Username: user.Username, Role: user.Role}
+	if !decodePatched(w, r, doc, &patched) {
+		return
+	}
+	if !canSetRole(w, r, user, patched.Role) {
+		return
+	}
+	user.Username, user.Role = patched.Username, patched.Role
+	if err := user.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UserDB.Update(user); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(user.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.UserResponse{
+		ID:        user.ID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Version:   user.Version,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+}
+
+// SetUserRole troca o papel de um usuário (admin); com If-Match, só se ele
+// não mudou desde a versão informada
+func (h *UserHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	user, err := h.UserDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, user.Version) {
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user.Role = req.Role
+	if err := user.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UserDB.Update(user); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(user.Version))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.UserResponse{
+		ID:        user.ID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Version:   user.Version,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+}
+
+// DeleteUser deleta um usuário (o próprio ou um admin); com If-Match, só
+// se ele não mudou desde a versão informada
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	// Extrair ID da URL
 	path := r.URL.Path
@@ -258,10 +371,11 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verificar se o usuário existe
-	_, err := h.UserDB.FindByID(id)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	user, ok := h.findUser(w, r, id)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(w, r, user.Version) {
 		return
 	}
 
@@ -273,6 +387,31 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// findUser loads the user a write is about. Only that user and admins may
+// change it; anyone else gets 403.
+func (h *UserHandler) findUser(w http.ResponseWriter, r *http.Request, id string) (*entity.User, bool) {
+	user, err := h.UserDB.FindByID(id)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return nil, false
+	}
+	if userID, _ := userIDFromContext(r); user.ID != userID && roleFromContext(r) != entity.RoleAdmin {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// canSetRole answers 403 when a caller other than an admin tries to give
+// user a role other than the one it has.
+func canSetRole(w http.ResponseWriter, r *http.Request, user *entity.User, role string) bool {
+	if role != user.Role && roleFromContext(r) != entity.RoleAdmin {
+		http.Error(w, "Only admins can change roles", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 // CheckUserExists verifica se um usuário existe por email
 func (h *UserHandler) CheckUserExists(w http.ResponseWriter, r *http.Request) {
 	email := r.URL.Query().Get("email")
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type VariantHandler struct {
+	ProductDB database.ProductDB
+	VariantDB database.VariantDB
+}
+
+func NewVariantHandler(products database.ProductDB, variants database.VariantDB) *VariantHandler {
+	return &VariantHandler{
+		ProductDB: products,
+		VariantDB: variants,
+	}
+}
+
+// GetProductVariants lista as opções e variantes de um produto
+func (h *VariantHandler) GetProductVariants(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	variants, err := h.VariantDB.FindVariants(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeVariants(w, product, variants)
+}
+
+// SetProductOptions substitui as opções de um produto (tamanho, cor...)
+// e gera as variantes das combinações novas
+func (h *VariantHandler) SetProductOptions(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	var req dto.SetProductOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requested := make([]entity.ProductOption, 0, len(req.Options))
+	for _, option := range req.Options {
+		requested = append(requested, entity.ProductOption{Name: option.Name, Values: option.Values})
+	}
+	options, err := entity.NormalizeOptions(product.ID, requested)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	generated, err := entity.GenerateVariants(product, options, req.SKUPrefix)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	variants, err := h.VariantDB.ReplaceOptions(product.ID.String(), options, generated)
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+
+	h.writeVariants(w, product, variants)
+}
+
+// CreateVariant cria uma variante com SKU próprio
+func (h *VariantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+
+	var req dto.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options, err := h.VariantDB.FindOptions(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	variant, err := entity.NewProductVariant(product, options, req.Options, req.SKU, req.Barcode, req.Price)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.VariantDB.CreateVariant(variant); err != nil {
+		writeVariantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toVariantResponse(product, variant))
+}
+
+// GetVariant busca uma variante de um produto
+func (h *VariantHandler) GetVariant(w http.ResponseWriter, r *http.Request) {
+	product, variant, ok := h.findVariant(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toVariantResponse(product, variant))
+}
+
+// UpdateVariant substitui SKU, código de barras, opções e preço de uma variante
+func (h *VariantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	product, variant, ok := h.findVariant(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options, err := h.VariantDB.FindOptions(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	variant.SKU = entity.NormalizeSKU(req.SKU)
+	variant.Barcode = req.Barcode
+	if err := variant.SetOptions(options, req.Options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := variant.SetPriceOverride(product, req.Price); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := variant.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.VariantDB.UpdateVariant(variant); err != nil {
+		writeVariantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toVariantResponse(product, variant))
+}
+
+// DeleteVariant remove uma variante
+func (h *VariantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	if err := h.VariantDB.DeleteVariant(chi.URLParam(r, "id"), chi.URLParam(r, "variantID")); err != nil {
+		writeVariantError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSKU busca uma variante pelo SKU, junto com o produto
+func (h *VariantHandler) GetSKU(w http.ResponseWriter, r *http.Request) {
+	product, variant, err := findItem(r, h.ProductDB, h.VariantDB, chi.URLParam(r, "sku"), "", "")
+	if err != nil {
+		http.Error(w, "SKU not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.SKUResponse{
+		VariantResponse: toVariantResponse(product, variant),
+		Product:         toProductResponse(product),
+	})
+}
+
+func (h *VariantHandler) findVariant(w http.ResponseWriter, r *http.Request) (*entity.Product, *entity.ProductVariant, bool) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	variant, err := h.VariantDB.FindVariant(product.ID.String(), chi.URLParam(r, "variantID"))
+	if err != nil {
+		http.Error(w, "Variant not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	return product, variant, true
+}
+
+func (h *VariantHandler) writeVariants(w http.ResponseWriter, product *entity.Product, variants []*entity.ProductVariant) {
+	options, err := h.VariantDB.FindOptions(product.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.ProductVariantsResponse{
+		ProductID: product.ID.String(),
+		Options:   make([]dto.ProductOption, 0, len(options)),
+		Variants:  make([]dto.VariantResponse, 0, len(variants)),
+	}
+	for _, option := range options {
+		response.Options = append(response.Options, dto.ProductOption{Name: option.Name, Values: option.Values})
+	}
+	for _, variant := range variants {
+		response.Variants = append(response.Variants, toVariantResponse(product, variant))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeVariantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, database.ErrSKUTaken), errors.Is(err, database.ErrVariantOptionsTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Variant not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func toVariantResponse(product *entity.Product, v *entity.ProductVariant) dto.VariantResponse {
+	options := v.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+	return dto.VariantResponse{
+		ID:            v.ID.String(),
+		ProductID:     v.ProductID.String(),
+		SKU:           v.SKU,
+		Barcode:       v.Barcode,
+		Options:       options,
+		Price:         v.EffectivePrice(product),
+		PriceOverride: v.PriceOverride() != nil,
+		CreatedAt:     v.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:     v.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
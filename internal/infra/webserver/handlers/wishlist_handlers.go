@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type WishlistHandler struct {
+	WishlistDB database.WishlistDB
+	ProductDB  database.ProductDB
+}
+
+func NewWishlistHandler(wishlists database.WishlistDB, products database.ProductDB) *WishlistHandler {
+	return &WishlistHandler{
+		WishlistDB: wishlists,
+		ProductDB:  products,
+	}
+}
+
+// GetWishlists lista as listas de desejos do usuário logado
+func (h *WishlistHandler) GetWishlists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlists, err := h.WishlistDB.FindByUser(userID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := h.toWishlistResponses(wishlists)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateWishlist cria uma lista de desejos vazia
+func (h *WishlistHandler) CreateWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req dto.WishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wishlist, err := entity.NewWishlist(userID, req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.WishlistDB.Create(wishlist); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	h.writeWishlist(w, http.StatusCreated, wishlist)
+}
+
+// GetWishlist busca uma lista de desejos do usuário logado
+func (h *WishlistHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+	h.writeWishlist(w, http.StatusOK, wishlist)
+}
+
+// GetSharedWishlist mostra uma lista compartilhada, sem login
+func (h *WishlistHandler) GetSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	wishlist, err := h.WishlistDB.FindByShareToken(chi.URLParam(r, "token"))
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+	h.writeWishlist(w, http.StatusOK, wishlist)
+}
+
+// RenameWishlist muda o nome de uma lista de desejos
+func (h *WishlistHandler) RenameWishlist(w http.ResponseWriter, r *http.Request) {
+	var req dto.WishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+
+	if err := wishlist.Rename(req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.saveWishlist(w, wishlist)
+}
+
+// ShareWishlist cria o link público da lista; chamar de novo mantém o mesmo
+func (h *WishlistHandler) ShareWishlist(w http.ResponseWriter, r *http.Request) {
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+
+	if err := wishlist.Share(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.saveWishlist(w, wishlist)
+}
+
+// UnshareWishlist desativa o link público da lista
+func (h *WishlistHandler) UnshareWishlist(w http.ResponseWriter, r *http.Request) {
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+
+	wishlist.Unshare()
+	h.saveWishlist(w, wishlist)
+}
+
+// DeleteWishlist remove uma lista de desejos
+func (h *WishlistHandler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := h.WishlistDB.Delete(userID.String(), chi.URLParam(r, "id")); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddWishlistItem coloca um produto na lista, guardando o preço atual
+func (h *WishlistHandler) AddWishlistItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+	product, err := h.ProductDB.FindByID(req.ProductID)
+	if err != nil || !visibleTo(r, product) {
+		http.Error(w, "Product not found", http.StatusBadRequest)
+		return
+	}
+
+	item := entity.NewWishlistItem(wishlist.ID, product)
+	if err := h.WishlistDB.AddItem(item); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+	wishlist.Items = append(wishlist.Items, item)
+
+	h.writeWishlist(w, http.StatusCreated, wishlist)
+}
+
+// RemoveWishlistItem tira um produto da lista
+func (h *WishlistHandler) RemoveWishlistItem(w http.ResponseWriter, r *http.Request) {
+	wishlist, ok := h.findWishlist(w, r)
+	if !ok {
+		return
+	}
+	err := h.WishlistDB.RemoveItem(wishlist.ID.String(), chi.URLParam(r, "productID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Product not in wishlist", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPriceDrops lista as quedas de preço dos produtos nas listas do usuário,
+// das mais recentes para as mais antigas
+func (h *WishlistHandler) GetPriceDrops(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	page, limit := pageFromQuery(r.URL.Query())
+
+	drops, err := h.WishlistDB.FindPriceDrops(userID.String(), page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]dto.PriceDropResponse, 0, len(drops))
+	for _, drop := range drops {
+		response = append(response, dto.PriceDropResponse{
+			ProductID:  drop.ProductID.String(),
+			OldPrice:   drop.OldPrice,
+			NewPrice:   drop.NewPrice,
+			DetectedAt: drop.CreatedAt.Format(time.RFC3339Nano),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// findWishlist loads the wishlist in the URL from the logged-in user's
+// lists.
+func (h *WishlistHandler) findWishlist(w http.ResponseWriter, r *http.Request) (*entity.Wishlist, bool) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	wishlist, err := h.WishlistDB.FindByID(userID.String(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeWishlistError(w, err)
+		return nil, false
+	}
+	return wishlist, true
+}
+
+func (h *WishlistHandler) saveWishlist(w http.ResponseWriter, wishlist *entity.Wishlist) {
+	if err := h.WishlistDB.Update(wishlist); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+	h.writeWishlist(w, http.StatusOK, wishlist)
+}
+
+func (h *WishlistHandler) writeWishlist(w http.ResponseWriter, status int, wishlist *entity.Wishlist) {
+	response, err := h.toWishlistResponses([]*entity.Wishlist{wishlist})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response[0])
+}
+
+// toWishlistResponses looks up the products on the wishlists in one query
+// to show their current name and price. Only published products are shown:
+// shared lists are public, and a product pulled back to draft or archived
+// must not leak through them.
+func (h *WishlistHandler) toWishlistResponses(wishlists []*entity.Wishlist) ([]dto.WishlistResponse, error) {
+	var ids []string
+	for _, wishlist := range wishlists {
+		for _, item := range wishlist.Items {
+			ids = append(ids, item.ProductID.String())
+		}
+	}
+	products := make(map[string]*entity.Product, len(ids))
+	if len(ids) > 0 {
+		found, err := h.ProductDB.List(database.ProductQuery{
+			Filter: database.ProductFilter{
+				IDs:      ids,
+				Statuses: []string{string(entity.ProductPublished)},
+			},
+			Page:  1,
+			Limit: len(ids),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range found {
+			products[product.ID.String()] = product
+		}
+	}
+
+	responses := make([]dto.WishlistResponse, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		response := dto.WishlistResponse{
+			ID:        wishlist.ID.String(),
+			Name:      wishlist.Name,
+			Items:     make([]dto.WishlistItemResponse, 0, len(wishlist.Items)),
+			CreatedAt: wishlist.CreatedAt.Format(time.RFC3339Nano),
+			UpdatedAt: wishlist.UpdatedAt.Format(time.RFC3339Nano),
+		}
+		if wishlist.ShareToken != nil {
+			response.ShareToken = *wishlist.ShareToken
+		}
+		for _, item := range wishlist.Items {
+			product, ok := products[item.ProductID.String()]
+			if !ok {
+				continue
+			}
+			response.Items = append(response.Items, dto.WishlistItemResponse{
+				ProductID:    product.ID.String(),
+				Name:         product.Name,
+				Price:        product.Price,
+				AddedPrice:   item.AddedPrice,
+				PriceDropped: entity.IsPriceDrop(item.AddedPrice, product.Price),
+				AddedAt:      item.CreatedAt.Format(time.RFC3339Nano),
+			})
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func writeWishlistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Wishlist not found", http.StatusNotFound)
+	case errors.Is(err, entity.ErrWishlistLimit), errors.Is(err, entity.ErrWishlistFull),
+		errors.Is(err, entity.ErrAlreadyWishlisted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,275 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	schemas *schemaRegistry
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// PathItem maps a lower-case HTTP method to its operation.
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                    `json:"operationId,omitempty"`
+	Summary     string                    `json:"summary,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Parameters  []ParameterObject         `json:"parameters,omitempty"`
+	RequestBody *RequestBodyObject        `json:"requestBody,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+	Security    []map[string][]string     `json:"security,omitempty"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBodyObject struct {
+	Required bool                       `json:"required"`
+	Content  map[string]MediaTypeObject `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string                     `json:"description"`
+	Headers     map[string]HeaderObject    `json:"headers,omitempty"`
+	Content     map[string]MediaTypeObject `json:"content,omitempty"`
+}
+
+type HeaderObject struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaTypeObject struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation is the annotation attached to a route. Request and response
+// bodies are given as zero values of the dto types and turned into schemas
+// by reflection.
+type Operation struct {
+	Method  string
+	Path    string
+	ID      string
+	Summary string
+	Tags    []string
+	Auth    bool
+	// OptionalAuth marks routes that accept a bearer token but also serve
+	// anonymous callers.
+	OptionalAuth bool
+	Params       []Param
+	Request      any
+	// RequestContentTypes overrides the default application/json request
+	// media type.
+	RequestContentTypes []string
+	// RequestByType gives some of the RequestContentTypes a body of their
+	// own instead of Request.
+	RequestByType map[string]any
+	Responses     []Response
+}
+
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Type        string
+	Multi       bool
+}
+
+type Response struct {
+	Status      int
+	Description string
+	Body        any
+	Headers     map[string]string
+}
+
+func PathParam(name, description string) Param {
+	return Param{Name: name, In: "path", Description: description, Required: true, Type: "string"}
+}
+
+func QueryParam(name, typ, description string) Param {
+	return Param{Name: name, In: "query", Description: description, Type: typ}
+}
+
+func HeaderParam(name, description string) Param {
+	return Param{Name: name, In: "header", Description: description, Type: "string"}
+}
+
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		schemas: newSchemaRegistry(),
+	}
+}
+
+// NormalizePath strips the trailing slash chi leaves on mounted index routes
+// so "/products/" and "/products" refer to the same spec entry.
+func NormalizePath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// Define fixes the schema used for the type of v, for types from packages
+// that cannot implement SchemaProvider. Call it before Add.
+func (d *Document) Define(v any, schema *Schema) {
+	d.schemas.defined[reflect.TypeOf(v)] = schema
+}
+
+func (d *Document) Add(ops ...Operation) {
+	for _, op := range ops {
+		d.add(op)
+	}
+	d.Components.Schemas = d.schemas.definitions
+}
+
+func (d *Document) add(op Operation) {
+	path := NormalizePath(op.Path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+
+	o := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Responses:   map[string]ResponseObject{},
+	}
+	switch {
+	case op.Auth:
+		o.Security = []map[string][]string{{"bearerAuth": {}}}
+	case op.OptionalAuth:
+		o.Security = []map[string][]string{{"bearerAuth": {}}, {}}
+	}
+	for _, p := range op.Params {
+		schema := &Schema{Type: p.Type}
+		if p.Multi {
+			schema = &Schema{Type: "array", Items: &Schema{Type: p.Type}}
+		}
+		o.Parameters = append(o.Parameters, ParameterObject{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required,
+			Schema:      schema,
+		})
+	}
+	if op.Request != nil {
+		types := op.RequestContentTypes
+		if len(types) == 0 {
+			types = []string{"application/json"}
+		}
+		body := &RequestBodyObject{Required: true, Content: map[string]MediaTypeObject{}}
+		for _, ct := range types {
+			request := op.Request
+			if own, ok := op.RequestByType[ct]; ok {
+				request = own
+			}
+			body.Content[ct] = MediaTypeObject{Schema: d.schemas.schemaFor(request)}
+		}
+		o.RequestBody = body
+	}
+	for _, resp := range op.Responses {
+		r := ResponseObject{Description: resp.Description}
+		if r.Description == "" {
+			r.Description = http.StatusText(resp.Status)
+		}
+		if resp.Body != nil {
+			r.Content = map[string]MediaTypeObject{
+				"application/json": {Schema: d.schemas.schemaFor(resp.Body)},
+			}
+		} else if resp.Status >= 400 {
+			r.Content = map[string]MediaTypeObject{
+				"text/plain": {Schema: &Schema{Type: "string"}},
+			}
+		}
+		if len(resp.Headers) > 0 {
+			r.Headers = map[string]HeaderObject{}
+			for name, desc := range resp.Headers {
+				r.Headers[name] = HeaderObject{Description: desc, Schema: &Schema{Type: "string"}}
+			}
+		}
+		o.Responses[strconv.Itoa(resp.Status)] = r
+	}
+
+	item[strings.ToLower(op.Method)] = o
+}
+
+// Has reports whether the document describes method on path.
+func (d *Document) Has(method, path string) bool {
+	item, ok := d.Paths[NormalizePath(path)]
+	if !ok {
+		return false
+	}
+	_, ok = item[strings.ToLower(method)]
+	return ok
+}
+
+// Routes lists every "METHOD path" pair in the document, sorted.
+func (d *Document) Routes() []string {
+	var routes []string
+	for path, item := range d.Paths {
+		for method := range item {
+			routes = append(routes, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Handler serves the document as JSON.
+func (d *Document) Handler() http.HandlerFunc {
+	body, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// OneOf documents a body that may take any of the given shapes.
+type OneOf []any
+
+// SchemaProvider lets a type describe its own JSON shape when it implements
+// custom marshalling and reflection would get it wrong.
+type SchemaProvider interface {
+	OpenAPISchema() *Schema
+}
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	uuidType           = reflect.TypeOf(uuid.UUID{})
+	rawJSONType        = reflect.TypeOf(json.RawMessage{})
+	schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+)
+
+type schemaRegistry struct {
+	definitions map[string]*Schema
+	defined     map[reflect.Type]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{definitions: map[string]*Schema{}, defined: map[reflect.Type]*Schema{}}
+}
+
+func (s *schemaRegistry) schemaFor(v any) *Schema {
+	if alternatives, ok := v.(OneOf); ok {
+		schema := &Schema{}
+		for _, alt := range alternatives {
+			schema.OneOf = append(schema.OneOf, s.schemaFor(alt))
+		}
+		return schema
+	}
+	return s.schemaOf(reflect.TypeOf(v))
+}
+
+func (s *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	if schema, ok := s.defined[t]; ok {
+		s.definitions[t.Name()] = schema
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	if t.Implements(schemaProviderType) {
+		return reflect.Zero(t).Interface().(SchemaProvider).OpenAPISchema()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := s.schemaOf(t.Elem())
+		if inner.Ref != "" {
+			return inner
+		}
+		nullable := *inner
+		if typ, ok := inner.Type.(string); ok {
+			nullable.Type = []string{typ, "null"}
+		}
+		return &nullable
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		return s.structRef(t)
+	}
+	return &Schema{}
+}
+
+func (s *schemaRegistry) structRef(t reflect.Type) *Schema {
+	name := t.Name()
+	if name == "" {
+		return s.structSchema(t)
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := s.definitions[name]; ok {
+		return ref
+	}
+	// Reserve the name first so recursive types terminate.
+	s.definitions[name] = &Schema{}
+	s.definitions[name] = s.structSchema(t)
+	return ref
+}
+
+func (s *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitempty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			embedded := s.structSchema(field.Type)
+			for k, v := range embedded.Properties {
+				schema.Properties[k] = v
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		schema.Properties[name] = s.schemaOf(field.Type)
+		if !omitempty && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+func jsonName(field reflect.StructField) (name string, omitempty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
@@ -0,0 +1,99 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	doc := `{"name":"Mouse","price":{"amount":"9.90","currency":"BRL"},"tags":["a","b"]}`
+
+	t.Run("should replace, merge and remove members", func(t *testing.T) {
+		patched, err := MergePatch([]byte(doc), []byte(`{"name":"Trackball","price":{"amount":"12.00"},"tags":null}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Trackball","price":{"amount":"12.00","currency":"BRL"}}`, string(patched))
+	})
+
+	t.Run("should replace arrays whole", func(t *testing.T) {
+		patched, err := MergePatch([]byte(doc), []byte(`{"tags":["c"]}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Mouse","price":{"amount":"9.90","currency":"BRL"},"tags":["c"]}`, string(patched))
+	})
+
+	t.Run("should reject malformed patches", func(t *testing.T) {
+		_, err := MergePatch([]byte(doc), []byte(`{"name":`))
+		assert.ErrorIs(t, err, ErrInvalidPatch)
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	doc := `{"name":"Mouse","weight":120,"tags":["a","b"],"dims":{"l":1}}`
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"add member", `[{"op":"add","path":"/description","value":"wireless"}]`,
+			`{"name":"Mouse","weight":120,"tags":["a","b"],"dims":{"l":1},"description":"wireless"}`},
+		{"insert and append to arrays", `[{"op":"add","path":"/tags/0","value":"z"},{"op":"add","path":"/tags/-","value":"c"}]`,
+			`{"name":"Mouse","weight":120,"tags":["z","a","b","c"],"dims":{"l":1}}`},
+		{"replace and remove", `[{"op":"replace","path":"/name","value":"Trackball"},{"op":"remove","path":"/tags/0"}]`,
+			`{"name":"Trackball","weight":120,"tags":["b"],"dims":{"l":1}}`},
+		{"move and copy", `[{"op":"copy","from":"/dims","path":"/box"},{"op":"move","from":"/weight","path":"/dims/w"}]`,
+			`{"name":"Mouse","tags":["a","b"],"dims":{"l":1,"w":120},"box":{"l":1}}`},
+		{"test numbers by value", `[{"op":"test","path":"/weight","value":120.0},{"op":"replace","path":"/weight","value":90}]`,
+			`{"name":"Mouse","weight":90,"tags":["a","b"],"dims":{"l":1}}`},
+		{"escape pointer tokens", `[{"op":"add","path":"/a~1b~0c","value":null}]`,
+			`{"name":"Mouse","weight":120,"tags":["a","b"],"dims":{"l":1},"a/b~c":null}`},
+	}
+	for _, tt := range tests {
+		t.Run("should "+tt.name, func(t *testing.T) {
+			patched, err := JSONPatch([]byte(doc), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(patched))
+		})
+	}
+
+	t.Run("should fail on a mismatched test", func(t *testing.T) {
+		_, err := JSONPatch([]byte(doc), []byte(`[{"op":"test","path":"/name","value":"Keyboard"}]`))
+		assert.ErrorIs(t, err, ErrTestFailed)
+	})
+
+	invalid := []string{
+		`{"op":"add"}`,
+		`[{"op":"replace","path":"/missing","value":1}]`,
+		`[{"op":"remove","path":"/tags/2"}]`,
+		`[{"op":"add","path":"/tags/01","value":1}]`,
+		`[{"op":"add","path":"name","value":1}]`,
+		`[{"op":"add","path":"/name"}]`,
+		`[{"op":"move","from":"/dims","path":"/dims/inner"}]`,
+		`[{"op":"remove","path":""}]`,
+		`[{"op":"frobnicate","path":"/name"}]`,
+	}
+	for _, p := range invalid {
+		t.Run("should reject "+p, func(t *testing.T) {
+			_, err := JSONPatch([]byte(doc), []byte(p))
+			assert.ErrorIs(t, err, ErrInvalidPatch)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Run("should pick the format from the media type", func(t *testing.T) {
+		patched, err := Apply(MergePatchType, []byte(`{"a":1}`), []byte(`{"a":2}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":2}`, string(patched))
+
+		patched, err = Apply(JSONPatchType, []byte(`{"a":1}`), []byte(`[{"op":"remove","path":"/a"}]`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(patched))
+	})
+
+	t.Run("should reject other media types", func(t *testing.T) {
+		_, err := Apply("application/json", []byte(`{}`), []byte(`{}`))
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+}
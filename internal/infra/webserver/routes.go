@@ -1,8 +1,15 @@
 package webserver
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github/GuilhermeHermes/GO_API/configs"
+	"github/GuilhermeHermes/GO_API/internal/entity"
 	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/internal/infra/payment"
+	"github/GuilhermeHermes/GO_API/internal/infra/shipping"
 	"github/GuilhermeHermes/GO_API/internal/infra/webserver/handlers"
 
 	"github.com/go-chi/chi/v5"
@@ -11,6 +18,31 @@ import (
 	"gorm.io/gorm"
 )
 
+// Options carries the settings the router needs besides the database, so
+// it can be built without reading the .env file (e.g. in tests).
+type Options struct {
+	TokenAuth     *jwtauth.JWTAuth
+	JwtExpiration int64
+	// ReservationTTL is how long stock reservations last by default;
+	// zero uses handlers.DefaultReservationTTL.
+	ReservationTTL time.Duration
+	// PaymentProvider is the payment gateway. It is required: without it
+	// there is no webhook secret to check gateway notifications against.
+	PaymentProvider payment.PaymentProvider
+	// Tax holds the store-wide tax rules; the zero value taxes prices on
+	// top, rounding half up, with no default location.
+	Tax entity.TaxSettings
+	// ShippingProvider quotes shipping; nil uses a
+	// shipping.TableRateProvider over the stored zones.
+	ShippingProvider shipping.ShippingRateProvider
+	// ReviewsVerifiedOnly only lets users who bought a product review it.
+	ReviewsVerifiedOnly bool
+	// RequireIfMatch rejects PUT, PATCH and DELETE on products and users
+	// without an If-Match header; otherwise the header is only checked when
+	// sent.
+	RequireIfMatch bool
+}
+
 func SetupRoutes(db *gorm.DB) *chi.Mux {
 
 	cfg, err := configs.LoadConfig(".")
@@ -18,6 +50,55 @@ func SetupRoutes(db *gorm.DB) *chi.Mux {
 		panic(err)
 	}
 
+	provider, err := newPaymentProvider(cfg.PaymentProvider, cfg.PaymentWebhookSecret)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewRouter(db, Options{
+		TokenAuth:           cfg.TokenAuth,
+		JwtExpiration:       cfg.JwtExpiration,
+		ReservationTTL:      time.Duration(cfg.ReservationTTL) * time.Second,
+		PaymentProvider:     provider,
+		Tax:                 newTaxSettings(cfg.TaxPricesIncludeTax, cfg.TaxRounding, cfg.TaxDefaultCountry, cfg.TaxDefaultRegion),
+		ReviewsVerifiedOnly: cfg.ReviewsVerifiedOnly,
+		RequireIfMatch:      cfg.RequireIfMatch,
+	})
+}
+
+// newTaxSettings validates the TAX_* settings.
+func newTaxSettings(pricesIncludeTax bool, rounding, country, region string) entity.TaxSettings {
+	mode, err := entity.ParseRoundingMode(rounding)
+	if err != nil {
+		panic(err)
+	}
+	loc, err := entity.NewTaxLocation(country, region)
+	if err != nil {
+		panic(fmt.Sprintf("default tax location: %v", err))
+	}
+	return entity.TaxSettings{PricesIncludeTax: pricesIncludeTax, Rounding: mode, DefaultLocation: loc}
+}
+
+// newPaymentProvider picks the gateway adapter named in PAYMENT_PROVIDER.
+// Without PAYMENT_WEBHOOK_SECRET anyone could forge webhooks, so the
+// server refuses to start.
+func newPaymentProvider(name, webhookSecret string) (payment.PaymentProvider, error) {
+	if webhookSecret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET is required")
+	}
+	switch name {
+	case "", "fake":
+		return payment.NewFakeProvider(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+}
+
+func NewRouter(db *gorm.DB, opts Options) *chi.Mux {
+	if opts.PaymentProvider == nil {
+		panic("webserver: Options.PaymentProvider is required")
+	}
+
 	r := chi.NewRouter()
 
 	// Middlewares
@@ -28,28 +109,294 @@ func SetupRoutes(db *gorm.DB) *chi.Mux {
 	// Repositories
 	productRepo := database.NewProductRepository(db)
 	userRepo := database.NewUserRepository(db)
+	addressRepo := database.NewAddressRepository(db)
+	priceRepo := database.NewPriceListRepository(db)
+	rateRepo := database.NewExchangeRateRepository(db)
+	categoryRepo := database.NewCategoryRepository(db)
+	variantRepo := database.NewVariantRepository(db)
+	inventoryRepo := database.NewInventoryRepository(db)
+	cartRepo := database.NewCartRepository(db)
+	orderRepo := database.NewOrderRepository(db)
+	paymentRepo := database.NewPaymentRepository(db)
+	couponRepo := database.NewCouponRepository(db)
+	promotionRepo := database.NewPromotionRepository(db)
+	taxRateRepo := database.NewTaxRateRepository(db)
+	shippingRepo := database.NewShippingRepository(db)
+	reviewRepo := database.NewReviewRepository(db)
+	wishlistRepo := database.NewWishlistRepository(db)
+	productSearcher, err := database.NewProductSearcher(db)
+	if err != nil {
+		panic(err)
+	}
+	if opts.ShippingProvider == nil {
+		opts.ShippingProvider = shipping.NewTableRateProvider(shippingRepo)
+	}
 
 	// Handlers
-	productHandler := handlers.NewProductHandler(productRepo)
-	userHandler := handlers.NewUserHandler(userRepo, cfg.TokenAuth, cfg.JwtExpiration)
+	productHandler := handlers.NewProductHandler(productRepo, priceRepo, rateRepo, categoryRepo, reviewRepo)
+	priceHandler := handlers.NewPriceHandler(productRepo, priceRepo, rateRepo)
+	categoryHandler := handlers.NewCategoryHandler(categoryRepo, productRepo)
+	variantHandler := handlers.NewVariantHandler(productRepo, variantRepo)
+	inventoryHandler := handlers.NewInventoryHandler(productRepo, variantRepo, inventoryRepo, opts.ReservationTTL)
+	pricer := handlers.NewDiscountPricer(couponRepo, promotionRepo, categoryRepo)
+	taxCalculator := handlers.NewTaxCalculator(taxRateRepo, opts.Tax)
+	cartHandler := handlers.NewCartHandler(cartRepo, productRepo, variantRepo, pricer, taxCalculator, opts.ShippingProvider)
+	orderHandler := handlers.NewOrderHandler(orderRepo, cartRepo, productRepo, variantRepo, pricer, taxCalculator, opts.ShippingProvider, addressRepo, paymentRepo)
+	taxHandler := handlers.NewTaxHandler(taxCalculator, productRepo, variantRepo)
+	shippingHandler := handlers.NewShippingHandler(shippingRepo)
+	discountHandler := handlers.NewDiscountHandler(couponRepo, promotionRepo, productRepo, categoryRepo)
+	paymentHandler := handlers.NewPaymentHandler(paymentRepo, orderRepo, opts.PaymentProvider)
+	userHandler := handlers.NewUserHandler(userRepo, cartRepo, opts.TokenAuth, opts.JwtExpiration)
+	addressHandler := handlers.NewAddressHandler(addressRepo)
+	searchHandler := handlers.NewProductSearchHandler(productSearcher)
+	reviewHandler := handlers.NewReviewHandler(reviewRepo, productRepo, opts.ReviewsVerifiedOnly)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistRepo, productRepo)
+
+	// Writes to versioned resources, optionally only with If-Match
+	conditional := chi.Middlewares{}
+	if opts.RequireIfMatch {
+		conditional = append(conditional, handlers.RequireIfMatch)
+	}
+
+	// API documentation
+	spec := APISpec()
+	r.Get("/openapi.json", spec.Handler()) // GET /openapi.json
+	r.Get("/docs", docsHandler)            // GET /docs (Swagger UI)
 
 	r.Route("/products", func(r chi.Router) {
-		r.Use(jwtauth.Verifier(cfg.TokenAuth))
-		r.Use(jwtauth.Authenticator)                    // Middleware to protect routes with JWT authentication
-		r.Post("/", productHandler.CreateProduct)       // POST /products
-		r.Get("/", productHandler.GetAllProducts)       // GET /products?page=1&limit=10&sort=asc
-		r.Get("/{id}", productHandler.GetProduct)       // GET /products/{id}
-		r.Put("/{id}", productHandler.UpdateProduct)    // PUT /products/{id}
-		r.Delete("/{id}", productHandler.DeleteProduct) // DELETE /products/{id}
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)                      // Middleware to protect routes with JWT authentication
+		r.Get("/", productHandler.GetAllProducts)         // GET /products?page=1&limit=10&sort=asc
+		r.Get("/search", searchHandler.SearchProducts)    // GET /products/search?q=term
+		r.Get("/facets", productHandler.GetProductFacets) // GET /products/facets?tag=a
+		r.Get("/{id}", productHandler.GetProduct)         // GET /products/{id}
+
+		r.Group(func(r chi.Router) {
+			r.Use(handlers.RequireRole(entity.ProductEditorRoles...))
+			r.Post("/", productHandler.CreateProduct)                // POST /products (editors)
+			r.Get("/{id}/history", productHandler.GetProductHistory) // GET /products/{id}/history (editors)
+			r.With(conditional...).
+				Put("/{id}", productHandler.UpdateProduct) // PUT /products/{id} (editors)
+			r.With(conditional...).
+				Patch("/{id}", productHandler.PatchProduct) // PATCH /products/{id} (merge or JSON patch; editors)
+			r.With(conditional...).
+				Delete("/{id}", productHandler.DeleteProduct) // DELETE /products/{id} (moves it to the trash; editors)
+			r.With(conditional...).
+				Post("/{id}/history/{revision}/revert", productHandler.RevertProduct) // POST /products/{id}/history/3/revert (editors)
+			r.With(conditional...).
+				Post("/{id}/transitions", productHandler.TransitionProduct) // POST /products/{id}/transitions (editors)
+			r.With(conditional...).
+				Put("/{id}/schedule", productHandler.ScheduleProduct) // PUT /products/{id}/schedule (editors)
+
+			r.Put("/{id}/prices/{currency}", priceHandler.SetProductPrice)       // PUT /products/{id}/prices/USD (editors)
+			r.Delete("/{id}/prices/{currency}", priceHandler.DeleteProductPrice) // DELETE /products/{id}/prices/USD (editors)
+			r.Put("/{id}/categories", categoryHandler.SetProductCategories)      // PUT /products/{id}/categories (editors)
+			r.Put("/{id}/options", variantHandler.SetProductOptions)             // PUT /products/{id}/options (editors)
+			r.Post("/{id}/variants", variantHandler.CreateVariant)               // POST /products/{id}/variants (editors)
+			r.Put("/{id}/variants/{variantID}", variantHandler.UpdateVariant)    // PUT /products/{id}/variants/{variantID} (editors)
+			r.Delete("/{id}/variants/{variantID}", variantHandler.DeleteVariant) // DELETE /products/{id}/variants/{variantID} (editors)
+		})
+
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Get("/trash", productHandler.GetTrash) // GET /products/trash (admin)
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/{id}/restore", productHandler.RestoreProduct) // POST /products/{id}/restore (admin)
+
+		r.Get("/{id}/prices", priceHandler.GetProductPrices)           // GET /products/{id}/prices
+		r.Get("/{id}/variants", variantHandler.GetProductVariants)     // GET /products/{id}/variants
+		r.Get("/{id}/variants/{variantID}", variantHandler.GetVariant) // GET /products/{id}/variants/{variantID}
+
+		r.Get("/{id}/reviews", reviewHandler.GetProductReviews) // GET /products/{id}/reviews
+		r.Post("/{id}/reviews", reviewHandler.CreateReview)     // POST /products/{id}/reviews
+	})
+
+	r.Route("/reviews", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Get("/", reviewHandler.GetReviews) // GET /reviews?status=pending (admin)
+		r.Put("/{id}", reviewHandler.UpdateReview)    // PUT /reviews/{id} (author)
+		r.Delete("/{id}", reviewHandler.DeleteReview) // DELETE /reviews/{id} (author or admin)
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/{id}/moderation", reviewHandler.ModerateReview) // POST /reviews/{id}/moderation (admin)
+	})
+
+	r.Route("/skus", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Get("/{sku}", variantHandler.GetSKU)           // GET /skus/{sku}
+		r.Get("/{sku}/stock", inventoryHandler.GetStock) // GET /skus/{sku}/stock
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/{sku}/stock/adjustments", inventoryHandler.AdjustStock) // POST /skus/{sku}/stock/adjustments (admin)
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Get("/{sku}/stock/movements", inventoryHandler.GetStockMovements) // GET /skus/{sku}/stock/movements (admin)
+	})
+
+	r.Route("/warehouses", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Get("/", inventoryHandler.GetWarehouses) // GET /warehouses
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/", inventoryHandler.CreateWarehouse) // POST /warehouses (admin)
+	})
+
+	r.Route("/reservations", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Post("/", inventoryHandler.CreateReservation) // POST /reservations
+		r.Get("/{id}", inventoryHandler.GetReservation) // GET /reservations/{id} (owner or admin)
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/{id}/commit", inventoryHandler.CommitReservation) // POST /reservations/{id}/commit (admin)
+		r.Post("/{id}/release", inventoryHandler.ReleaseReservation) // POST /reservations/{id}/release (owner or admin)
+	})
+
+	r.Route("/categories", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Get("/", categoryHandler.GetCategoryTree)                 // GET /categories
+		r.Get("/{id}", categoryHandler.GetCategory)                 // GET /categories/{id or slug}
+		r.Get("/{id}/products", productHandler.GetCategoryProducts) // GET /categories/{id}/products?include_descendants=true
+
+		r.Group(func(r chi.Router) {
+			r.Use(handlers.RequireRole(entity.ProductEditorRoles...))
+			r.Post("/", categoryHandler.CreateCategory)       // POST /categories (editors)
+			r.Put("/{id}", categoryHandler.UpdateCategory)    // PUT /categories/{id} (editors)
+			r.Delete("/{id}", categoryHandler.DeleteCategory) // DELETE /categories/{id} (editors)
+		})
+	})
+
+	r.Route("/exchange-rates", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Get("/", priceHandler.GetExchangeRates) // GET /exchange-rates
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Put("/", priceHandler.UpdateExchangeRates) // PUT /exchange-rates (admin)
+	})
+
+	r.Route("/tax", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Get("/rates", taxHandler.GetTaxRates) // GET /tax/rates
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Put("/rates", taxHandler.UpdateTaxRates) // PUT /tax/rates (admin)
+		r.Post("/quote", taxHandler.QuoteTax) // POST /tax/quote
+	})
+
+	// Guests use the cart with X-Cart-Token instead of a JWT
+	r.Route("/cart", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(handlers.OptionalAuth)
+		r.Get("/", cartHandler.GetCart)                         // GET /cart?country=BR&region=SP
+		r.Delete("/", cartHandler.ClearCart)                    // DELETE /cart
+		r.Post("/items", cartHandler.AddCartItem)               // POST /cart/items
+		r.Put("/items/{itemID}", cartHandler.UpdateCartItem)    // PUT /cart/items/{itemID}
+		r.Delete("/items/{itemID}", cartHandler.RemoveCartItem) // DELETE /cart/items/{itemID}
+		r.Put("/coupon", cartHandler.ApplyCoupon)               // PUT /cart/coupon
+		r.Delete("/coupon", cartHandler.RemoveCoupon)           // DELETE /cart/coupon
+		r.Get("/shipping-rates", cartHandler.QuoteShipping)     // GET /cart/shipping-rates?country=BR&postal_code=01310-100
+	})
+
+	r.Route("/shipping/zones", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Use(handlers.RequireRole(entity.RoleAdmin))
+		r.Post("/", shippingHandler.CreateZone)                            // POST /shipping/zones (admin)
+		r.Get("/", shippingHandler.GetZones)                               // GET /shipping/zones (admin)
+		r.Get("/{id}", shippingHandler.GetZone)                            // GET /shipping/zones/{id} (admin)
+		r.Delete("/{id}", shippingHandler.DeleteZone)                      // DELETE /shipping/zones/{id} (admin)
+		r.Post("/{id}/methods", shippingHandler.CreateMethod)              // POST /shipping/zones/{id}/methods (admin)
+		r.Delete("/{id}/methods/{methodID}", shippingHandler.DeleteMethod) // DELETE /shipping/zones/{id}/methods/{methodID} (admin)
+	})
+
+	r.Route("/orders", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Post("/", orderHandler.CreateOrder) // POST /orders
+		r.Get("/", orderHandler.GetOrders)    // GET /orders?status=paid (admins: ?all=true)
+		r.Get("/{id}", orderHandler.GetOrder) // GET /orders/{id}
+		r.With(handlers.RequireRole(entity.RoleAdmin)).
+			Post("/{id}/transitions", orderHandler.TransitionOrder) // POST /orders/{id}/transitions (admin)
+		r.Post("/{id}/payments", paymentHandler.CreatePayment)   // POST /orders/{id}/payments
+		r.Get("/{id}/payments", paymentHandler.GetOrderPayments) // GET /orders/{id}/payments
+	})
+
+	r.Route("/coupons", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Use(handlers.RequireRole(entity.RoleAdmin))
+		r.Post("/", discountHandler.CreateCoupon)         // POST /coupons (admin)
+		r.Get("/", discountHandler.GetCoupons)            // GET /coupons (admin)
+		r.Get("/{code}", discountHandler.GetCoupon)       // GET /coupons/{code} (admin)
+		r.Delete("/{code}", discountHandler.DeleteCoupon) // DELETE /coupons/{code} (admin)
+	})
+
+	r.Route("/promotions", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(opts.TokenAuth))
+		r.Use(jwtauth.Authenticator)
+		r.Use(handlers.RequireRole(entity.RoleAdmin))
+		r.Post("/", discountHandler.CreatePromotion)       // POST /promotions (admin)
+		r.Get("/", discountHandler.GetPromotions)          // GET /promotions (admin)
+		r.Delete("/{id}", discountHandler.DeletePromotion) // DELETE /promotions/{id} (admin)
+	})
+
+	r.Route("/payments", func(r chi.Router) {
+		// The gateway signs webhooks instead of sending a JWT
+		r.Post("/webhook", paymentHandler.PaymentWebhook) // POST /payments/webhook
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(opts.TokenAuth))
+			r.Use(jwtauth.Authenticator)
+			r.Use(handlers.RequireRole(entity.RoleAdmin))
+			r.Post("/{id}/capture", paymentHandler.CapturePayment) // POST /payments/{id}/capture (admin)
+			r.Post("/{id}/refund", paymentHandler.RefundPayment)   // POST /payments/{id}/refund (admin)
+		})
+	})
+
+	r.Route("/wishlists", func(r chi.Router) {
+		// Anyone with the link can read a shared wishlist
+		r.Get("/shared/{token}", wishlistHandler.GetSharedWishlist) // GET /wishlists/shared/{token}
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(opts.TokenAuth))
+			r.Use(jwtauth.Authenticator)
+			r.Get("/", wishlistHandler.GetWishlists)                                // GET /wishlists
+			r.Post("/", wishlistHandler.CreateWishlist)                             // POST /wishlists
+			r.Get("/price-drops", wishlistHandler.GetPriceDrops)                    // GET /wishlists/price-drops
+			r.Get("/{id}", wishlistHandler.GetWishlist)                             // GET /wishlists/{id}
+			r.Put("/{id}", wishlistHandler.RenameWishlist)                          // PUT /wishlists/{id}
+			r.Delete("/{id}", wishlistHandler.DeleteWishlist)                       // DELETE /wishlists/{id}
+			r.Post("/{id}/items", wishlistHandler.AddWishlistItem)                  // POST /wishlists/{id}/items
+			r.Delete("/{id}/items/{productID}", wishlistHandler.RemoveWishlistItem) // DELETE /wishlists/{id}/items/{productID}
+			r.Put("/{id}/share", wishlistHandler.ShareWishlist)                     // PUT /wishlists/{id}/share
+			r.Delete("/{id}/share", wishlistHandler.UnshareWishlist)                // DELETE /wishlists/{id}/share
+		})
 	})
 
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/", userHandler.CreateUser)                 // POST /users
 		r.Get("/email/{email}", userHandler.GetUserByEmail) // GET /users/email/{email}
 		r.Get("/{id}", userHandler.GetUserByID)             // GET /users/{id}
-		r.Put("/{id}", userHandler.UpdateUser)
-		r.Delete("/{id}", userHandler.DeleteUser)
-		r.Post("/generate-jwt", userHandler.GetJwt) // POST /users/generate-jwt
+		r.Post("/generate-jwt", userHandler.GetJwt)         // POST /users/generate-jwt
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(opts.TokenAuth))
+			r.Use(jwtauth.Authenticator)
+			r.With(conditional...).Put("/{id}", userHandler.UpdateUser)    // PUT /users/{id} (self or admin)
+			r.With(conditional...).Patch("/{id}", userHandler.PatchUser)   // PATCH /users/{id} (merge or JSON patch; self or admin)
+			r.With(conditional...).Delete("/{id}", userHandler.DeleteUser) // DELETE /users/{id} (self or admin)
+			r.With(handlers.RequireRole(entity.RoleAdmin)).With(conditional...).
+				Put("/{id}/role", userHandler.SetUserRole) // PUT /users/{id}/role (admin)
+		})
+
+		r.Route("/me/addresses", func(r chi.Router) {
+			r.Use(jwtauth.Verifier(opts.TokenAuth))
+			r.Use(jwtauth.Authenticator)
+			r.Get("/", addressHandler.GetAddresses)         // GET /users/me/addresses
+			r.Post("/", addressHandler.CreateAddress)       // POST /users/me/addresses
+			r.Get("/{id}", addressHandler.GetAddress)       // GET /users/me/addresses/{id}
+			r.Put("/{id}", addressHandler.UpdateAddress)    // PUT /users/me/addresses/{id}
+			r.Delete("/{id}", addressHandler.DeleteAddress) // DELETE /users/me/addresses/{id}
+		})
 	})
 
 	return r
@@ -0,0 +1,26 @@
+package webserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPaymentProvider(t *testing.T) {
+	t.Run("should refuse to start without a webhook secret", func(t *testing.T) {
+		_, err := newPaymentProvider("fake", "")
+		assert.ErrorContains(t, err, "PAYMENT_WEBHOOK_SECRET")
+	})
+
+	t.Run("should refuse unknown providers", func(t *testing.T) {
+		_, err := newPaymentProvider("acme", "secret")
+		assert.Error(t, err)
+	})
+
+	t.Run("should default to the fake provider", func(t *testing.T) {
+		provider, err := newPaymentProvider("", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "fake", provider.Name())
+	})
+}
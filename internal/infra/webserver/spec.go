@@ -0,0 +1,1321 @@
+package webserver
+
+import (
+	"net/http"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/openapi"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/patch"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+var (
+	idParam       = openapi.PathParam("id", "Resource ID (UUID)")
+	errBadRequest = openapi.Response{Status: http.StatusBadRequest}
+	errUnauthz    = openapi.Response{Status: http.StatusUnauthorized}
+	errForbidden  = openapi.Response{Status: http.StatusForbidden}
+	errNotFound   = openapi.Response{Status: http.StatusNotFound}
+	errInternal   = openapi.Response{Status: http.StatusInternalServerError}
+
+	ifMatchParam = openapi.HeaderParam("If-Match",
+		"ETag from a previous read; the write fails with 412 if the resource changed since. Required when the server sets REQUIRE_IF_MATCH")
+	errStale     = openapi.Response{Status: http.StatusPreconditionFailed, Description: "The resource changed since the If-Match version"}
+	errNoIfMatch = openapi.Response{Status: http.StatusPreconditionRequired, Description: "If-Match is required and was not sent"}
+	etagHeader   = map[string]string{"ETag": "Current version of the resource, for If-Match"}
+
+	patchContentTypes = []string{patch.MergePatchType, patch.JSONPatchType}
+	errTestFailed     = openapi.Response{Status: http.StatusConflict, Description: "A JSON Patch test operation did not match"}
+	errNoPatchType    = openapi.Response{Status: http.StatusUnsupportedMediaType, Description: "The body is neither a merge patch nor a JSON patch"}
+
+	currencyParams = []openapi.Param{
+		openapi.QueryParam("currency", "string", "ISO 4217 code to price products in; wins over Accept-Currency"),
+		openapi.HeaderParam("Accept-Currency", "Preferred currencies, e.g. \"USD, EUR;q=0.8\"; unsupported codes are ignored"),
+	}
+	errNoCurrency = openapi.Response{
+		Status:      http.StatusNotAcceptable,
+		Description: "No price-list entry and no exchange rate for the requested currency",
+	}
+)
+
+// APISpec describes every route registered by NewRouter. Adding a route
+// without an entry here makes TestAPISpecCoversAllRoutes fail.
+func APISpec() *openapi.Document {
+	doc := openapi.NewDocument("GO_API", "1.0.0")
+	doc.Define(entity.Money{}, &openapi.Schema{
+		Type:        "object",
+		Description: "Exact amount; a bare amount is read in " + entity.DefaultCurrency,
+		Properties: map[string]*openapi.Schema{
+			"amount":   {Type: "string", Description: "Decimal with the currency's minor-unit digits"},
+			"currency": {Type: "string", Description: "ISO 4217 code"},
+		},
+		Required: []string{"amount", "currency"},
+	})
+
+	doc.Add(productOperations()...)
+	doc.Add(priceOperations()...)
+	doc.Add(categoryOperations()...)
+	doc.Add(variantOperations()...)
+	doc.Add(inventoryOperations()...)
+	doc.Add(cartOperations()...)
+	doc.Add(orderOperations()...)
+	doc.Add(paymentOperations()...)
+	doc.Add(discountOperations()...)
+	doc.Add(taxOperations()...)
+	doc.Add(shippingOperations()...)
+	doc.Add(userOperations()...)
+	doc.Add(addressOperations()...)
+	doc.Add(reviewOperations()...)
+	doc.Add(wishlistOperations()...)
+
+	return doc
+}
+
+func productOperations() []openapi.Operation {
+	tags := []string{"products"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/products", ID: "createProduct",
+			Summary: "Create a product (editors)", Tags: tags, Auth: true,
+			Request: dto.CreateProductRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products", ID: "listProducts",
+			Summary: "List products with filters and sorting", Tags: tags, Auth: true,
+			Params:    productListParams(),
+			Responses: productListResponses(),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/facets", ID: "getProductFacets",
+			Summary: "Count matching products by tag, category and price bucket", Tags: tags, Auth: true,
+			Params: append(productFilterParams(),
+				openapi.QueryParam("price_buckets", "string",
+					"Comma separated increasing bucket bounds in price_currency (default 50,100,200,500,1000)")),
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductFacetsResponse{}},
+				errBadRequest, errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/search", ID: "searchProducts",
+			Summary: "Full-text search over the names and descriptions of published products, ranked by relevance",
+			Tags:    tags, Auth: true,
+			Params: []openapi.Param{
+				{Name: "q", In: "query", Required: true, Type: "string",
+					Description: "Search terms; every term matches as a word prefix"},
+				openapi.QueryParam("page", "integer", "Page number, starting at 1"),
+				openapi.QueryParam("limit", "integer", "Page size (max 100)"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ProductSearchResult{}},
+				errBadRequest, errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}", ID: "getProduct",
+			Summary: "Get a product by ID, with the rating of its approved reviews; unpublished products are only found by editors",
+			Tags:    tags, Auth: true,
+			Params: append([]openapi.Param{idParam,
+				openapi.QueryParam("as_of", "string",
+					"RFC 3339 timestamp; returns the product as it was then, from its history, without rating or ETag"),
+			}, currencyParams...),
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errNotFound, errNoCurrency,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}", ID: "updateProduct",
+			Summary: "Replace a product's name and price (editors)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam, ifMatchParam},
+			Request: dto.CreateProductRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/products/{id}", ID: "patchProduct",
+			Summary: "Change some of a product's fields with a JSON Merge Patch or JSON Patch (editors)", Tags: tags, Auth: true,
+			Params:              []openapi.Param{idParam, ifMatchParam},
+			Request:             dto.UpdateProductRequest{},
+			RequestContentTypes: patchContentTypes,
+			RequestByType:       map[string]any{patch.JSONPatchType: []patch.Operation{}},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errTestFailed, errStale, errNoIfMatch, errNoPatchType, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/products/{id}", ID: "deleteProduct",
+			Summary: "Move a product to the trash (editors)", Tags: tags, Auth: true,
+			Params: []openapi.Param{idParam, ifMatchParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/trash", ID: "listTrash",
+			Summary: "List deleted products, most recently deleted first (admin)", Tags: tags, Auth: true,
+			Params: append([]openapi.Param{
+				openapi.QueryParam("page", "integer", "Page number (default 1)"),
+				openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+			}, currencyParams...),
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ProductResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/products/{id}/restore", ID: "restoreProduct",
+			Summary: "Take a product out of the trash (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{idParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}},
+				errBadRequest, errUnauthz, errForbidden,
+				{Status: http.StatusNotFound, Description: "Product not in the trash"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/products/{id}/transitions", ID: "transitionProduct",
+			Summary: "Move a product to draft, published or archived (editors)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam, ifMatchParam},
+			Request: dto.ProductTransitionRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound,
+				{Status: http.StatusConflict, Description: "The product's status cannot move to the one requested"},
+				errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}/schedule", ID: "scheduleProduct",
+			Summary: "Set when a draft is published and a published product archived by the scheduler (editors)",
+			Tags:    tags, Auth: true,
+			Params:  []openapi.Param{idParam, ifMatchParam},
+			Request: dto.ProductScheduleRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}/history", ID: "getProductHistory",
+			Summary: "List a product's revisions, newest first, with the fields each changed and who changed them (editors)",
+			Tags:    tags, Auth: true,
+			Params: []openapi.Param{idParam,
+				openapi.QueryParam("page", "integer", "Page number (default 1)"),
+				openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ProductRevisionResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/products/{id}/history/{revision}/revert", ID: "revertProduct",
+			Summary: "Put a product back the way it was at a revision, recorded as a new revision (editors)",
+			Tags:    tags, Auth: true,
+			Params: []openapi.Param{idParam,
+				openapi.PathParam("revision", "Revision number to go back to"), ifMatchParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden,
+				{Status: http.StatusNotFound, Description: "Product or revision not found"},
+				errStale, errNoIfMatch, errInternal,
+			},
+		},
+	}
+}
+
+// productListParams are the filters, sorting and pagination shared by every
+// product listing.
+func productListParams() []openapi.Param {
+	params := []openapi.Param{
+		openapi.QueryParam("page", "integer", "Page number, starting at 1; switches to offset pagination"),
+		openapi.QueryParam("limit", "integer", "Page size (max 100)"),
+		openapi.QueryParam("sort", "string",
+			"Comma separated sort keys (name, price, created_at, updated_at), '-' prefix for descending, e.g. -price,name. price groups products by currency first. asc/desc sort by created_at"),
+	}
+	params = append(params, productFilterParams()...)
+	params = append(params,
+		openapi.QueryParam("cursor", "string",
+			"Switches to cursor pages: empty for the first page, then next_cursor/prev_cursor. Ignored with page"),
+		openapi.QueryParam("include_total", "boolean", "Also count every matching product"),
+	)
+	return append(params, currencyParams...)
+}
+
+// productFilterParams are the filters shared by listings and facet counts.
+func productFilterParams() []openapi.Param {
+	return []openapi.Param{
+		openapi.QueryParam("min_price", "number", "Minimum price, inclusive"),
+		openapi.QueryParam("max_price", "number", "Maximum price, inclusive"),
+		openapi.QueryParam("price_currency", "string", "Currency of min_price/max_price (default BRL)"),
+		openapi.QueryParam("name", "string", "Case-insensitive substring of the name"),
+		openapi.QueryParam("created_after", "string", "RFC 3339 timestamp or YYYY-MM-DD"),
+		openapi.QueryParam("created_before", "string", "RFC 3339 timestamp or YYYY-MM-DD"),
+		openapi.QueryParam("updated_after", "string", "RFC 3339 timestamp or YYYY-MM-DD"),
+		openapi.QueryParam("updated_before", "string", "RFC 3339 timestamp or YYYY-MM-DD"),
+		openapi.QueryParam("ids", "string", "Comma separated product IDs"),
+		openapi.QueryParam("category_id", "string", "Category ID, repeatable; products directly in any of them"),
+		openapi.QueryParam("tag", "string", "Tag, repeatable or comma separated"),
+		openapi.QueryParam("tag_mode", "string", "any (default): at least one tag; all: every tag"),
+		openapi.QueryParam("status", "string",
+			"Comma separated draft, published or archived; editors only, everyone else only sees published products"),
+	}
+}
+
+func productListResponses() []openapi.Response {
+	return []openapi.Response{
+		{
+			Status: http.StatusOK,
+			Description: "By default the offset array, total in X-Total-Count. " +
+				"With cursor: a cursor envelope (sorting by created_at only)",
+			Body: openapi.OneOf{dto.ProductListResponse{}, []dto.ProductResponse{}},
+			Headers: map[string]string{
+				"Link":          "RFC 8288 first/prev/next links",
+				"X-Total-Count": "Matching products (offset mode with include_total=true)",
+			},
+		},
+		errBadRequest, errUnauthz, errNoCurrency, errInternal,
+	}
+}
+
+func priceOperations() []openapi.Operation {
+	currencyParam := openapi.PathParam("currency", "ISO 4217 code")
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/products/{id}/prices", ID: "getProductPrices",
+			Summary: "List a product's base price and explicit prices per currency",
+			Tags:    []string{"products"}, Auth: true,
+			Params: []openapi.Param{idParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductPricesResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}/prices/{currency}", ID: "setProductPrice",
+			Summary: "Set an explicit price in a currency other than the base one (editors)",
+			Tags:    []string{"products"}, Auth: true,
+			Params:  []openapi.Param{idParam, currencyParam},
+			Request: dto.SetProductPriceRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductPricesResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/products/{id}/prices/{currency}", ID: "deleteProductPrice",
+			Summary: "Remove an explicit price; the currency falls back to conversion (editors)",
+			Tags:    []string{"products"}, Auth: true,
+			Params: []openapi.Param{idParam, currencyParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/exchange-rates", ID: "getExchangeRates",
+			Summary: "List exchange rates relative to the base currency",
+			Tags:    []string{"exchange-rates"}, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ExchangeRatesResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/exchange-rates", ID: "updateExchangeRates",
+			Summary: "Replace the exchange-rate table (admin only)",
+			Tags:    []string{"exchange-rates"}, Auth: true,
+			Request: dto.ExchangeRatesRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ExchangeRatesResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+	}
+}
+
+func categoryOperations() []openapi.Operation {
+	tags := []string{"categories"}
+	keyParam := openapi.PathParam("id", "Category ID (UUID) or slug")
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/categories", ID: "createCategory",
+			Summary: "Create a category, optionally under a parent (editors)", Tags: tags, Auth: true,
+			Request: dto.CreateCategoryRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.CategoryResponse{}},
+				errBadRequest, errUnauthz, errForbidden, {Status: http.StatusConflict, Description: "Slug already in use"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories", ID: "getCategoryTree",
+			Summary: "List every category as a tree, siblings by position then name", Tags: tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.CategoryTreeNode{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/{id}", ID: "getCategory",
+			Summary: "Get a category with its breadcrumb path", Tags: tags, Auth: true,
+			Params: []openapi.Param{keyParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CategoryResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/categories/{id}", ID: "updateCategory",
+			Summary: "Rename, move or reorder a category (editors)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{keyParam},
+			Request: dto.CreateCategoryRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CategoryResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid fields, unknown parent or a parent below the category"},
+				errUnauthz, errForbidden, errNotFound, {Status: http.StatusConflict, Description: "Slug already in use"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/categories/{id}", ID: "deleteCategory",
+			Summary: "Delete a category without subcategories (editors)", Tags: tags, Auth: true,
+			Params: []openapi.Param{keyParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, {Status: http.StatusConflict, Description: "Category has subcategories"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/{id}/products", ID: "listCategoryProducts",
+			Summary: "List a category's products, same filters and pagination as GET /products",
+			Tags:    tags, Auth: true,
+			Params: append([]openapi.Param{
+				keyParam,
+				openapi.QueryParam("include_descendants", "boolean", "Also list products of every subcategory"),
+			}, productListParams()...),
+			Responses: append(productListResponses(), errNotFound),
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}/categories", ID: "setProductCategories",
+			Summary: "Replace the categories a product belongs to (editors)", Tags: []string{"products"}, Auth: true,
+			Params:  []openapi.Param{idParam},
+			Request: dto.SetProductCategoriesRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductCategoriesResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func variantOperations() []openapi.Operation {
+	tags := []string{"variants"}
+	variantParams := []openapi.Param{idParam, openapi.PathParam("variantID", "Variant ID (UUID)")}
+	errConflict := openapi.Response{Status: http.StatusConflict, Description: "SKU or option combination already in use"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodPut, Path: "/products/{id}/options", ID: "setProductOptions",
+			Summary: "Replace a product's options and generate variants for new value combinations; " +
+				"variants for combinations that no longer exist are removed (editors)",
+			Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam},
+			Request: dto.SetProductOptionsRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductVariantsResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errConflict, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}/variants", ID: "listProductVariants",
+			Summary: "List a product's options and variants", Tags: tags, Auth: true,
+			Params: []openapi.Param{idParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ProductVariantsResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/products/{id}/variants", ID: "createVariant",
+			Summary: "Create a variant for one combination of option values (editors)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam},
+			Request: dto.VariantRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.VariantResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errConflict, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}/variants/{variantID}", ID: "getVariant",
+			Summary: "Get a variant", Tags: tags, Auth: true,
+			Params: variantParams,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.VariantResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}/variants/{variantID}", ID: "updateVariant",
+			Summary: "Replace a variant's SKU, barcode, options and price override (editors)", Tags: tags, Auth: true,
+			Params:  variantParams,
+			Request: dto.VariantRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.VariantResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errConflict, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/products/{id}/variants/{variantID}", ID: "deleteVariant",
+			Summary: "Delete a variant (editors)", Tags: tags, Auth: true,
+			Params: variantParams,
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/skus/{sku}", ID: "getSKU",
+			Summary: "Look a variant up by SKU (case-insensitive), with its product", Tags: tags, Auth: true,
+			Params: []openapi.Param{openapi.PathParam("sku", "Stock keeping unit")},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.SKUResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func inventoryOperations() []openapi.Operation {
+	tags := []string{"inventory"}
+	skuParam := openapi.PathParam("sku", "Stock keeping unit")
+	reservationParam := openapi.PathParam("id", "Reservation ID (UUID)")
+	errNotActive := openapi.Response{Status: http.StatusConflict, Description: "Reservation already committed, released or expired"}
+	errExpired := openapi.Response{Status: http.StatusGone, Description: "Reservation expired; its units were released"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/warehouses", ID: "listWarehouses",
+			Summary: "List warehouses", Tags: tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.WarehouseResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/warehouses", ID: "createWarehouse",
+			Summary: "Create a warehouse (admin)", Tags: tags, Auth: true,
+			Request: dto.WarehouseRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.WarehouseResponse{}},
+				errBadRequest, errUnauthz, errForbidden,
+				{Status: http.StatusConflict, Description: "Code already in use"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/skus/{sku}/stock", ID: "getStock",
+			Summary: "Get a SKU's stock per warehouse", Tags: tags, Auth: true,
+			Params: []openapi.Param{skuParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.StockResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/skus/{sku}/stock/adjustments", ID: "adjustStock",
+			Summary: "Add or remove units of a SKU in a warehouse (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{skuParam},
+			Request: dto.StockAdjustmentRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.StockResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound,
+				{Status: http.StatusConflict, Description: "Stock on hand would drop below what is reserved"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/skus/{sku}/stock/movements", ID: "listStockMovements",
+			Summary: "List a SKU's stock ledger, newest first (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{skuParam, openapi.QueryParam("limit", "integer", "Entries to return (default 100, max 500)")},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.StockMovementResponse{}},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/reservations", ID: "createReservation",
+			Summary: "Hold up to 100 units of a SKU for up to an hour, until committed, released or expired", Tags: tags, Auth: true,
+			Request: dto.ReservationRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.ReservationResponse{}},
+				errBadRequest, errUnauthz,
+				{Status: http.StatusConflict, Description: "Not enough units available, or the user already holds too many"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/reservations/{id}", ID: "getReservation",
+			Summary: "Get one of your reservations; admins get any", Tags: tags, Auth: true,
+			Params: []openapi.Param{reservationParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReservationResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/reservations/{id}/commit", ID: "commitReservation",
+			Summary: "Commit a reservation; its units leave the warehouse (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{reservationParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReservationResponse{}},
+				errUnauthz, errForbidden, errNotFound, errNotActive, errExpired, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/reservations/{id}/release", ID: "releaseReservation",
+			Summary: "Release one of your reservations; its units become available again", Tags: tags, Auth: true,
+			Params: []openapi.Param{reservationParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReservationResponse{}},
+				errUnauthz, errNotFound, errNotActive, errExpired, errInternal,
+			},
+		},
+	}
+}
+
+func cartOperations() []openapi.Operation {
+	tags := []string{"cart"}
+	cartToken := openapi.HeaderParam("X-Cart-Token", "Guest cart token; ignored with a bearer token")
+	country := openapi.QueryParam("country", "string", "ISO 3166-1 country to tax the cart in (default: the store's)")
+	region := openapi.QueryParam("region", "string", "State or province code within country")
+	itemParams := []openapi.Param{openapi.PathParam("itemID", "Cart item ID (UUID)"), cartToken, country, region}
+	errCartNotFound := openapi.Response{Status: http.StatusNotFound, Description: "Unknown X-Cart-Token or cart item"}
+	errCartChanged := openapi.Response{Status: http.StatusConflict, Description: "Cart changed by another request; retry"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/cart", ID: "getCart",
+			Summary: "Get the user's cart, or the guest cart named by X-Cart-Token", Tags: tags, OptionalAuth: true,
+			Params: []openapi.Param{cartToken, country, region},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CartResponse{}},
+				errBadRequest, errUnauthz, errCartNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/cart", ID: "clearCart",
+			Summary: "Remove every item from the cart", Tags: tags, OptionalAuth: true,
+			Params: []openapi.Param{cartToken},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errCartNotFound, errCartChanged, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/cart/items", ID: "addCartItem",
+			Summary: "Add a product or variant at its current price; guests without X-Cart-Token get a new cart",
+			Tags:    tags, OptionalAuth: true,
+			Params:  []openapi.Param{cartToken, country, region},
+			Request: dto.AddCartItemRequest{},
+			Responses: []openapi.Response{
+				{
+					Status: http.StatusCreated, Body: dto.CartResponse{},
+					Headers: map[string]string{"X-Cart-Token": "Token of the guest cart just created"},
+				},
+				errBadRequest, errUnauthz, errCartNotFound,
+				{Status: http.StatusConflict, Description: "Cart full, item priced in another currency, or cart changed by another request"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/cart/items/{itemID}", ID: "updateCartItem",
+			Summary: "Set an item's quantity; zero removes it", Tags: tags, OptionalAuth: true,
+			Params:  itemParams,
+			Request: dto.UpdateCartItemRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CartResponse{}},
+				errBadRequest, errUnauthz, errCartNotFound, errCartChanged, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/cart/items/{itemID}", ID: "removeCartItem",
+			Summary: "Remove an item", Tags: tags, OptionalAuth: true,
+			Params: itemParams,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CartResponse{}},
+				errBadRequest, errUnauthz, errCartNotFound, errCartChanged, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/cart/coupon", ID: "applyCartCoupon",
+			Summary: "Apply a coupon to the cart if it is valid for the items", Tags: tags, OptionalAuth: true,
+			Params:  []openapi.Param{cartToken, country, region},
+			Request: dto.ApplyCouponRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CartResponse{}},
+				{Status: http.StatusBadRequest, Description: "Unknown, expired or inapplicable coupon"},
+				errUnauthz, errCartNotFound,
+				{Status: http.StatusConflict, Description: "Coupon usage limit reached, or cart changed by another request"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/cart/coupon", ID: "removeCartCoupon",
+			Summary: "Remove the cart's coupon", Tags: tags, OptionalAuth: true,
+			Params: []openapi.Param{cartToken},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errCartNotFound, errCartChanged, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/cart/shipping-rates", ID: "quoteCartShipping",
+			Summary: "List the ways to ship the cart to a destination, cheapest first", Tags: tags, OptionalAuth: true,
+			Params: []openapi.Param{
+				cartToken,
+				openapi.QueryParam("country", "string", "ISO 3166-1 country to ship to (required)"),
+				openapi.QueryParam("region", "string", "State or province code within country"),
+				openapi.QueryParam("postal_code", "string", "Postal code to ship to"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ShippingQuoteResponse{}},
+				{Status: http.StatusBadRequest, Description: "Missing or invalid destination"},
+				errUnauthz, errCartNotFound,
+				{Status: http.StatusConflict, Description: "An item is no longer available"}, errInternal,
+			},
+		},
+	}
+}
+
+func taxOperations() []openapi.Operation {
+	tags := []string{"tax"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/tax/rates", ID: "getTaxRates",
+			Summary: "List the tax rates by country, region and tax class, with the store's tax settings",
+			Tags:    tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.TaxRatesResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/tax/rates", ID: "updateTaxRates",
+			Summary: "Replace the tax-rate table (admin only)",
+			Tags:    tags, Auth: true,
+			Request: dto.TaxRatesRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.TaxRatesResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/tax/quote", ID: "quoteTax",
+			Summary: "Work out the tax on items at their catalog prices, before discounts",
+			Tags:    tags, Auth: true,
+			Request: dto.TaxQuoteRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.TaxQuoteResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid items or tax location"}, errUnauthz,
+				{Status: http.StatusConflict, Description: "Items priced in different currencies"}, errInternal,
+			},
+		},
+	}
+}
+
+func shippingOperations() []openapi.Operation {
+	tags := []string{"shipping"}
+	zoneParam := openapi.PathParam("id", "Shipping zone ID (UUID)")
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/shipping/zones", ID: "createShippingZone",
+			Summary: "Create a shipping zone from countries, regions and postal prefixes (admin)", Tags: tags, Auth: true,
+			Request: dto.CreateShippingZoneRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.ShippingZoneResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/shipping/zones", ID: "listShippingZones",
+			Summary: "List shipping zones with their methods (admin)", Tags: tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ShippingZoneResponse{}},
+				errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/shipping/zones/{id}", ID: "getShippingZone",
+			Summary: "Get a shipping zone with its methods (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{zoneParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ShippingZoneResponse{}},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/shipping/zones/{id}", ID: "deleteShippingZone",
+			Summary: "Delete a shipping zone and its methods (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{zoneParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/shipping/zones/{id}/methods", ID: "createShippingMethod",
+			Summary: "Add a flat, weight-based or free-over-threshold method to a zone (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{zoneParam},
+			Request: dto.CreateShippingMethodRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.ShippingMethodResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/shipping/zones/{id}/methods/{methodID}", ID: "deleteShippingMethod",
+			Summary: "Delete a shipping method (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{zoneParam, openapi.PathParam("methodID", "Shipping method ID (UUID)")},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func orderOperations() []openapi.Operation {
+	tags := []string{"orders"}
+	orderParam := openapi.PathParam("id", "Order ID (UUID)")
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/orders", ID: "createOrder",
+			Summary: "Place an order for the given items, or for the whole cart when items is empty",
+			Tags:    tags, Auth: true,
+			Request: dto.CreateOrderRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.OrderResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid items, address, tax location or shipping destination, or a coupon that does not apply"}, errUnauthz,
+				{Status: http.StatusConflict, Description: "Items priced in different currencies, no longer available or out of stock, coupon usage limit reached, shipping method not available, or cart changed meanwhile"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/orders", ID: "listOrders",
+			Summary: "List the user's orders, newest first", Tags: tags, Auth: true,
+			Params: []openapi.Param{
+				openapi.QueryParam("status", "string", "Only orders in this status"),
+				openapi.QueryParam("all", "boolean", "Every user's orders (admin)"),
+				openapi.QueryParam("user_id", "string", "With all=true, only this user's orders"),
+				openapi.QueryParam("page", "integer", "Page number (default 1)"),
+				openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.OrderResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/{id}", ID: "getOrder",
+			Summary: "Get an order with its status history", Tags: tags, Auth: true,
+			Params: []openapi.Param{orderParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.OrderResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/orders/{id}/transitions", ID: "transitionOrder",
+			Summary: "Move an order to another status (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{orderParam},
+			Request: dto.OrderTransitionRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.OrderResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound,
+				{Status: http.StatusConflict, Description: "Transition not allowed from the current status, or a payment must be refunded first"}, errInternal,
+			},
+		},
+	}
+}
+
+func paymentOperations() []openapi.Operation {
+	tags := []string{"payments"}
+	paymentParam := openapi.PathParam("id", "Payment ID (UUID)")
+	errPaymentState := openapi.Response{Status: http.StatusConflict, Description: "Order not pending or payment in the wrong status"}
+	errGateway := openapi.Response{Status: http.StatusBadGateway, Description: "Payment gateway error"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/orders/{id}/payments", ID: "createPayment",
+			Summary: "Pay a pending order; captured and marked paid right away unless manual_capture is set",
+			Tags:    tags, Auth: true,
+			Params:  []openapi.Param{openapi.PathParam("id", "Order ID (UUID)")},
+			Request: dto.CreatePaymentRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.PaymentResponse{}},
+				errBadRequest, errUnauthz,
+				{Status: http.StatusPaymentRequired, Description: "Payment declined"},
+				errNotFound,
+				{Status: http.StatusConflict, Description: "Order not pending or already has a pending, authorized or captured payment"},
+				errInternal, errGateway,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/{id}/payments", ID: "listOrderPayments",
+			Summary: "List an order's payment attempts", Tags: tags, Auth: true,
+			Params: []openapi.Param{openapi.PathParam("id", "Order ID (UUID)")},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.PaymentResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/payments/{id}/capture", ID: "capturePayment",
+			Summary: "Capture an authorized payment and mark the order paid (admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{paymentParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.PaymentResponse{}},
+				errUnauthz, errForbidden, errNotFound, errPaymentState, errGateway,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/payments/{id}/refund", ID: "refundPayment",
+			Summary: "Refund part or all of a captured payment (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{paymentParam},
+			Request: dto.RefundPaymentRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.PaymentResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errPaymentState, errGateway,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/payments/webhook", ID: "paymentWebhook",
+			Summary: "Receive payment gateway notifications; the body and signature header depend on the gateway",
+			Tags:    tags,
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				{Status: http.StatusBadRequest, Description: "Invalid signature or body"},
+				{Status: http.StatusNotFound, Description: "Unknown payment reference"}, errInternal,
+			},
+		},
+	}
+}
+
+func discountOperations() []openapi.Operation {
+	couponTags := []string{"coupons"}
+	promotionTags := []string{"promotions"}
+	codeParam := openapi.PathParam("code", "Coupon code (case-insensitive)")
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/coupons", ID: "createCoupon",
+			Summary: "Create a percent or fixed amount coupon (admin)", Tags: couponTags, Auth: true,
+			Request: dto.CreateCouponRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.CouponResponse{}},
+				errBadRequest, errUnauthz, errForbidden,
+				{Status: http.StatusConflict, Description: "Code already exists"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/coupons", ID: "listCoupons",
+			Summary: "List coupons, newest first (admin)", Tags: couponTags, Auth: true,
+			Params: []openapi.Param{
+				openapi.QueryParam("page", "integer", "Page number (default 1)"),
+				openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.CouponResponse{}},
+				errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/coupons/{code}", ID: "getCoupon",
+			Summary: "Get a coupon and how many times it was used (admin)", Tags: couponTags, Auth: true,
+			Params: []openapi.Param{codeParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.CouponResponse{}},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/coupons/{code}", ID: "deleteCoupon",
+			Summary: "Delete a coupon; orders that used it keep their discount (admin)", Tags: couponTags, Auth: true,
+			Params: []openapi.Param{codeParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/promotions", ID: "createPromotion",
+			Summary: "Create an automatic buy X get Y promotion (admin)", Tags: promotionTags, Auth: true,
+			Request: dto.CreatePromotionRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.PromotionResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/promotions", ID: "listPromotions",
+			Summary: "List promotions in the order they apply (admin)", Tags: promotionTags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.PromotionResponse{}},
+				errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/promotions/{id}", ID: "deletePromotion",
+			Summary: "Delete a promotion (admin)", Tags: promotionTags, Auth: true,
+			Params: []openapi.Param{openapi.PathParam("id", "Promotion ID (UUID)")},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func userOperations() []openapi.Operation {
+	tags := []string{"users"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodPost, Path: "/users", ID: "createUser",
+			Summary: "Register a user with the user role", Tags: tags,
+			Request: dto.CreateUserRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.UserResponse{}},
+				errBadRequest, {Status: http.StatusConflict}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/users/email/{email}", ID: "getUserByEmail",
+			Summary: "Find a user by email", Tags: tags,
+			Params: []openapi.Param{openapi.PathParam("email", "User email")},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.UserResponse{}, Headers: etagHeader},
+				errBadRequest, errNotFound,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/users/{id}", ID: "getUser",
+			Summary: "Get a user by ID", Tags: tags,
+			Params: []openapi.Param{idParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.UserResponse{}, Headers: etagHeader},
+				errBadRequest, errNotFound,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/users/{id}", ID: "updateUser",
+			Summary: "Update a user's username, or role (admin); only the user and admins may", Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam, ifMatchParam},
+			Request: dto.UpdateUserRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.UserResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPatch, Path: "/users/{id}", ID: "patchUser",
+			Summary: "Change a user's username, or role (admin), with a JSON Merge Patch or JSON Patch; only the user and admins may",
+			Tags:    tags, Auth: true,
+			Params:              []openapi.Param{idParam, ifMatchParam},
+			Request:             dto.UpdateUserRequest{},
+			RequestContentTypes: patchContentTypes,
+			RequestByType:       map[string]any{patch.JSONPatchType: []patch.Operation{}},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.UserResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errTestFailed, errStale, errNoIfMatch, errNoPatchType, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/users/{id}/role", ID: "setUserRole",
+			Summary: "Give a user the admin, editor or user role (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{idParam, ifMatchParam},
+			Request: dto.UpdateUserRoleRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.UserResponse{}, Headers: etagHeader},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/users/{id}", ID: "deleteUser",
+			Summary: "Delete a user; only the user and admins may. The account is purged after the retention period",
+			Tags:    tags, Auth: true,
+			Params: []openapi.Param{idParam, ifMatchParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errStale, errNoIfMatch, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/users/generate-jwt", ID: "generateJwt",
+			Summary: "Exchange email and password for a JWT", Tags: []string{"auth"},
+			Params: []openapi.Param{
+				openapi.HeaderParam("X-Cart-Token", "Guest cart to merge into the user's cart"),
+			},
+			Request: dto.GetJwtRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.GetJwtResponse{}},
+				errBadRequest, errUnauthz, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>GO_API docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// docsHandler serves the Swagger UI pointed at /openapi.json
+func docsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
+
+func addressOperations() []openapi.Operation {
+	tags := []string{"addresses"}
+	addressParam := openapi.PathParam("id", "Address ID (UUID)")
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/users/me/addresses", ID: "listAddresses",
+			Summary: "List the logged-in user's addresses, oldest first", Tags: tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.AddressResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/users/me/addresses", ID: "createAddress",
+			Summary: "Add an address; the first one becomes the default for shipping and billing", Tags: tags, Auth: true,
+			Request: dto.AddressRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.AddressResponse{}},
+				{Status: http.StatusBadRequest, Description: "Missing fields, or a postal code that does not fit the country"},
+				errUnauthz, {Status: http.StatusConflict, Description: "Address book full"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/users/me/addresses/{id}", ID: "getAddress",
+			Summary: "Get one of the logged-in user's addresses", Tags: tags, Auth: true,
+			Params: []openapi.Param{addressParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.AddressResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/users/me/addresses/{id}", ID: "updateAddress",
+			Summary: "Replace an address; orders keep the copy they were placed with", Tags: tags, Auth: true,
+			Params:  []openapi.Param{addressParam},
+			Request: dto.AddressRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.AddressResponse{}},
+				errBadRequest, errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/users/me/addresses/{id}", ID: "deleteAddress",
+			Summary: "Delete an address", Tags: tags, Auth: true,
+			Params: []openapi.Param{addressParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func reviewOperations() []openapi.Operation {
+	tags := []string{"reviews"}
+	productParam := openapi.PathParam("id", "Product ID (UUID)")
+	reviewParam := openapi.PathParam("id", "Review ID (UUID)")
+	pageParams := []openapi.Param{
+		openapi.QueryParam("page", "integer", "Page number (default 1)"),
+		openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+	}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/products/{id}/reviews", ID: "listProductReviews",
+			Summary: "List a product's approved reviews, newest first", Tags: tags, Auth: true,
+			Params: append([]openapi.Param{productParam}, pageParams...),
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ReviewResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/products/{id}/reviews", ID: "createReview",
+			Summary: "Review a product; the review waits for moderation before it is shown", Tags: tags, Auth: true,
+			Params:  []openapi.Param{productParam},
+			Request: dto.ReviewRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.ReviewResponse{}},
+				errBadRequest, errUnauthz,
+				{Status: http.StatusForbidden, Description: "Only customers who bought the product may review it"},
+				errNotFound, {Status: http.StatusConflict, Description: "The user already reviewed this product"}, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/reviews", ID: "listReviews",
+			Summary: "List reviews by moderation status, oldest first (admin)", Tags: tags, Auth: true,
+			Params: append([]openapi.Param{
+				openapi.QueryParam("status", "string", "pending (default), approved or rejected"),
+			}, pageParams...),
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.ReviewResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/reviews/{id}", ID: "updateReview",
+			Summary: "Rewrite the logged-in user's review; it goes back to moderation", Tags: tags, Auth: true,
+			Params:  []openapi.Param{reviewParam},
+			Request: dto.ReviewRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReviewResponse{}},
+				errBadRequest, errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/reviews/{id}", ID: "deleteReview",
+			Summary: "Delete a review (its author or an admin)", Tags: tags, Auth: true,
+			Params: []openapi.Param{reviewParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/reviews/{id}/moderation", ID: "moderateReview",
+			Summary: "Approve or reject a review (admin)", Tags: tags, Auth: true,
+			Params:  []openapi.Param{reviewParam},
+			Request: dto.ModerateReviewRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReviewResponse{}},
+				errBadRequest, errUnauthz, errForbidden, errNotFound, errInternal,
+			},
+		},
+	}
+}
+
+func wishlistOperations() []openapi.Operation {
+	tags := []string{"wishlists"}
+	wishlistParam := openapi.PathParam("id", "Wishlist ID (UUID)")
+	errLimit := openapi.Response{Status: http.StatusConflict, Description: "Too many wishlists, wishlist full, or product already on it"}
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/wishlists", ID: "listWishlists",
+			Summary: "List the logged-in user's wishlists, oldest first", Tags: tags, Auth: true,
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.WishlistResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/wishlists", ID: "createWishlist",
+			Summary: "Create an empty wishlist", Tags: tags, Auth: true,
+			Request: dto.WishlistRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.WishlistResponse{}},
+				errBadRequest, errUnauthz, errLimit, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/wishlists/price-drops", ID: "listPriceDrops",
+			Summary: "List price drops of products on the user's wishlists, newest first", Tags: tags, Auth: true,
+			Params: []openapi.Param{
+				openapi.QueryParam("page", "integer", "Page number (default 1)"),
+				openapi.QueryParam("limit", "integer", "Page size (default 10, max 100)"),
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: []dto.PriceDropResponse{}},
+				errUnauthz, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/wishlists/shared/{token}", ID: "getSharedWishlist",
+			Summary: "Read a shared wishlist; no login needed", Tags: tags,
+			Params: []openapi.Param{openapi.PathParam("token", "Share token")},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.WishlistResponse{}},
+				errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/wishlists/{id}", ID: "getWishlist",
+			Summary: "Get one of the logged-in user's wishlists", Tags: tags, Auth: true,
+			Params: []openapi.Param{wishlistParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.WishlistResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/wishlists/{id}", ID: "renameWishlist",
+			Summary: "Rename a wishlist", Tags: tags, Auth: true,
+			Params:  []openapi.Param{wishlistParam},
+			Request: dto.WishlistRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.WishlistResponse{}},
+				errBadRequest, errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/wishlists/{id}", ID: "deleteWishlist",
+			Summary: "Delete a wishlist", Tags: tags, Auth: true,
+			Params: []openapi.Param{wishlistParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/wishlists/{id}/items", ID: "addWishlistItem",
+			Summary: "Add a product to a wishlist, remembering its current price", Tags: tags, Auth: true,
+			Params:  []openapi.Param{wishlistParam},
+			Request: dto.AddWishlistItemRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Body: dto.WishlistResponse{}},
+				{Status: http.StatusBadRequest, Description: "Unknown product"}, errUnauthz, errNotFound, errLimit, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/wishlists/{id}/items/{productID}", ID: "removeWishlistItem",
+			Summary: "Remove a product from a wishlist", Tags: tags, Auth: true,
+			Params: []openapi.Param{wishlistParam, openapi.PathParam("productID", "Product ID (UUID)")},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/wishlists/{id}/share", ID: "shareWishlist",
+			Summary: "Turn on the wishlist's public link; sharing again keeps the same token", Tags: tags, Auth: true,
+			Params: []openapi.Param{wishlistParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.WishlistResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/wishlists/{id}/share", ID: "unshareWishlist",
+			Summary: "Turn off the wishlist's public link", Tags: tags, Auth: true,
+			Params: []openapi.Param{wishlistParam},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.WishlistResponse{}},
+				errUnauthz, errNotFound, errInternal,
+			},
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github/GuilhermeHermes/GO_API/internal/infra/payment"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/openapi"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// undocumentedRoutes are served by the router but are not part of the API.
+var undocumentedRoutes = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+}
+
+func setupTestRouter(t *testing.T) *chi.Mux {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	return NewRouter(db, Options{
+		TokenAuth:       jwtauth.New("HS256", []byte("test-secret"), nil),
+		JwtExpiration:   300,
+		PaymentProvider: payment.NewFakeProvider("webhook-secret"),
+	})
+}
+
+func TestAPISpecCoversAllRoutes(t *testing.T) {
+	router := setupTestRouter(t)
+	spec := APISpec()
+
+	served := map[string]bool{}
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		key := method + " " + openapi.NormalizePath(route)
+		served[key] = true
+		if undocumentedRoutes[key] {
+			return nil
+		}
+		assert.True(t, spec.Has(method, route), "route %s has no OpenAPI entry", key)
+		return nil
+	})
+	require.NoError(t, err)
+
+	for _, route := range spec.Routes() {
+		assert.True(t, served[route], "OpenAPI entry %s has no route", route)
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	router := setupTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc struct {
+		OpenAPI    string                     `json:"openapi"`
+		Paths      map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/products/{id}")
+	assert.Contains(t, doc.Components.Schemas, "ProductResponse")
+	assert.Contains(t, doc.Components.Schemas, "CreateUserRequest")
+
+	req = httptest.NewRequest(http.MethodGet, "/docs", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/openapi.json")
+}
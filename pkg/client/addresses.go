@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListAddresses returns the logged-in user's address book.
+func (c *Client) ListAddresses(ctx context.Context) ([]Address, error) {
+	var addresses []Address
+	if err := c.doAuth(ctx, http.MethodGet, "/users/me/addresses", nil, nil, &addresses); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// CreateAddress adds an address to the logged-in user's book; the first
+// one becomes the default for shipping and billing.
+func (c *Client) CreateAddress(ctx context.Context, req AddressRequest) (*Address, error) {
+	var address Address
+	if err := c.doAuth(ctx, http.MethodPost, "/users/me/addresses", nil, req, &address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (c *Client) GetAddress(ctx context.Context, id string) (*Address, error) {
+	var address Address
+	if err := c.doAuth(ctx, http.MethodGet, "/users/me/addresses/"+url.PathEscape(id), nil, nil, &address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// UpdateAddress replaces an address; nil default flags keep theirs.
+func (c *Client) UpdateAddress(ctx context.Context, id string, req AddressRequest) (*Address, error) {
+	var address Address
+	if err := c.doAuth(ctx, http.MethodPut, "/users/me/addresses/"+url.PathEscape(id), nil, req, &address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (c *Client) DeleteAddress(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/users/me/addresses/"+url.PathEscape(id), nil, nil, nil)
+}
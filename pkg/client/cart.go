@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+// The cart calls work logged in or as a guest. A guest's first AddCartItem
+// creates a cart whose token the client keeps and sends on later calls,
+// including the login that merges the guest cart into the user's.
+
+func (c *Client) GetCart(ctx context.Context) (*Cart, error) {
+	var cart Cart
+	if err := c.doCart(ctx, http.MethodGet, "/cart", nil, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// AddCartItem adds a product, a variant, or a SKU; set the fields of req
+// that identify it.
+func (c *Client) AddCartItem(ctx context.Context, req AddCartItemRequest) (*Cart, error) {
+	var cart Cart
+	if err := c.doCart(ctx, http.MethodPost, "/cart/items", req, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// UpdateCartItem sets a line's quantity; zero removes the line.
+func (c *Client) UpdateCartItem(ctx context.Context, itemID string, quantity int64) (*Cart, error) {
+	var cart Cart
+	req := dto.UpdateCartItemRequest{Quantity: quantity}
+	if err := c.doCart(ctx, http.MethodPut, "/cart/items/"+url.PathEscape(itemID), req, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (c *Client) RemoveCartItem(ctx context.Context, itemID string) (*Cart, error) {
+	var cart Cart
+	if err := c.doCart(ctx, http.MethodDelete, "/cart/items/"+url.PathEscape(itemID), nil, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (c *Client) ClearCart(ctx context.Context) error {
+	return c.doCart(ctx, http.MethodDelete, "/cart", nil, nil)
+}
+
+// doCart authenticates when the client can, and otherwise calls as a guest.
+func (c *Client) doCart(ctx context.Context, method, path string, in, out any) error {
+	c.mu.Lock()
+	guest := c.token == "" && c.email == ""
+	c.mu.Unlock()
+	if guest {
+		return c.do(ctx, method, path, nil, in, out)
+	}
+	return c.doAuth(ctx, method, path, nil, in, out)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+func (c *Client) CreateCategory(ctx context.Context, req CategoryRequest) (*CategoryResponse, error) {
+	var category CategoryResponse
+	if err := c.doAuth(ctx, http.MethodPost, "/categories", nil, req, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetCategory accepts a category ID or slug.
+func (c *Client) GetCategory(ctx context.Context, key string) (*CategoryResponse, error) {
+	var category CategoryResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/categories/"+url.PathEscape(key), nil, nil, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (c *Client) GetCategoryTree(ctx context.Context) ([]CategoryTreeNode, error) {
+	var tree []CategoryTreeNode
+	if err := c.doAuth(ctx, http.MethodGet, "/categories", nil, nil, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (c *Client) UpdateCategory(ctx context.Context, key string, req CategoryRequest) (*CategoryResponse, error) {
+	var category CategoryResponse
+	if err := c.doAuth(ctx, http.MethodPut, "/categories/"+url.PathEscape(key), nil, req, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (c *Client) DeleteCategory(ctx context.Context, key string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/categories/"+url.PathEscape(key), nil, nil, nil)
+}
+
+// ListCategoryProducts fetches one offset page of a category's products,
+// optionally including those of its subcategories.
+func (c *Client) ListCategoryProducts(ctx context.Context, key string, includeDescendants bool, params ListProductsParams) ([]ProductResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	query := params.query()
+	if includeDescendants {
+		query.Set("include_descendants", "true")
+	}
+
+	var products []ProductResponse
+	path := "/categories/" + url.PathEscape(key) + "/products"
+	if err := c.doAuth(ctx, http.MethodGet, path, query, nil, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// SetProductCategories replaces the product's categories and returns the
+// resulting breadcrumbs.
+func (c *Client) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) ([][]CategoryRef, error) {
+	var result dto.ProductCategoriesResponse
+	req := dto.SetProductCategoriesRequest{CategoryIDs: categoryIDs}
+	path := "/products/" + url.PathEscape(productID) + "/categories"
+	if err := c.doAuth(ctx, http.MethodPut, path, nil, req, &result); err != nil {
+		return nil, err
+	}
+	return result.Breadcrumbs, nil
+}
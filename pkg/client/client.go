@@ -0,0 +1,350 @@
+// Package client is a typed Go client for the GO_API HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/patch"
+)
+
+// The client speaks the same wire types as the server. They are aliased here
+// so callers outside this module can name them.
+type (
+	CreateProductRequest   = dto.CreateProductRequest
+	UpdateProductRequest   = dto.UpdateProductRequest
+	ProductResponse        = dto.ProductResponse
+	ProductRevision        = dto.ProductRevisionResponse
+	ProductScheduleRequest = dto.ProductScheduleRequest
+	ProductListResponse    = dto.ProductListResponse
+	ProductSearchResult    = dto.ProductSearchResult
+	ProductFacets          = dto.ProductFacetsResponse
+	TagFacet               = dto.TagFacet
+	ProductPrices          = dto.ProductPricesResponse
+	ExchangeRates          = dto.ExchangeRatesResponse
+	CategoryRequest        = dto.CreateCategoryRequest
+	CategoryResponse       = dto.CategoryResponse
+	CategoryTreeNode       = dto.CategoryTreeNode
+	CategoryRef            = dto.CategoryRef
+	ProductOption          = dto.ProductOption
+	ProductOptionsRequest  = dto.SetProductOptionsRequest
+	ProductVariants        = dto.ProductVariantsResponse
+	VariantRequest         = dto.VariantRequest
+	VariantResponse        = dto.VariantResponse
+	SKUResponse            = dto.SKUResponse
+	WarehouseRequest       = dto.WarehouseRequest
+	Warehouse              = dto.WarehouseResponse
+	Stock                  = dto.StockResponse
+	StockAdjustmentRequest = dto.StockAdjustmentRequest
+	StockMovement          = dto.StockMovementResponse
+	ReservationRequest     = dto.ReservationRequest
+	Reservation            = dto.ReservationResponse
+	AddCartItemRequest     = dto.AddCartItemRequest
+	Cart                   = dto.CartResponse
+	CartItem               = dto.CartItemResponse
+	OrderItemRequest       = dto.OrderItemRequest
+	CreateOrderRequest     = dto.CreateOrderRequest
+	Order                  = dto.OrderResponse
+	Payment                = dto.PaymentResponse
+	CreateCouponRequest    = dto.CreateCouponRequest
+	Coupon                 = dto.CouponResponse
+	CreatePromotionRequest = dto.CreatePromotionRequest
+	Promotion              = dto.PromotionResponse
+	AppliedDiscount        = dto.AppliedDiscountResponse
+	TaxRate                = dto.TaxRateEntry
+	TaxRates               = dto.TaxRatesResponse
+	TaxQuoteRequest        = dto.TaxQuoteRequest
+	TaxQuote               = dto.TaxQuoteResponse
+	ShippingZoneRequest    = dto.CreateShippingZoneRequest
+	ShippingArea           = dto.ShippingArea
+	ShippingZone           = dto.ShippingZoneResponse
+	ShippingMethodRequest  = dto.CreateShippingMethodRequest
+	ShippingMethod         = dto.ShippingMethodResponse
+	WeightTier             = dto.WeightTier
+	ShippingQuote          = dto.ShippingQuoteResponse
+	Dimensions             = dto.Dimensions
+	PostalAddress          = dto.PostalAddress
+	AddressRequest         = dto.AddressRequest
+	Address                = dto.AddressResponse
+	ReviewRequest          = dto.ReviewRequest
+	Review                 = dto.ReviewResponse
+	RatingSummary          = dto.RatingSummary
+	WishlistRequest        = dto.WishlistRequest
+	AddWishlistItemRequest = dto.AddWishlistItemRequest
+	Wishlist               = dto.WishlistResponse
+	WishlistItem           = dto.WishlistItemResponse
+	PriceDrop              = dto.PriceDropResponse
+	CreateUserRequest      = dto.CreateUserRequest
+	UpdateUserRequest      = dto.UpdateUserRequest
+	UpdateUserRoleRequest  = dto.UpdateUserRoleRequest
+	UserResponse           = dto.UserResponse
+	PatchOperation         = patch.Operation
+)
+
+// cartTokenHeader carries the guest cart token both ways.
+const cartTokenHeader = "X-Cart-Token"
+
+// refreshSkew is how long before expiry a token is considered stale.
+const refreshSkew = 30 * time.Second
+
+var ErrNoCredentials = errors.New("client: no credentials to log in with")
+
+// APIError is returned for any non-2xx response.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsPreconditionFailed reports whether err is an APIError with status
+// 412: the resource changed since the version sent with IfMatch.
+func IsPreconditionFailed(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+type (
+	ifMatchKey     struct{}
+	contentTypeKey struct{}
+)
+
+// IfMatch makes writes made with ctx conditional on the resource still
+// being at version, the Version of the product or user last read.
+func IfMatch(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, ifMatchKey{}, version)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	email     string
+	password  string
+	token     string
+	expiresAt time.Time
+	// cartToken names the guest cart the client has built before logging
+	// in; the server merges it on login.
+	cartToken string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithCredentials makes the client log in lazily and log in again whenever
+// its token is about to expire or is rejected.
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.email = email
+		c.password = password
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Login exchanges email and password for a token and remembers the
+// credentials for automatic refresh.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	c.mu.Lock()
+	c.email = email
+	c.password = password
+	c.mu.Unlock()
+	return c.login(ctx)
+}
+
+// Token returns the current bearer token, if any.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// CartToken returns the token of the guest cart, if the client has one.
+func (c *Client) CartToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cartToken
+}
+
+// SetToken installs a token obtained elsewhere.
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiresAt = tokenExpiry(token)
+}
+
+func (c *Client) login(ctx context.Context) error {
+	c.mu.Lock()
+	email, password := c.email, c.password
+	c.mu.Unlock()
+	if email == "" {
+		return ErrNoCredentials
+	}
+
+	var resp dto.GetJwtResponse
+	req := dto.GetJwtRequest{Email: email, Password: password}
+	if err := c.do(ctx, http.MethodPost, "/users/generate-jwt", nil, req, &resp); err != nil {
+		return err
+	}
+	c.SetToken(resp.Token)
+
+	// The server has merged the guest cart, if any, into the user's.
+	c.mu.Lock()
+	c.cartToken = ""
+	c.mu.Unlock()
+	return nil
+}
+
+// authToken returns a usable token, logging in first when there is none or
+// it is about to expire.
+func (c *Client) authToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expiresAt, canLogin := c.token, c.expiresAt, c.email != ""
+	c.mu.Unlock()
+
+	stale := token == "" || (!expiresAt.IsZero() && time.Until(expiresAt) < refreshSkew)
+	if stale && canLogin {
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+		return c.Token(), nil
+	}
+	return token, nil
+}
+
+// doAuth performs an authenticated request, logging in again and retrying
+// once if the server rejects the token.
+func (c *Client) doAuth(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	token, err := c.authToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.doWithToken(ctx, method, path, query, token, in, out)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if loginErr := c.login(ctx); loginErr != nil {
+			return err
+		}
+		return c.doWithToken(ctx, method, path, query, c.Token(), in, out)
+	}
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	return c.doWithToken(ctx, method, path, query, "", in, out)
+}
+
+func (c *Client) doWithToken(ctx context.Context, method, path string, query url.Values, token string, in, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		contentType, ok := ctx.Value(contentTypeKey{}).(string)
+		if !ok {
+			contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if cartToken := c.CartToken(); cartToken != "" {
+		req.Header.Set(cartTokenHeader, cartToken)
+	}
+	if version, ok := ctx.Value(ifMatchKey{}).(int64); ok && method != http.MethodGet {
+		req.Header.Set("If-Match", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if cartToken := resp.Header.Get(cartTokenHeader); cartToken != "" {
+		c.mu.Lock()
+		c.cartToken = cartToken
+		c.mu.Unlock()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(resp.Body)
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// tokenExpiry reads the exp claim without verifying the signature; the
+// server is the one that verifies it.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
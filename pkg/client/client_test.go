@@ -0,0 +1,1908 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	internalentity "github/GuilhermeHermes/GO_API/internal/entity"
+	"github/GuilhermeHermes/GO_API/internal/infra/database"
+	"github/GuilhermeHermes/GO_API/internal/infra/payment"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	testEmail         = "client@example.com"
+	testPassword      = "password123"
+	testWebhookSecret = "webhook-secret"
+)
+
+func setupTestServer(t *testing.T) *httptest.Server {
+	return setupTestServerWith(t, webserver.Options{})
+}
+
+// setupTestServerWith starts a server with opts on top of the test
+// defaults, with an admin account for testEmail.
+func setupTestServerWith(t *testing.T, opts webserver.Options) *httptest.Server {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	// Every connection to ":memory:" is a separate database, so keep one.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, database.Migrate(db))
+	// Signing up never grants a role, so the admin is created directly.
+	admin, err := internalentity.NewUser("client", testEmail, testPassword, internalentity.RoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, database.NewUserRepository(db).Create(admin))
+
+	opts.TokenAuth = jwtauth.New("HS256", []byte("test-secret"), nil)
+	opts.JwtExpiration = 300
+	opts.PaymentProvider = payment.NewFakeProvider(testWebhookSecret)
+	router := webserver.NewRouter(db, opts)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func setupLoggedInClient(t *testing.T) *Client {
+	server := setupTestServer(t)
+	c := New(server.URL)
+	require.NoError(t, c.Login(context.Background(), testEmail, testPassword))
+	return c
+}
+
+func TestClient_Users(t *testing.T) {
+	server := setupTestServer(t)
+	c := New(server.URL)
+	ctx := context.Background()
+
+	created, err := c.CreateUser(ctx, CreateUserRequest{
+		Username: "alice", Email: "alice@example.com", Password: "secret",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "alice", created.Username)
+
+	found, err := c.GetUser(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Email, found.Email)
+
+	byEmail, err := c.GetUserByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, byEmail.ID)
+
+	var apiErr *APIError
+	_, err = c.UpdateUser(ctx, created.ID, UpdateUserRequest{Username: "anonymous"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	t.Run("only the user and admins change it", func(t *testing.T) {
+		mallory := New(server.URL, WithCredentials("mallory@example.com", "secret"))
+		_, err := mallory.CreateUser(ctx, CreateUserRequest{Username: "mallory", Email: "mallory@example.com", Password: "secret"})
+		require.NoError(t, err)
+		_, err = mallory.UpdateUser(ctx, created.ID, UpdateUserRequest{Username: "pwned"})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+		err = mallory.DeleteUser(ctx, created.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		admin := New(server.URL, WithCredentials(testEmail, testPassword))
+		renamed, err := admin.UpdateUser(ctx, created.ID, UpdateUserRequest{Username: "alice (by admin)"})
+		require.NoError(t, err)
+		found.Version = renamed.Version
+	})
+
+	require.NoError(t, c.Login(ctx, "alice@example.com", "secret"))
+	_, err = c.UpdateUser(ctx, created.ID, UpdateUserRequest{Role: "admin"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	updated, err := c.UpdateUser(IfMatch(ctx, found.Version), created.ID, UpdateUserRequest{Username: "alice2"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice2", updated.Username)
+	assert.Equal(t, found.Version+1, updated.Version)
+
+	_, err = c.UpdateUser(IfMatch(ctx, found.Version), created.ID, UpdateUserRequest{Username: "stale"})
+	assert.True(t, IsPreconditionFailed(err))
+	assert.True(t, IsPreconditionFailed(c.DeleteUser(IfMatch(ctx, found.Version), created.ID)))
+
+	require.NoError(t, c.DeleteUser(ctx, created.ID))
+	_, err = c.GetUser(ctx, created.ID)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClient_UserRoles(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	// A role in the signup body is ignored.
+	var created UserResponse
+	signup := map[string]string{"username": "mallory", "email": "mallory@example.com", "password": "secret", "role": "admin"}
+	require.NoError(t, admin.do(ctx, http.MethodPost, "/users", nil, signup, &created))
+	assert.Equal(t, "user", created.Role)
+
+	t.Run("only admins assign roles", func(t *testing.T) {
+		mallory := New(admin.baseURL, WithCredentials("mallory@example.com", "secret"))
+		_, err := mallory.SetUserRole(ctx, created.ID, "admin")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		_, err = admin.SetUserRole(ctx, created.ID, "superuser")
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		promoted, err := admin.SetUserRole(IfMatch(ctx, created.Version), created.ID, "editor")
+		require.NoError(t, err)
+		assert.Equal(t, "editor", promoted.Role)
+		assert.Equal(t, created.Version+1, promoted.Version)
+	})
+}
+
+func TestClient_Products(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Keyboard", Description: "Mechanical", Price: entity.MustParseMoney("199.90", "BRL"),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "Mechanical", created.Description)
+	assert.Equal(t, "draft", created.Status)
+	assert.Equal(t, []string{"published", "archived"}, created.NextStatuses)
+	assert.Equal(t, []string{}, created.Tags)
+	_, err = c.TransitionProduct(ctx, created.ID, "published")
+	require.NoError(t, err)
+
+	found, err := c.GetProduct(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Keyboard", found.Name)
+
+	results, err := c.SearchProducts(ctx, "mech", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, created.ID, results[0].Product.ID)
+	assert.Equal(t, "<mark>Mechanical</mark>", results[0].Highlights.Description)
+
+	updated, err := c.UpdateProduct(ctx, created.ID, CreateProductRequest{
+		Name: "Keyboard v2", Description: "Wireless", Price: entity.MustParseMoney("249.90", "BRL"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Keyboard v2", updated.Name)
+	assert.Equal(t, "Wireless", updated.Description)
+	assert.Equal(t, []string{"draft", "archived"}, updated.NextStatuses)
+	found, err = c.GetProduct(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Wireless", found.Description)
+
+	require.NoError(t, c.DeleteProduct(ctx, created.ID))
+	_, err = c.GetProduct(ctx, created.ID)
+	assert.True(t, IsNotFound(err))
+	results, err = c.SearchProducts(ctx, "mech", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	trash, err := c.ListTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.NotEmpty(t, trash[0].DeletedAt)
+
+	restored, err := c.RestoreProduct(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Keyboard v2", restored.Name)
+	assert.Empty(t, restored.DeletedAt)
+	_, err = c.RestoreProduct(ctx, created.ID)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClient_ProductVersions(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Mouse", Price: entity.MustParseMoney("99.90", "BRL"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	first, err := c.UpdateProduct(IfMatch(ctx, created.Version), created.ID, CreateProductRequest{
+		Name: "Mouse (first editor)", Price: entity.MustParseMoney("99.90", "BRL"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), first.Version)
+
+	_, err = c.UpdateProduct(IfMatch(ctx, created.Version), created.ID, CreateProductRequest{
+		Name: "Mouse (second editor)", Price: entity.MustParseMoney("99.90", "BRL"),
+	})
+	assert.True(t, IsPreconditionFailed(err))
+	assert.True(t, IsPreconditionFailed(c.DeleteProduct(IfMatch(ctx, created.Version), created.ID)))
+
+	found, err := c.GetProduct(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Mouse (first editor)", found.Name)
+	assert.Equal(t, first.Version, found.Version)
+
+	// If-Match compares strongly, so a weak tag never matches.
+	req, err := http.NewRequest(http.MethodDelete, c.baseURL+"/products/"+created.ID, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+c.Token())
+	req.Header.Set("If-Match", `W/"2"`)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	require.NoError(t, c.DeleteProduct(IfMatch(ctx, found.Version), created.ID))
+}
+
+func TestClient_ProductHistory(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+	admin, err := c.GetUserByEmail(ctx, testEmail)
+	require.NoError(t, err)
+
+	created, err := c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Monitor", Price: entity.MustParseMoney("900.00", "BRL"),
+	})
+	require.NoError(t, err)
+	before := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	_, err = c.UpdateProduct(ctx, created.ID, CreateProductRequest{
+		Name: "Monitor", Price: entity.MustParseMoney("750.00", "BRL"),
+	})
+	require.NoError(t, err)
+
+	history, err := c.ProductHistory(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "updated", history[0].Action)
+	assert.Equal(t, admin.ID, history[0].ActorID)
+	require.Len(t, history[0].Changes, 1)
+	assert.Equal(t, "price", history[0].Changes[0].Field)
+	assert.Equal(t, "created", history[1].Action)
+
+	then, err := c.GetProductAsOf(ctx, created.ID, before)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MustParseMoney("900.00", "BRL"), then.Price)
+	_, err = c.GetProductAsOf(ctx, created.ID, before.Add(-time.Hour))
+	assert.True(t, IsNotFound(err))
+
+	reverted, err := c.RevertProduct(IfMatch(ctx, history[0].Version), created.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MustParseMoney("900.00", "BRL"), reverted.Price)
+	assert.Equal(t, history[0].Version+1, reverted.Version)
+	_, err = c.RevertProduct(IfMatch(ctx, history[0].Version), created.ID, 1)
+	assert.True(t, IsPreconditionFailed(err))
+	_, err = c.RevertProduct(ctx, created.ID, 99)
+	assert.True(t, IsNotFound(err))
+
+	history, err = c.ProductHistory(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "reverted", history[0].Action)
+	assert.Equal(t, int64(1), *history[0].RevertOf)
+}
+
+func TestClient_ProductLifecycle(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	ed, err := admin.CreateUser(ctx, CreateUserRequest{Username: "ed", Email: "ed@example.com", Password: testPassword})
+	require.NoError(t, err)
+	_, err = admin.SetUserRole(ctx, ed.ID, "editor")
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	editor := New(admin.baseURL, WithCredentials("ed@example.com", testPassword))
+	shopper := New(admin.baseURL, WithCredentials("shopper@example.com", testPassword))
+
+	created, err := editor.CreateProduct(ctx, CreateProductRequest{
+		Name: "Desk lamp", Price: entity.MustParseMoney("120.00", "BRL"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "draft", created.Status)
+	variant, err := editor.CreateVariant(ctx, created.ID, VariantRequest{SKU: "LAMP-01"})
+	require.NoError(t, err)
+
+	t.Run("hides drafts from non-editors", func(t *testing.T) {
+		_, err := shopper.GetProduct(ctx, created.ID)
+		assert.True(t, IsNotFound(err))
+		products, err := shopper.ListProducts(ctx, ListProductsParams{Statuses: []string{"draft"}})
+		require.NoError(t, err)
+		assert.Empty(t, products)
+
+		_, err = shopper.GetSKU(ctx, "LAMP-01")
+		assert.True(t, IsNotFound(err))
+		_, err = shopper.GetStock(ctx, "LAMP-01")
+		assert.True(t, IsNotFound(err))
+		_, err = shopper.ListVariants(ctx, created.ID)
+		assert.True(t, IsNotFound(err))
+		_, err = shopper.GetVariant(ctx, created.ID, variant.ID)
+		assert.True(t, IsNotFound(err))
+		_, err = shopper.GetProductPrices(ctx, created.ID)
+		assert.True(t, IsNotFound(err))
+
+		var apiErr *APIError
+		_, err = shopper.AddCartItem(ctx, AddCartItemRequest{SKU: "LAMP-01", Quantity: 1})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		_, err = shopper.CreateOrder(ctx, []OrderItemRequest{{SKU: "LAMP-01", Quantity: 1}})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		_, err = shopper.Reserve(ctx, ReservationRequest{SKU: "LAMP-01", Quantity: 1})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		wishlist, err := shopper.CreateWishlist(ctx, "Later")
+		require.NoError(t, err)
+		_, err = shopper.AddToWishlist(ctx, wishlist.ID, created.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		found, err := editor.GetSKU(ctx, "LAMP-01")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.Product.ID)
+
+		products, err = editor.ListProducts(ctx, ListProductsParams{Statuses: []string{"draft"}})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, []string{"published", "archived"}, products[0].NextStatuses)
+	})
+
+	t.Run("only lets editors change the catalog", func(t *testing.T) {
+		forbidden := func(t *testing.T, err error) {
+			t.Helper()
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+		}
+		_, err := shopper.CreateProduct(ctx, CreateProductRequest{Name: "Knockoff", Price: entity.MustParseMoney("1", "BRL")})
+		forbidden(t, err)
+		_, err = shopper.UpdateProduct(ctx, created.ID, CreateProductRequest{Name: "Mine now", Price: entity.MustParseMoney("1", "BRL")})
+		forbidden(t, err)
+		forbidden(t, shopper.DeleteProduct(ctx, created.ID))
+		_, err = shopper.SetProductPrice(ctx, created.ID, entity.MustParseMoney("1", "USD"))
+		forbidden(t, err)
+		_, err = shopper.CreateVariant(ctx, created.ID, VariantRequest{SKU: "LAMP-02"})
+		forbidden(t, err)
+		_, err = shopper.CreateCategory(ctx, CategoryRequest{Name: "Lamps"})
+		forbidden(t, err)
+	})
+
+	t.Run("only lets editors change the status", func(t *testing.T) {
+		_, err := shopper.TransitionProduct(ctx, created.ID, "published")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		published, err := editor.TransitionProduct(IfMatch(ctx, created.Version), created.ID, "published")
+		require.NoError(t, err)
+		assert.Equal(t, "published", published.Status)
+
+		found, err := shopper.GetProduct(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, published.Version, found.Version)
+		results, err := shopper.SearchProducts(ctx, "lamp", 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("schedules publishing and archiving", func(t *testing.T) {
+		publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		_, err := editor.ScheduleProduct(ctx, created.ID, ProductScheduleRequest{PublishAt: &publishAt, UnpublishAt: &publishAt})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		unpublishAt := publishAt.Add(24 * time.Hour)
+		scheduled, err := editor.ScheduleProduct(ctx, created.ID, ProductScheduleRequest{UnpublishAt: &unpublishAt})
+		require.NoError(t, err)
+		assert.Empty(t, scheduled.PublishAt)
+		assert.Equal(t, unpublishAt.Format(time.RFC3339Nano), scheduled.UnpublishAt)
+	})
+
+	t.Run("refuses transitions the lifecycle does not allow", func(t *testing.T) {
+		archived, err := editor.TransitionProduct(ctx, created.ID, "archived")
+		require.NoError(t, err)
+		assert.Empty(t, archived.UnpublishAt)
+
+		_, err = editor.TransitionProduct(ctx, created.ID, "published")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		history, err := editor.ProductHistory(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "status_changed", history[0].Action)
+		_, err = shopper.ProductHistory(ctx, created.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	})
+}
+
+func TestClient_PatchProduct(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+	weight := int64(300)
+
+	created, err := c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Headset", Description: "Wired", Price: entity.MustParseMoney("150.00", "BRL"),
+		Tags: []string{"audio"}, WeightGrams: &weight,
+	})
+	require.NoError(t, err)
+
+	description := "Wireless"
+	merged, err := c.PatchProduct(ctx, created.ID, UpdateProductRequest{Description: &description})
+	require.NoError(t, err)
+	assert.Equal(t, "Headset", merged.Name)
+	assert.Equal(t, "Wireless", merged.Description)
+	assert.Equal(t, created.Price, merged.Price)
+	assert.Equal(t, []string{"audio"}, merged.Tags)
+	assert.Equal(t, int64(300), merged.WeightGrams)
+	assert.Equal(t, created.Version+1, merged.Version)
+
+	patched, err := c.PatchProductOps(ctx, created.ID, []PatchOperation{
+		{Op: "test", Path: "/name", Value: json.RawMessage(`"Headset"`)},
+		{Op: "add", Path: "/tags/-", Value: json.RawMessage(`"wireless"`)},
+		{Op: "replace", Path: "/price/amount", Value: json.RawMessage(`"129.90"`)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audio", "wireless"}, patched.Tags)
+	assert.Equal(t, entity.MustParseMoney("129.90", "BRL"), patched.Price)
+
+	var apiErr *APIError
+	_, err = c.PatchProductOps(ctx, created.ID, []PatchOperation{
+		{Op: "test", Path: "/name", Value: json.RawMessage(`"Speaker"`)},
+		{Op: "replace", Path: "/name", Value: json.RawMessage(`"Speaker"`)},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	_, err = c.PatchProductOps(ctx, created.ID, []PatchOperation{{Op: "remove", Path: "/name"}})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	_, err = c.PatchProductOps(ctx, created.ID, []PatchOperation{{Op: "add", Path: "/version", Value: json.RawMessage(`1`)}})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+	_, err = c.PatchProduct(IfMatch(ctx, created.Version), created.ID, UpdateProductRequest{Description: &description})
+	assert.True(t, IsPreconditionFailed(err))
+
+	found, err := c.GetProduct(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Headset", found.Name)
+	assert.Equal(t, patched.Version, found.Version)
+}
+
+func TestClient_PatchUser(t *testing.T) {
+	server := setupTestServer(t)
+	c := New(server.URL, WithCredentials("carol@example.com", "secret"))
+	admin := New(server.URL, WithCredentials(testEmail, testPassword))
+	ctx := context.Background()
+
+	created, err := c.CreateUser(ctx, CreateUserRequest{
+		Username: "carol", Email: "carol@example.com", Password: "secret",
+	})
+	require.NoError(t, err)
+
+	merged, err := c.PatchUser(ctx, created.ID, UpdateUserRequest{Username: "carol2"})
+	require.NoError(t, err)
+	assert.Equal(t, "carol2", merged.Username)
+	assert.Equal(t, "user", merged.Role)
+
+	promote := []PatchOperation{{Op: "replace", Path: "/role", Value: json.RawMessage(`"admin"`)}}
+	var apiErr *APIError
+	_, err = c.PatchUserOps(ctx, created.ID, promote)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	patched, err := admin.PatchUserOps(ctx, created.ID, promote)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", patched.Role)
+
+	_, err = admin.PatchUserOps(ctx, created.ID, []PatchOperation{
+		{Op: "replace", Path: "/role", Value: json.RawMessage(`"superuser"`)},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/users/"+created.ID, bytes.NewBufferString(`{"role":"user"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+admin.Token())
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Accept-Patch"), "application/merge-patch+json")
+
+	found, err := c.GetUser(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", found.Role)
+}
+
+func TestClient_RequireIfMatch(t *testing.T) {
+	server := setupTestServerWith(t, webserver.Options{RequireIfMatch: true})
+	c := New(server.URL, WithCredentials("bob@example.com", "secret"))
+	ctx := context.Background()
+
+	created, err := c.CreateUser(ctx, CreateUserRequest{
+		Username: "bob", Email: "bob@example.com", Password: "secret",
+	})
+	require.NoError(t, err)
+
+	_, err = c.UpdateUser(ctx, created.ID, UpdateUserRequest{Username: "bob2"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusPreconditionRequired, apiErr.StatusCode)
+
+	updated, err := c.UpdateUser(IfMatch(ctx, created.Version), created.ID, UpdateUserRequest{Username: "bob2"})
+	require.NoError(t, err)
+	assert.Equal(t, "bob2", updated.Username)
+}
+
+func TestClient_ProductsIterator(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		_, err := c.CreateProduct(ctx, CreateProductRequest{Name: fmt.Sprintf("p%d", i), Price: entity.MustParseMoney("10", "BRL")})
+		require.NoError(t, err)
+	}
+
+	var names []string
+	for p, err := range c.Products(ctx, ListProductsParams{Limit: 3}) {
+		require.NoError(t, err)
+		names = append(names, p.Name)
+	}
+	assert.Len(t, names, 7)
+
+	// Sorting by anything but created_at falls back to offset pages.
+	names = nil
+	for p, err := range c.Products(ctx, ListProductsParams{Limit: 3, Sort: "-name"}) {
+		require.NoError(t, err)
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"p6", "p5", "p4", "p3", "p2", "p1", "p0"}, names)
+
+	page, err := c.ListProductsPage(ctx, ListProductsParams{Limit: 5, IncludeTotal: true}, "")
+	require.NoError(t, err)
+	assert.Len(t, page.Data, 5)
+	require.NotNil(t, page.Total)
+	assert.Equal(t, int64(7), *page.Total)
+	assert.Empty(t, page.PrevCursor)
+
+	page, err = c.ListProductsPage(ctx, ListProductsParams{Limit: 5}, page.NextCursor)
+	require.NoError(t, err)
+	assert.Len(t, page.Data, 2)
+	assert.Empty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	// Breaking out early stops fetching.
+	count := 0
+	for range c.Products(ctx, ListProductsParams{Limit: 3}) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	// Without page or cursor the listing is an offset array, whatever the sort.
+	var sorted []ProductResponse
+	query := url.Values{"sort": {"-name"}}
+	require.NoError(t, c.doAuth(ctx, http.MethodGet, "/products", query, nil, &sorted))
+	require.Len(t, sorted, 7)
+	assert.Equal(t, "p6", sorted[0].Name)
+}
+
+func TestClient_TokenRefresh(t *testing.T) {
+	t.Run("logs in again when the token is rejected", func(t *testing.T) {
+		c := setupLoggedInClient(t)
+		c.SetToken("not-a-valid-token")
+
+		_, err := c.ListProducts(context.Background(), ListProductsParams{})
+		require.NoError(t, err)
+		assert.NotEqual(t, "not-a-valid-token", c.Token())
+	})
+
+	t.Run("logs in lazily with configured credentials", func(t *testing.T) {
+		server := setupTestServer(t)
+		ctx := context.Background()
+
+		c := New(server.URL, WithCredentials(testEmail, testPassword))
+		_, err := c.ListProducts(ctx, ListProductsParams{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, c.Token())
+	})
+
+	t.Run("returns the API error without credentials", func(t *testing.T) {
+		server := setupTestServer(t)
+		_, err := New(server.URL).ListProducts(context.Background(), ListProductsParams{})
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	})
+}
+
+func TestClient_Currencies(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	product, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Mouse", Price: entity.MustParseMoney("10.99", "BRL")})
+	require.NoError(t, err)
+
+	rates, err := c.SetExchangeRates(ctx, map[string]string{"USD": "0.2", "JPY": "28.5"})
+	require.NoError(t, err)
+	assert.Equal(t, "BRL", rates.Base)
+	assert.Equal(t, "0.2", rates.Rates["USD"])
+
+	t.Run("converts with the exchange rate", func(t *testing.T) {
+		found, err := c.GetProductIn(ctx, product.ID, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, entity.MustParseMoney("2.20", "USD"), found.Price)
+		assert.Equal(t, "converted", found.PriceSource)
+		require.NotNil(t, found.BasePrice)
+		assert.Equal(t, "10.99", found.BasePrice.Decimal())
+	})
+
+	t.Run("prefers the price list", func(t *testing.T) {
+		prices, err := c.SetProductPrice(ctx, product.ID, entity.MustParseMoney("1.99", "USD"))
+		require.NoError(t, err)
+		assert.Len(t, prices.Prices, 1)
+
+		page, err := c.ListProductsPage(ctx, ListProductsParams{Currency: "USD"}, "")
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "1.99", page.Data[0].Price.Decimal())
+		assert.Equal(t, "list", page.Data[0].PriceSource)
+
+		require.NoError(t, c.DeleteProductPrice(ctx, product.ID, "USD"))
+		found, err := c.GetProductIn(ctx, product.ID, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, "2.20", found.Price.Decimal())
+	})
+
+	t.Run("rejects a price in the base currency", func(t *testing.T) {
+		_, err := c.SetProductPrice(ctx, product.ID, entity.MustParseMoney("5", "BRL"))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("answers 406 without a rate", func(t *testing.T) {
+		_, err := c.GetProductIn(ctx, product.ID, "EUR")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotAcceptable, apiErr.StatusCode)
+	})
+
+	t.Run("honours Accept-Currency", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, c.baseURL+"/products/"+product.ID, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+c.Token())
+		req.Header.Set("Accept-Currency", "XYZ, USD;q=0.5, JPY;q=0.9")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var found ProductResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&found))
+		assert.Equal(t, entity.MustParseMoney("313", "JPY"), found.Price)
+		assert.Equal(t, "Accept-Currency", resp.Header.Get("Vary"))
+	})
+
+	t.Run("only admins update rates", func(t *testing.T) {
+		_, err := c.CreateUser(ctx, CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: "secret"})
+		require.NoError(t, err)
+		bob := New(c.baseURL, WithCredentials("bob@example.com", "secret"))
+
+		_, err = bob.GetExchangeRates(ctx)
+		require.NoError(t, err)
+		_, err = bob.SetExchangeRates(ctx, map[string]string{"USD": "1"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	})
+}
+
+func TestClient_Categories(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	electronics, err := c.CreateCategory(ctx, CategoryRequest{Name: "Eletrônicos"})
+	require.NoError(t, err)
+	assert.Equal(t, "eletronicos", electronics.Slug)
+
+	computers, err := c.CreateCategory(ctx, CategoryRequest{Name: "Computers", ParentID: &electronics.ID, Position: 1})
+	require.NoError(t, err)
+	phones, err := c.CreateCategory(ctx, CategoryRequest{Name: "Phones", ParentID: &electronics.ID, Position: 0})
+	require.NoError(t, err)
+	assert.Equal(t, []CategoryRef{
+		{ID: electronics.ID, Name: "Eletrônicos", Slug: "eletronicos"},
+		{ID: phones.ID, Name: "Phones", Slug: "phones"},
+	}, phones.Path)
+
+	tree, err := c.GetCategoryTree(ctx)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "phones", tree[0].Children[0].Slug)
+
+	laptop, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Laptop", Price: entity.MustParseMoney("5000", "BRL")})
+	require.NoError(t, err)
+	_, err = c.CreateProduct(ctx, CreateProductRequest{Name: "Cable", Price: entity.MustParseMoney("20", "BRL")})
+	require.NoError(t, err)
+
+	breadcrumbs, err := c.SetProductCategories(ctx, laptop.ID, []string{computers.ID})
+	require.NoError(t, err)
+	require.Len(t, breadcrumbs, 1)
+	assert.Len(t, breadcrumbs[0], 2)
+
+	t.Run("shows breadcrumbs on the product", func(t *testing.T) {
+		found, err := c.GetProduct(ctx, laptop.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Breadcrumbs, 1)
+		assert.Equal(t, "computers", found.Breadcrumbs[0][1].Slug)
+	})
+
+	t.Run("lists products with or without descendants", func(t *testing.T) {
+		products, err := c.ListCategoryProducts(ctx, "eletronicos", false, ListProductsParams{})
+		require.NoError(t, err)
+		assert.Empty(t, products)
+
+		products, err = c.ListCategoryProducts(ctx, "eletronicos", true, ListProductsParams{})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, laptop.ID, products[0].ID)
+	})
+
+	t.Run("refuses cycles and non-empty deletes", func(t *testing.T) {
+		_, err := c.UpdateCategory(ctx, electronics.ID, CategoryRequest{Name: "Eletrônicos", ParentID: &computers.ID})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		err = c.DeleteCategory(ctx, electronics.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	})
+
+	t.Run("renames and deletes a leaf", func(t *testing.T) {
+		updated, err := c.UpdateCategory(ctx, "phones", CategoryRequest{Name: "Smartphones", Slug: "smartphones", ParentID: &electronics.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "smartphones", updated.Slug)
+
+		require.NoError(t, c.DeleteCategory(ctx, "smartphones"))
+		_, err = c.GetCategory(ctx, "smartphones")
+		assert.True(t, IsNotFound(err))
+	})
+}
+
+func TestClient_TagsAndFacets(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	audio, err := c.CreateCategory(ctx, CategoryRequest{Name: "Audio"})
+	require.NoError(t, err)
+
+	headphones, err := c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Headphones", Price: entity.MustParseMoney("80", "BRL"), Tags: []string{"Wireless", "audio", "audio"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audio", "wireless"}, headphones.Tags)
+	_, err = c.SetProductCategories(ctx, headphones.ID, []string{audio.ID})
+	require.NoError(t, err)
+
+	_, err = c.CreateProduct(ctx, CreateProductRequest{
+		Name: "Speaker", Price: entity.MustParseMoney("300", "BRL"), Tags: []string{"audio"},
+	})
+	require.NoError(t, err)
+	_, err = c.CreateProduct(ctx, CreateProductRequest{Name: "Mouse", Price: entity.MustParseMoney("40", "BRL"), Tags: []string{"wireless"}})
+	require.NoError(t, err)
+
+	t.Run("filters by any or all tags", func(t *testing.T) {
+		products, err := c.ListProducts(ctx, ListProductsParams{Tags: []string{"audio", "wireless"}})
+		require.NoError(t, err)
+		assert.Len(t, products, 3)
+
+		products, err = c.ListProducts(ctx, ListProductsParams{Tags: []string{"audio", "wireless"}, AllTags: true})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, headphones.ID, products[0].ID)
+	})
+
+	t.Run("keeps tags on update unless given", func(t *testing.T) {
+		updated, err := c.UpdateProduct(ctx, headphones.ID, CreateProductRequest{Name: "Headphones 2", Price: headphones.Price})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"audio", "wireless"}, updated.Tags)
+	})
+
+	t.Run("counts facets under the current filters", func(t *testing.T) {
+		facets, err := c.ProductFacets(ctx, ListProductsParams{Tags: []string{"audio"}}, []string{"100"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), facets.Total)
+		assert.Equal(t, []TagFacet{{Tag: "audio", Count: 2}, {Tag: "wireless", Count: 1}}, facets.Tags)
+		require.Len(t, facets.Categories, 1)
+		assert.Equal(t, "audio", facets.Categories[0].Slug)
+		assert.Equal(t, "BRL", facets.Currency)
+		require.Len(t, facets.PriceBuckets, 2)
+		assert.Equal(t, int64(1), facets.PriceBuckets[0].Count)
+		assert.Equal(t, int64(1), facets.PriceBuckets[1].Count)
+	})
+
+	t.Run("rejects invalid tags", func(t *testing.T) {
+		_, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Bad", Price: entity.MustParseMoney("1", "BRL"), Tags: []string{"a,b"}})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+}
+
+func TestClient_Variants(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	shirt, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Basic Tee", Price: entity.MustParseMoney("49.90", "BRL")})
+	require.NoError(t, err)
+
+	variants, err := c.SetProductOptions(ctx, shirt.ID, ProductOptionsRequest{
+		Options: []ProductOption{
+			{Name: "Color", Values: []string{"Red", "Blue"}},
+			{Name: "Size", Values: []string{"M", "L"}},
+		},
+		SKUPrefix: "tee",
+	})
+	require.NoError(t, err)
+	require.Len(t, variants.Variants, 4)
+	assert.Equal(t, "TEE-RED-M", variants.Variants[0].SKU)
+	assert.Equal(t, "color", variants.Options[0].Name)
+
+	redL := variants.Variants[1]
+	price := entity.MustParseMoney("54.90", "BRL")
+
+	t.Run("updates a variant's price and barcode", func(t *testing.T) {
+		updated, err := c.UpdateVariant(ctx, shirt.ID, redL.ID, VariantRequest{
+			SKU: redL.SKU, Barcode: "4006381333931", Options: redL.Options, Price: &price,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, price, updated.Price)
+		assert.True(t, updated.PriceOverride)
+	})
+
+	t.Run("looks a SKU up with its product", func(t *testing.T) {
+		found, err := c.GetSKU(ctx, "tee-red-l")
+		require.NoError(t, err)
+		assert.Equal(t, redL.ID, found.ID)
+		assert.Equal(t, "4006381333931", found.Barcode)
+		assert.Equal(t, shirt.ID, found.Product.ID)
+
+		_, err = c.GetSKU(ctx, "nope")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("refuses a SKU in use", func(t *testing.T) {
+		other, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Mug", Price: entity.MustParseMoney("20", "BRL")})
+		require.NoError(t, err)
+		_, err = c.CreateVariant(ctx, other.ID, VariantRequest{SKU: "TEE-RED-M"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	})
+
+	t.Run("keeps surviving variants when options change", func(t *testing.T) {
+		variants, err := c.SetProductOptions(ctx, shirt.ID, ProductOptionsRequest{
+			Options:   []ProductOption{{Name: "color", Values: []string{"Red"}}, {Name: "size", Values: []string{"L", "XL"}}},
+			SKUPrefix: "tee",
+		})
+		require.NoError(t, err)
+		require.Len(t, variants.Variants, 2)
+		assert.Equal(t, redL.ID, variants.Variants[0].ID)
+		assert.Equal(t, price, variants.Variants[0].Price)
+		assert.Equal(t, "TEE-RED-XL", variants.Variants[1].SKU)
+
+		require.NoError(t, c.DeleteVariant(ctx, shirt.ID, redL.ID))
+		_, err = c.GetVariant(ctx, shirt.ID, redL.ID)
+		assert.True(t, IsNotFound(err))
+	})
+}
+
+func TestClient_Inventory(t *testing.T) {
+	c := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	mug, err := c.CreateProduct(ctx, CreateProductRequest{Name: "Mug", Price: entity.MustParseMoney("20", "BRL")})
+	require.NoError(t, err)
+	_, err = c.TransitionProduct(ctx, mug.ID, "published")
+	require.NoError(t, err)
+	_, err = c.CreateVariant(ctx, mug.ID, VariantRequest{SKU: "MUG-01"})
+	require.NoError(t, err)
+	_, err = c.CreateWarehouse(ctx, "sp-01", "São Paulo")
+	require.NoError(t, err)
+
+	stock, err := c.AdjustStock(ctx, "mug-01", "SP-01", 10, "initial count")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Available)
+
+	t.Run("reserves concurrently without overselling", func(t *testing.T) {
+		results := make(chan error, 15)
+		for range 15 {
+			go func() {
+				_, err := c.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 1})
+				results <- err
+			}()
+		}
+		conflicts := 0
+		for range 15 {
+			if err := <-results; err != nil {
+				var apiErr *APIError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+				conflicts++
+			}
+		}
+		assert.Equal(t, 5, conflicts)
+
+		stock, err := c.GetStock(ctx, "MUG-01")
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), stock.Reserved)
+		assert.Equal(t, int64(0), stock.Available)
+	})
+
+	t.Run("commits and releases reservations", func(t *testing.T) {
+		_, err := c.AdjustStock(ctx, "MUG-01", "SP-01", 2, "restock")
+		require.NoError(t, err)
+		res, err := c.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 2, Warehouse: "sp-01", Reference: "order-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "SP-01", res.Warehouse)
+		assert.Equal(t, "MUG-01", res.SKU)
+
+		committed, err := c.CommitReservation(ctx, res.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "committed", committed.Status)
+
+		_, err = c.ReleaseReservation(ctx, res.ID)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		stock, err := c.GetStock(ctx, "MUG-01")
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), stock.OnHand)
+	})
+
+	t.Run("lists the ledger", func(t *testing.T) {
+		movements, err := c.StockMovements(ctx, "MUG-01", 2)
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		assert.Equal(t, "commit", movements[0].Kind)
+		assert.Equal(t, int64(-2), movements[0].OnHandDelta)
+	})
+
+	t.Run("keeps reservations to their owner", func(t *testing.T) {
+		_, err := c.AdjustStock(ctx, "MUG-01", "SP-01", 2, "restock")
+		require.NoError(t, err)
+		_, err = c.CreateUser(ctx, CreateUserRequest{Username: "dave", Email: "dave@example.com", Password: testPassword})
+		require.NoError(t, err)
+		dave := New(c.baseURL, WithCredentials("dave@example.com", testPassword))
+
+		var apiErr *APIError
+		_, err = dave.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 101})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		_, err = dave.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 1, TTLSeconds: 7200})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		mine, err := dave.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 1})
+		require.NoError(t, err)
+		_, err = dave.CommitReservation(ctx, mine.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		theirs, err := c.Reserve(ctx, ReservationRequest{SKU: "MUG-01", Quantity: 1})
+		require.NoError(t, err)
+		_, err = dave.GetReservation(ctx, theirs.ID)
+		assert.True(t, IsNotFound(err))
+		_, err = dave.ReleaseReservation(ctx, theirs.ID)
+		assert.True(t, IsNotFound(err))
+
+		released, err := dave.ReleaseReservation(ctx, mine.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "released", released.Status)
+		_, err = c.ReleaseReservation(ctx, theirs.ID)
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_Cart(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	book, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Book", Price: entity.MustParseMoney("35.50", "BRL")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, book.ID, "published")
+	require.NoError(t, err)
+	tee, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Tee", Price: entity.MustParseMoney("49.90", "BRL")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, tee.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.CreateVariant(ctx, tee.ID, VariantRequest{SKU: "TEE-M"})
+	require.NoError(t, err)
+
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	shopper := New(admin.baseURL)
+
+	t.Run("builds a guest cart", func(t *testing.T) {
+		cart, err := shopper.GetCart(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+
+		cart, err = shopper.AddCartItem(ctx, AddCartItemRequest{SKU: "tee-m", Quantity: 2})
+		require.NoError(t, err)
+		assert.NotEmpty(t, shopper.CartToken())
+		assert.Equal(t, entity.MustParseMoney("99.80", "BRL"), cart.Subtotal)
+
+		_, err = shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: tee.ID, Quantity: 1})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("merges the guest cart on login", func(t *testing.T) {
+		require.NoError(t, admin.Login(ctx, "shopper@example.com", testPassword))
+		_, err := admin.AddCartItem(ctx, AddCartItemRequest{SKU: "TEE-M", Quantity: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, shopper.Login(ctx, "shopper@example.com", testPassword))
+		assert.Empty(t, shopper.CartToken())
+
+		cart, err := shopper.GetCart(ctx)
+		require.NoError(t, err)
+		require.Len(t, cart.Items, 1)
+		assert.Equal(t, int64(3), cart.Items[0].Quantity)
+		assert.Equal(t, "TEE-M", cart.Items[0].SKU)
+	})
+
+	t.Run("updates quantities within limits", func(t *testing.T) {
+		cart, err := shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: book.ID, Quantity: 1})
+		require.NoError(t, err)
+		require.Len(t, cart.Items, 2)
+		line := cart.Items[1].ID
+
+		_, err = shopper.UpdateCartItem(ctx, line, 100)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		cart, err = shopper.UpdateCartItem(ctx, line, 4)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), cart.ItemCount)
+		assert.Equal(t, entity.MustParseMoney("291.70", "BRL"), cart.Subtotal)
+
+		cart, err = shopper.RemoveCartItem(ctx, line)
+		require.NoError(t, err)
+		assert.Len(t, cart.Items, 1)
+
+		require.NoError(t, shopper.ClearCart(ctx))
+		cart, err = shopper.GetCart(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+	})
+}
+
+func TestClient_Orders(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	book, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Book", Price: entity.MustParseMoney("35.50", "BRL")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, book.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "buyer", Email: "buyer@example.com", Password: testPassword})
+	require.NoError(t, err)
+	buyer := New(admin.baseURL, WithCredentials("buyer@example.com", testPassword))
+
+	t.Run("places an order from the cart at current prices", func(t *testing.T) {
+		_, err := buyer.AddCartItem(ctx, AddCartItemRequest{ProductID: book.ID, Quantity: 2})
+		require.NoError(t, err)
+		_, err = admin.UpdateProduct(ctx, book.ID, CreateProductRequest{Name: "Book", Price: entity.MustParseMoney("40", "BRL")})
+		require.NoError(t, err)
+
+		order, err := buyer.CheckoutCart(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", order.Status)
+		assert.Equal(t, []string{"paid", "cancelled"}, order.NextStatuses)
+		assert.Equal(t, entity.MustParseMoney("80", "BRL"), order.Total)
+
+		cart, err := buyer.GetCart(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+
+		_, err = buyer.CheckoutCart(ctx)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("moves orders through the state machine", func(t *testing.T) {
+		order, err := buyer.CreateOrder(ctx, []OrderItemRequest{{ProductID: book.ID, Quantity: 1}})
+		require.NoError(t, err)
+
+		_, err = buyer.TransitionOrder(ctx, order.ID, "paid", "")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		_, err = admin.TransitionOrder(ctx, order.ID, "completed", "")
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = admin.TransitionOrder(ctx, order.ID, "paid", "pix")
+		require.NoError(t, err)
+		paid, err := admin.TransitionOrder(ctx, order.ID, "fulfilled", "")
+		require.NoError(t, err)
+		assert.Equal(t, "fulfilled", paid.Status)
+		require.Len(t, paid.Transitions, 2)
+		assert.Equal(t, "pix", paid.Transitions[0].Note)
+	})
+
+	t.Run("lists each user's own orders", func(t *testing.T) {
+		orders, err := buyer.ListOrders(ctx, ListOrdersParams{})
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "fulfilled", orders[0].Status)
+
+		orders, err = buyer.ListOrders(ctx, ListOrdersParams{Status: "pending"})
+		require.NoError(t, err)
+		assert.Len(t, orders, 1)
+
+		_, err = buyer.ListOrders(ctx, ListOrdersParams{All: true})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		mine, err := admin.ListOrders(ctx, ListOrdersParams{})
+		require.NoError(t, err)
+		assert.Empty(t, mine)
+		all, err := admin.ListOrders(ctx, ListOrdersParams{All: true})
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+
+		_, err = admin.GetOrder(ctx, all[0].ID)
+		require.NoError(t, err)
+	})
+	t.Run("refuses SKUs out of stock and restocks cancelled orders", func(t *testing.T) {
+		poster, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Poster", Price: entity.MustParseMoney("25", "BRL")})
+		require.NoError(t, err)
+		_, err = admin.TransitionProduct(ctx, poster.ID, "published")
+		require.NoError(t, err)
+		_, err = admin.CreateVariant(ctx, poster.ID, VariantRequest{SKU: "POSTER-A2"})
+		require.NoError(t, err)
+
+		_, err = buyer.CreateOrder(ctx, []OrderItemRequest{{SKU: "POSTER-A2", Quantity: 1}})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = admin.CreateWarehouse(ctx, "sp-01", "São Paulo")
+		require.NoError(t, err)
+		_, err = admin.AdjustStock(ctx, "POSTER-A2", "SP-01", 1, "initial count")
+		require.NoError(t, err)
+		order, err := buyer.CreateOrder(ctx, []OrderItemRequest{{SKU: "POSTER-A2", Quantity: 1}})
+		require.NoError(t, err)
+		_, err = buyer.CreateOrder(ctx, []OrderItemRequest{{SKU: "POSTER-A2", Quantity: 1}})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = admin.TransitionOrder(ctx, order.ID, "cancelled", "")
+		require.NoError(t, err)
+		stock, err := admin.GetStock(ctx, "POSTER-A2")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stock.Available)
+	})
+}
+
+func TestClient_Discounts(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	brl := func(amount string) entity.Money { return entity.MustParseMoney(amount, "BRL") }
+
+	clothes, err := admin.CreateCategory(ctx, CategoryRequest{Name: "Clothes"})
+	require.NoError(t, err)
+	socksCategory, err := admin.CreateCategory(ctx, CategoryRequest{Name: "Socks", ParentID: &clothes.ID})
+	require.NoError(t, err)
+	shirt, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Shirt", Price: brl("50")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, shirt.ID, "published")
+	require.NoError(t, err)
+	socks, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Socks", Price: brl("10")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, socks.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.SetProductCategories(ctx, shirt.ID, []string{clothes.ID})
+	require.NoError(t, err)
+	_, err = admin.SetProductCategories(ctx, socks.ID, []string{socksCategory.ID})
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	shopper := New(admin.baseURL, WithCredentials("shopper@example.com", testPassword))
+
+	t.Run("creates coupons and promotions as admin only", func(t *testing.T) {
+		_, err := admin.CreatePromotion(ctx, CreatePromotionRequest{
+			Name: "3 for 2", BuyQuantity: 2, GetQuantity: 1, CategoryIDs: []string{clothes.ID},
+		})
+		require.NoError(t, err)
+		coupon, err := admin.CreateCoupon(ctx, CreateCouponRequest{
+			Code: "welcome", Type: "percent", PercentOff: 10, MaxUsesPerUser: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "WELCOME", coupon.Code)
+
+		_, err = admin.CreateCoupon(ctx, CreateCouponRequest{Code: "WELCOME", Type: "percent", PercentOff: 5})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = shopper.ListCoupons(ctx, 0, 0)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	})
+
+	t.Run("shows promotions and the coupon line by line in the cart", func(t *testing.T) {
+		_, err := shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: shirt.ID, Quantity: 1})
+		require.NoError(t, err)
+		cart, err := shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: socks.ID, Quantity: 2})
+		require.NoError(t, err)
+		// Socks are in a subcategory of Clothes, so the cheapest of the
+		// three units is free.
+		assert.Equal(t, brl("70"), cart.Subtotal)
+		assert.Equal(t, brl("10"), cart.Discount)
+		assert.Equal(t, brl("10"), cart.Items[1].Total)
+
+		cart, err = shopper.ApplyCoupon(ctx, "Welcome")
+		require.NoError(t, err)
+		assert.Equal(t, "WELCOME", cart.CouponCode)
+		assert.Equal(t, brl("16"), cart.Discount)
+		assert.Equal(t, brl("54"), cart.Total)
+		require.Len(t, cart.Discounts, 2)
+		assert.Equal(t, "promotion", cart.Discounts[0].Kind)
+		assert.Equal(t, AppliedDiscount{
+			Kind: "coupon", SourceID: cart.Discounts[1].SourceID, Source: "WELCOME", Amount: brl("6"),
+		}, cart.Discounts[1])
+
+		_, err = shopper.ApplyCoupon(ctx, "NOPE")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("keeps the discount on the order and counts the use", func(t *testing.T) {
+		order, err := shopper.CheckoutCart(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "WELCOME", order.CouponCode)
+		assert.Equal(t, brl("70"), order.Subtotal)
+		assert.Equal(t, brl("54"), order.Total)
+		assert.Equal(t, brl("45"), order.Items[0].Total)
+
+		coupon, err := admin.GetCoupon(ctx, "welcome")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), coupon.Uses)
+
+		_, err = shopper.PlaceOrder(ctx, CreateOrderRequest{
+			Items: []OrderItemRequest{{ProductID: shirt.ID, Quantity: 1}}, CouponCode: "WELCOME",
+		})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	})
+
+	t.Run("deletes coupons and promotions", func(t *testing.T) {
+		require.NoError(t, admin.DeleteCoupon(ctx, "WELCOME"))
+		assert.True(t, IsNotFound(admin.DeleteCoupon(ctx, "WELCOME")))
+
+		promotions, err := admin.ListPromotions(ctx)
+		require.NoError(t, err)
+		require.Len(t, promotions, 1)
+		require.NoError(t, admin.DeletePromotion(ctx, promotions[0].ID))
+	})
+}
+
+func TestClient_Tax(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	brl := func(amount string) entity.Money { return entity.MustParseMoney(amount, "BRL") }
+
+	book, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Book", Price: brl("40"), TaxClass: "Reduced"})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, book.ID, "published")
+	require.NoError(t, err)
+	lamp, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Lamp", Price: brl("100")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	shopper := New(admin.baseURL, WithCredentials("shopper@example.com", testPassword))
+
+	t.Run("sets the tax table as admin only", func(t *testing.T) {
+		rates, err := admin.SetTaxRates(ctx, []TaxRate{
+			{Country: "br", TaxClass: "standard", Rate: "17"},
+			{Country: "BR", Region: "sp", TaxClass: "standard", Rate: "18"},
+			{Country: "BR", TaxClass: "reduced", Rate: "5"},
+		})
+		require.NoError(t, err)
+		require.Len(t, rates.Rates, 3)
+		assert.Equal(t, "half_up", rates.Rounding)
+		assert.False(t, rates.PricesIncludeTax)
+
+		_, err = shopper.SetTaxRates(ctx, nil)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		_, err = admin.SetTaxRates(ctx, []TaxRate{{Country: "BR", TaxClass: "standard", Rate: "150"}})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("quotes tax by location and tax class", func(t *testing.T) {
+		quote, err := shopper.QuoteTax(ctx, TaxQuoteRequest{
+			Items:   []OrderItemRequest{{ProductID: book.ID, Quantity: 2}, {ProductID: lamp.ID, Quantity: 1}},
+			Country: "BR", Region: "SP",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "reduced", quote.Lines[0].TaxClass)
+		assert.Equal(t, brl("4"), quote.Lines[0].Tax)
+		assert.Equal(t, "18", quote.Lines[1].Rate)
+		assert.Equal(t, brl("22"), quote.Tax)
+		assert.Equal(t, brl("202"), quote.Gross)
+
+		quote, err = shopper.QuoteTax(ctx, TaxQuoteRequest{Items: []OrderItemRequest{{ProductID: lamp.ID, Quantity: 1}}})
+		require.NoError(t, err)
+		assert.Equal(t, brl("0"), quote.Tax, "no default location, no tax")
+
+		_, err = shopper.QuoteTax(ctx, TaxQuoteRequest{Items: []OrderItemRequest{{ProductID: lamp.ID, Quantity: 1}}, Country: "Brazil"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("shows tax in the cart and charges it with the order", func(t *testing.T) {
+		_, err := shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: lamp.ID, Quantity: 1})
+		require.NoError(t, err)
+		cart, err := shopper.GetCartIn(ctx, "BR", "RJ")
+		require.NoError(t, err)
+		assert.Equal(t, "BR", cart.TaxCountry)
+		assert.Equal(t, "17", cart.Items[0].TaxRate)
+		assert.Equal(t, brl("17"), cart.Tax)
+		assert.Equal(t, brl("117"), cart.Total)
+
+		order, err := shopper.PlaceOrder(ctx, CreateOrderRequest{Country: "BR", Region: "SP"})
+		require.NoError(t, err)
+		assert.Equal(t, brl("18"), order.Tax)
+		assert.Equal(t, brl("118"), order.Total)
+		assert.Equal(t, brl("18"), order.Items[0].Tax)
+		assert.Equal(t, "SP", order.TaxRegion)
+	})
+}
+
+func TestClient_Shipping(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	brl := func(amount string) entity.Money { return entity.MustParseMoney(amount, "BRL") }
+	money := func(amount string) *entity.Money { m := brl(amount); return &m }
+	grams := func(g int64) *int64 { return &g }
+
+	lamp, err := admin.CreateProduct(ctx, CreateProductRequest{
+		Name: "Lamp", Price: brl("80"), WeightGrams: grams(1500),
+		Dimensions: &Dimensions{LengthMM: 300, WidthMM: 300, HeightMM: 400},
+	})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), lamp.WeightGrams)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	shopper := New(admin.baseURL, WithCredentials("shopper@example.com", testPassword))
+
+	zone, err := admin.CreateShippingZone(ctx, ShippingZoneRequest{Name: "Brasil", Areas: []ShippingArea{{Country: "br"}}})
+	require.NoError(t, err)
+	flat, err := admin.AddShippingMethod(ctx, zone.ID, ShippingMethodRequest{Name: "Standard", Kind: "flat", Cost: money("30")})
+	require.NoError(t, err)
+	_, err = admin.AddShippingMethod(ctx, zone.ID, ShippingMethodRequest{
+		Name: "Correio", Kind: "weight", VolumetricDivisor: 6000,
+		Tiers: []WeightTier{{UpToGrams: 5000, Cost: brl("20")}, {UpToGrams: 20000, Cost: brl("45")}},
+	})
+	require.NoError(t, err)
+	_, err = admin.AddShippingMethod(ctx, zone.ID, ShippingMethodRequest{Name: "Free", Kind: "free_over", Threshold: money("150")})
+	require.NoError(t, err)
+
+	t.Run("manages zones as admin only", func(t *testing.T) {
+		zones, err := admin.ListShippingZones(ctx)
+		require.NoError(t, err)
+		require.Len(t, zones, 1)
+		assert.Len(t, zones[0].Methods, 3)
+
+		_, err = shopper.CreateShippingZone(ctx, ShippingZoneRequest{Name: "Sul", Areas: []ShippingArea{{Country: "BR"}}})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		_, err = admin.AddShippingMethod(ctx, zone.ID, ShippingMethodRequest{Name: "Bad", Kind: "teleport"})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("quotes the cart's shipping options, cheapest first", func(t *testing.T) {
+		_, err := shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: lamp.ID, Quantity: 1})
+		require.NoError(t, err)
+		quote, err := shopper.QuoteShipping(ctx, "BR", "SP", "01310-100")
+		require.NoError(t, err)
+		assert.Equal(t, "01310100", quote.PostalCode)
+		assert.Equal(t, int64(1500), quote.WeightGrams)
+		// The lamp is bulky: 36,000,000 mm³ / 6,000 is 6 kg.
+		require.Len(t, quote.Rates, 2)
+		assert.Equal(t, "Standard", quote.Rates[0].Name)
+		assert.Equal(t, "Correio", quote.Rates[1].Name)
+		assert.Equal(t, brl("45"), quote.Rates[1].Cost)
+
+		_, err = shopper.AddCartItem(ctx, AddCartItemRequest{ProductID: lamp.ID, Quantity: 1})
+		require.NoError(t, err)
+		quote, err = shopper.QuoteShipping(ctx, "BR", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "Free", quote.Rates[0].Name)
+
+		quote, err = shopper.QuoteShipping(ctx, "PT", "", "")
+		require.NoError(t, err)
+		assert.Empty(t, quote.Rates)
+
+		_, err = shopper.QuoteShipping(ctx, "", "", "")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("charges the chosen method with the order", func(t *testing.T) {
+		_, err := shopper.PlaceOrder(ctx, CreateOrderRequest{Country: "PT", ShippingMethodID: flat.ID})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		order, err := shopper.PlaceOrder(ctx, CreateOrderRequest{Country: "BR", ShippingMethodID: flat.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "Standard", order.ShippingMethod)
+		assert.Equal(t, brl("30"), order.Shipping)
+		assert.Equal(t, brl("190"), order.Total)
+	})
+}
+
+func TestClient_Addresses(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	brl := func(amount string) entity.Money { return entity.MustParseMoney(amount, "BRL") }
+	yes := true
+
+	lamp, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Lamp", Price: brl("100")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.SetTaxRates(ctx, []TaxRate{{Country: "BR", Region: "RJ", TaxClass: "standard", Rate: "20"}})
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "shopper", Email: "shopper@example.com", Password: testPassword})
+	require.NoError(t, err)
+	shopper := New(admin.baseURL, WithCredentials("shopper@example.com", testPassword))
+
+	home, err := shopper.CreateAddress(ctx, AddressRequest{Label: "Casa", PostalAddress: PostalAddress{
+		Recipient: "Ana", Line1: "Rua das Flores, 10", City: "Rio de Janeiro", Region: "rj", PostalCode: "20000000", Country: "br",
+	}})
+	require.NoError(t, err)
+
+	t.Run("keeps a normalized address book with one default of each", func(t *testing.T) {
+		assert.Equal(t, "20000-000", home.PostalCode)
+		assert.True(t, home.DefaultShipping)
+		assert.True(t, home.DefaultBilling)
+
+		office, err := shopper.CreateAddress(ctx, AddressRequest{
+			PostalAddress:  PostalAddress{Recipient: "Ana", Line1: "Av. Paulista, 1000", City: "São Paulo", Region: "SP", PostalCode: "01310-100", Country: "BR"},
+			DefaultBilling: &yes,
+		})
+		require.NoError(t, err)
+		assert.False(t, office.DefaultShipping)
+
+		addresses, err := shopper.ListAddresses(ctx)
+		require.NoError(t, err)
+		require.Len(t, addresses, 2)
+		assert.False(t, addresses[0].DefaultBilling)
+		assert.True(t, addresses[1].DefaultBilling)
+
+		_, err = admin.GetAddress(ctx, home.ID)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+		_, err = shopper.CreateAddress(ctx, AddressRequest{PostalAddress: PostalAddress{
+			Recipient: "Ana", Line1: "Main St 1", City: "Boston", PostalCode: "0211", Country: "US",
+		}})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("copies the addresses onto orders", func(t *testing.T) {
+		order, err := shopper.PlaceOrder(ctx, CreateOrderRequest{Items: []OrderItemRequest{{ProductID: lamp.ID, Quantity: 1}}})
+		require.NoError(t, err)
+		require.NotNil(t, order.ShippingAddress)
+		assert.Equal(t, "Rio de Janeiro", order.ShippingAddress.City)
+		assert.Equal(t, "São Paulo", order.BillingAddress.City)
+		assert.Equal(t, "RJ", order.TaxRegion, "taxed where it ships")
+		assert.Equal(t, brl("20"), order.Tax)
+
+		moved := home.PostalAddress
+		moved.City = "Niterói"
+		_, err = shopper.UpdateAddress(ctx, home.ID, AddressRequest{PostalAddress: moved})
+		require.NoError(t, err)
+		order, err = shopper.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Rio de Janeiro", order.ShippingAddress.City)
+
+		_, err = shopper.PlaceOrder(ctx, CreateOrderRequest{
+			Items:             []OrderItemRequest{{ProductID: lamp.ID, Quantity: 1}},
+			ShippingAddressID: "00000000-0000-0000-0000-000000000000",
+		})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("deletes addresses", func(t *testing.T) {
+		require.NoError(t, shopper.DeleteAddress(ctx, home.ID))
+		addresses, err := shopper.ListAddresses(ctx)
+		require.NoError(t, err)
+		assert.Len(t, addresses, 1)
+	})
+}
+
+func TestClient_Payments(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	book, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Book", Price: entity.MustParseMoney("50", "BRL")})
+	require.NoError(t, err)
+	newOrder := func(t *testing.T) *Order {
+		order, err := admin.CreateOrder(ctx, []OrderItemRequest{{ProductID: book.ID, Quantity: 2}})
+		require.NoError(t, err)
+		return order
+	}
+
+	t.Run("captures and marks the order paid", func(t *testing.T) {
+		order := newOrder(t)
+		_, err := admin.PayOrder(ctx, order.ID, payment.FakeTokenDeclined, false)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusPaymentRequired, apiErr.StatusCode)
+
+		paid, err := admin.PayOrder(ctx, order.ID, payment.FakeTokenApproved, false)
+		require.NoError(t, err)
+		assert.Equal(t, "captured", paid.Status)
+		assert.Equal(t, entity.MustParseMoney("100", "BRL"), paid.Amount)
+
+		_, err = admin.PayOrder(ctx, order.ID, payment.FakeTokenApproved, false)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		payments, err := admin.ListPayments(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, payments, 2)
+		assert.Equal(t, "card_declined", payments[0].FailureCode)
+
+		order, err = admin.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "paid", order.Status)
+	})
+
+	t.Run("captures later and refunds in parts", func(t *testing.T) {
+		order := newOrder(t)
+		authorized, err := admin.PayOrder(ctx, order.ID, payment.FakeTokenApproved, true)
+		require.NoError(t, err)
+		assert.Equal(t, "authorized", authorized.Status)
+
+		captured, err := admin.CapturePayment(ctx, authorized.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "captured", captured.Status)
+
+		part := entity.MustParseMoney("30", "BRL")
+		refunded, err := admin.RefundPayment(ctx, captured.ID, &part)
+		require.NoError(t, err)
+		assert.Equal(t, "captured", refunded.Status)
+		assert.Equal(t, part, refunded.Refunded)
+
+		refunded, err = admin.RefundPayment(ctx, captured.ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "refunded", refunded.Status)
+
+		order, err = admin.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "refunded", order.Status)
+	})
+
+	t.Run("refunds through the payment before cancelling or refunding orders", func(t *testing.T) {
+		order := newOrder(t)
+		authorized, err := admin.PayOrder(ctx, order.ID, payment.FakeTokenApproved, true)
+		require.NoError(t, err)
+		_, err = admin.TransitionOrder(ctx, order.ID, "cancelled", "")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+		assert.Contains(t, apiErr.Message, "/payments/"+authorized.ID+"/refund")
+
+		_, err = admin.CapturePayment(ctx, authorized.ID)
+		require.NoError(t, err)
+		_, err = admin.TransitionOrder(ctx, order.ID, "refunded", "")
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = admin.RefundPayment(ctx, authorized.ID, nil)
+		require.NoError(t, err)
+		order, err = admin.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "refunded", order.Status)
+	})
+
+	t.Run("applies signed webhooks once", func(t *testing.T) {
+		order := newOrder(t)
+		authorized, err := admin.PayOrder(ctx, order.ID, payment.FakeTokenApproved, true)
+		require.NoError(t, err)
+
+		gateway := payment.NewFakeProvider(testWebhookSecret)
+		send := func(event payment.WebhookEvent, secret *payment.FakeProvider) int {
+			body, header, err := secret.SignedWebhook(event)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, admin.baseURL+"/payments/webhook", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header = header
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		event := payment.WebhookEvent{ID: "evt_1", Type: payment.EventCaptured, Reference: authorized.Reference, Amount: authorized.Amount}
+		assert.Equal(t, http.StatusBadRequest, send(event, payment.NewFakeProvider("forged")))
+		assert.Equal(t, http.StatusNoContent, send(event, gateway))
+		assert.Equal(t, http.StatusNoContent, send(event, gateway))
+
+		refund := payment.WebhookEvent{ID: "evt_2", Type: payment.EventRefunded, Reference: authorized.Reference, Amount: entity.MustParseMoney("100", "BRL")}
+		assert.Equal(t, http.StatusNoContent, send(refund, gateway))
+		assert.Equal(t, http.StatusNoContent, send(refund, gateway))
+
+		order, err = admin.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "refunded", order.Status)
+		require.Len(t, order.Transitions, 2)
+		assert.Equal(t, "paid", order.Transitions[0].To)
+
+		payments, err := admin.ListPayments(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authorized.Amount, payments[0].Refunded)
+	})
+}
+
+func TestClient_Reviews(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+
+	lamp, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Lamp", Price: entity.MustParseMoney("100", "BRL")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "reviewer", Email: "reviewer@example.com", Password: testPassword})
+	require.NoError(t, err)
+	reviewer := New(admin.baseURL, WithCredentials("reviewer@example.com", testPassword))
+
+	order, err := reviewer.CreateOrder(ctx, []OrderItemRequest{{ProductID: lamp.ID, Quantity: 1}})
+	require.NoError(t, err)
+	_, err = admin.TransitionOrder(ctx, order.ID, "paid", "")
+	require.NoError(t, err)
+
+	mine, err := reviewer.CreateReview(ctx, lamp.ID, ReviewRequest{Rating: 5, Title: "Ilumina bem", Body: "Recomendo."})
+	require.NoError(t, err)
+	theirs, err := admin.CreateReview(ctx, lamp.ID, ReviewRequest{Rating: 2, Title: "Fraca"})
+	require.NoError(t, err)
+
+	t.Run("marks verified purchases and allows one review per product", func(t *testing.T) {
+		assert.True(t, mine.VerifiedPurchase)
+		assert.False(t, theirs.VerifiedPurchase)
+		assert.Equal(t, "pending", mine.Status)
+
+		_, err := reviewer.CreateReview(ctx, lamp.ID, ReviewRequest{Rating: 4, Title: "De novo"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = reviewer.CreateReview(ctx, lamp.ID, ReviewRequest{Rating: 6, Title: "Demais"})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("hides reviews until an admin approves them", func(t *testing.T) {
+		reviews, err := reviewer.ListProductReviews(ctx, lamp.ID)
+		require.NoError(t, err)
+		assert.Empty(t, reviews)
+
+		_, err = reviewer.ListReviews(ctx, "")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+		queue, err := admin.ListReviews(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, queue, 2)
+		assert.Equal(t, mine.ID, queue[0].ID)
+
+		for _, id := range []string{mine.ID, theirs.ID} {
+			_, err = admin.ModerateReview(ctx, id, "approved", "")
+			require.NoError(t, err)
+		}
+		reviews, err = reviewer.ListProductReviews(ctx, lamp.ID)
+		require.NoError(t, err)
+		assert.Len(t, reviews, 2)
+	})
+
+	t.Run("shows the rating on the product", func(t *testing.T) {
+		product, err := reviewer.GetProduct(ctx, lamp.ID)
+		require.NoError(t, err)
+		require.NotNil(t, product.Rating)
+		assert.Equal(t, 3.5, product.Rating.Average)
+		assert.Equal(t, int64(2), product.Rating.Count)
+		assert.Equal(t, map[string]int64{"1": 0, "2": 1, "3": 0, "4": 0, "5": 1}, product.Rating.Distribution)
+	})
+
+	t.Run("sends edited reviews back to moderation", func(t *testing.T) {
+		edited, err := reviewer.UpdateReview(ctx, mine.ID, ReviewRequest{Rating: 4, Title: "Ilumina bem"})
+		require.NoError(t, err)
+		assert.Equal(t, "pending", edited.Status)
+
+		product, err := reviewer.GetProduct(ctx, lamp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), product.Rating.Count)
+
+		_, err = admin.UpdateReview(ctx, mine.ID, ReviewRequest{Rating: 1, Title: "Alterada"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+		rejected, err := admin.ModerateReview(ctx, mine.ID, "rejected", "Sem detalhes")
+		require.NoError(t, err)
+		assert.Equal(t, "Sem detalhes", rejected.ModerationNote)
+	})
+
+	t.Run("lets authors and admins delete reviews", func(t *testing.T) {
+		require.NoError(t, admin.DeleteReview(ctx, mine.ID))
+		err := reviewer.DeleteReview(ctx, theirs.ID)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		require.NoError(t, admin.DeleteReview(ctx, theirs.ID))
+	})
+}
+
+func TestClient_Wishlists(t *testing.T) {
+	admin := setupLoggedInClient(t)
+	ctx := context.Background()
+	brl := func(amount string) entity.Money { return entity.MustParseMoney(amount, "BRL") }
+
+	lamp, err := admin.CreateProduct(ctx, CreateProductRequest{Name: "Lamp", Price: brl("100")})
+	require.NoError(t, err)
+	_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+	require.NoError(t, err)
+	_, err = admin.CreateUser(ctx, CreateUserRequest{Username: "saver", Email: "saver@example.com", Password: testPassword})
+	require.NoError(t, err)
+	saver := New(admin.baseURL, WithCredentials("saver@example.com", testPassword))
+
+	gifts, err := saver.CreateWishlist(ctx, "Presentes")
+	require.NoError(t, err)
+
+	t.Run("adds products once with their current price", func(t *testing.T) {
+		wishlist, err := saver.AddToWishlist(ctx, gifts.ID, lamp.ID)
+		require.NoError(t, err)
+		require.Len(t, wishlist.Items, 1)
+		assert.Equal(t, "Lamp", wishlist.Items[0].Name)
+		assert.Equal(t, brl("100"), wishlist.Items[0].AddedPrice)
+
+		_, err = saver.AddToWishlist(ctx, gifts.ID, lamp.ID)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = admin.GetWishlist(ctx, gifts.ID)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+
+	t.Run("shares wishlists through a link until unshared", func(t *testing.T) {
+		shared, err := saver.ShareWishlist(ctx, gifts.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, shared.ShareToken)
+
+		public, err := New(admin.baseURL).GetSharedWishlist(ctx, shared.ShareToken)
+		require.NoError(t, err)
+		assert.Equal(t, "Presentes", public.Name)
+		assert.Len(t, public.Items, 1)
+
+		_, err = saver.UnshareWishlist(ctx, gifts.ID)
+		require.NoError(t, err)
+		_, err = New(admin.baseURL).GetSharedWishlist(ctx, shared.ShareToken)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+
+	t.Run("records price drops when a product gets cheaper", func(t *testing.T) {
+		_, err := admin.UpdateProduct(ctx, lamp.ID, CreateProductRequest{Name: "Lamp", Price: brl("79.90")})
+		require.NoError(t, err)
+
+		drops, err := saver.ListPriceDrops(ctx)
+		require.NoError(t, err)
+		require.Len(t, drops, 1)
+		assert.Equal(t, brl("100"), drops[0].OldPrice)
+		assert.Equal(t, brl("79.90"), drops[0].NewPrice)
+
+		wishlist, err := saver.GetWishlist(ctx, gifts.ID)
+		require.NoError(t, err)
+		assert.True(t, wishlist.Items[0].PriceDropped)
+		assert.Equal(t, brl("79.90"), wishlist.Items[0].Price)
+
+		drops, err = admin.ListPriceDrops(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, drops)
+	})
+
+	t.Run("hides products pulled back to draft", func(t *testing.T) {
+		shared, err := saver.ShareWishlist(ctx, gifts.ID)
+		require.NoError(t, err)
+		_, err = admin.TransitionProduct(ctx, lamp.ID, "draft")
+		require.NoError(t, err)
+
+		public, err := New(admin.baseURL).GetSharedWishlist(ctx, shared.ShareToken)
+		require.NoError(t, err)
+		assert.Empty(t, public.Items)
+		own, err := saver.GetWishlist(ctx, gifts.ID)
+		require.NoError(t, err)
+		assert.Empty(t, own.Items)
+
+		_, err = admin.TransitionProduct(ctx, lamp.ID, "published")
+		require.NoError(t, err)
+		public, err = New(admin.baseURL).GetSharedWishlist(ctx, shared.ShareToken)
+		require.NoError(t, err)
+		assert.Len(t, public.Items, 1)
+	})
+
+	t.Run("removes products and wishlists", func(t *testing.T) {
+		require.NoError(t, saver.RemoveFromWishlist(ctx, gifts.ID, lamp.ID))
+		renamed, err := saver.RenameWishlist(ctx, gifts.ID, "Natal")
+		require.NoError(t, err)
+		assert.Equal(t, "Natal", renamed.Name)
+		assert.Empty(t, renamed.Items)
+
+		require.NoError(t, saver.DeleteWishlist(ctx, gifts.ID))
+		wishlists, err := saver.ListWishlists(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, wishlists)
+	})
+}
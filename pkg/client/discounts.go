@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+// ApplyCoupon sets the cart's coupon; it fails if the coupon does not
+// apply to the cart's items.
+func (c *Client) ApplyCoupon(ctx context.Context, code string) (*Cart, error) {
+	var cart Cart
+	req := dto.ApplyCouponRequest{Code: code}
+	if err := c.doCart(ctx, http.MethodPut, "/cart/coupon", req, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (c *Client) RemoveCoupon(ctx context.Context) error {
+	return c.doCart(ctx, http.MethodDelete, "/cart/coupon", nil, nil)
+}
+
+// The coupon and promotion calls below need an admin token.
+
+func (c *Client) CreateCoupon(ctx context.Context, req CreateCouponRequest) (*Coupon, error) {
+	var coupon Coupon
+	if err := c.doAuth(ctx, http.MethodPost, "/coupons", nil, req, &coupon); err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// ListCoupons returns one page of coupons, newest first; zero page and
+// limit use the server defaults.
+func (c *Client) ListCoupons(ctx context.Context, page, limit int) ([]Coupon, error) {
+	q := url.Values{}
+	if page > 0 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var coupons []Coupon
+	if err := c.doAuth(ctx, http.MethodGet, "/coupons", q, nil, &coupons); err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (c *Client) GetCoupon(ctx context.Context, code string) (*Coupon, error) {
+	var coupon Coupon
+	if err := c.doAuth(ctx, http.MethodGet, "/coupons/"+url.PathEscape(code), nil, nil, &coupon); err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (c *Client) DeleteCoupon(ctx context.Context, code string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/coupons/"+url.PathEscape(code), nil, nil, nil)
+}
+
+func (c *Client) CreatePromotion(ctx context.Context, req CreatePromotionRequest) (*Promotion, error) {
+	var promotion Promotion
+	if err := c.doAuth(ctx, http.MethodPost, "/promotions", nil, req, &promotion); err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (c *Client) ListPromotions(ctx context.Context) ([]Promotion, error) {
+	var promotions []Promotion
+	if err := c.doAuth(ctx, http.MethodGet, "/promotions", nil, nil, &promotions); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func (c *Client) DeletePromotion(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/promotions/"+url.PathEscape(id), nil, nil, nil)
+}
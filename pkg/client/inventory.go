@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) CreateWarehouse(ctx context.Context, code, name string) (*Warehouse, error) {
+	var warehouse Warehouse
+	req := WarehouseRequest{Code: code, Name: name}
+	if err := c.doAuth(ctx, http.MethodPost, "/warehouses", nil, req, &warehouse); err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (c *Client) ListWarehouses(ctx context.Context) ([]Warehouse, error) {
+	var warehouses []Warehouse
+	if err := c.doAuth(ctx, http.MethodGet, "/warehouses", nil, nil, &warehouses); err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+func (c *Client) GetStock(ctx context.Context, sku string) (*Stock, error) {
+	var stock Stock
+	if err := c.doAuth(ctx, http.MethodGet, "/skus/"+url.PathEscape(sku)+"/stock", nil, nil, &stock); err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// AdjustStock adds delta units (negative to remove) of sku to a warehouse.
+func (c *Client) AdjustStock(ctx context.Context, sku, warehouse string, delta int64, reason string) (*Stock, error) {
+	var stock Stock
+	req := StockAdjustmentRequest{Warehouse: warehouse, Delta: delta, Reason: reason}
+	path := "/skus/" + url.PathEscape(sku) + "/stock/adjustments"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, req, &stock); err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// StockMovements returns sku's ledger, newest first; limit may be zero to
+// use the server default.
+func (c *Client) StockMovements(ctx context.Context, sku string, limit int) ([]StockMovement, error) {
+	var query url.Values
+	if limit > 0 {
+		query = url.Values{"limit": {strconv.Itoa(limit)}}
+	}
+	var movements []StockMovement
+	path := "/skus/" + url.PathEscape(sku) + "/stock/movements"
+	if err := c.doAuth(ctx, http.MethodGet, path, query, nil, &movements); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (c *Client) Reserve(ctx context.Context, req ReservationRequest) (*Reservation, error) {
+	var reservation Reservation
+	if err := c.doAuth(ctx, http.MethodPost, "/reservations", nil, req, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (c *Client) GetReservation(ctx context.Context, id string) (*Reservation, error) {
+	var reservation Reservation
+	if err := c.doAuth(ctx, http.MethodGet, "/reservations/"+url.PathEscape(id), nil, nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// CommitReservation turns a reservation into a sale. Only admins may call
+// it.
+func (c *Client) CommitReservation(ctx context.Context, id string) (*Reservation, error) {
+	var reservation Reservation
+	if err := c.doAuth(ctx, http.MethodPost, "/reservations/"+url.PathEscape(id)+"/commit", nil, nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (c *Client) ReleaseReservation(ctx context.Context, id string) (*Reservation, error) {
+	var reservation Reservation
+	if err := c.doAuth(ctx, http.MethodPost, "/reservations/"+url.PathEscape(id)+"/release", nil, nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
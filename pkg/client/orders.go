@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+// ListOrdersParams filters ListOrders. All lists every user's orders and
+// needs an admin token.
+type ListOrdersParams struct {
+	Status string
+	All    bool
+	UserID string
+	Page   int
+	Limit  int
+}
+
+func (p ListOrdersParams) values() url.Values {
+	q := url.Values{}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.All {
+		q.Set("all", "true")
+	}
+	if p.UserID != "" {
+		q.Set("user_id", p.UserID)
+	}
+	if p.Page > 0 {
+		q.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return q
+}
+
+// CreateOrder places an order for items at their current prices.
+func (c *Client) CreateOrder(ctx context.Context, items []OrderItemRequest) (*Order, error) {
+	return c.PlaceOrder(ctx, CreateOrderRequest{Items: items})
+}
+
+// PlaceOrder is CreateOrder with every option, such as a coupon code.
+func (c *Client) PlaceOrder(ctx context.Context, req CreateOrderRequest) (*Order, error) {
+	var order Order
+	if err := c.doAuth(ctx, http.MethodPost, "/orders", nil, req, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// CheckoutCart places an order for everything in the user's cart and
+// empties it.
+func (c *Client) CheckoutCart(ctx context.Context) (*Order, error) {
+	return c.CreateOrder(ctx, nil)
+}
+
+func (c *Client) ListOrders(ctx context.Context, params ListOrdersParams) ([]Order, error) {
+	var orders []Order
+	if err := c.doAuth(ctx, http.MethodGet, "/orders", params.values(), nil, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (c *Client) GetOrder(ctx context.Context, id string) (*Order, error) {
+	var order Order
+	if err := c.doAuth(ctx, http.MethodGet, "/orders/"+url.PathEscape(id), nil, nil, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// TransitionOrder moves an order to status; it needs an admin token.
+func (c *Client) TransitionOrder(ctx context.Context, id, status, note string) (*Order, error) {
+	var order Order
+	req := dto.OrderTransitionRequest{Status: status, Note: note}
+	path := "/orders/" + url.PathEscape(id) + "/transitions"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, req, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
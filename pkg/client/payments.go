@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+// PayOrder pays a pending order with a payment method token from the
+// gateway. Unless manualCapture is set the payment is captured at once and
+// the order becomes paid.
+func (c *Client) PayOrder(ctx context.Context, orderID, paymentMethod string, manualCapture bool) (*Payment, error) {
+	var p Payment
+	req := dto.CreatePaymentRequest{PaymentMethod: paymentMethod, ManualCapture: manualCapture}
+	path := "/orders/" + url.PathEscape(orderID) + "/payments"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *Client) ListPayments(ctx context.Context, orderID string) ([]Payment, error) {
+	var payments []Payment
+	path := "/orders/" + url.PathEscape(orderID) + "/payments"
+	if err := c.doAuth(ctx, http.MethodGet, path, nil, nil, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// CapturePayment captures an authorized payment; it needs an admin token.
+func (c *Client) CapturePayment(ctx context.Context, id string) (*Payment, error) {
+	var p Payment
+	path := "/payments/" + url.PathEscape(id) + "/capture"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// RefundPayment refunds amount, or everything left when amount is nil; it
+// needs an admin token.
+func (c *Client) RefundPayment(ctx context.Context, id string, amount *entity.Money) (*Payment, error) {
+	var p Payment
+	req := dto.RefundPaymentRequest{Amount: amount}
+	path := "/payments/" + url.PathEscape(id) + "/refund"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
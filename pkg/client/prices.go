@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+func (c *Client) GetProductPrices(ctx context.Context, productID string) (*ProductPrices, error) {
+	var prices ProductPrices
+	if err := c.doAuth(ctx, http.MethodGet, productPricesPath(productID), nil, nil, &prices); err != nil {
+		return nil, err
+	}
+	return &prices, nil
+}
+
+// SetProductPrice sets an explicit price in price.Currency, which must
+// differ from the product's base currency.
+func (c *Client) SetProductPrice(ctx context.Context, productID string, price entity.Money) (*ProductPrices, error) {
+	var prices ProductPrices
+	path := productPricesPath(productID) + "/" + url.PathEscape(price.Currency)
+	req := dto.SetProductPriceRequest{Amount: price.Decimal()}
+	if err := c.doAuth(ctx, http.MethodPut, path, nil, req, &prices); err != nil {
+		return nil, err
+	}
+	return &prices, nil
+}
+
+func (c *Client) DeleteProductPrice(ctx context.Context, productID, currency string) error {
+	path := productPricesPath(productID) + "/" + url.PathEscape(currency)
+	return c.doAuth(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+func (c *Client) GetExchangeRates(ctx context.Context) (*ExchangeRates, error) {
+	var rates ExchangeRates
+	if err := c.doAuth(ctx, http.MethodGet, "/exchange-rates", nil, nil, &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// SetExchangeRates replaces the whole rate table; rates maps ISO 4217 codes
+// to decimal strings per unit of the base currency. Requires an admin token.
+func (c *Client) SetExchangeRates(ctx context.Context, rates map[string]string) (*ExchangeRates, error) {
+	var result ExchangeRates
+	req := dto.ExchangeRatesRequest{Rates: rates}
+	if err := c.doAuth(ctx, http.MethodPut, "/exchange-rates", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func productPricesPath(productID string) string {
+	return "/products/" + url.PathEscape(productID) + "/prices"
+}
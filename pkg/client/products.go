@@ -0,0 +1,359 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/patch"
+	"github/GuilhermeHermes/GO_API/pkg/entity"
+)
+
+type ListProductsParams struct {
+	Page  int
+	Limit int
+	// Sort is a comma separated list of fields, "-" prefixed for
+	// descending order, e.g. "-price,name".
+	Sort string
+
+	// MinPrice and MaxPrice only match products priced in their currency.
+	MinPrice      *entity.Money
+	MaxPrice      *entity.Money
+	Name          string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	IDs           []string
+	CategoryIDs   []string
+	// Tags match products with any of them, or all of them with AllTags.
+	Tags    []string
+	AllTags bool
+	// Statuses only has an effect for editors; everyone else only gets
+	// published products.
+	Statuses []string
+
+	// IncludeTotal asks ListProductsPage for the total match count.
+	IncludeTotal bool
+
+	// Currency prices the results in this ISO 4217 code.
+	Currency string
+}
+
+func (p ListProductsParams) query() url.Values {
+	q := url.Values{}
+	if p.Page > 0 {
+		q.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	if p.MinPrice != nil {
+		q.Set("min_price", p.MinPrice.Decimal())
+		q.Set("price_currency", p.MinPrice.Currency)
+	}
+	if p.MaxPrice != nil {
+		q.Set("max_price", p.MaxPrice.Decimal())
+		q.Set("price_currency", p.MaxPrice.Currency)
+	}
+	if p.Name != "" {
+		q.Set("name", p.Name)
+	}
+	setTime(q, "created_after", p.CreatedAfter)
+	setTime(q, "created_before", p.CreatedBefore)
+	setTime(q, "updated_after", p.UpdatedAfter)
+	setTime(q, "updated_before", p.UpdatedBefore)
+	if len(p.IDs) > 0 {
+		q.Set("ids", strings.Join(p.IDs, ","))
+	}
+	for _, id := range p.CategoryIDs {
+		q.Add("category_id", id)
+	}
+	for _, tag := range p.Tags {
+		q.Add("tag", tag)
+	}
+	if p.AllTags {
+		q.Set("tag_mode", "all")
+	}
+	if len(p.Statuses) > 0 {
+		q.Set("status", strings.Join(p.Statuses, ","))
+	}
+	if p.Currency != "" {
+		q.Set("currency", p.Currency)
+	}
+	return q
+}
+
+func setTime(q url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		q.Set(key, t.Format(time.RFC3339Nano))
+	}
+}
+
+func (c *Client) CreateProduct(ctx context.Context, req CreateProductRequest) (*ProductResponse, error) {
+	var product ProductResponse
+	if err := c.doAuth(ctx, http.MethodPost, "/products", nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (c *Client) GetProduct(ctx context.Context, id string) (*ProductResponse, error) {
+	var product ProductResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/products/"+url.PathEscape(id), nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductAsOf fetches a product as it was at the given time, rebuilt
+// from its history.
+func (c *Client) GetProductAsOf(ctx context.Context, id string, at time.Time) (*ProductResponse, error) {
+	var product ProductResponse
+	query := url.Values{"as_of": {at.Format(time.RFC3339Nano)}}
+	if err := c.doAuth(ctx, http.MethodGet, "/products/"+url.PathEscape(id), query, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductIn fetches a product priced in currency, from its price list
+// or converted with the server's exchange rates.
+func (c *Client) GetProductIn(ctx context.Context, id, currency string) (*ProductResponse, error) {
+	var product ProductResponse
+	query := url.Values{"currency": {currency}}
+	if err := c.doAuth(ctx, http.MethodGet, "/products/"+url.PathEscape(id), query, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ListProducts fetches a single page with offset pagination (page
+// defaults to 1).
+func (c *Client) ListProducts(ctx context.Context, params ListProductsParams) ([]ProductResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	var products []ProductResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/products", params.query(), nil, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// ListProductsPage fetches a single page with cursor pagination. Pass an
+// empty cursor for the first page, then NextCursor/PrevCursor from the
+// response. params.Page is ignored and Sort must be created_at based.
+func (c *Client) ListProductsPage(ctx context.Context, params ListProductsParams, cursor string) (*ProductListResponse, error) {
+	params.Page = 0
+	query := params.query()
+	query.Set("cursor", cursor)
+	if params.IncludeTotal {
+		query.Set("include_total", "true")
+	}
+
+	var page ProductListResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/products", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Products iterates over every product matching params, fetching pages of
+// params.Limit (default 10). It follows cursors when the sort allows it and
+// falls back to offset pages (from params.Page) otherwise. Iteration stops
+// at the first error, which is yielded once.
+func (c *Client) Products(ctx context.Context, params ListProductsParams) iter.Seq2[ProductResponse, error] {
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	switch params.Sort {
+	case "", "asc", "desc", "created_at", "-created_at":
+		if params.Page <= 0 {
+			return c.productsByCursor(ctx, params)
+		}
+	}
+	return c.productsByOffset(ctx, params)
+}
+
+func (c *Client) productsByCursor(ctx context.Context, params ListProductsParams) iter.Seq2[ProductResponse, error] {
+	return func(yield func(ProductResponse, error) bool) {
+		cursor := ""
+		for {
+			page, err := c.ListProductsPage(ctx, params, cursor)
+			if err != nil {
+				yield(ProductResponse{}, err)
+				return
+			}
+			for _, p := range page.Data {
+				if !yield(p, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
+
+func (c *Client) productsByOffset(ctx context.Context, params ListProductsParams) iter.Seq2[ProductResponse, error] {
+	return func(yield func(ProductResponse, error) bool) {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		for {
+			page, err := c.ListProducts(ctx, params)
+			if err != nil {
+				yield(ProductResponse{}, err)
+				return
+			}
+			for _, p := range page {
+				if !yield(p, nil) {
+					return
+				}
+			}
+			if len(page) < params.Limit {
+				return
+			}
+			params.Page++
+		}
+	}
+}
+
+// ProductFacets counts the products matching params by tag, category and
+// price bucket. bounds split the price range in the currency of
+// params.MinPrice/MaxPrice (default BRL); nil uses the server defaults.
+// Pagination, sort and Currency in params are ignored.
+func (c *Client) ProductFacets(ctx context.Context, params ListProductsParams, bounds []string) (*ProductFacets, error) {
+	params.Page, params.Limit, params.Sort, params.Currency = 0, 0, "", ""
+	query := params.query()
+	if len(bounds) > 0 {
+		query.Set("price_buckets", strings.Join(bounds, ","))
+	}
+
+	var facets ProductFacets
+	if err := c.doAuth(ctx, http.MethodGet, "/products/facets", query, nil, &facets); err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+// SearchProducts runs a full-text search; page and limit may be zero to use
+// the server defaults.
+func (c *Client) SearchProducts(ctx context.Context, q string, page, limit int) ([]ProductSearchResult, error) {
+	query := ListProductsParams{Page: page, Limit: limit}.query()
+	query.Set("q", q)
+
+	var results []ProductSearchResult
+	if err := c.doAuth(ctx, http.MethodGet, "/products/search", query, nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *Client) UpdateProduct(ctx context.Context, id string, req CreateProductRequest) (*ProductResponse, error) {
+	var product ProductResponse
+	if err := c.doAuth(ctx, http.MethodPut, "/products/"+url.PathEscape(id), nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// PatchProduct changes the fields req sets, sent as a JSON Merge Patch.
+func (c *Client) PatchProduct(ctx context.Context, id string, req UpdateProductRequest) (*ProductResponse, error) {
+	return c.patchProduct(context.WithValue(ctx, contentTypeKey{}, patch.MergePatchType), id, req)
+}
+
+// PatchProductOps applies JSON Patch operations to a product; a failed
+// "test" operation gives a 409 and changes nothing.
+func (c *Client) PatchProductOps(ctx context.Context, id string, ops []PatchOperation) (*ProductResponse, error) {
+	return c.patchProduct(context.WithValue(ctx, contentTypeKey{}, patch.JSONPatchType), id, ops)
+}
+
+func (c *Client) patchProduct(ctx context.Context, id string, body any) (*ProductResponse, error) {
+	var product ProductResponse
+	if err := c.doAuth(ctx, http.MethodPatch, "/products/"+url.PathEscape(id), nil, body, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProduct moves a product to the trash; RestoreProduct brings it
+// back until the trash is purged.
+func (c *Client) DeleteProduct(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/products/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListTrash returns the first page of deleted products, most recently
+// deleted first. It needs an admin token.
+func (c *Client) ListTrash(ctx context.Context) ([]ProductResponse, error) {
+	var products []ProductResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/products/trash", nil, nil, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// RestoreProduct takes a product out of the trash; it needs an admin token.
+func (c *Client) RestoreProduct(ctx context.Context, id string) (*ProductResponse, error) {
+	var product ProductResponse
+	if err := c.doAuth(ctx, http.MethodPost, "/products/"+url.PathEscape(id)+"/restore", nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ProductHistory returns the first page of a product's revisions, newest
+// first.
+func (c *Client) ProductHistory(ctx context.Context, id string) ([]ProductRevision, error) {
+	var revisions []ProductRevision
+	if err := c.doAuth(ctx, http.MethodGet, "/products/"+url.PathEscape(id)+"/history", nil, nil, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RevertProduct puts a product back the way it was at revision. Pass
+// IfMatch to do it only if the product has not changed since.
+func (c *Client) RevertProduct(ctx context.Context, id string, revision int64) (*ProductResponse, error) {
+	var product ProductResponse
+	path := "/products/" + url.PathEscape(id) + "/history/" + strconv.FormatInt(revision, 10) + "/revert"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// TransitionProduct moves a product to draft, published or archived; it
+// needs an admin or editor token. Pass IfMatch to do it only if the
+// product has not changed since.
+func (c *Client) TransitionProduct(ctx context.Context, id, status string) (*ProductResponse, error) {
+	var product ProductResponse
+	req := dto.ProductTransitionRequest{Status: status}
+	path := "/products/" + url.PathEscape(id) + "/transitions"
+	if err := c.doAuth(ctx, http.MethodPost, path, nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ScheduleProduct replaces when the server publishes and archives a
+// product; it needs an admin or editor token.
+func (c *Client) ScheduleProduct(ctx context.Context, id string, req ProductScheduleRequest) (*ProductResponse, error) {
+	var product ProductResponse
+	path := "/products/" + url.PathEscape(id) + "/schedule"
+	if err := c.doAuth(ctx, http.MethodPut, path, nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
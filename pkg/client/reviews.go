@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+// ListProductReviews returns the first page of a product's approved
+// reviews, newest first. The product's rating comes with GetProduct.
+func (c *Client) ListProductReviews(ctx context.Context, productID string) ([]Review, error) {
+	var reviews []Review
+	if err := c.doAuth(ctx, http.MethodGet, "/products/"+url.PathEscape(productID)+"/reviews", nil, nil, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// CreateReview reviews a product as the logged-in user. The review is
+// pending until an admin approves it.
+func (c *Client) CreateReview(ctx context.Context, productID string, req ReviewRequest) (*Review, error) {
+	var review Review
+	if err := c.doAuth(ctx, http.MethodPost, "/products/"+url.PathEscape(productID)+"/reviews", nil, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// UpdateReview rewrites one of the logged-in user's reviews, sending it
+// back to moderation.
+func (c *Client) UpdateReview(ctx context.Context, id string, req ReviewRequest) (*Review, error) {
+	var review Review
+	if err := c.doAuth(ctx, http.MethodPut, "/reviews/"+url.PathEscape(id), nil, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (c *Client) DeleteReview(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/reviews/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListReviews returns the first page of reviews in status, oldest first;
+// an empty status lists the moderation queue. It needs an admin token.
+func (c *Client) ListReviews(ctx context.Context, status string) ([]Review, error) {
+	var reviews []Review
+	var query url.Values
+	if status != "" {
+		query = url.Values{"status": {status}}
+	}
+	if err := c.doAuth(ctx, http.MethodGet, "/reviews", query, nil, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ModerateReview approves or rejects a review; it needs an admin token.
+func (c *Client) ModerateReview(ctx context.Context, id, status, note string) (*Review, error) {
+	var review Review
+	req := dto.ModerateReviewRequest{Status: status, Note: note}
+	if err := c.doAuth(ctx, http.MethodPost, "/reviews/"+url.PathEscape(id)+"/moderation", nil, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
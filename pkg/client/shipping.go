@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateShippingZone requires an admin token.
+func (c *Client) CreateShippingZone(ctx context.Context, req ShippingZoneRequest) (*ShippingZone, error) {
+	var zone ShippingZone
+	if err := c.doAuth(ctx, http.MethodPost, "/shipping/zones", nil, req, &zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// ListShippingZones requires an admin token.
+func (c *Client) ListShippingZones(ctx context.Context) ([]ShippingZone, error) {
+	var zones []ShippingZone
+	if err := c.doAuth(ctx, http.MethodGet, "/shipping/zones", nil, nil, &zones); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+// DeleteShippingZone deletes the zone and its methods. Requires an admin
+// token.
+func (c *Client) DeleteShippingZone(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/shipping/zones/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AddShippingMethod adds a method to the zone. Requires an admin token.
+func (c *Client) AddShippingMethod(ctx context.Context, zoneID string, req ShippingMethodRequest) (*ShippingMethod, error) {
+	var method ShippingMethod
+	if err := c.doAuth(ctx, http.MethodPost, "/shipping/zones/"+url.PathEscape(zoneID)+"/methods", nil, req, &method); err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+// DeleteShippingMethod requires an admin token.
+func (c *Client) DeleteShippingMethod(ctx context.Context, zoneID, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/shipping/zones/"+url.PathEscape(zoneID)+"/methods/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// QuoteShipping lists the ways to ship the cart to the destination,
+// cheapest first. region and postalCode may be empty.
+func (c *Client) QuoteShipping(ctx context.Context, country, region, postalCode string) (*ShippingQuote, error) {
+	var quote ShippingQuote
+	q := url.Values{"country": {country}}
+	if region != "" {
+		q.Set("region", region)
+	}
+	if postalCode != "" {
+		q.Set("postal_code", postalCode)
+	}
+	if err := c.doCart(ctx, http.MethodGet, "/cart/shipping-rates?"+q.Encode(), nil, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/dto"
+)
+
+// GetCartIn returns the cart taxed in country and region instead of the
+// store's default tax location.
+func (c *Client) GetCartIn(ctx context.Context, country, region string) (*Cart, error) {
+	var cart Cart
+	q := url.Values{"country": {country}}
+	if region != "" {
+		q.Set("region", region)
+	}
+	if err := c.doCart(ctx, http.MethodGet, "/cart?"+q.Encode(), nil, &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (c *Client) GetTaxRates(ctx context.Context) (*TaxRates, error) {
+	var rates TaxRates
+	if err := c.doAuth(ctx, http.MethodGet, "/tax/rates", nil, nil, &rates); err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+// SetTaxRates replaces the whole tax table. Requires an admin token.
+func (c *Client) SetTaxRates(ctx context.Context, rates []TaxRate) (*TaxRates, error) {
+	var result TaxRates
+	req := dto.TaxRatesRequest{Rates: rates}
+	if err := c.doAuth(ctx, http.MethodPut, "/tax/rates", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// QuoteTax works out the tax on items at their catalog prices, before
+// discounts; empty country and region use the store's tax location.
+func (c *Client) QuoteTax(ctx context.Context, req TaxQuoteRequest) (*TaxQuote, error) {
+	var quote TaxQuote
+	if err := c.doAuth(ctx, http.MethodPost, "/tax/quote", nil, req, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github/GuilhermeHermes/GO_API/internal/infra/webserver/patch"
+)
+
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodPost, "/users", nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) GetUserByEmail(ctx context.Context, email string) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodGet, "/users/email/"+url.PathEscape(email), nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser changes a user's username, or role if the client is logged in
+// as an admin. Only the user and admins may call it.
+func (c *Client) UpdateUser(ctx context.Context, id string, req UpdateUserRequest) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.doAuth(ctx, http.MethodPut, "/users/"+url.PathEscape(id), nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchUser changes the fields req sets, sent as a JSON Merge Patch. Like
+// UpdateUser, only the user and admins may call it.
+func (c *Client) PatchUser(ctx context.Context, id string, req UpdateUserRequest) (*UserResponse, error) {
+	return c.patchUser(context.WithValue(ctx, contentTypeKey{}, patch.MergePatchType), id, req)
+}
+
+// PatchUserOps applies JSON Patch operations to a user.
+func (c *Client) PatchUserOps(ctx context.Context, id string, ops []PatchOperation) (*UserResponse, error) {
+	return c.patchUser(context.WithValue(ctx, contentTypeKey{}, patch.JSONPatchType), id, ops)
+}
+
+func (c *Client) patchUser(ctx context.Context, id string, body any) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.doAuth(ctx, http.MethodPatch, "/users/"+url.PathEscape(id), nil, body, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetUserRole gives a user the admin, editor or user role. Only admins
+// may call it.
+func (c *Client) SetUserRole(ctx context.Context, id, role string) (*UserResponse, error) {
+	var user UserResponse
+	req := UpdateUserRoleRequest{Role: role}
+	if err := c.doAuth(ctx, http.MethodPut, "/users/"+url.PathEscape(id)+"/role", nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser deletes a user. Only the user and admins may call it.
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil, nil)
+}
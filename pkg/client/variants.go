@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func variantPath(productID, variantID string) string {
+	path := "/products/" + url.PathEscape(productID) + "/variants"
+	if variantID != "" {
+		path += "/" + url.PathEscape(variantID)
+	}
+	return path
+}
+
+// SetProductOptions replaces a product's options, generating variants for
+// new value combinations and removing those for combinations that are gone.
+func (c *Client) SetProductOptions(ctx context.Context, productID string, req ProductOptionsRequest) (*ProductVariants, error) {
+	var variants ProductVariants
+	path := "/products/" + url.PathEscape(productID) + "/options"
+	if err := c.doAuth(ctx, http.MethodPut, path, nil, req, &variants); err != nil {
+		return nil, err
+	}
+	return &variants, nil
+}
+
+func (c *Client) ListVariants(ctx context.Context, productID string) (*ProductVariants, error) {
+	var variants ProductVariants
+	if err := c.doAuth(ctx, http.MethodGet, variantPath(productID, ""), nil, nil, &variants); err != nil {
+		return nil, err
+	}
+	return &variants, nil
+}
+
+func (c *Client) CreateVariant(ctx context.Context, productID string, req VariantRequest) (*VariantResponse, error) {
+	var variant VariantResponse
+	if err := c.doAuth(ctx, http.MethodPost, variantPath(productID, ""), nil, req, &variant); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (c *Client) GetVariant(ctx context.Context, productID, variantID string) (*VariantResponse, error) {
+	var variant VariantResponse
+	if err := c.doAuth(ctx, http.MethodGet, variantPath(productID, variantID), nil, nil, &variant); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (c *Client) UpdateVariant(ctx context.Context, productID, variantID string, req VariantRequest) (*VariantResponse, error) {
+	var variant VariantResponse
+	if err := c.doAuth(ctx, http.MethodPut, variantPath(productID, variantID), nil, req, &variant); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (c *Client) DeleteVariant(ctx context.Context, productID, variantID string) error {
+	return c.doAuth(ctx, http.MethodDelete, variantPath(productID, variantID), nil, nil, nil)
+}
+
+// GetSKU looks a variant up by SKU, ignoring case.
+func (c *Client) GetSKU(ctx context.Context, sku string) (*SKUResponse, error) {
+	var variant SKUResponse
+	if err := c.doAuth(ctx, http.MethodGet, "/skus/"+url.PathEscape(sku), nil, nil, &variant); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListWishlists returns the logged-in user's wishlists with their products.
+func (c *Client) ListWishlists(ctx context.Context) ([]Wishlist, error) {
+	var wishlists []Wishlist
+	if err := c.doAuth(ctx, http.MethodGet, "/wishlists", nil, nil, &wishlists); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
+
+func (c *Client) CreateWishlist(ctx context.Context, name string) (*Wishlist, error) {
+	return c.wishlistRequest(ctx, http.MethodPost, "/wishlists", WishlistRequest{Name: name})
+}
+
+func (c *Client) GetWishlist(ctx context.Context, id string) (*Wishlist, error) {
+	return c.wishlistRequest(ctx, http.MethodGet, "/wishlists/"+url.PathEscape(id), nil)
+}
+
+func (c *Client) RenameWishlist(ctx context.Context, id, name string) (*Wishlist, error) {
+	return c.wishlistRequest(ctx, http.MethodPut, "/wishlists/"+url.PathEscape(id), WishlistRequest{Name: name})
+}
+
+func (c *Client) DeleteWishlist(ctx context.Context, id string) error {
+	return c.doAuth(ctx, http.MethodDelete, "/wishlists/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AddToWishlist puts a product on a wishlist and returns the whole list.
+func (c *Client) AddToWishlist(ctx context.Context, id, productID string) (*Wishlist, error) {
+	req := AddWishlistItemRequest{ProductID: productID}
+	return c.wishlistRequest(ctx, http.MethodPost, "/wishlists/"+url.PathEscape(id)+"/items", req)
+}
+
+func (c *Client) RemoveFromWishlist(ctx context.Context, id, productID string) error {
+	path := "/wishlists/" + url.PathEscape(id) + "/items/" + url.PathEscape(productID)
+	return c.doAuth(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// ShareWishlist turns on the wishlist's public link; the token in the
+// result is what GetSharedWishlist takes.
+func (c *Client) ShareWishlist(ctx context.Context, id string) (*Wishlist, error) {
+	return c.wishlistRequest(ctx, http.MethodPut, "/wishlists/"+url.PathEscape(id)+"/share", nil)
+}
+
+func (c *Client) UnshareWishlist(ctx context.Context, id string) (*Wishlist, error) {
+	return c.wishlistRequest(ctx, http.MethodDelete, "/wishlists/"+url.PathEscape(id)+"/share", nil)
+}
+
+// GetSharedWishlist reads a shared wishlist; it needs no login.
+func (c *Client) GetSharedWishlist(ctx context.Context, token string) (*Wishlist, error) {
+	var wishlist Wishlist
+	if err := c.do(ctx, http.MethodGet, "/wishlists/shared/"+url.PathEscape(token), nil, nil, &wishlist); err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+// ListPriceDrops returns the first page of price drops of products on the
+// logged-in user's wishlists, newest first.
+func (c *Client) ListPriceDrops(ctx context.Context) ([]PriceDrop, error) {
+	var drops []PriceDrop
+	if err := c.doAuth(ctx, http.MethodGet, "/wishlists/price-drops", nil, nil, &drops); err != nil {
+		return nil, err
+	}
+	return drops, nil
+}
+
+func (c *Client) wishlistRequest(ctx context.Context, method, path string, in any) (*Wishlist, error) {
+	var wishlist Wishlist
+	if err := c.doAuth(ctx, method, path, nil, in, &wishlist); err != nil {
+		return nil, err
+	}
+	return &wishlist, nil
+}
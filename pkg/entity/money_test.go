@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney("10.99", "brl")
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 1099, Currency: "BRL"}, m)
+
+	m, err = ParseMoney("10.5", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), m.Amount)
+
+	m, err = ParseMoney("1500", "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), m.Amount)
+
+	m, err = ParseMoney("-0.05", "")
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: -5, Currency: DefaultCurrency}, m)
+
+	for _, bad := range []string{"", "abc", "1.999", "1.", ".5", "1,50", "1e3"} {
+		_, err := ParseMoney(bad, "BRL")
+		assert.ErrorIs(t, err, ErrInvalidAmount, bad)
+	}
+
+	_, err = ParseMoney("1.5", "JPY")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = ParseMoney("1", "ABC")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "10.99", Money{Amount: 1099, Currency: "BRL"}.Decimal())
+	assert.Equal(t, "0.05", Money{Amount: 5, Currency: "USD"}.Decimal())
+	assert.Equal(t, "-1.20", Money{Amount: -120, Currency: "EUR"}.Decimal())
+	assert.Equal(t, "1500", Money{Amount: 1500, Currency: "JPY"}.Decimal())
+	assert.Equal(t, "1.005", Money{Amount: 1005, Currency: "KWD"}.Decimal())
+	assert.Equal(t, "10.99 BRL", Money{Amount: 1099, Currency: "BRL"}.String())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MustParseMoney("0.10", "BRL")
+	b := MustParseMoney("0.20", "BRL")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	// The classic float failure: 0.1 + 0.2 == 0.30000000000000004.
+	assert.Equal(t, "0.30", sum.Decimal())
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, "-0.10", diff.Decimal())
+
+	assert.Equal(t, "0.30", a.Mul(3).Decimal())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	_, err = a.Add(MustParseMoney("1", "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	raw, err := json.Marshal(MustParseMoney("10.90", "BRL"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"10.90","currency":"BRL"}`, string(raw))
+
+	cases := map[string]Money{
+		`{"amount":"10.90","currency":"usd"}`: {Amount: 1090, Currency: "USD"},
+		`{"amount":10.9,"currency":"USD"}`:    {Amount: 1090, Currency: "USD"},
+		`{"amount":"3"}`:                      {Amount: 300, Currency: DefaultCurrency},
+		`12.5`:                                {Amount: 1250, Currency: DefaultCurrency},
+		`"12.50"`:                             {Amount: 1250, Currency: DefaultCurrency},
+	}
+	for input, want := range cases {
+		var m Money
+		require.NoError(t, json.Unmarshal([]byte(input), &m), input)
+		assert.Equal(t, want, m, input)
+	}
+
+	var m Money
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.234","currency":"BRL"}`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1","currency":"XYZ"}`), &m))
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	cases := map[float64]string{
+		19.99:  "19.99",
+		0.285:  "0.29",
+		0.1:    "0.10",
+		2.5:    "2.50",
+		-1.005: "-1.01",
+		100:    "100.00",
+	}
+	for f, want := range cases {
+		m, err := MoneyFromFloat(f, "BRL")
+		require.NoError(t, err)
+		assert.Equal(t, want, m.Decimal(), f)
+	}
+
+	m, err := MoneyFromFloat(2.5, "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, "3", m.Decimal())
+}